@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -17,13 +18,14 @@ const (
 )
 
 func main() {
-	cfg := config.MustLoad().AuthConfig
+	fullCfg := config.MustLoad()
+	cfg := fullCfg.AuthConfig
 
 	log := setupLogger(cfg.Env)
 
 	log.Info("starting app")
 	//TODO: [x] init app
-	application := auth.New(log, cfg.Port, cfg.StoragePath, cfg.TokenTTL, cfg.SecretKey)
+	application := auth.New(log, cfg.Port, cfg.StoragePath, cfg.Storage, cfg.TokenTTL, cfg.SecretKey, cfg.Outbox, fmt.Sprintf("localhost:%d", fullCfg.TaskConfig.Port))
 
 	go application.GRPCSrv.MustRun()
 
@@ -32,7 +34,7 @@ func main() {
 
 	<-stop
 
-	application.GRPCSrv.Stop()
+	application.Stop()
 
 	log.Info("application stopped")
 
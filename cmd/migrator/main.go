@@ -6,20 +6,42 @@ import (
 	"fmt"
 
 	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
 func main() {
-	var storagePath, migrationsPath, migrationsTable string
+	var driver, storagePath, dsn, authStoragePath, authDSN, migrationsPath, migrationsTable string
 
+	flag.StringVar(&driver, "driver", "sqlite", "storage driver, sqlite or postgres")
 	flag.StringVar(&storagePath, "storage-path", "", "path to storage")
-	flag.StringVar(&migrationsPath, "migrations-path", "", "path to migrations")
+	flag.StringVar(&dsn, "dsn", "", "postgres:// URL of the database")
+	flag.StringVar(&authStoragePath, "auth-storage-path", "", "path to the auth storage the users of the task storage are copied to before their credentials are dropped")
+	flag.StringVar(&authDSN, "auth-dsn", "", "postgres:// URL of the auth database the users of the task database are copied to before their credentials are dropped")
+	flag.StringVar(&migrationsPath, "migrations-path", "", "path to migrations, ./migrations/auth or ./migrations/task, under ./migrations/postgres for postgres")
 	flag.StringVar(&migrationsTable, "migrations-table", "migrations", "name of migrations table")
 	flag.Parse()
 
-	if storagePath == "" {
-		panic("storage-path is required")
+	var databaseURL string
+	var task, auth database
+	switch driver {
+	case "sqlite":
+		if storagePath == "" {
+			panic("storage-path is required")
+		}
+		databaseURL = fmt.Sprintf("sqlite3://%s", storagePath)
+		task = database{driver: "sqlite3", dsn: storagePath}
+		auth = database{driver: "sqlite3", dsn: authStoragePath}
+	case "postgres":
+		if dsn == "" {
+			panic("dsn is required")
+		}
+		databaseURL = dsn
+		task = database{driver: "postgres", dsn: dsn}
+		auth = database{driver: "postgres", dsn: authDSN}
+	default:
+		panic(fmt.Sprintf("unknown driver %q", driver))
 	}
 	if migrationsPath == "" {
 		panic("migrations-path is required")
@@ -27,12 +49,16 @@ func main() {
 
 	m, err := migrate.New(
 		"file://"+migrationsPath,
-		fmt.Sprintf("sqlite3://%s", storagePath),
+		databaseURL,
 	)
 	if err != nil {
 		panic(err)
 	}
 
+	if err := copyUsers(m, migrationsPath, task, auth); err != nil {
+		panic(err)
+	}
+
 	if err := m.Up(); err != nil {
 		if errors.Is(err, migrate.ErrNoChange) {
 			fmt.Println("no migrations to apply")
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// userReferenceSuffix names the task migration that drops the credentials of the users, which
+// live in the auth database from then on.
+const userReferenceSuffix = "_user_reference.up.sql"
+
+// database is where a migrator connects to, for database/sql.
+type database struct {
+	driver string
+	dsn    string
+}
+
+// copyUsers copies the users of a task database that has yet to drop their credentials to the auth
+// database, migrating the task database up to the migration that drops them first. Without an auth
+// database to copy to it refuses, unless there are no users to lose. Users the auth database has
+// already are left as they are, so a migration that failed later on can be run again.
+func copyUsers(m *migrate.Migrate, migrationsPath string, task, auth database) error {
+	version, ok, err := userReferenceVersion(migrationsPath)
+	if err != nil || !ok {
+		return err
+	}
+
+	current, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		// A new database has no users.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get version: %w", err)
+	}
+	if dirty || current >= version {
+		return nil
+	}
+
+	if current < version-1 {
+		if err := m.Migrate(version - 1); err != nil {
+			return fmt.Errorf("migrate to version %d: %w", version-1, err)
+		}
+	}
+
+	users, err := selectUsers(task)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	if auth.dsn == "" {
+		return fmt.Errorf("migration %d drops the credentials of %d users, "+
+			"give the migrated auth database to copy them to with -auth-storage-path or -auth-dsn", version, len(users))
+	}
+
+	if err := insertUsers(auth, users); err != nil {
+		return err
+	}
+
+	fmt.Printf("copied %d users to the auth database\n", len(users))
+
+	return nil
+}
+
+// userReferenceVersion returns the version of the migration that drops the credentials, if the
+// migrations are the ones of the task database.
+func userReferenceVersion(migrationsPath string) (uint, bool, error) {
+	entries, err := os.ReadDir(migrationsPath)
+	if err != nil {
+		return 0, false, fmt.Errorf("read migrations: %w", err)
+	}
+
+	for _, entry := range entries {
+		prefix, ok := strings.CutSuffix(entry.Name(), userReferenceSuffix)
+		if !ok {
+			continue
+		}
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("parse version of %s: %w", entry.Name(), err)
+		}
+
+		return uint(version), true, nil
+	}
+
+	return 0, false, nil
+}
+
+type user struct {
+	id, email, password, timezone string
+}
+
+func selectUsers(task database) ([]user, error) {
+	db, err := sql.Open(task.driver, task.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open task database: %w", err)
+	}
+	defer db.Close()
+
+	// "user" is reserved in Postgres, its table is users.
+	table := "user"
+	if task.driver == "postgres" {
+		table = "users"
+	}
+
+	rows, err := db.Query("SELECT id, email, password, timezone FROM " + table)
+	if err != nil {
+		return nil, fmt.Errorf("select users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []user
+	for rows.Next() {
+		var u user
+		if err := rows.Scan(&u.id, &u.email, &u.password, &u.timezone); err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select users: %w", err)
+	}
+
+	return users, nil
+}
+
+func insertUsers(auth database, users []user) error {
+	db, err := sql.Open(auth.driver, auth.dsn)
+	if err != nil {
+		return fmt.Errorf("open auth database: %w", err)
+	}
+	defer db.Close()
+
+	query := "INSERT OR IGNORE INTO user(id, email, password, timezone) VALUES ($1, $2, $3, $4)"
+	if auth.driver == "postgres" {
+		query = "INSERT INTO users(id, email, password, timezone) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING"
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, u := range users {
+		if _, err := tx.Exec(query, u.id, u.email, u.password, u.timezone); err != nil {
+			return fmt.Errorf("insert user %s: %w", u.id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+//go:build sqlite_fts5
+
+// The task migrations create an FTS5 table, which go-sqlite3 only builds in with the tag. make test
+// sets it.
+
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	taskMigrations = "../../migrations/task"
+	authMigrations = "../../migrations/auth"
+)
+
+// newDatabase migrates a new SQLite database with the migrations up to the version, every one
+// when it is 0.
+func newDatabase(t *testing.T, migrationsPath string, version uint) (*migrate.Migrate, database) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "storage.db")
+	m, err := migrate.New("file://"+migrationsPath, "sqlite3://"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	if version == 0 {
+		err = m.Up()
+	} else {
+		err = m.Migrate(version)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m, database{driver: "sqlite3", dsn: path}
+}
+
+func open(t *testing.T, d database) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open(d.driver, d.dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestCopyUsers(t *testing.T) {
+	m, task := newDatabase(t, taskMigrations, 10)
+	_, auth := newDatabase(t, authMigrations, 0)
+
+	id := uuid.NewString()
+	if _, err := open(t, task).Exec(
+		"INSERT INTO user(id, email, password, timezone) VALUES ($1, 'ann@example.com', 'hash', 'Europe/Berlin')", id); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyUsers(m, taskMigrations, task, auth); err != nil {
+		t.Fatalf("copy users: %v", err)
+	}
+	// Running it again, as after a failed migration, changes nothing.
+	if err := copyUsers(m, taskMigrations, task, auth); err != nil {
+		t.Fatalf("copy users again: %v", err)
+	}
+
+	version, _, err := m.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 19 {
+		t.Errorf("want the task database migrated up to the migration dropping the credentials, got version %d", version)
+	}
+
+	var email, password, timezone string
+	err = open(t, auth).QueryRow("SELECT email, password, timezone FROM user WHERE id = $1", id).Scan(&email, &password, &timezone)
+	if err != nil {
+		t.Fatalf("select copied user: %v", err)
+	}
+	if email != "ann@example.com" || password != "hash" || timezone != "Europe/Berlin" {
+		t.Errorf("want the user copied, got %q %q %q", email, password, timezone)
+	}
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := copyUsers(m, taskMigrations, task, database{driver: "sqlite3"}); err != nil {
+		t.Errorf("copy users of a migrated database: %v", err)
+	}
+}
+
+func TestCopyUsersRefusesWithoutAuthDatabase(t *testing.T) {
+	m, task := newDatabase(t, taskMigrations, 19)
+	if _, err := open(t, task).Exec(
+		"INSERT INTO user(id, email, password) VALUES ($1, 'ann@example.com', 'hash')", uuid.NewString()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := copyUsers(m, taskMigrations, task, database{driver: "sqlite3"})
+	if err == nil || !strings.Contains(err.Error(), "-auth-storage-path") {
+		t.Fatalf("want copying without an auth database refused, got %v", err)
+	}
+
+	var count int
+	if err := open(t, task).QueryRow("SELECT count(*) FROM user WHERE email IS NOT NULL").Scan(&count); err != nil {
+		t.Fatalf("select users: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("want the credentials kept, got %d users with them", count)
+	}
+}
+
+func TestCopyUsersWithoutUsers(t *testing.T) {
+	m, task := newDatabase(t, taskMigrations, 1)
+
+	if err := copyUsers(m, taskMigrations, task, database{driver: "sqlite3"}); err != nil {
+		t.Fatalf("copy no users: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	m, _ = newDatabase(t, authMigrations, 1)
+	if err := copyUsers(m, authMigrations, task, database{driver: "sqlite3"}); err != nil {
+		t.Errorf("copy users of the auth migrations: %v", err)
+	}
+}
@@ -23,7 +23,7 @@ func main() {
 
 	log.Info("starting app")
 
-	application := todo.New(log, cfg.Port, cfg.StoragePath)
+	application := todo.New(log, cfg.Port, cfg.StoragePath, cfg.Storage, cfg.Attachments, cfg.Board, cfg.RebalanceInterval, cfg.IdempotencyTTL, cfg.EventHistory, cfg.ChangeRetention, cfg.Webhooks, cfg.Outbox)
 
 	go application.GRPCSrv.MustRun()
 
@@ -32,7 +32,7 @@ func main() {
 
 	<-stop
 
-	application.GRPCSrv.Stop()
+	application.Stop()
 
 	log.Info("application stopped")
 
@@ -1,26 +1,74 @@
 package auth
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"time"
 
 	grpcapp "github.com/SlashLight/todo-list/internal/app/auth/grpc"
+	taskgrpc "github.com/SlashLight/todo-list/internal/clients/task-service/grpc"
+	"github.com/SlashLight/todo-list/internal/config"
+	"github.com/SlashLight/todo-list/internal/events/taskservice"
 	auth_service "github.com/SlashLight/todo-list/internal/services/auth-service"
+	outbox_relay "github.com/SlashLight/todo-list/internal/services/outbox-relay"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+	"github.com/SlashLight/todo-list/internal/storage/postgres"
 	"github.com/SlashLight/todo-list/internal/storage/sqlite"
 )
 
 type App struct {
 	GRPCSrv *grpcapp.App
+
+	stopWorkers context.CancelFunc
+}
+
+// Storage is what the auth service and the outbox relay need from the database.
+type Storage interface {
+	auth_service.UserSaver
+	auth_service.UserProvider
+	outbox_relay.OutboxProvider
 }
 
-func New(log *slog.Logger, grpcPort int, storagePath string, tokenTTL time.Duration, secret string) *App {
-	storage, err := sqlite.New(storagePath)
+// New returns the app of the auth service, which relays the changes to its users to the task
+// service at taskServiceAddr.
+func New(log *slog.Logger, grpcPort int, storagePath string, storageCfg config.StorageConfig, tokenTTL time.Duration, secret string, outboxCfg config.AuthOutboxConfig, taskServiceAddr string) *App {
+	storage, err := newStorage(storageCfg, storagePath)
+	if err != nil {
+		panic(err)
+	}
+
+	// The relay retries the events the task service failed to apply, the client need not.
+	taskService, err := taskgrpc.New(taskServiceAddr, log, 0, outboxCfg.Timeout)
 	if err != nil {
 		panic(err)
 	}
 
 	authService := auth_service.New(storage, storage, log, tokenTTL, secret)
 	grpcApp := grpcapp.New(log, authService, grpcPort)
+	relay := outbox_relay.New(storage, taskservice.New(taskService, outboxCfg.Timeout), outboxCfg.BatchSize, outboxCfg.Retention, log)
 
-	return &App{GRPCSrv: grpcApp}
+	ctx, cancel := context.WithCancel(context.Background())
+	go relay.Run(ctx, outboxCfg.PollInterval)
+
+	return &App{GRPCSrv: grpcApp, stopWorkers: cancel}
+}
+
+// Stop stops the outbox relay and gracefully stops the gRPC server.
+func (a *App) Stop() {
+	a.stopWorkers()
+	a.GRPCSrv.Stop()
+}
+
+func newStorage(cfg config.StorageConfig, storagePath string) (Storage, error) {
+	switch cfg.Driver {
+	case "sqlite":
+		return sqlite.New(storagePath)
+	case "postgres":
+		return postgres.New(cfg.DSN)
+	case "memory":
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -16,8 +17,11 @@ type App struct {
 	port       int
 }
 
-func New(log *slog.Logger, taskService taskgrpc.Service, port int) *App {
-	gRPCServer := grpc.NewServer()
+func New(log *slog.Logger, taskService taskgrpc.Service, idempotencyStore taskgrpc.IdempotencyStore, idempotencyTTL time.Duration, port int) *App {
+	gRPCServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(taskgrpc.IdempotencyInterceptor(idempotencyStore, idempotencyTTL, log)),
+		grpc.ChainStreamInterceptor(taskgrpc.IdempotencyStreamInterceptor()),
+	)
 
 	taskgrpc.RegisterServerAPI(gRPCServer, taskService)
 
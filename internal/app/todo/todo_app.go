@@ -1,25 +1,146 @@
 package todo
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"log/slog"
+	"time"
 
 	grpcapp "github.com/SlashLight/todo-list/internal/app/todo/grpc"
+	"github.com/SlashLight/todo-list/internal/config"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/events/file"
+	"github.com/SlashLight/todo-list/internal/events/inprocess"
+	"github.com/SlashLight/todo-list/internal/events/nats"
+	taskgrpc "github.com/SlashLight/todo-list/internal/grpc/task-service"
+	"github.com/SlashLight/todo-list/internal/lib/webhook"
+	outbox_relay "github.com/SlashLight/todo-list/internal/services/outbox-relay"
 	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/internal/storage/blob/local"
+	"github.com/SlashLight/todo-list/internal/storage/blob/s3"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+	"github.com/SlashLight/todo-list/internal/storage/postgres"
 	"github.com/SlashLight/todo-list/internal/storage/sqlite"
 )
 
+// changePruneInterval is how often the changes clients no longer need are pruned.
+const changePruneInterval = time.Hour
+
 type App struct {
 	GRPCSrv *grpcapp.App
+
+	taskService *task_service.Service
+	publisher   outbox_relay.EventPublisher
+	stopWorkers context.CancelFunc
 }
 
-func New(log *slog.Logger, grpcPort int, storagePath string) *App {
-	storage, err := sqlite.New(storagePath)
+// Storage is what the services and workers of the app need from the database.
+type Storage interface {
+	task_service.Storage
+	taskgrpc.IdempotencyStore
+	outbox_relay.OutboxProvider
+}
+
+func New(log *slog.Logger, grpcPort int, storagePath string, storageCfg config.StorageConfig, attachmentsCfg config.AttachmentsConfig, boardCfg config.BoardConfig, rebalanceInterval, idempotencyTTL time.Duration, eventHistory int, changeRetention time.Duration, webhooksCfg config.WebhooksConfig, outboxCfg config.OutboxConfig) *App {
+	storage, err := newStorage(storageCfg, storagePath)
+	if err != nil {
+		panic(err)
+	}
+
+	blobStore, err := newBlobStore(attachmentsCfg)
+	if err != nil {
+		panic(err)
+	}
+
+	publisher, err := newEventPublisher(outboxCfg)
 	if err != nil {
 		panic(err)
 	}
 
-	taskService := task_service.New(storage, log) // 7 days
-	grpcApp := grpcapp.New(log, taskService, grpcPort)
+	boardColumns := make([]models.BoardColumn, len(boardCfg.Columns))
+	for i, column := range boardCfg.Columns {
+		boardColumns[i] = models.BoardColumn{Status: column.Status, Title: column.Title, WIPLimit: column.WIPLimit}
+	}
+
+	if err := task_service.ValidateBoardColumns(boardColumns); err != nil {
+		panic(err)
+	}
+
+	settings := task_service.Settings{
+		AttachmentQuota:     attachmentsCfg.UserQuota,
+		BoardColumns:        boardColumns,
+		EventHistory:        eventHistory,
+		ChangeRetention:     changeRetention,
+		WebhookMaxAttempts:  webhooksCfg.MaxAttempts,
+		WebhookDisableAfter: webhooksCfg.DisableAfter,
+		WebhookTimeout:      webhooksCfg.Timeout,
+	}
 
-	return &App{GRPCSrv: grpcApp}
+	webhookSender := webhook.New(webhooksCfg.Timeout, webhooksCfg.AllowPrivateNetworks)
+
+	taskService := task_service.New(storage, blobStore, webhookSender, settings, log)
+	grpcApp := grpcapp.New(log, taskService, storage, idempotencyTTL, grpcPort)
+
+	// The relay queues the task events for webhooks before publishing them. Queuing an event again
+	// is a no-op, so a publisher failing after it does not post the event twice.
+	consumers := inprocess.New()
+	consumers.Subscribe(taskService.QueueWebhooks)
+	consumers.Subscribe(publisher.Publish)
+	relay := outbox_relay.New(storage, consumers, outboxCfg.BatchSize, outboxCfg.Retention, log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go taskService.RunRebalancer(ctx, rebalanceInterval)
+	go taskService.RunChangePruner(ctx, changePruneInterval)
+	go taskService.RunWebhookDispatcher(ctx, webhooksCfg.PollInterval)
+	go relay.Run(ctx, outboxCfg.PollInterval)
+
+	return &App{GRPCSrv: grpcApp, taskService: taskService, publisher: publisher, stopWorkers: cancel}
+}
+
+// Stop stops the background workers, ends the task watchers, which would otherwise never finish,
+// gracefully stops the gRPC server and closes the event publisher.
+func (a *App) Stop() {
+	a.stopWorkers()
+	a.taskService.CloseWatchers()
+	a.GRPCSrv.Stop()
+
+	if closer, ok := a.publisher.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+func newStorage(cfg config.StorageConfig, storagePath string) (Storage, error) {
+	switch cfg.Driver {
+	case "sqlite":
+		return sqlite.New(storagePath)
+	case "postgres":
+		return postgres.New(cfg.DSN)
+	case "memory":
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+func newBlobStore(cfg config.AttachmentsConfig) (task_service.BlobStore, error) {
+	switch cfg.Store {
+	case "local":
+		return local.New(cfg.LocalPath)
+	case "s3":
+		return s3.New(context.Background(), cfg.S3.Endpoint, cfg.S3.AccessKey, cfg.S3.SecretKey, cfg.S3.Bucket, cfg.S3.Region, cfg.S3.UseSSL)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.Store)
+	}
+}
+
+func newEventPublisher(cfg config.OutboxConfig) (outbox_relay.EventPublisher, error) {
+	switch cfg.Publisher {
+	case "nats":
+		return nats.New(cfg.NATS.URL, cfg.NATS.SubjectPrefix, cfg.NATS.Timeout)
+	case "file":
+		return file.New(cfg.FilePath)
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", cfg.Publisher)
+	}
 }
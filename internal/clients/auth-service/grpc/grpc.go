@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/google/uuid"
 	grpclog "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	grpcretry "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/retry"
 	"google.golang.org/grpc"
@@ -52,12 +53,13 @@ func InterceptorLogger(l *slog.Logger) grpclog.Logger {
 	})
 }
 
-func (c *Client) Register(ctx context.Context, email, password string) (string, error) {
+func (c *Client) Register(ctx context.Context, email, password, timezone string) (string, error) {
 	const op = "auth.grpc.Register"
 
 	resp, err := c.api.Register(ctx, &authv1.RegisterRequest{
 		Email:    email,
 		Password: password,
+		Timezone: timezone,
 	})
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
@@ -79,3 +81,32 @@ func (c *Client) Login(ctx context.Context, email, password string) (string, err
 
 	return resp.Token, nil
 }
+
+// UpdateProfile sets the user's timezone and returns a token carrying the new profile.
+func (c *Client) UpdateProfile(ctx context.Context, userID uuid.UUID, timezone string) (string, error) {
+	const op = "auth.grpc.UpdateProfile"
+
+	resp, err := c.api.UpdateProfile(ctx, &authv1.UpdateProfileRequest{
+		UserId:   userID.String(),
+		Timezone: timezone,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp.Token, nil
+}
+
+// DeleteAccount deletes the user, the task service deletes their data shortly after.
+func (c *Client) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
+	const op = "auth.grpc.DeleteAccount"
+
+	_, err := c.api.DeleteAccount(ctx, &authv1.DeleteAccountRequest{
+		UserId: userID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
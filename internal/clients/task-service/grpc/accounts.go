@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+)
+
+// ApplyUserEvent tells the task service about a change to a user of the auth service.
+func (c *Client) ApplyUserEvent(ctx context.Context, eventType string, userID uuid.UUID) error {
+	const op = "task.grpc.ApplyUserEvent"
+
+	_, err := c.accounts.ApplyUserEvent(ctx, &taskv1.UserEventRequest{
+		Type:   eventType,
+		UserId: userID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+const uploadChunkSize = 32 * 1024
+
+func (c *Client) UploadAttachment(ctx context.Context, taskID, authorID uuid.UUID, name, contentType string, r io.Reader) (*models.Attachment, error) {
+	const op = "task.grpc.UploadAttachment"
+
+	stream, err := c.api.UploadAttachment(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = stream.Send(&taskv1.UploadAttachmentRequest{Data: &taskv1.UploadAttachmentRequest_Meta{Meta: &taskv1.AttachmentMeta{
+		TaskId:      taskID.String(),
+		AuthorId:    authorID.String(),
+		Name:        name,
+		ContentType: contentType,
+	}}})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, closeAndRecvErr(stream, err))
+	}
+
+	buf := make([]byte, uploadChunkSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			err = stream.Send(&taskv1.UploadAttachmentRequest{Data: &taskv1.UploadAttachmentRequest_Chunk{Chunk: buf[:n]}})
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, closeAndRecvErr(stream, err))
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+
+		if readErr != nil {
+			return nil, fmt.Errorf("%s: read upload: %w", op, readErr)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attachmentFromProto(resp)
+}
+
+func (c *Client) ListAttachments(ctx context.Context, taskID, authorID uuid.UUID) ([]*models.Attachment, error) {
+	const op = "task.grpc.ListAttachments"
+
+	resp, err := c.api.ListAttachments(ctx, &taskv1.ListAttachmentsRequest{
+		TaskId:   taskID.String(),
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attachments := make([]*models.Attachment, len(resp.Attachments))
+	for i := range resp.Attachments {
+		attachments[i], err = attachmentFromProto(resp.Attachments[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return attachments, nil
+}
+
+// DownloadAttachment returns attachment metadata and a reader over its contents, the caller must close the reader.
+func (c *Client) DownloadAttachment(ctx context.Context, attachmentID, authorID uuid.UUID) (*models.Attachment, io.ReadCloser, error) {
+	const op = "task.grpc.DownloadAttachment"
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := c.api.DownloadAttachment(ctx, &taskv1.AttachmentRequest{
+		AttachmentId: attachmentID.String(),
+		AuthorId:     authorID.String(),
+	})
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if first.GetMeta() == nil {
+		cancel()
+		return nil, nil, fmt.Errorf("%s: first message has no metadata", op)
+	}
+
+	attachment, err := attachmentFromProto(first.GetMeta())
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recv := func() ([]byte, error) {
+		msg, err := stream.Recv()
+		return msg.GetChunk(), err
+	}
+
+	return attachment, &chunkReader{recv: recv, cancel: cancel}, nil
+}
+
+func (c *Client) DeleteAttachment(ctx context.Context, attachmentID, authorID uuid.UUID) error {
+	const op = "task.grpc.DeleteAttachment"
+
+	_, err := c.api.DeleteAttachment(ctx, &taskv1.AttachmentRequest{
+		AttachmentId: attachmentID.String(),
+		AuthorId:     authorID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// closeAndRecvErr replaces the io.EOF a failed Send returns with the real status sent by the server.
+func closeAndRecvErr[Req, Res any](stream grpc.ClientStreamingClient[Req, Res], sendErr error) error {
+	if !errors.Is(sendErr, io.EOF) {
+		return sendErr
+	}
+
+	if _, err := stream.CloseAndRecv(); err != nil {
+		return err
+	}
+
+	return sendErr
+}
+
+func attachmentFromProto(attachment *taskv1.Attachment) (*models.Attachment, error) {
+	createdAt, err := time.Parse(time.RFC3339, attachment.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created at: %w", err)
+	}
+
+	return &models.Attachment{
+		ID:          uuid.MustParse(attachment.Id),
+		TaskID:      uuid.MustParse(attachment.TaskId),
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.Sha256,
+		CreatedAt:   createdAt,
+	}, nil
+}
+
+// chunkReader exposes the chunks of a server stream as an io.ReadCloser, Close cancels the stream.
+type chunkReader struct {
+	recv   func() ([]byte, error)
+	cancel context.CancelFunc
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+
+		r.buf = chunk
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+func (r *chunkReader) Close() error {
+	r.cancel()
+
+	return nil
+}
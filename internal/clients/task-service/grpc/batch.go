@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) BatchCreateTasks(ctx context.Context, authorID uuid.UUID, tasks []*models.Task, atomic bool) ([]models.BatchResult, error) {
+	const op = "task.grpc.BatchCreateTasks"
+
+	protoTasks := make([]*taskv1.BatchTask, len(tasks))
+	for i, task := range tasks {
+		protoTasks[i] = &taskv1.BatchTask{
+			Title:       task.Title,
+			Description: task.Description,
+			Deadline:    deadlineToProto(task.Deadline),
+			DueDate:     task.DueDate,
+			Priority:    task.Priority,
+			Tags:        task.Tags,
+		}
+		if task.ProjectID.Valid {
+			protoTasks[i].ProjectId = task.ProjectID.UUID.String()
+		}
+	}
+
+	resp, err := c.api.BatchCreateTasks(ctx, &taskv1.BatchCreateTasksRequest{
+		AuthorId: authorID.String(),
+		Tasks:    protoTasks,
+		Atomic:   atomic,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return batchResultsFromProto(resp), nil
+}
+
+func (c *Client) BatchUpdateTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector, patch models.TaskPatch, atomic bool) ([]models.BatchResult, error) {
+	const op = "task.grpc.BatchUpdateTasks"
+
+	resp, err := c.api.BatchUpdateTasks(ctx, &taskv1.BatchUpdateTasksRequest{
+		AuthorId: authorID.String(),
+		Selector: selectorToProto(selector),
+		Patch: &taskv1.TaskPatch{
+			Status:     patch.Status,
+			Priority:   patch.Priority,
+			SetDue:     patch.SetDue,
+			Deadline:   deadlineToProto(patch.Deadline),
+			DueDate:    patch.DueDate,
+			AddTags:    patch.AddTags,
+			RemoveTags: patch.RemoveTags,
+		},
+		Atomic: atomic,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return batchResultsFromProto(resp), nil
+}
+
+func (c *Client) BatchDeleteTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector, atomic bool) ([]models.BatchResult, error) {
+	const op = "task.grpc.BatchDeleteTasks"
+
+	resp, err := c.api.BatchDeleteTasks(ctx, &taskv1.BatchDeleteTasksRequest{
+		AuthorId: authorID.String(),
+		Selector: selectorToProto(selector),
+		Atomic:   atomic,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return batchResultsFromProto(resp), nil
+}
+
+func selectorToProto(selector models.TaskSelector) *taskv1.TaskSelector {
+	taskIDs := make([]string, len(selector.IDs))
+	for i, id := range selector.IDs {
+		taskIDs[i] = id.String()
+	}
+
+	return &taskv1.TaskSelector{
+		TaskIds:  taskIDs,
+		Filter:   selector.Filter,
+		Timezone: selector.Timezone,
+	}
+}
+
+// batchResultsFromProto keeps the per-item failures as gRPC status errors, so callers can map them like any other call.
+func batchResultsFromProto(resp *taskv1.BatchResponse) []models.BatchResult {
+	results := make([]models.BatchResult, len(resp.Results))
+	for i, result := range resp.Results {
+		if result.TaskId != "" {
+			results[i].TaskID, _ = uuid.Parse(result.TaskId)
+		}
+		if result.Code != int32(codes.OK) {
+			results[i].Err = status.Error(codes.Code(result.Code), result.Error)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// ListCalendars returns the CalDAV collections of the author and the sync token of the account.
+func (c *Client) ListCalendars(ctx context.Context, authorID uuid.UUID) ([]models.CalendarCollection, int64, error) {
+	const op = "task.grpc.ListCalendars"
+
+	resp, err := c.api.ListCalendars(ctx, &taskv1.ListCalendarsRequest{AuthorId: authorID.String()})
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	calendars := make([]models.CalendarCollection, 0, len(resp.GetCalendars()))
+	for _, calendar := range resp.GetCalendars() {
+		collection := models.CalendarCollection{Name: calendar.GetName(), CTag: calendar.GetCtag()}
+		if calendar.ProjectId != "" {
+			collection.ProjectID = uuid.NullUUID{UUID: uuid.MustParse(calendar.ProjectId), Valid: true}
+		}
+		calendars = append(calendars, collection)
+	}
+
+	return calendars, resp.GetSyncToken(), nil
+}
+
+// GetCalendarObjects returns the objects of a collection with the given names, all of them when names is nil.
+func (c *Client) GetCalendarObjects(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, names []string) ([]*models.CalendarObject, error) {
+	const op = "task.grpc.GetCalendarObjects"
+
+	resp, err := c.api.GetCalendarObjects(ctx, &taskv1.GetCalendarObjectsRequest{
+		AuthorId:  authorID.String(),
+		ProjectId: projectIDString(projectID),
+		Names:     names,
+		All:       names == nil,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return calendarObjectsFromProto(resp.GetObjects()), nil
+}
+
+func (c *Client) SyncCalendarObjects(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, syncToken int64) (*models.CalendarChanges, error) {
+	const op = "task.grpc.SyncCalendarObjects"
+
+	resp, err := c.api.SyncCalendarObjects(ctx, &taskv1.SyncCalendarObjectsRequest{
+		AuthorId:  authorID.String(),
+		ProjectId: projectIDString(projectID),
+		SyncToken: syncToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.CalendarChanges{
+		Changed:   calendarObjectsFromProto(resp.GetChanged()),
+		Deleted:   resp.GetDeleted(),
+		SyncToken: resp.GetSyncToken(),
+	}, nil
+}
+
+// PutCalendarObject stores the calendar object data under name and reports whether it created a task.
+func (c *Client) PutCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name string, data []byte, ifMatch, ifNoneMatch, timezone string) (bool, error) {
+	const op = "task.grpc.PutCalendarObject"
+
+	resp, err := c.api.PutCalendarObject(ctx, &taskv1.PutCalendarObjectRequest{
+		AuthorId:    authorID.String(),
+		ProjectId:   projectIDString(projectID),
+		Name:        name,
+		Data:        data,
+		IfMatch:     ifMatch,
+		IfNoneMatch: ifNoneMatch,
+		Timezone:    timezone,
+	})
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp.GetCreated(), nil
+}
+
+func (c *Client) DeleteCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name, ifMatch string) error {
+	const op = "task.grpc.DeleteCalendarObject"
+
+	_, err := c.api.DeleteCalendarObject(ctx, &taskv1.DeleteCalendarObjectRequest{
+		AuthorId:  authorID.String(),
+		ProjectId: projectIDString(projectID),
+		Name:      name,
+		IfMatch:   ifMatch,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func calendarObjectsFromProto(protoObjects []*taskv1.CalendarObject) []*models.CalendarObject {
+	objects := make([]*models.CalendarObject, 0, len(protoObjects))
+	for _, object := range protoObjects {
+		objects = append(objects, &models.CalendarObject{Name: object.GetName(), Data: object.GetData(), ETag: object.GetEtag()})
+	}
+
+	return objects
+}
+
+// projectIDString is the project ID of a request, empty for the inbox.
+func projectIDString(projectID uuid.NullUUID) string {
+	if !projectID.Valid {
+		return ""
+	}
+
+	return projectID.UUID.String()
+}
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) RegenerateCalendarToken(ctx context.Context, authorID uuid.UUID, reminderMinutes int) (string, *models.CalendarFeed, error) {
+	const op = "task.grpc.RegenerateCalendarToken"
+
+	resp, err := c.api.RegenerateCalendarToken(ctx, &taskv1.RegenerateCalendarTokenRequest{
+		AuthorId:        authorID.String(),
+		ReminderMinutes: int32(reminderMinutes),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, resp.GetCreatedAt())
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: parse created at: %w", op, err)
+	}
+
+	return resp.GetToken(), &models.CalendarFeed{
+		AuthorID:        authorID,
+		ReminderMinutes: int(resp.GetReminderMinutes()),
+		CreatedAt:       createdAt,
+	}, nil
+}
+
+func (c *Client) RevokeCalendarToken(ctx context.Context, authorID uuid.UUID) error {
+	const op = "task.grpc.RevokeCalendarToken"
+
+	if _, err := c.api.RevokeCalendarToken(ctx, &taskv1.RevokeCalendarTokenRequest{AuthorId: authorID.String()}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetCalendarFeed returns the iCalendar data of the feed with the given token and its ETag.
+func (c *Client) GetCalendarFeed(ctx context.Context, token string) ([]byte, string, error) {
+	const op = "task.grpc.GetCalendarFeed"
+
+	resp, err := c.api.GetCalendarFeed(ctx, &taskv1.CalendarFeedRequest{Token: token})
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp.GetData(), resp.GetEtag(), nil
+}
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) AddChecklistItem(ctx context.Context, taskID, authorID uuid.UUID, text string, position *int) (*models.ChecklistItem, error) {
+	const op = "task.grpc.AddChecklistItem"
+
+	req := &taskv1.AddChecklistItemRequest{
+		TaskId:   taskID.String(),
+		AuthorId: authorID.String(),
+		Text:     text,
+	}
+	if position != nil {
+		p := int32(*position)
+		req.Position = &p
+	}
+
+	resp, err := c.api.AddChecklistItem(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checklistItemFromProto(resp), nil
+}
+
+func (c *Client) ToggleChecklistItem(ctx context.Context, itemID, authorID uuid.UUID) (*models.ChecklistItem, error) {
+	const op = "task.grpc.ToggleChecklistItem"
+
+	resp, err := c.api.ToggleChecklistItem(ctx, &taskv1.ChecklistItemRequest{
+		ItemId:   itemID.String(),
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checklistItemFromProto(resp), nil
+}
+
+func (c *Client) ReorderChecklistItem(ctx context.Context, itemID, authorID uuid.UUID, position int) ([]*models.ChecklistItem, *models.ChecklistSummary, error) {
+	const op = "task.grpc.ReorderChecklistItem"
+
+	resp, err := c.api.ReorderChecklistItem(ctx, &taskv1.ReorderChecklistItemRequest{
+		ItemId:   itemID.String(),
+		AuthorId: authorID.String(),
+		Position: int32(position),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checklistFromProto(resp.Items), checklistSummaryFromProto(resp.Summary), nil
+}
+
+func (c *Client) RemoveChecklistItem(ctx context.Context, itemID, authorID uuid.UUID) ([]*models.ChecklistItem, *models.ChecklistSummary, error) {
+	const op = "task.grpc.RemoveChecklistItem"
+
+	resp, err := c.api.RemoveChecklistItem(ctx, &taskv1.ChecklistItemRequest{
+		ItemId:   itemID.String(),
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return checklistFromProto(resp.Items), checklistSummaryFromProto(resp.Summary), nil
+}
+
+func checklistFromProto(items []*taskv1.ChecklistItem) []*models.ChecklistItem {
+	if len(items) == 0 {
+		return nil
+	}
+
+	checklist := make([]*models.ChecklistItem, len(items))
+	for i := range items {
+		checklist[i] = checklistItemFromProto(items[i])
+	}
+
+	return checklist
+}
+
+func checklistItemFromProto(item *taskv1.ChecklistItem) *models.ChecklistItem {
+	return &models.ChecklistItem{
+		ID:       uuid.MustParse(item.Id),
+		TaskID:   uuid.MustParse(item.TaskId),
+		Text:     item.Text,
+		Checked:  item.Checked,
+		Position: int(item.Position),
+	}
+}
+
+func checklistSummaryFromProto(summary *taskv1.ChecklistSummary) *models.ChecklistSummary {
+	if summary == nil {
+		return nil
+	}
+
+	return &models.ChecklistSummary{
+		Done:  int(summary.Done),
+		Total: int(summary.Total),
+		Text:  summary.Text,
+	}
+}
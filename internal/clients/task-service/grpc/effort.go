@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// SetTaskEstimate sets the estimate of a task in minutes, nil clears it.
+func (c *Client) SetTaskEstimate(ctx context.Context, taskID, authorID uuid.UUID, minutes *int) (*models.Task, error) {
+	const op = "task.grpc.SetTaskEstimate"
+
+	req := &taskv1.SetTaskEstimateRequest{
+		TaskId:   taskID.String(),
+		AuthorId: authorID.String(),
+	}
+	if minutes != nil {
+		estimate := int32(*minutes)
+		req.EstimateMinutes = &estimate
+	}
+
+	resp, err := c.api.SetTaskEstimate(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	task, err := taskFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return task, nil
+}
+
+func effortFromProto(effort *taskv1.Effort) models.Effort {
+	return models.Effort{
+		EstimateMinutes:  int(effort.EstimateMinutes),
+		ActualMinutes:    int(effort.ActualMinutes),
+		VarianceMinutes:  int(effort.VarianceMinutes),
+		RemainingMinutes: int(effort.RemainingMinutes),
+	}
+}
+
+func projectEffortFromProto(effort *taskv1.ProjectEffort) *models.ProjectEffort {
+	projectEffort := &models.ProjectEffort{
+		EstimatedTasks:   int(effort.EstimatedTasks),
+		UnestimatedTasks: int(effort.UnestimatedTasks),
+		ByStatus:         make([]models.StatusEffort, len(effort.ByStatus)),
+	}
+	if effort.Total != nil {
+		projectEffort.Effort = effortFromProto(effort.Total)
+	}
+
+	for idx, byStatus := range effort.ByStatus {
+		projectEffort.ByStatus[idx] = models.StatusEffort{
+			Status:           byStatus.Status,
+			Tasks:            int(byStatus.Tasks),
+			EstimatedTasks:   int(byStatus.EstimatedTasks),
+			EstimateMinutes:  int(byStatus.EstimateMinutes),
+			ActualMinutes:    int(byStatus.ActualMinutes),
+			RemainingMinutes: int(byStatus.RemainingMinutes),
+		}
+	}
+
+	return projectEffort
+}
@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+)
+
+// ExportTasks streams the author's tasks in format. The first chunk is read before returning,
+// so that a rejected export fails here rather than in the middle of the returned reader.
+func (c *Client) ExportTasks(ctx context.Context, authorID uuid.UUID, format, filter, timezone string) (io.ReadCloser, error) {
+	const op = "task.grpc.ExportTasks"
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := c.api.ExportTasks(ctx, &taskv1.ExportTasksRequest{
+		AuthorId: authorID.String(),
+		Format:   format,
+		Filter:   filter,
+		Timezone: timezone,
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	first, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		cancel()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recv := func() ([]byte, error) {
+		msg, err := stream.Recv()
+		return msg.GetData(), err
+	}
+	if errors.Is(err, io.EOF) {
+		recv = func() ([]byte, error) { return nil, io.EOF }
+	}
+
+	return &chunkReader{recv: recv, cancel: cancel, buf: first.GetData()}, nil
+}
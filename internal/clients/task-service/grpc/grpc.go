@@ -12,14 +12,18 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
 	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/idempotency"
 )
 
 type Client struct {
-	api taskv1.TodoClient
-	log *slog.Logger
+	api      taskv1.TodoClient
+	webhooks taskv1.WebhooksClient
+	accounts taskv1.AccountsClient
+	log      *slog.Logger
 }
 
 func New(addr string, log *slog.Logger, retries int, timeout time.Duration) (*Client, error) {
@@ -39,13 +43,15 @@ func New(addr string, log *slog.Logger, retries int, timeout time.Duration) (*Cl
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithChainUnaryInterceptor(
 			grpclog.UnaryClientInterceptor(InterceptorLogger(log), logOpts...),
+			idempotency.UnaryClientInterceptor(),
 			grpcretry.UnaryClientInterceptor(retryOpts...),
-		))
+		),
+		grpc.WithChainStreamInterceptor(idempotency.StreamClientInterceptor()))
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Client{api: taskv1.NewTodoClient(conn)}, nil
+	return &Client{api: taskv1.NewTodoClient(conn), webhooks: taskv1.NewWebhooksClient(conn), accounts: taskv1.NewAccountsClient(conn)}, nil
 }
 
 func InterceptorLogger(l *slog.Logger) grpclog.Logger {
@@ -54,15 +60,24 @@ func InterceptorLogger(l *slog.Logger) grpclog.Logger {
 	})
 }
 
-func (c *Client) CreateTask(ctx context.Context, authorID uuid.UUID, title, description, deadline string) (string, error) {
+// CreateTask creates a task in the project, uuid.Nil puts it in the inbox. An empty status starts it in to-do.
+// A zero deadline and an empty dueDate leave the task without a due date.
+func (c *Client) CreateTask(ctx context.Context, authorID, projectID uuid.UUID, title, description, status string, deadline time.Time, dueDate string) (string, error) {
 	const op = "task.grpc.CreateTask"
 
-	resp, err := c.api.CreateTask(ctx, &taskv1.NewTaskRequest{
+	req := &taskv1.NewTaskRequest{
 		AuthorId:    authorID.String(),
 		Title:       title,
 		Description: description,
-		Deadline:    deadline,
-	})
+		Status:      status,
+		Deadline:    deadlineToProto(deadline),
+		DueDate:     dueDate,
+	}
+	if projectID != uuid.Nil {
+		req.ProjectId = projectID.String()
+	}
+
+	resp, err := c.api.CreateTask(ctx, req)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -82,29 +97,16 @@ func (c *Client) GetTask(ctx context.Context, authorID uuid.UUID) ([]*models.Tas
 
 	tasks := make([]*models.Task, len(resp.Tasks))
 	for i := range resp.Tasks {
-		var deadline time.Time
-		if resp.Tasks[i].Deadline != "" {
-			deadline, err = time.Parse(time.RFC3339, resp.Tasks[i].Deadline)
-			if err != nil {
-				return nil, fmt.Errorf("%s: failed to parse deadline: %w", op, err)
-			}
+		tasks[i], err = taskFromProto(resp.Tasks[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-
-		task := &models.Task{
-			ID:          uuid.MustParse(resp.Tasks[i].Id),
-			AuthorID:    uuid.MustParse(resp.Tasks[i].AuthorId),
-			Title:       resp.Tasks[i].Title,
-			Description: resp.Tasks[i].Description,
-			Status:      resp.Tasks[i].Status,
-			Deadline:    deadline,
-		}
-		tasks[i] = task
 	}
 
 	return tasks, nil
 }
 
-func (c *Client) UpdateTask(ctx context.Context, taskID, authorID uuid.UUID, title, description, status, deadline string) error {
+func (c *Client) UpdateTask(ctx context.Context, taskID, authorID uuid.UUID, title, description, status string, deadline time.Time, dueDate string) error {
 	const op = "task.grpc.UpdateTask"
 
 	_, err := c.api.UpdateTask(ctx, &taskv1.UpdateRequest{
@@ -113,7 +115,8 @@ func (c *Client) UpdateTask(ctx context.Context, taskID, authorID uuid.UUID, tit
 		NewTitle:       title,
 		NewDescription: description,
 		NewStatus:      status,
-		NewDeadline:    deadline,
+		NewDeadline:    deadlineToProto(deadline),
+		NewDueDate:     dueDate,
 	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -122,6 +125,31 @@ func (c *Client) UpdateTask(ctx context.Context, taskID, authorID uuid.UUID, tit
 	return nil
 }
 
+// MoveTask places the task between two neighbours, uuid.Nil stands for the top or the bottom of the list.
+// A non-empty status moves the task to that board column.
+func (c *Client) MoveTask(ctx context.Context, taskID, authorID, beforeID, afterID uuid.UUID, status string) (string, error) {
+	const op = "task.grpc.MoveTask"
+
+	req := &taskv1.MoveTaskRequest{
+		TaskId:   taskID.String(),
+		AuthorId: authorID.String(),
+		Status:   status,
+	}
+	if beforeID != uuid.Nil {
+		req.BeforeId = beforeID.String()
+	}
+	if afterID != uuid.Nil {
+		req.AfterId = afterID.String()
+	}
+
+	resp, err := c.api.MoveTask(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp.Position, nil
+}
+
 func (c *Client) DeleteTask(ctx context.Context, taskID, authorID uuid.UUID) error {
 	const op = "task.grpc.DeleteTask"
 
@@ -135,3 +163,58 @@ func (c *Client) DeleteTask(ctx context.Context, taskID, authorID uuid.UUID) err
 
 	return nil
 }
+
+func taskFromProto(protoTask *taskv1.Task) (*models.Task, error) {
+	var deadline time.Time
+	if protoTask.Deadline != nil {
+		if err := protoTask.Deadline.CheckValid(); err != nil {
+			return nil, fmt.Errorf("invalid deadline: %w", err)
+		}
+		deadline = protoTask.Deadline.AsTime()
+	}
+
+	task := &models.Task{
+		ID:             uuid.MustParse(protoTask.Id),
+		AuthorID:       uuid.MustParse(protoTask.AuthorId),
+		Title:          protoTask.Title,
+		Description:    protoTask.Description,
+		Status:         protoTask.Status,
+		Deadline:       deadline,
+		DueDate:        protoTask.DueDate,
+		Priority:       protoTask.Priority,
+		Tags:           protoTask.Tags,
+		Recurrence:     protoTask.Recurrence,
+		Assignee:       protoTask.Assignee,
+		Position:       protoTask.Position,
+		TrackedSeconds: protoTask.TrackedSeconds,
+		Checklist:      checklistFromProto(protoTask.Checklist),
+	}
+	if protoTask.ProjectId != "" {
+		task.ProjectID = uuid.NullUUID{UUID: uuid.MustParse(protoTask.ProjectId), Valid: true}
+	}
+	if protoTask.ParentId != "" {
+		task.ParentID = uuid.NullUUID{UUID: uuid.MustParse(protoTask.ParentId), Valid: true}
+	}
+	if protoTask.EstimateMinutes != nil {
+		estimate := int(protoTask.GetEstimateMinutes())
+		task.EstimateMinutes = &estimate
+	}
+	if protoTask.Effort != nil {
+		effort := effortFromProto(protoTask.Effort)
+		task.Effort = &effort
+	}
+	if summary := protoTask.ChecklistSummary; summary != nil {
+		task.ChecklistSummary = checklistSummaryFromProto(summary)
+	}
+
+	return task, nil
+}
+
+// deadlineToProto leaves the timestamp unset for the zero time of a task without a deadline.
+func deadlineToProto(deadline time.Time) *timestamppb.Timestamp {
+	if deadline.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(deadline)
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) ImportTasks(ctx context.Context, authorID uuid.UUID, options models.ImportOptions, r io.Reader) (*models.ImportReport, error) {
+	const op = "task.grpc.ImportTasks"
+
+	stream, err := c.api.ImportTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	protoOptions := &taskv1.ImportOptions{
+		AuthorId:      authorID.String(),
+		Format:        options.Format,
+		ColumnMapping: options.ColumnMapping,
+		Timezone:      options.Timezone,
+		DryRun:        options.DryRun,
+	}
+	if options.ProjectID.Valid {
+		protoOptions.ProjectId = options.ProjectID.UUID.String()
+	}
+
+	err = stream.Send(&taskv1.ImportTasksRequest{Data: &taskv1.ImportTasksRequest_Options{Options: protoOptions}})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, closeAndRecvErr(stream, err))
+	}
+
+	buf := make([]byte, uploadChunkSize)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			err = stream.Send(&taskv1.ImportTasksRequest{Data: &taskv1.ImportTasksRequest_Chunk{Chunk: buf[:n]}})
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, closeAndRecvErr(stream, err))
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+
+		if readErr != nil {
+			return nil, fmt.Errorf("%s: read import: %w", op, readErr)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return importReportFromProto(resp), nil
+}
+
+func importReportFromProto(resp *taskv1.ImportReport) *models.ImportReport {
+	report := &models.ImportReport{
+		DryRun:     resp.GetDryRun(),
+		Rows:       int(resp.GetRows()),
+		Created:    int(resp.GetCreated()),
+		Duplicates: int(resp.GetDuplicates()),
+		Committed:  resp.GetCommitted(),
+	}
+
+	for _, rowErr := range resp.GetErrors() {
+		report.Errors = append(report.Errors, models.ImportError{
+			Row:        int(rowErr.GetRow()),
+			ExternalID: rowErr.GetExternalId(),
+			Message:    rowErr.GetMessage(),
+		})
+	}
+
+	return report
+}
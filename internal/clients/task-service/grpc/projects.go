@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) CreateProject(ctx context.Context, authorID uuid.UUID, name string) (*models.Project, error) {
+	const op = "task.grpc.CreateProject"
+
+	resp, err := c.api.CreateProject(ctx, &taskv1.CreateProjectRequest{
+		AuthorId: authorID.String(),
+		Name:     name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	project, err := projectFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return project, nil
+}
+
+func (c *Client) ListProjects(ctx context.Context, authorID uuid.UUID) ([]*models.Project, error) {
+	const op = "task.grpc.ListProjects"
+
+	resp, err := c.api.ListProjects(ctx, &taskv1.ListProjectsRequest{
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	projects := make([]*models.Project, len(resp.Projects))
+	for i := range resp.Projects {
+		projects[i], err = projectFromProto(resp.Projects[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return projects, nil
+}
+
+// GetBoard fetches the kanban board of a project, uuid.Nil selects the inbox.
+// A non-empty status restricts the board to that column and enables pageToken.
+func (c *Client) GetBoard(ctx context.Context, authorID, projectID uuid.UUID, status string, limit int, pageToken string) (*models.Board, error) {
+	const op = "task.grpc.GetBoard"
+
+	req := &taskv1.GetBoardRequest{
+		AuthorId:  authorID.String(),
+		Limit:     int32(limit),
+		Status:    status,
+		PageToken: pageToken,
+	}
+	if projectID != uuid.Nil {
+		req.ProjectId = projectID.String()
+	}
+
+	resp, err := c.api.GetBoard(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	board := &models.Board{Columns: make([]*models.BoardColumnPage, len(resp.Columns))}
+	if resp.ProjectId != "" {
+		board.ProjectID = uuid.NullUUID{UUID: uuid.MustParse(resp.ProjectId), Valid: true}
+	}
+
+	for idx, column := range resp.Columns {
+		tasks := make([]*models.Task, len(column.Tasks))
+		for i := range column.Tasks {
+			tasks[i], err = taskFromProto(column.Tasks[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		board.Columns[idx] = &models.BoardColumnPage{
+			Status:        column.Status,
+			Title:         column.Title,
+			WIPLimit:      int(column.WipLimit),
+			Count:         int(column.Count),
+			OverWIPLimit:  column.OverWipLimit,
+			Tasks:         tasks,
+			NextPageToken: column.NextPageToken,
+
+			EstimateMinutes:  int(column.EstimateMinutes),
+			RemainingMinutes: int(column.RemainingMinutes),
+		}
+	}
+
+	return board, nil
+}
+
+func projectFromProto(project *taskv1.Project) (*models.Project, error) {
+	createdAt, err := time.Parse(time.RFC3339, project.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created at: %w", err)
+	}
+
+	result := &models.Project{
+		ID:        uuid.MustParse(project.Id),
+		Name:      project.Name,
+		CreatedAt: createdAt,
+	}
+	if project.Effort != nil {
+		result.Effort = projectEffortFromProto(project.Effort)
+	}
+
+	return result, nil
+}
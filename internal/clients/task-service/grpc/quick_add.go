@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// QuickAdd creates a task from a line of text read in the given timezone, uuid.Nil puts it in the inbox.
+func (c *Client) QuickAdd(ctx context.Context, authorID, projectID uuid.UUID, text, timezone string) (*models.Task, *models.QuickAddParse, error) {
+	const op = "task.grpc.QuickAdd"
+
+	req := &taskv1.QuickAddRequest{
+		AuthorId: authorID.String(),
+		Text:     text,
+		Timezone: timezone,
+	}
+	if projectID != uuid.Nil {
+		req.ProjectId = projectID.String()
+	}
+
+	resp, err := c.api.QuickAdd(ctx, req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	task, err := taskFromProto(resp.Task)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return task, quickAddParseFromProto(resp.Parse), nil
+}
+
+func quickAddParseFromProto(protoParse *taskv1.QuickAddParse) *models.QuickAddParse {
+	parse := &models.QuickAddParse{
+		Title:      protoParse.Title,
+		DueDate:    protoParse.DueDate,
+		Recurrence: protoParse.Recurrence,
+		Tags:       protoParse.Tags,
+		Priority:   protoParse.Priority,
+		Assignee:   protoParse.Assignee,
+		Tokens:     make([]models.QuickAddToken, len(protoParse.Tokens)),
+	}
+
+	if protoParse.Deadline != nil {
+		parse.Deadline = protoParse.Deadline.AsTime()
+	}
+
+	for idx, token := range protoParse.Tokens {
+		parse.Tokens[idx] = models.QuickAddToken{
+			Kind:  token.Kind,
+			Text:  token.Text,
+			Value: token.Value,
+		}
+	}
+
+	return parse
+}
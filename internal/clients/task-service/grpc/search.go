@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) SearchTasks(ctx context.Context, authorID uuid.UUID, query string, limit, offset int) ([]*models.SearchHit, error) {
+	const op = "task.grpc.SearchTasks"
+
+	resp, err := c.api.SearchTasks(ctx, &taskv1.SearchTasksRequest{
+		AuthorId: authorID.String(),
+		Query:    query,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hits := make([]*models.SearchHit, len(resp.Hits))
+	for i, hit := range resp.Hits {
+		task, err := taskFromProto(hit.Task)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		hits[i] = &models.SearchHit{
+			Task:           task,
+			Rank:           hit.Rank,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.Snippet,
+		}
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) Sync(ctx context.Context, authorID uuid.UUID, syncToken int64, clientTime time.Time, changes []models.TaskChange) (*models.SyncResult, error) {
+	const op = "task.grpc.Sync"
+
+	protoChanges := make([]*taskv1.TaskChange, len(changes))
+	for i, change := range changes {
+		protoChanges[i] = &taskv1.TaskChange{
+			ChangeId:  change.ChangeID,
+			TaskId:    change.TaskID.String(),
+			Deleted:   change.Deleted,
+			Fields:    change.Fields,
+			ChangedAt: deadlineToProto(change.ChangedAt),
+		}
+
+		if task := change.Task; task != nil {
+			protoChanges[i].Task = &taskv1.Task{
+				Title:       task.Title,
+				Description: task.Description,
+				Status:      task.Status,
+				Deadline:    deadlineToProto(task.Deadline),
+				DueDate:     task.DueDate,
+				Priority:    task.Priority,
+				Tags:        task.Tags,
+				Recurrence:  task.Recurrence,
+			}
+			if task.ProjectID.Valid {
+				protoChanges[i].Task.ProjectId = task.ProjectID.UUID.String()
+			}
+		}
+	}
+
+	resp, err := c.api.Sync(ctx, &taskv1.SyncRequest{
+		AuthorId:   authorID.String(),
+		SyncToken:  syncToken,
+		ClientTime: deadlineToProto(clientTime),
+		Changes:    protoChanges,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := &models.SyncResult{
+		SyncToken:  resp.GetSyncToken(),
+		Full:       resp.GetFull(),
+		Changed:    make([]*models.Task, len(resp.GetChanged())),
+		Deleted:    make([]uuid.UUID, len(resp.GetDeleted())),
+		Results:    make([]models.TaskChangeResult, len(resp.GetResults())),
+		ServerTime: resp.GetServerTime().AsTime(),
+	}
+
+	for i, protoTask := range resp.GetChanged() {
+		if result.Changed[i], err = taskFromProto(protoTask); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for i, rawID := range resp.GetDeleted() {
+		if result.Deleted[i], err = uuid.Parse(rawID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// Like the items of a batch, rejected changes keep their gRPC status.
+	for i, protoResult := range resp.GetResults() {
+		result.Results[i] = models.TaskChangeResult{
+			ChangeID:  protoResult.GetChangeId(),
+			Status:    protoResult.GetStatus(),
+			Conflicts: protoResult.GetConflicts(),
+		}
+		result.Results[i].TaskID, _ = uuid.Parse(protoResult.GetTaskId())
+		if protoResult.GetCode() != int32(codes.OK) {
+			result.Results[i].Err = status.Error(codes.Code(protoResult.GetCode()), protoResult.GetError())
+		}
+	}
+
+	return result, nil
+}
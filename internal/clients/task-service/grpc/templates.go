@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// CreateTemplate saves a template from its JSON task tree, uuid.Nil makes it a personal template.
+func (c *Client) CreateTemplate(ctx context.Context, authorID, projectID uuid.UUID, name, tasks string) (*models.Template, error) {
+	const op = "task.grpc.CreateTemplate"
+
+	req := &taskv1.CreateTemplateRequest{
+		AuthorId: authorID.String(),
+		Name:     name,
+		Tasks:    tasks,
+	}
+	if projectID != uuid.Nil {
+		req.ProjectId = projectID.String()
+	}
+
+	resp, err := c.api.CreateTemplate(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	template, err := templateFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return template, nil
+}
+
+func (c *Client) ListTemplates(ctx context.Context, authorID uuid.UUID) ([]*models.Template, error) {
+	const op = "task.grpc.ListTemplates"
+
+	resp, err := c.api.ListTemplates(ctx, &taskv1.ListTemplatesRequest{
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	templates := make([]*models.Template, len(resp.Templates))
+	for i := range resp.Templates {
+		templates[i], err = templateFromProto(resp.Templates[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return templates, nil
+}
+
+func (c *Client) DeleteTemplate(ctx context.Context, templateID, authorID uuid.UUID) error {
+	const op = "task.grpc.DeleteTemplate"
+
+	_, err := c.api.DeleteTemplate(ctx, &taskv1.TemplateRequest{
+		TemplateId: templateID.String(),
+		AuthorId:   authorID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// InstantiateTemplate creates the tasks of a template, uuid.Nil keeps the project of the template.
+func (c *Client) InstantiateTemplate(ctx context.Context, templateID, authorID, projectID uuid.UUID, start, timezone string, variables map[string]string) ([]*models.Task, error) {
+	const op = "task.grpc.InstantiateTemplate"
+
+	req := &taskv1.InstantiateTemplateRequest{
+		TemplateId: templateID.String(),
+		AuthorId:   authorID.String(),
+		Start:      start,
+		Timezone:   timezone,
+		Variables:  variables,
+	}
+	if projectID != uuid.Nil {
+		req.ProjectId = projectID.String()
+	}
+
+	resp, err := c.api.InstantiateTemplate(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tasks := make([]*models.Task, len(resp.Tasks))
+	for i := range resp.Tasks {
+		tasks[i], err = taskFromProto(resp.Tasks[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return tasks, nil
+}
+
+func templateFromProto(template *taskv1.Template) (*models.Template, error) {
+	var tasks []models.TemplateTask
+	if err := json.Unmarshal([]byte(template.Tasks), &tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse template tasks: %w", err)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, template.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created at: %w", err)
+	}
+
+	result := &models.Template{
+		ID:        uuid.MustParse(template.Id),
+		Name:      template.Name,
+		Tasks:     tasks,
+		Variables: template.Variables,
+		CreatedAt: createdAt,
+	}
+	if template.ProjectId != "" {
+		result.ProjectID = uuid.NullUUID{UUID: uuid.MustParse(template.ProjectId), Valid: true}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) StartTimer(ctx context.Context, taskID, authorID uuid.UUID, note string) (*models.TimeEntry, error) {
+	const op = "task.grpc.StartTimer"
+
+	resp, err := c.api.StartTimer(ctx, &taskv1.StartTimerRequest{
+		TaskId:   taskID.String(),
+		AuthorId: authorID.String(),
+		Note:     note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entry, err := timeEntryFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entry, nil
+}
+
+func (c *Client) StopTimer(ctx context.Context, authorID uuid.UUID) (*models.TimeEntry, error) {
+	const op = "task.grpc.StopTimer"
+
+	resp, err := c.api.StopTimer(ctx, &taskv1.StopTimerRequest{
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entry, err := timeEntryFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entry, nil
+}
+
+// LogTime records finished work, a zero startedAt means the work just ended.
+func (c *Client) LogTime(ctx context.Context, taskID, authorID uuid.UUID, startedAt time.Time, duration time.Duration, note string) (*models.TimeEntry, error) {
+	const op = "task.grpc.LogTime"
+
+	req := &taskv1.LogTimeRequest{
+		TaskId:          taskID.String(),
+		AuthorId:        authorID.String(),
+		DurationSeconds: int64(duration / time.Second),
+		Note:            note,
+	}
+	if !startedAt.IsZero() {
+		req.StartedAt = startedAt.Format(time.RFC3339)
+	}
+
+	resp, err := c.api.LogTime(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entry, err := timeEntryFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entry, nil
+}
+
+func (c *Client) ListTimeEntries(ctx context.Context, taskID, authorID uuid.UUID) ([]*models.TimeEntry, error) {
+	const op = "task.grpc.ListTimeEntries"
+
+	resp, err := c.api.ListTimeEntries(ctx, &taskv1.ListTimeEntriesRequest{
+		TaskId:   taskID.String(),
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entries := make([]*models.TimeEntry, len(resp.Entries))
+	for i := range resp.Entries {
+		entries[i], err = timeEntryFromProto(resp.Entries[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return entries, nil
+}
+
+// GetTimeReport sums the user's time entries that started within [from, to), uuid.Nil reports on every project.
+func (c *Client) GetTimeReport(ctx context.Context, authorID, projectID uuid.UUID, from, to time.Time) (*models.TimeReport, error) {
+	const op = "task.grpc.GetTimeReport"
+
+	req := &taskv1.TimeReportRequest{
+		AuthorId: authorID.String(),
+		From:     from.Format(time.RFC3339),
+		To:       to.Format(time.RFC3339),
+	}
+	if projectID != uuid.Nil {
+		req.ProjectId = projectID.String()
+	}
+
+	resp, err := c.api.GetTimeReport(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := &models.TimeReport{
+		From:         from,
+		To:           to,
+		TotalSeconds: resp.TotalSeconds,
+		Projects:     make([]*models.ProjectTime, len(resp.Projects)),
+	}
+
+	for idx, project := range resp.Projects {
+		projectTime := &models.ProjectTime{
+			ProjectName: project.ProjectName,
+			Seconds:     project.Seconds,
+			Tasks:       make([]*models.TaskTime, len(project.Tasks)),
+		}
+		if project.ProjectId != "" {
+			projectTime.ProjectID = uuid.NullUUID{UUID: uuid.MustParse(project.ProjectId), Valid: true}
+		}
+
+		for i, task := range project.Tasks {
+			projectTime.Tasks[i] = &models.TaskTime{
+				TaskID:  uuid.MustParse(task.TaskId),
+				Title:   task.Title,
+				Seconds: task.Seconds,
+			}
+		}
+
+		report.Projects[idx] = projectTime
+	}
+
+	return report, nil
+}
+
+func timeEntryFromProto(entry *taskv1.TimeEntry) (*models.TimeEntry, error) {
+	startedAt, err := time.Parse(time.RFC3339, entry.StartedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse started at: %w", err)
+	}
+
+	var endedAt time.Time
+	if entry.EndedAt != "" {
+		endedAt, err = time.Parse(time.RFC3339, entry.EndedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ended at: %w", err)
+		}
+	}
+
+	return &models.TimeEntry{
+		ID:              uuid.MustParse(entry.Id),
+		TaskID:          uuid.MustParse(entry.TaskId),
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: entry.DurationSeconds,
+		Note:            entry.Note,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (c *Client) CreateView(ctx context.Context, authorID uuid.UUID, name, query string) (*models.View, error) {
+	const op = "task.grpc.CreateView"
+
+	resp, err := c.api.CreateView(ctx, &taskv1.CreateViewRequest{
+		AuthorId: authorID.String(),
+		Name:     name,
+		Query:    query,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	view, err := viewFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return view, nil
+}
+
+func (c *Client) ListViews(ctx context.Context, authorID uuid.UUID) ([]*models.View, error) {
+	const op = "task.grpc.ListViews"
+
+	resp, err := c.api.ListViews(ctx, &taskv1.ListViewsRequest{
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	views := make([]*models.View, len(resp.Views))
+	for i := range resp.Views {
+		views[i], err = viewFromProto(resp.Views[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return views, nil
+}
+
+func (c *Client) RunView(ctx context.Context, authorID uuid.UUID, viewID, timezone string) ([]*models.Task, error) {
+	const op = "task.grpc.RunView"
+
+	resp, err := c.api.RunView(ctx, &taskv1.RunViewRequest{
+		AuthorId: authorID.String(),
+		ViewId:   viewID,
+		Timezone: timezone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tasks := make([]*models.Task, len(resp.Tasks))
+	for i := range resp.Tasks {
+		tasks[i], err = taskFromProto(resp.Tasks[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return tasks, nil
+}
+
+func (c *Client) DeleteView(ctx context.Context, authorID uuid.UUID, viewID string) error {
+	const op = "task.grpc.DeleteView"
+
+	_, err := c.api.DeleteView(ctx, &taskv1.ViewRequest{
+		ViewId:   viewID,
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func viewFromProto(view *taskv1.View) (*models.View, error) {
+	var query models.ViewQuery
+	if err := json.Unmarshal([]byte(view.Query), &query); err != nil {
+		return nil, fmt.Errorf("failed to parse view query: %w", err)
+	}
+
+	return &models.View{
+		ID:      view.Id,
+		Name:    view.Name,
+		Query:   query,
+		Builtin: view.Builtin,
+	}, nil
+}
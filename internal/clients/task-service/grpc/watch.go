@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// WatchTasks passes handle the events of the author's tasks published after the event numbered after,
+// 0 for the events to come only, see the task service for resetOnExpiry. It returns when the stream
+// ends, ctx is done or handle fails.
+func (c *Client) WatchTasks(ctx context.Context, authorID uuid.UUID, after uint64, resetOnExpiry bool, handle func(models.TaskEvent) error) error {
+	const op = "task.grpc.WatchTasks"
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.api.WatchTasks(ctx, &taskv1.WatchTasksRequest{
+		AuthorId:      authorID.String(),
+		AfterSequence: after,
+		ResetOnExpiry: resetOnExpiry,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		event, err := taskEventFromProto(authorID, msg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := handle(event); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+}
+
+func taskEventFromProto(authorID uuid.UUID, protoEvent *taskv1.TaskEvent) (models.TaskEvent, error) {
+	event := models.TaskEvent{
+		Seq:        protoEvent.GetSequence(),
+		Type:       protoEvent.GetType(),
+		AuthorID:   authorID,
+		OccurredAt: protoEvent.GetOccurredAt().AsTime(),
+	}
+	if protoEvent.GetTaskId() != "" {
+		event.TaskID = uuid.MustParse(protoEvent.GetTaskId())
+	}
+
+	if protoEvent.Task != nil {
+		task, err := taskFromProto(protoEvent.Task)
+		if err != nil {
+			return models.TaskEvent{}, err
+		}
+		event.Task = task
+	}
+
+	return event, nil
+}
@@ -0,0 +1,223 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	taskv1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// CreateWebhook registers an endpoint for the given event types, every type when there are none.
+// uuid.Nil posts the events of every project. The returned webhook carries its secret.
+func (c *Client) CreateWebhook(ctx context.Context, authorID, projectID uuid.UUID, url string, eventTypes []string) (*models.Webhook, error) {
+	const op = "task.grpc.CreateWebhook"
+
+	req := &taskv1.CreateWebhookRequest{
+		AuthorId:   authorID.String(),
+		Url:        url,
+		EventTypes: eventTypes,
+	}
+	if projectID != uuid.Nil {
+		req.ProjectId = projectID.String()
+	}
+
+	resp, err := c.webhooks.CreateWebhook(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	webhook, err := webhookFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhook, nil
+}
+
+func (c *Client) ListWebhooks(ctx context.Context, authorID uuid.UUID) ([]*models.Webhook, error) {
+	const op = "task.grpc.ListWebhooks"
+
+	resp, err := c.webhooks.ListWebhooks(ctx, &taskv1.ListWebhooksRequest{
+		AuthorId: authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	webhooks := make([]*models.Webhook, len(resp.Webhooks))
+	for i := range resp.Webhooks {
+		webhooks[i], err = webhookFromProto(resp.Webhooks[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook replaces the endpoint and filters of the webhook, uuid.Nil posts the events of every project.
+func (c *Client) UpdateWebhook(ctx context.Context, webhookID, authorID, projectID uuid.UUID, url string, eventTypes []string, enabled bool) (*models.Webhook, error) {
+	const op = "task.grpc.UpdateWebhook"
+
+	req := &taskv1.UpdateWebhookRequest{
+		WebhookId:  webhookID.String(),
+		AuthorId:   authorID.String(),
+		Url:        url,
+		EventTypes: eventTypes,
+		Enabled:    enabled,
+	}
+	if projectID != uuid.Nil {
+		req.ProjectId = projectID.String()
+	}
+
+	resp, err := c.webhooks.UpdateWebhook(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	webhook, err := webhookFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhook, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID, authorID uuid.UUID) error {
+	const op = "task.grpc.DeleteWebhook"
+
+	_, err := c.webhooks.DeleteWebhook(ctx, &taskv1.WebhookRequest{
+		WebhookId: webhookID.String(),
+		AuthorId:  authorID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (c *Client) ListWebhookDeliveries(ctx context.Context, webhookID, authorID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	const op = "task.grpc.ListWebhookDeliveries"
+
+	resp, err := c.webhooks.ListWebhookDeliveries(ctx, &taskv1.ListWebhookDeliveriesRequest{
+		WebhookId: webhookID.String(),
+		AuthorId:  authorID.String(),
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deliveries := make([]*models.WebhookDelivery, len(resp.Deliveries))
+	for i := range resp.Deliveries {
+		deliveries[i], err = webhookDeliveryFromProto(resp.Deliveries[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (c *Client) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID, authorID uuid.UUID) (*models.WebhookDelivery, error) {
+	const op = "task.grpc.RedeliverWebhookDelivery"
+
+	resp, err := c.webhooks.RedeliverWebhookDelivery(ctx, &taskv1.WebhookDeliveryRequest{
+		WebhookId:  webhookID.String(),
+		DeliveryId: deliveryID.String(),
+		AuthorId:   authorID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	delivery, err := webhookDeliveryFromProto(resp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return delivery, nil
+}
+
+func webhookFromProto(webhook *taskv1.Webhook) (*models.Webhook, error) {
+	id, err := uuid.Parse(webhook.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook ID: %w", err)
+	}
+
+	result := &models.Webhook{
+		ID:             id,
+		URL:            webhook.GetUrl(),
+		Secret:         webhook.GetSecret(),
+		EventTypes:     webhook.GetEventTypes(),
+		Enabled:        webhook.GetEnabled(),
+		Failures:       int(webhook.GetFailures()),
+		DisabledReason: webhook.GetDisabledReason(),
+		CreatedAt:      webhook.GetCreatedAt().AsTime(),
+	}
+
+	if result.EventTypes == nil {
+		result.EventTypes = []string{}
+	}
+
+	if webhook.GetProjectId() != "" {
+		projectID, err := uuid.Parse(webhook.GetProjectId())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse project ID: %w", err)
+		}
+		result.ProjectID = uuid.NullUUID{UUID: projectID, Valid: true}
+	}
+
+	return result, nil
+}
+
+func webhookDeliveryFromProto(delivery *taskv1.WebhookDelivery) (*models.WebhookDelivery, error) {
+	id, err := uuid.Parse(delivery.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse delivery ID: %w", err)
+	}
+
+	webhookID, err := uuid.Parse(delivery.GetWebhookId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook ID: %w", err)
+	}
+
+	result := &models.WebhookDelivery{
+		ID:             id,
+		WebhookID:      webhookID,
+		EventType:      delivery.GetEventType(),
+		Payload:        delivery.GetPayload(),
+		Status:         delivery.GetStatus(),
+		Attempts:       int(delivery.GetAttempts()),
+		NextAttemptAt:  optionalTimeFromProto(delivery.GetNextAttemptAt()),
+		LastAttemptAt:  optionalTimeFromProto(delivery.GetLastAttemptAt()),
+		ResponseStatus: int(delivery.GetResponseStatus()),
+		Error:          delivery.GetError(),
+		CreatedAt:      delivery.GetCreatedAt().AsTime(),
+	}
+
+	if delivery.GetRedeliveryOf() != "" {
+		redeliveryOf, err := uuid.Parse(delivery.GetRedeliveryOf())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse redelivered delivery ID: %w", err)
+		}
+		result.RedeliveryOf = uuid.NullUUID{UUID: redeliveryOf, Valid: true}
+	}
+
+	return result, nil
+}
+
+// optionalTimeFromProto reads an unset timestamp as the zero time.
+func optionalTimeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return ts.AsTime()
+}
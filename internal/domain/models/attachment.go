@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Attachment struct {
+	ID          uuid.UUID `json:"id"`
+	TaskID      uuid.UUID `json:"task-id"`
+	AuthorID    uuid.UUID `json:"-"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content-type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created-at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskSelector picks the tasks of a batch update or delete, either by ID or with a filter.
+type TaskSelector struct {
+	IDs []uuid.UUID
+	// Filter is a JSON view query, see ViewQuery, evaluated in Timezone.
+	Filter   string
+	Timezone string
+}
+
+// TaskPatch is a partial update applied to every task of a batch. Nil fields are left alone.
+type TaskPatch struct {
+	Status   *string
+	Priority *string
+	// SetDue replaces both the deadline and the due date, zero values clear them.
+	SetDue     bool
+	Deadline   time.Time
+	DueDate    string
+	AddTags    []string
+	RemoveTags []string
+}
+
+// BatchResult is the outcome of one item of a batch, results are in request order.
+type BatchResult struct {
+	// TaskID is uuid.Nil for tasks of a create batch that failed.
+	TaskID uuid.UUID
+	// Err is nil for items that were applied.
+	Err error
+}
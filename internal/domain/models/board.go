@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// BoardColumn configures one kanban column. A zero WIPLimit means no limit.
+type BoardColumn struct {
+	Status   string
+	Title    string
+	WIPLimit int
+}
+
+type Board struct {
+	// ProjectID is invalid for the inbox board of tasks without a project.
+	ProjectID uuid.NullUUID      `json:"project-id"`
+	Columns   []*BoardColumnPage `json:"columns"`
+}
+
+// BoardColumnPage is one page of a column's tasks, in position order.
+type BoardColumnPage struct {
+	Status       string `json:"status"`
+	Title        string `json:"title"`
+	WIPLimit     int    `json:"wip-limit,omitempty"`
+	Count        int    `json:"count"`
+	OverWIPLimit bool   `json:"over-wip-limit"`
+	// EstimateMinutes and RemainingMinutes sum the whole column, not just this page.
+	EstimateMinutes  int     `json:"estimate-minutes"`
+	RemainingMinutes int     `json:"remaining-minutes"`
+	Tasks            []*Task `json:"tasks"`
+	NextPageToken    string  `json:"next-page-token,omitempty"`
+}
+
+// BoardCursor points right after the last task of a column page.
+type BoardCursor struct {
+	Position string
+	TaskID   uuid.UUID
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// CalendarCollection is a CalDAV calendar of to-dos: a project, or the inbox when ProjectID is not valid.
+type CalendarCollection struct {
+	ProjectID uuid.NullUUID
+	Name      string
+	// CTag is the sequence number of the last change to the collection, 0 when it never changed.
+	CTag int64
+}
+
+// CalendarObject is a task as a CalDAV resource.
+type CalendarObject struct {
+	Task *Task
+	// Name is the last segment of the resource URL, UID the UID of its VTODO.
+	Name string
+	UID  string
+	// ModifiedAt is the Unix time of the last change to the task.
+	ModifiedAt int64
+	// ChangeSeq is the sequence number of the last change to the task, an update of the task
+	// expects it to still be the last one.
+	ChangeSeq int64
+	// Data is the rendered VCALENDAR and ETag a strong entity tag of it.
+	Data []byte
+	ETag string
+}
+
+// CalendarChange is the last change to a task in a collection since a sync token.
+type CalendarChange struct {
+	TaskID  uuid.UUID
+	Deleted bool
+	// Name is only set for deleted tasks, the name the task had when it left the collection.
+	Name string
+}
+
+// CalendarChanges is the difference between a collection and what a client saw at an earlier sync token.
+type CalendarChanges struct {
+	Changed   []*CalendarObject
+	Deleted   []string
+	SyncToken int64
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed is the iCalendar subscription of a user, reached through a secret token.
+type CalendarFeed struct {
+	AuthorID uuid.UUID `json:"-"`
+	// TokenHash is the hex SHA-256 of the token, the token itself is only shown when it is made.
+	TokenHash string `json:"-"`
+	// ReminderMinutes is how long before the due time events alert, 0 for no alerts.
+	ReminderMinutes int       `json:"reminder-minutes"`
+	CreatedAt       time.Time `json:"created-at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type ChecklistItem struct {
+	ID       uuid.UUID `json:"id"`
+	TaskID   uuid.UUID `json:"-"`
+	Text     string    `json:"text"`
+	Checked  bool      `json:"checked"`
+	Position int       `json:"position"`
+}
+
+// ChecklistSummary is the "3/7 done" progress shown next to a task.
+type ChecklistSummary struct {
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+	Text  string `json:"text"`
+}
+
+func SummarizeChecklist(items []*ChecklistItem) ChecklistSummary {
+	summary := ChecklistSummary{Total: len(items)}
+	for _, item := range items {
+		if item.Checked {
+			summary.Done++
+		}
+	}
+
+	if summary.Total > 0 {
+		summary.Text = fmt.Sprintf("%d/%d done", summary.Done, summary.Total)
+	}
+
+	return summary
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Effort compares the estimate of a task with the time tracked on it.
+type Effort struct {
+	EstimateMinutes int `json:"estimate-minutes"`
+	ActualMinutes   int `json:"actual-minutes"`
+	// VarianceMinutes is actual minus estimate, positive when over the estimate.
+	VarianceMinutes int `json:"variance-minutes"`
+	// RemainingMinutes is what is left of the estimate, zero once the task is done.
+	RemainingMinutes int `json:"remaining-minutes"`
+}
+
+// ProjectEffort rolls the effort of every task of a project up. The actual time includes
+// tasks without an estimate, UnestimatedTasks tells how much of the project that is.
+type ProjectEffort struct {
+	Effort
+	EstimatedTasks   int            `json:"estimated-tasks"`
+	UnestimatedTasks int            `json:"unestimated-tasks"`
+	ByStatus         []StatusEffort `json:"by-status"`
+}
+
+// StatusEffort sums the effort of the tasks of one project and status.
+type StatusEffort struct {
+	// ProjectID is invalid for inbox tasks.
+	ProjectID        uuid.NullUUID `json:"-"`
+	Status           string        `json:"status"`
+	Tasks            int           `json:"tasks"`
+	EstimatedTasks   int           `json:"estimated-tasks"`
+	EstimateMinutes  int           `json:"estimate-minutes"`
+	ActualMinutes    int           `json:"actual-minutes"`
+	RemainingMinutes int           `json:"remaining-minutes"`
+}
+
+// SummarizeEffort returns the effort of a task, nil when it has no estimate.
+func SummarizeEffort(task *Task) *Effort {
+	if task.EstimateMinutes == nil {
+		return nil
+	}
+
+	effort := &Effort{
+		EstimateMinutes: *task.EstimateMinutes,
+		ActualMinutes:   int(task.TrackedSeconds / 60),
+	}
+	effort.VarianceMinutes = effort.ActualMinutes - effort.EstimateMinutes
+
+	if task.Status != StatusDone {
+		effort.RemainingMinutes = max(effort.EstimateMinutes-effort.ActualMinutes, 0)
+	}
+
+	return effort
+}
+
+// RollUpEffort sums the per-status effort of one project.
+func RollUpEffort(byStatus []StatusEffort) *ProjectEffort {
+	effort := &ProjectEffort{ByStatus: byStatus}
+	for _, status := range byStatus {
+		effort.EstimateMinutes += status.EstimateMinutes
+		effort.ActualMinutes += status.ActualMinutes
+		effort.RemainingMinutes += status.RemainingMinutes
+		effort.EstimatedTasks += status.EstimatedTasks
+		effort.UnestimatedTasks += status.Tasks - status.EstimatedTasks
+	}
+	effort.VarianceMinutes = effort.ActualMinutes - effort.EstimateMinutes
+
+	return effort
+}
@@ -0,0 +1,13 @@
+package models
+
+// Formats of a task export.
+const (
+	// ExportJSONL is one JSON task per line.
+	ExportJSONL = "jsonl"
+	// ExportCSV is one row per task after a header row.
+	ExportCSV = "csv"
+	// ExportMarkdown is a Markdown checklist, with the checklist of every task nested under it.
+	ExportMarkdown = "markdown"
+	// ExportICS is an iCalendar file with a VTODO per task and a VEVENT per task that falls due.
+	ExportICS = "ics"
+)
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is the outcome of a request sent with an idempotency key.
+type IdempotencyRecord struct {
+	// Scope is the author of the request, empty for requests without one.
+	Scope       string
+	Key         string
+	Method      string
+	Fingerprint string
+	// Completed is false while the first request with the key is still running.
+	Completed bool
+	// Response is the marshaled anypb.Any of the response, nil when the request failed.
+	Response []byte
+	// Code and Message are the gRPC status of a failed request.
+	Code      uint32
+	Message   string
+	ExpiresAt time.Time
+}
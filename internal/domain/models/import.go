@@ -0,0 +1,61 @@
+package models
+
+import "github.com/google/uuid"
+
+// Formats of a task import. They are also the sources external IDs are kept apart by.
+const (
+	// ImportCSV is a CSV file with a header row, see ImportOptions.ColumnMapping.
+	ImportCSV = "csv"
+	// ImportTodoTxt is a todo.txt file, one task per line.
+	ImportTodoTxt = "todotxt"
+	// ImportTodoist is the JSON of the Todoist tasks API, or a Todoist backup with an "items" list.
+	ImportTodoist = "todoist"
+	// ImportTrello is the JSON export of a Trello board.
+	ImportTrello = "trello"
+)
+
+type ImportOptions struct {
+	Format string
+	// ColumnMapping maps task fields (external_id, title, description, status, priority, deadline,
+	// due_date, tags, recurrence, parent_id) to the CSV header naming their column. Unmapped fields
+	// are read from the column named like the field, "id" for external_id, which reads exports back in.
+	ColumnMapping map[string]string
+	// ProjectID is where the tasks go, the inbox when invalid.
+	ProjectID uuid.NullUUID
+	// Timezone is used for deadlines without an offset.
+	Timezone string
+	// DryRun validates the file and reports what an import would do without writing anything.
+	DryRun bool
+}
+
+// ImportRow is a task read from an import file, before it is validated.
+type ImportRow struct {
+	// Row is the line of the task for CSV and todo.txt files, its item number from 1 for JSON.
+	Row int
+	// ExternalID identifies the task in the source, importing it again skips it. Rows without one
+	// are created by every import.
+	ExternalID string
+	// ParentExternalID makes the task a subtask of another task of the source.
+	ParentExternalID string
+	Task             Task
+	// Err is set when the row could not be read at all.
+	Err error
+}
+
+type ImportReport struct {
+	DryRun bool `json:"dry-run"`
+	Rows   int  `json:"rows"`
+	// Created counts the tasks created, or that would be created by a dry run.
+	Created int `json:"created"`
+	// Duplicates counts the rows skipped because an earlier import already created them.
+	Duplicates int `json:"duplicates"`
+	// Committed is false when errors kept the import from writing anything.
+	Committed bool          `json:"committed"`
+	Errors    []ImportError `json:"errors,omitempty"`
+}
+
+type ImportError struct {
+	Row        int    `json:"row"`
+	ExternalID string `json:"external-id,omitempty"`
+	Message    string `json:"message"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AggregateTask = "task"
+	AggregateUser = "user"
+)
+
+const (
+	EventTaskCreated  = "task.created"
+	EventTaskUpdated  = "task.updated"
+	EventTaskDeleted  = "task.deleted"
+	EventTaskTagged   = "task.tagged"
+	EventTaskUntagged = "task.untagged"
+	EventUserCreated  = "user.created"
+	EventUserUpdated  = "user.updated"
+	EventUserDeleted  = "user.deleted"
+)
+
+// OutboxEvent is a domain event recorded in the transaction of the change that raised it.
+// Events of one aggregate are published in the order of their sequence numbers.
+type OutboxEvent struct {
+	Seq           int64     `json:"seq"`
+	AggregateType string    `json:"aggregate-type"`
+	AggregateID   uuid.UUID `json:"aggregate-id"`
+	// Type is the aggregate type and what happened to it, such as task.created or user.deleted.
+	Type string `json:"type"`
+	// Payload is the JSON state of the aggregate after the change, its ID alone once it is deleted.
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred-at"`
+}
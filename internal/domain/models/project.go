@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Project struct {
+	ID        uuid.UUID `json:"id"`
+	AuthorID  uuid.UUID `json:"-"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created-at"`
+
+	Effort *ProjectEffort `json:"effort,omitempty"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	QuickAddTag        = "tag"
+	QuickAddPriority   = "priority"
+	QuickAddAssignee   = "assignee"
+	QuickAddDate       = "date"
+	QuickAddTime       = "time"
+	QuickAddRecurrence = "recurrence"
+)
+
+// QuickAddParse is what the quick-add parser understood from a line of text.
+type QuickAddParse struct {
+	Title string `json:"title"`
+	// Deadline is set when a time of day was given, DueDate when only a date was.
+	Deadline   time.Time       `json:"deadline,omitzero"`
+	DueDate    string          `json:"due-date,omitempty"`
+	Recurrence string          `json:"recurrence,omitempty"`
+	Tags       []string        `json:"tags,omitempty"`
+	Priority   string          `json:"priority"`
+	Assignee   string          `json:"assignee,omitempty"`
+	Tokens     []QuickAddToken `json:"tokens"`
+}
+
+// QuickAddToken is one recognised part of the input, in input order. The words that
+// are not part of any token make up the title.
+type QuickAddToken struct {
+	// Kind is one of the QuickAdd* constants.
+	Kind string `json:"kind"`
+	// Text is the input as typed.
+	Text string `json:"text"`
+	// Value is the normalised meaning: a tag name, a priority, a YYYY-MM-DD date, an HH:MM time or an RRULE.
+	Value string `json:"value"`
+}
@@ -0,0 +1,11 @@
+package models
+
+type SearchHit struct {
+	Task *Task `json:"task"`
+	// Rank is the score of the match, lower is better: bm25 in SQLite, the negated ts_rank in Postgres,
+	// the negated weighted count of matching words in memory.
+	Rank float64 `json:"rank"`
+	// TitleHighlight and Snippet are HTML: the text is escaped and the matches are in <mark> elements.
+	TitleHighlight string `json:"title-highlight"`
+	Snippet        string `json:"snippet,omitempty"`
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -11,6 +12,18 @@ import (
 type Session struct {
 	UserID uuid.UUID
 	Email  string
+	// Timezone is the IANA timezone of the user's profile, empty in tokens issued before it existed.
+	Timezone string
+}
+
+// Location returns the user's timezone, UTC when it is unset or unknown.
+func (s *Session) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
 }
 
 type sessKey string
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fields of a task that offline changes set and that merge one by one.
+const (
+	SyncFieldTitle       = "title"
+	SyncFieldDescription = "description"
+	SyncFieldStatus      = "status"
+	// SyncFieldDue covers both the deadline and the due date, a task has only one of them.
+	SyncFieldDue        = "due"
+	SyncFieldPriority   = "priority"
+	SyncFieldTags       = "tags"
+	SyncFieldRecurrence = "recurrence"
+	SyncFieldProject    = "project_id"
+)
+
+const (
+	SyncAccepted = "accepted"
+	// SyncConflict reports a change that later writes on the server won, in part or in whole.
+	SyncConflict = "conflict"
+	SyncRejected = "rejected"
+)
+
+// TaskChange is a change a client made to one task while offline.
+type TaskChange struct {
+	// ChangeID is chosen by the client to match the change with its result.
+	ChangeID string
+	// TaskID is chosen by the client for the tasks it creates.
+	TaskID  uuid.UUID
+	Deleted bool
+	// Fields lists the fields of Task the change sets, see SyncFieldTitle.
+	Fields []string
+	Task   *Task
+	// ChangedAt is when the change was made, on the clock of the client.
+	ChangedAt time.Time
+}
+
+// TaskChangeResult is the outcome of a TaskChange, results are in request order.
+type TaskChangeResult struct {
+	ChangeID string
+	TaskID   uuid.UUID
+	// Status is SyncAccepted, SyncConflict or SyncRejected.
+	Status string
+	// Conflicts lists the fields of a conflicting change the server kept its own value of.
+	Conflicts []string
+	// Err tells why a change was rejected.
+	Err error
+}
+
+// SyncResult is what a client gets back from a sync.
+type SyncResult struct {
+	// SyncToken is what the client sends with its next sync.
+	SyncToken int64
+	// Full is set when the client has to replace its tasks with Changed: its sync token was
+	// older than the deletions the server still remembers, or it had none.
+	Full bool
+	// Changed holds the current state of the tasks changed since the sync token, the ones the
+	// client changed included.
+	Changed []*Task
+	// Deleted holds the IDs of the tasks deleted since the sync token.
+	Deleted    []uuid.UUID
+	Results    []TaskChangeResult
+	ServerTime time.Time
+}
@@ -6,11 +6,61 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	StatusToDo       = "to-do"
+	StatusInProgress = "in-progress"
+	StatusDone       = "done"
+)
+
+const (
+	PriorityNone   = "none"
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+)
+
 type Task struct {
-	ID          uuid.UUID `json:"id"`
-	AuthorID    uuid.UUID `json:"author-id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description,omitempty"`
-	Status      string    `json:"status"`
-	Deadline    time.Time `json:"deadline,omitempty"`
+	ID       uuid.UUID `json:"id"`
+	AuthorID uuid.UUID `json:"author-id"`
+	// ProjectID is invalid for tasks in the inbox.
+	ProjectID uuid.NullUUID `json:"project-id"`
+	// ParentID is set on subtasks.
+	ParentID    uuid.NullUUID `json:"parent-id"`
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Status      string        `json:"status"`
+	// Deadline is a point in time, kept in UTC. A task has either a Deadline or a DueDate.
+	Deadline time.Time `json:"deadline,omitzero"`
+	// DueDate is an all-day due date (YYYY-MM-DD) with no time of day, so it is the same
+	// calendar day in every timezone.
+	DueDate  string   `json:"due-date,omitempty"`
+	Priority string   `json:"priority"`
+	Tags     []string `json:"tags,omitempty"`
+	// Recurrence is an RFC 5545 RRULE value such as "FREQ=WEEKLY;BYDAY=MO", empty for one-off tasks.
+	Recurrence string `json:"recurrence,omitempty"`
+	// Assignee is the handle the task was assigned to, empty when unassigned.
+	Assignee string `json:"assignee,omitempty"`
+	// Position is a fractional rank key ordering tasks of the same author and status.
+	Position string `json:"position,omitempty"`
+	// TrackedSeconds is the total duration of the task's finished time entries.
+	TrackedSeconds int64 `json:"tracked-seconds"`
+	// EstimateMinutes is nil for tasks that were not estimated.
+	EstimateMinutes *int    `json:"estimate-minutes,omitempty"`
+	Effort          *Effort `json:"effort,omitempty"`
+
+	Checklist        []*ChecklistItem  `json:"checklist,omitempty"`
+	ChecklistSummary *ChecklistSummary `json:"checklist-summary,omitempty"`
+}
+
+// TaskRank is the position of a task within its author's status group.
+type TaskRank struct {
+	ID       uuid.UUID
+	Position string
+}
+
+// RankGroup identifies the tasks that share one ordering.
+type RankGroup struct {
+	AuthorID  uuid.UUID
+	ProjectID uuid.NullUUID
+	Status    string
 }
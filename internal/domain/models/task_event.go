@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TaskEventCreated = "created"
+	TaskEventUpdated = "updated"
+	TaskEventDeleted = "deleted"
+	// TaskEventReset tells a watcher that events it asked for are no longer kept,
+	// it has to reload its tasks. Reset events have no task.
+	TaskEventReset = "reset"
+)
+
+// TaskEvent tells the watchers of an author that one of their tasks changed.
+type TaskEvent struct {
+	// Seq numbers the events in publishing order, watchers resume after the last one they saw.
+	Seq      uint64
+	Type     string
+	AuthorID uuid.UUID
+	TaskID   uuid.UUID
+	// Task is the task after the change, nil when it was deleted.
+	Task       *Task
+	OccurredAt time.Time
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Template is a reusable tree of tasks, such as an onboarding checklist or a release procedure.
+type Template struct {
+	ID       uuid.UUID `json:"id"`
+	AuthorID uuid.UUID `json:"-"`
+	// ProjectID is the project the template creates its tasks in, invalid for personal templates.
+	ProjectID uuid.NullUUID  `json:"project-id"`
+	Name      string         `json:"name"`
+	Tasks     []TemplateTask `json:"tasks"`
+	// Variables are the {{name}} placeholders used by the tasks, in order of first use.
+	Variables []string  `json:"variables"`
+	CreatedAt time.Time `json:"created-at"`
+}
+
+// TemplateTask is one task of a template. Title, description and checklist items may use
+// {{name}} placeholders, which are filled in when the template is instantiated.
+type TemplateTask struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Due is an offset from the start date such as "+3d", "+2w" or "-1d", empty for no due date.
+	Due string `json:"due,omitempty"`
+	// At is an HH:MM time of day that turns the due date into a deadline in the user's timezone.
+	At        string         `json:"at,omitempty"`
+	Priority  string         `json:"priority,omitempty"`
+	Tags      []string       `json:"tags,omitempty"`
+	Checklist []string       `json:"checklist,omitempty"`
+	Subtasks  []TemplateTask `json:"subtasks,omitempty"`
+}
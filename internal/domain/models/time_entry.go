@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimeEntry struct {
+	ID        uuid.UUID `json:"id"`
+	TaskID    uuid.UUID `json:"task-id"`
+	AuthorID  uuid.UUID `json:"-"`
+	StartedAt time.Time `json:"started-at"`
+	// EndedAt is zero while the timer is running.
+	EndedAt         time.Time `json:"ended-at,omitempty"`
+	DurationSeconds int64     `json:"duration-seconds"`
+	Note            string    `json:"note,omitempty"`
+}
+
+func (e *TimeEntry) Running() bool {
+	return e.EndedAt.IsZero()
+}
+
+// TimeReport sums the finished time entries of one user that started within [From, To).
+type TimeReport struct {
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	TotalSeconds int64          `json:"total-seconds"`
+	Projects     []*ProjectTime `json:"projects"`
+}
+
+type ProjectTime struct {
+	// ProjectID is invalid for inbox tasks.
+	ProjectID   uuid.NullUUID `json:"project-id"`
+	ProjectName string        `json:"project-name,omitempty"`
+	Seconds     int64         `json:"seconds"`
+	Tasks       []*TaskTime   `json:"tasks"`
+}
+
+type TaskTime struct {
+	TaskID  uuid.UUID `json:"task-id"`
+	Title   string    `json:"title"`
+	Seconds int64     `json:"seconds"`
+}
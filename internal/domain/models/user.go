@@ -8,4 +8,6 @@ type User struct {
 	ID           uuid.UUID
 	Email        string
 	PasswordHash string
+	// Timezone is the IANA name of the user's timezone.
+	Timezone string
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// View is a named, saved task query. Built-in smart lists are views too, but they are not stored.
+type View struct {
+	ID       string    `json:"id"`
+	AuthorID uuid.UUID `json:"-"`
+	Name     string    `json:"name"`
+	Query    ViewQuery `json:"query"`
+	Builtin  bool      `json:"builtin"`
+}
+
+const (
+	DueOverdue  = "overdue"
+	DueToday    = "today"
+	DueUpcoming = "upcoming"
+	DueNone     = "none"
+	DueAny      = "any"
+)
+
+// ViewQuery is the JSON filter and sort spec of a view.
+type ViewQuery struct {
+	Status []string `json:"status,omitempty"`
+	// Due is one of the Due* constants, evaluated in the timezone of the user running the view.
+	Due string `json:"due,omitempty"`
+	// DueWithinDays bounds the "upcoming" window, today included.
+	DueWithinDays int        `json:"due_within_days,omitempty"`
+	TitleContains string     `json:"title_contains,omitempty"`
+	Sort          []ViewSort `json:"sort,omitempty"`
+	Limit         int        `json:"limit,omitempty"`
+}
+
+const (
+	SortByDeadline = "deadline"
+	SortByTitle    = "title"
+	SortByStatus   = "status"
+)
+
+type ViewSort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook is an endpoint the events of a user's tasks are posted to, signed with its secret.
+type Webhook struct {
+	ID       uuid.UUID `json:"id"`
+	AuthorID uuid.UUID `json:"-"`
+	// ProjectID limits the events to the tasks of the project, invalid for every task.
+	ProjectID uuid.NullUUID `json:"project-id"`
+	URL       string        `json:"url"`
+	// Secret is the HMAC key of the signatures, only shown when the webhook is created.
+	Secret string `json:"-"`
+	// EventTypes are the task event types posted, every type when empty.
+	EventTypes []string `json:"event-types"`
+	Enabled    bool     `json:"enabled"`
+	// Failures counts the deliveries in a row that failed every attempt.
+	Failures       int       `json:"failures"`
+	DisabledReason string    `json:"disabled-reason,omitempty"`
+	CreatedAt      time.Time `json:"created-at"`
+}
+
+// WebhookDelivery is one task event queued for, or posted to, a webhook.
+type WebhookDelivery struct {
+	ID        uuid.UUID       `json:"id"`
+	WebhookID uuid.UUID       `json:"webhook-id"`
+	EventType string          `json:"event-type"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	// NextAttemptAt is when a pending delivery is attempted next.
+	NextAttemptAt time.Time `json:"next-attempt-at,omitzero"`
+	LastAttemptAt time.Time `json:"last-attempt-at,omitzero"`
+	// ResponseStatus is the HTTP status of the last response, 0 when there was none.
+	ResponseStatus int    `json:"response-status,omitempty"`
+	Error          string `json:"error,omitempty"`
+	// RedeliveryOf is the delivery this one posts again.
+	RedeliveryOf uuid.NullUUID `json:"redelivery-of"`
+	CreatedAt    time.Time     `json:"created-at"`
+}
+
+// QueuedDelivery is a delivery due to be attempted with the endpoint it goes to.
+type QueuedDelivery struct {
+	Delivery *WebhookDelivery
+	URL      string
+	Secret   string
+}
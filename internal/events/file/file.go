@@ -0,0 +1,69 @@
+// Package file appends outbox events to a file as JSON lines, for setups without a broker and
+// for consumers tailing the file.
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+type Publisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func New(path string) (*Publisher, error) {
+	const op = "events.file.New"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Publisher{file: file}, nil
+}
+
+// Publish returns once the event is synced to disk.
+func (p *Publisher) Publish(_ context.Context, event models.OutboxEvent) error {
+	const op = "events.file.Publish"
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := p.file.Stat()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := p.file.Write(line); err != nil {
+		// A partial line would run into the line of the event published again.
+		_ = p.file.Truncate(info.Size())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := p.file.Sync(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	return p.file.Close()
+}
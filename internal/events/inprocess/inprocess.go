@@ -0,0 +1,56 @@
+// Package inprocess publishes outbox events to handlers within the process, for a relay embedded
+// along with the consumers of its events.
+package inprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// Handler consumes an event. An error has the event published again, to every handler, later on.
+type Handler func(ctx context.Context, event models.OutboxEvent) error
+
+// ErrNoHandlers refuses the events published before any handler subscribed, which stay in the
+// outbox rather than being marked dispatched with no one to consume them.
+var ErrNoHandlers = errors.New("no handlers subscribed")
+
+// Publisher calls its handlers in the order they subscribed.
+type Publisher struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func New() *Publisher {
+	return &Publisher{}
+}
+
+func (p *Publisher) Subscribe(handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers = append(p.handlers, handler)
+}
+
+func (p *Publisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	const op = "events.inprocess.Publish"
+
+	p.mu.RLock()
+	handlers := p.handlers
+	p.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		return fmt.Errorf("%s: %w", op, ErrNoHandlers)
+	}
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package inprocess_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/events/inprocess"
+)
+
+func TestPublish(t *testing.T) {
+	p := inprocess.New()
+	event := models.OutboxEvent{Seq: 1, Type: models.EventTaskCreated}
+
+	if err := p.Publish(t.Context(), event); !errors.Is(err, inprocess.ErrNoHandlers) {
+		t.Fatalf("publish without handlers: want %v, got %v", inprocess.ErrNoHandlers, err)
+	}
+
+	var calls []string
+	errRefused := errors.New("refused")
+	refuse := true
+	p.Subscribe(func(ctx context.Context, event models.OutboxEvent) error {
+		calls = append(calls, "first")
+		if refuse {
+			return errRefused
+		}
+		return nil
+	})
+	p.Subscribe(func(ctx context.Context, event models.OutboxEvent) error {
+		calls = append(calls, "second")
+		return nil
+	})
+
+	if err := p.Publish(t.Context(), event); !errors.Is(err, errRefused) {
+		t.Fatalf("publish refused: want %v, got %v", errRefused, err)
+	}
+
+	refuse = false
+	if err := p.Publish(t.Context(), event); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if want := []string{"first", "first", "second"}; !slices.Equal(calls, want) {
+		t.Errorf("want handlers called in order of subscription %v, got %v", want, calls)
+	}
+}
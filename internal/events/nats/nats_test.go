@@ -0,0 +1,371 @@
+package nats_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/events/nats"
+)
+
+// message is a PUB or HPUB the fake server received.
+type message struct {
+	subject string
+	header  string
+	body    []byte
+}
+
+// server speaks the server side of the NATS client protocol to one client at a time, as far as
+// publishing needs it.
+type server struct {
+	t        *testing.T
+	listener net.Listener
+	info     string
+	// reject answers the publishes with -ERR, pingFirst has the server PING before its PONG and
+	// silent never answers.
+	reject, pingFirst, silent bool
+
+	mu       sync.Mutex
+	connects []map[string]any
+	messages []message
+	conns    int
+}
+
+// newServer starts a server, configure sets its behavior before it does.
+func newServer(t *testing.T, info string, configure ...func(s *server)) *server {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	s := &server{t: t, listener: l, info: info}
+	for _, c := range configure {
+		c(s)
+	}
+	go s.serve()
+
+	return s
+}
+
+func (s *server) url(user string) string {
+	if user != "" {
+		user += "@"
+	}
+
+	return "nats://" + user + s.listener.Addr().String()
+}
+
+func (s *server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns++
+		s.mu.Unlock()
+
+		s.handle(conn)
+	}
+}
+
+func (s *server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	if _, err := fmt.Fprintf(conn, "INFO %s\r\n", s.info); err != nil {
+		return
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		verb, args, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+
+		switch verb {
+		case "CONNECT":
+			var options map[string]any
+			if err := json.Unmarshal([]byte(args), &options); err != nil {
+				s.t.Errorf("CONNECT: %v", err)
+				return
+			}
+			s.mu.Lock()
+			s.connects = append(s.connects, options)
+			s.mu.Unlock()
+		case "PING":
+			s.mu.Lock()
+			silent, pingFirst := s.silent, s.pingFirst
+			s.mu.Unlock()
+
+			if silent {
+				continue
+			}
+			if pingFirst {
+				fmt.Fprint(conn, "PING\r\n")
+				if pong, err := reader.ReadString('\n'); err != nil || pong != "PONG\r\n" {
+					s.t.Errorf("want PONG, got %q, %v", pong, err)
+					return
+				}
+			}
+			fmt.Fprint(conn, "PONG\r\n")
+		case "PUB", "HPUB":
+			msg, err := readMessage(reader, verb, args)
+			if err != nil {
+				s.t.Errorf("%s: %v", verb, err)
+				return
+			}
+			s.mu.Lock()
+			reject := s.reject
+			s.mu.Unlock()
+
+			if reject {
+				fmt.Fprint(conn, "-ERR 'Permissions Violation for Publish'\r\n")
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+		default:
+			s.t.Errorf("unexpected client message %q", line)
+			return
+		}
+	}
+}
+
+func readMessage(reader *bufio.Reader, verb, args string) (message, error) {
+	fields := strings.Fields(args)
+
+	var msg message
+	var headerSize, totalSize int
+	var err error
+	switch {
+	case verb == "PUB" && len(fields) == 2:
+		totalSize, err = strconv.Atoi(fields[1])
+	case verb == "HPUB" && len(fields) == 3:
+		if headerSize, err = strconv.Atoi(fields[1]); err == nil {
+			totalSize, err = strconv.Atoi(fields[2])
+		}
+	default:
+		return msg, fmt.Errorf("malformed arguments %q", args)
+	}
+	if err != nil {
+		return msg, err
+	}
+
+	payload := make([]byte, totalSize+2)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return msg, err
+	}
+	if string(payload[totalSize:]) != "\r\n" {
+		return msg, fmt.Errorf("payload of %d bytes not followed by CRLF: %q", totalSize, payload)
+	}
+
+	msg.subject = fields[0]
+	msg.header = string(payload[:headerSize])
+	msg.body = payload[headerSize:totalSize]
+
+	return msg, nil
+}
+
+func (s *server) received() ([]map[string]any, []message, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.connects, s.messages, s.conns
+}
+
+func newEvent(seq int64, eventType string) models.OutboxEvent {
+	return models.OutboxEvent{
+		Seq:           seq,
+		AggregateType: "task",
+		AggregateID:   uuid.New(),
+		Type:          eventType,
+		Payload:       json.RawMessage(`{"title":"Write report"}`),
+		OccurredAt:    time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		name       string
+		info       string
+		user       string
+		prefix     string
+		wantHeader string
+		wantAuth   map[string]any
+	}{
+		{
+			name:       "headers",
+			info:       `{"server_id":"fake","headers":true}`,
+			prefix:     "todo.",
+			wantHeader: "NATS/1.0\r\nNats-Msg-Id: 7\r\n\r\n",
+		},
+		{
+			name:   "no headers",
+			info:   `{"server_id":"fake"}`,
+			prefix: "todo",
+		},
+		{
+			name:     "user and password",
+			info:     `{"server_id":"fake"}`,
+			user:     "relay:secret",
+			prefix:   "todo",
+			wantAuth: map[string]any{"user": "relay", "pass": "secret"},
+		},
+		{
+			name:     "token",
+			info:     `{"server_id":"fake"}`,
+			user:     "s3cr3t",
+			prefix:   "todo",
+			wantAuth: map[string]any{"auth_token": "s3cr3t"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t, tt.info)
+
+			p, err := nats.New(s.url(tt.user), tt.prefix, time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer p.Close()
+
+			events := []models.OutboxEvent{newEvent(7, models.EventTaskCreated), newEvent(8, models.EventTaskDeleted)}
+			for _, event := range events {
+				if err := p.Publish(t.Context(), event); err != nil {
+					t.Fatalf("publish %d: %v", event.Seq, err)
+				}
+			}
+
+			// Every publish awaits its PONG, so the server has the messages by now.
+			connects, messages, conns := s.received()
+			if conns != 1 || len(connects) != 1 {
+				t.Fatalf("want the events published over one connection, got %d connections", conns)
+			}
+			for key, want := range tt.wantAuth {
+				if got := connects[0][key]; got != want {
+					t.Errorf("CONNECT %s: want %v, got %v", key, want, got)
+				}
+			}
+			if connects[0]["verbose"] != false {
+				t.Errorf("want CONNECT without verbose, got %v", connects[0])
+			}
+
+			if len(messages) != len(events) {
+				t.Fatalf("want %d messages, got %d", len(events), len(messages))
+			}
+			if messages[0].subject != "todo.task.created" || messages[1].subject != "todo.task.deleted" {
+				t.Errorf("want the subjects prefixed with todo, got %q and %q", messages[0].subject, messages[1].subject)
+			}
+			if messages[0].header != tt.wantHeader {
+				t.Errorf("want header %q, got %q", tt.wantHeader, messages[0].header)
+			}
+
+			var got models.OutboxEvent
+			if err := json.Unmarshal(messages[0].body, &got); err != nil {
+				t.Fatalf("unmarshal message: %v", err)
+			}
+			if got.Seq != events[0].Seq || got.AggregateID != events[0].AggregateID || string(got.Payload) != string(events[0].Payload) {
+				t.Errorf("want event %+v, got %+v", events[0], got)
+			}
+		})
+	}
+}
+
+func TestPublishAnswersServerPing(t *testing.T) {
+	s := newServer(t, `{"server_id":"fake"}`, func(s *server) { s.pingFirst = true })
+
+	p, err := nats.New(s.url(""), "todo", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	if err := p.Publish(t.Context(), newEvent(1, models.EventTaskCreated)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if _, messages, _ := s.received(); len(messages) != 1 {
+		t.Errorf("want one message, got %d", len(messages))
+	}
+}
+
+func TestPublishReconnectsAfterError(t *testing.T) {
+	s := newServer(t, `{"server_id":"fake"}`, func(s *server) { s.reject = true })
+
+	p, err := nats.New(s.url(""), "todo", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	err = p.Publish(t.Context(), newEvent(1, models.EventTaskCreated))
+	if err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Fatalf("want the server error, got %v", err)
+	}
+
+	s.mu.Lock()
+	s.reject = false
+	s.mu.Unlock()
+
+	if err := p.Publish(t.Context(), newEvent(1, models.EventTaskCreated)); err != nil {
+		t.Fatalf("publish again: %v", err)
+	}
+	if _, messages, conns := s.received(); conns != 2 || len(messages) != 1 {
+		t.Errorf("want the event published over a new connection, got %d messages over %d connections", len(messages), conns)
+	}
+}
+
+func TestPublishTimesOut(t *testing.T) {
+	s := newServer(t, `{"server_id":"fake"}`, func(s *server) { s.silent = true })
+
+	p, err := nats.New(s.url(""), "todo", 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	var netErr net.Error
+	if err := p.Publish(t.Context(), newEvent(1, models.EventTaskCreated)); !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("want a timeout, got %v", err)
+	}
+}
+
+func TestPublishRefusesTLS(t *testing.T) {
+	s := newServer(t, `{"server_id":"fake","tls_required":true}`)
+
+	p, err := nats.New(s.url(""), "todo", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	if err := p.Publish(t.Context(), newEvent(1, models.EventTaskCreated)); !errors.Is(err, nats.ErrTLSRequired) {
+		t.Errorf("want %v, got %v", nats.ErrTLSRequired, err)
+	}
+}
+
+func TestNewRefusesURL(t *testing.T) {
+	for _, rawURL := range []string{"http://localhost:4222", "nats://", "tls://localhost:4222"} {
+		if _, err := nats.New(rawURL, "todo", time.Second); err == nil {
+			t.Errorf("want %q refused", rawURL)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package taskservice publishes the user events of the auth service to the task service, which
+// keeps references to the users its data belongs to. An event counts as published once the task
+// service applied it, so a task service that is down gets the events it missed when it is back.
+package taskservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// UserEventApplier is the task service, e.g. through its gRPC client.
+type UserEventApplier interface {
+	ApplyUserEvent(ctx context.Context, eventType string, userID uuid.UUID) error
+}
+
+type Publisher struct {
+	applier UserEventApplier
+	timeout time.Duration
+}
+
+// New returns a publisher giving the task service timeout to apply each event.
+func New(applier UserEventApplier, timeout time.Duration) *Publisher {
+	return &Publisher{applier: applier, timeout: timeout}
+}
+
+// Publish drops the events of other aggregates, the task service only follows users.
+func (p *Publisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	const op = "events.taskservice.Publish"
+
+	if event.AggregateType != models.AggregateUser {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	if err := p.applier.ApplyUserEvent(ctx, event.Type, event.AggregateID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,7 +16,9 @@ import (
 
 type Service interface {
 	Login(ctx context.Context, email, password string) (string, error)
-	Register(ctx context.Context, email, password string) (string, error)
+	Register(ctx context.Context, email, password, timezone string) (string, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, timezone string) (string, error)
+	DeleteAccount(ctx context.Context, userID uuid.UUID) error
 }
 
 type serverAPI struct {
@@ -53,17 +56,56 @@ func (s *serverAPI) Register(ctx context.Context, req *authv1.RegisterRequest) (
 		return nil, status.Error(codes.InvalidArgument, "password is empty")
 	}
 
-	userID, err := s.service.Register(ctx, req.GetEmail(), req.GetPassword())
+	userID, err := s.service.Register(ctx, req.GetEmail(), req.GetPassword(), req.GetTimezone())
 	if err != nil {
 		if errors.Is(err, my_err.ErrUserExists) {
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
 		}
+		if errors.Is(err, my_err.ErrInvalidTimezone) {
+			return nil, status.Error(codes.InvalidArgument, "unknown timezone")
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
 	return &authv1.RegisterResponse{UserId: userID}, nil
 }
 
+func (s *serverAPI) UpdateProfile(ctx context.Context, req *authv1.UpdateProfileRequest) (*authv1.UpdateProfileResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+
+	token, err := s.service.UpdateProfile(ctx, userID, req.GetTimezone())
+	if err != nil {
+		switch {
+		case errors.Is(err, my_err.ErrInvalidTimezone):
+			return nil, status.Error(codes.InvalidArgument, "unknown timezone")
+		case errors.Is(err, my_err.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &authv1.UpdateProfileResponse{Token: token}, nil
+}
+
+func (s *serverAPI) DeleteAccount(ctx context.Context, req *authv1.DeleteAccountRequest) (*authv1.DeleteAccountResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+
+	if err := s.service.DeleteAccount(ctx, userID); err != nil {
+		if errors.Is(err, my_err.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &authv1.DeleteAccountResponse{}, nil
+}
+
 func validateLogin(req *authv1.LoginRequest) error {
 	if req.GetEmail() == "" {
 		return status.Error(codes.InvalidArgument, "email is empty")
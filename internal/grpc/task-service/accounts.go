@@ -0,0 +1,35 @@
+package task_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+)
+
+type AccountService interface {
+	ApplyUserEvent(ctx context.Context, eventType string, userID uuid.UUID) error
+}
+
+// accountsAPI serves the Accounts service next to Todo, the auth service calls it with its user events.
+type accountsAPI struct {
+	todov1.UnimplementedAccountsServer
+	service Service
+}
+
+func (s *accountsAPI) ApplyUserEvent(ctx context.Context, req *todov1.UserEventRequest) (*todov1.EmptyResponse, error) {
+	userID, err := validateUID(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid user ID: %s", err))
+	}
+
+	if err := s.service.ApplyUserEvent(ctx, req.GetType(), userID); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &todov1.EmptyResponse{}, nil
+}
@@ -0,0 +1,198 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const attachmentChunkSize = 32 * 1024
+
+type AttachmentService interface {
+	AddAttachment(ctx context.Context, taskID, authorID uuid.UUID, name, contentType string, r io.Reader) (*models.Attachment, error)
+	GetAttachments(ctx context.Context, taskID, authorID uuid.UUID) ([]*models.Attachment, error)
+	OpenAttachment(ctx context.Context, attachmentID, authorID uuid.UUID) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, attachmentID, authorID uuid.UUID) error
+}
+
+func (s *serverAPI) UploadAttachment(stream todov1.Todo_UploadAttachmentServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "missing attachment metadata")
+	}
+
+	meta := first.GetMeta()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry attachment metadata")
+	}
+
+	if meta.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "name is empty")
+	}
+
+	taskID, err := validateUID(meta.GetTaskId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid task ID: %s", err))
+	}
+
+	authorID, err := validateUID(meta.GetAuthorId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	contentType := meta.GetContentType()
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	body := &uploadReader{recv: func() ([]byte, error) {
+		msg, err := stream.Recv()
+		return msg.GetChunk(), err
+	}}
+	attachment, err := s.service.AddAttachment(stream.Context(), taskID, authorID, meta.GetName(), contentType, body)
+	if err != nil {
+		return attachmentError(err)
+	}
+
+	return stream.SendAndClose(attachmentToProto(attachment))
+}
+
+func (s *serverAPI) ListAttachments(ctx context.Context, req *todov1.ListAttachmentsRequest) (*todov1.ListAttachmentsResponse, error) {
+	taskID, err := validateUID(req.GetTaskId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid task ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	attachments, err := s.service.GetAttachments(ctx, taskID, authorID)
+	if err != nil {
+		return nil, attachmentError(err)
+	}
+
+	protoAttachments := make([]*todov1.Attachment, len(attachments))
+	for idx, attachment := range attachments {
+		protoAttachments[idx] = attachmentToProto(attachment)
+	}
+
+	return &todov1.ListAttachmentsResponse{Attachments: protoAttachments}, nil
+}
+
+func (s *serverAPI) DownloadAttachment(req *todov1.AttachmentRequest, stream todov1.Todo_DownloadAttachmentServer) error {
+	id, err := validateUID(req.GetAttachmentId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid attachment ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	attachment, body, err := s.service.OpenAttachment(stream.Context(), id, authorID)
+	if err != nil {
+		return attachmentError(err)
+	}
+	defer body.Close()
+
+	if err := stream.Send(&todov1.AttachmentChunk{Data: &todov1.AttachmentChunk_Meta{Meta: attachmentToProto(attachment)}}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, attachmentChunkSize)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&todov1.AttachmentChunk{Data: &todov1.AttachmentChunk_Chunk{Chunk: buf[:n]}}); sendErr != nil {
+				return sendErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return status.Error(codes.Internal, "internal error")
+		}
+	}
+}
+
+func (s *serverAPI) DeleteAttachment(ctx context.Context, req *todov1.AttachmentRequest) (*todov1.EmptyResponse, error) {
+	id, err := validateUID(req.GetAttachmentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid attachment ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	if err := s.service.DeleteAttachment(ctx, id, authorID); err != nil {
+		return nil, attachmentError(err)
+	}
+
+	return &todov1.EmptyResponse{}, nil
+}
+
+func attachmentError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrTaskNotFound):
+		return status.Error(codes.NotFound, "task not found")
+	case errors.Is(err, my_err.ErrAttachmentNotFound), errors.Is(err, my_err.ErrBlobNotFound):
+		return status.Error(codes.NotFound, "attachment not found")
+	case errors.Is(err, my_err.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, "attachment quota exceeded")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func attachmentToProto(attachment *models.Attachment) *todov1.Attachment {
+	return &todov1.Attachment{
+		Id:          attachment.ID.String(),
+		TaskId:      attachment.TaskID.String(),
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Sha256:      attachment.SHA256,
+		CreatedAt:   attachment.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// uploadReader exposes the chunks of a client stream as a plain io.Reader.
+type uploadReader struct {
+	// recv returns the next chunk, io.EOF at the end of the stream.
+	recv func() ([]byte, error)
+	buf  []byte
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+
+		r.buf = chunk
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
@@ -0,0 +1,171 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type BatchService interface {
+	BatchCreateTasks(ctx context.Context, authorID uuid.UUID, tasks []*models.Task, atomic bool) ([]models.BatchResult, error)
+	BatchUpdateTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector, patch models.TaskPatch, atomic bool) ([]models.BatchResult, error)
+	BatchDeleteTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector, atomic bool) ([]models.BatchResult, error)
+}
+
+func (s *serverAPI) BatchCreateTasks(ctx context.Context, req *todov1.BatchCreateTasksRequest) (*todov1.BatchResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	tasks := make([]*models.Task, len(req.GetTasks()))
+	for idx, protoTask := range req.GetTasks() {
+		projectID, err := validateOptionalUID(protoTask.GetProjectId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("item %d: invalid project ID: %s", idx, err))
+		}
+
+		deadline, err := deadlineFromProto(protoTask.GetDeadline())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("item %d: deadline is out of range", idx))
+		}
+
+		tasks[idx] = &models.Task{
+			ProjectID:   nullUID(projectID),
+			Title:       protoTask.GetTitle(),
+			Description: protoTask.GetDescription(),
+			Deadline:    deadline,
+			DueDate:     protoTask.GetDueDate(),
+			Priority:    protoTask.GetPriority(),
+			Tags:        protoTask.GetTags(),
+		}
+	}
+
+	results, err := s.service.BatchCreateTasks(ctx, authorID, tasks, req.GetAtomic())
+	if err != nil {
+		return nil, batchStatus(err).Err()
+	}
+
+	return batchResultsToProto(results), nil
+}
+
+func (s *serverAPI) BatchUpdateTasks(ctx context.Context, req *todov1.BatchUpdateTasksRequest) (*todov1.BatchResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	selector, err := selectorFromProto(req.GetSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	protoPatch := req.GetPatch()
+	if protoPatch == nil {
+		return nil, status.Error(codes.InvalidArgument, "patch is empty")
+	}
+
+	deadline, err := deadlineFromProto(protoPatch.GetDeadline())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "deadline is out of range")
+	}
+
+	patch := models.TaskPatch{
+		Status:     protoPatch.Status,
+		Priority:   protoPatch.Priority,
+		SetDue:     protoPatch.GetSetDue(),
+		Deadline:   deadline,
+		DueDate:    protoPatch.GetDueDate(),
+		AddTags:    protoPatch.GetAddTags(),
+		RemoveTags: protoPatch.GetRemoveTags(),
+	}
+
+	results, err := s.service.BatchUpdateTasks(ctx, authorID, selector, patch, req.GetAtomic())
+	if err != nil {
+		return nil, batchStatus(err).Err()
+	}
+
+	return batchResultsToProto(results), nil
+}
+
+func (s *serverAPI) BatchDeleteTasks(ctx context.Context, req *todov1.BatchDeleteTasksRequest) (*todov1.BatchResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	selector, err := selectorFromProto(req.GetSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.service.BatchDeleteTasks(ctx, authorID, selector, req.GetAtomic())
+	if err != nil {
+		return nil, batchStatus(err).Err()
+	}
+
+	return batchResultsToProto(results), nil
+}
+
+func selectorFromProto(protoSelector *todov1.TaskSelector) (models.TaskSelector, error) {
+	selector := models.TaskSelector{
+		IDs:      make([]uuid.UUID, len(protoSelector.GetTaskIds())),
+		Filter:   protoSelector.GetFilter(),
+		Timezone: protoSelector.GetTimezone(),
+	}
+
+	for idx, rawID := range protoSelector.GetTaskIds() {
+		id, err := validateUID(rawID)
+		if err != nil {
+			return models.TaskSelector{}, status.Error(codes.InvalidArgument, fmt.Sprintf("item %d: invalid task ID: %s", idx, err))
+		}
+		selector.IDs[idx] = id
+	}
+
+	return selector, nil
+}
+
+// batchStatus maps the error of a whole batch, or of one of its items, onto a gRPC status.
+func batchStatus(err error) *status.Status {
+	switch {
+	case errors.Is(err, my_err.ErrTaskNotFound), errors.Is(err, my_err.ErrProjectNotFound):
+		return status.New(codes.NotFound, err.Error())
+	case errors.Is(err, my_err.ErrInvalidBatch),
+		errors.Is(err, my_err.ErrEmptyTitle),
+		errors.Is(err, my_err.ErrInvalidDueDate),
+		errors.Is(err, my_err.ErrInvalidStatus),
+		errors.Is(err, my_err.ErrInvalidViewQuery),
+		errors.Is(err, my_err.ErrInvalidTimezone):
+		return status.New(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.New(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.New(codes.Internal, "internal error")
+	}
+}
+
+func batchResultsToProto(results []models.BatchResult) *todov1.BatchResponse {
+	protoResults := make([]*todov1.BatchItemResult, len(results))
+	for idx, result := range results {
+		protoResults[idx] = &todov1.BatchItemResult{}
+		if result.TaskID != uuid.Nil {
+			protoResults[idx].TaskId = result.TaskID.String()
+		}
+
+		if result.Err != nil {
+			st := batchStatus(result.Err)
+			protoResults[idx].Code = int32(st.Code())
+			protoResults[idx].Error = st.Message()
+		}
+	}
+
+	return &todov1.BatchResponse{Results: protoResults}
+}
@@ -0,0 +1,164 @@
+package task_service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type CalDAVService interface {
+	ListCalendars(ctx context.Context, authorID uuid.UUID) ([]models.CalendarCollection, int64, error)
+	GetCalendarObjects(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, names []string) ([]*models.CalendarObject, error)
+	SyncCalendarObjects(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, syncToken int64) (*models.CalendarChanges, error)
+	PutCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name string, r io.Reader, ifMatch, ifNoneMatch, timezone string) (bool, error)
+	DeleteCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name, ifMatch string) error
+}
+
+func (s *serverAPI) ListCalendars(ctx context.Context, req *todov1.ListCalendarsRequest) (*todov1.ListCalendarsResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	calendars, syncToken, err := s.service.ListCalendars(ctx, authorID)
+	if err != nil {
+		return nil, calDAVError(err)
+	}
+
+	resp := &todov1.ListCalendarsResponse{SyncToken: syncToken}
+	for _, calendar := range calendars {
+		protoCalendar := &todov1.Calendar{Name: calendar.Name, Ctag: calendar.CTag}
+		if calendar.ProjectID.Valid {
+			protoCalendar.ProjectId = calendar.ProjectID.UUID.String()
+		}
+		resp.Calendars = append(resp.Calendars, protoCalendar)
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetCalendarObjects(ctx context.Context, req *todov1.GetCalendarObjectsRequest) (*todov1.CalendarObjects, error) {
+	authorID, projectID, err := validateCalendar(req.GetAuthorId(), req.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+
+	names := req.GetNames()
+	if req.GetAll() {
+		names = nil
+	} else if names == nil {
+		names = []string{}
+	}
+
+	objects, err := s.service.GetCalendarObjects(ctx, authorID, projectID, names)
+	if err != nil {
+		return nil, calDAVError(err)
+	}
+
+	return &todov1.CalendarObjects{Objects: calendarObjectsToProto(objects)}, nil
+}
+
+func (s *serverAPI) SyncCalendarObjects(ctx context.Context, req *todov1.SyncCalendarObjectsRequest) (*todov1.CalendarChanges, error) {
+	authorID, projectID, err := validateCalendar(req.GetAuthorId(), req.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.service.SyncCalendarObjects(ctx, authorID, projectID, req.GetSyncToken())
+	if err != nil {
+		return nil, calDAVError(err)
+	}
+
+	return &todov1.CalendarChanges{
+		Changed:   calendarObjectsToProto(changes.Changed),
+		Deleted:   changes.Deleted,
+		SyncToken: changes.SyncToken,
+	}, nil
+}
+
+func (s *serverAPI) PutCalendarObject(ctx context.Context, req *todov1.PutCalendarObjectRequest) (*todov1.PutCalendarObjectResponse, error) {
+	authorID, projectID, err := validateCalendar(req.GetAuthorId(), req.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.service.PutCalendarObject(ctx, authorID, projectID, req.GetName(), bytes.NewReader(req.GetData()),
+		req.GetIfMatch(), req.GetIfNoneMatch(), req.GetTimezone())
+	if err != nil {
+		return nil, calDAVError(err)
+	}
+
+	return &todov1.PutCalendarObjectResponse{Created: created}, nil
+}
+
+func (s *serverAPI) DeleteCalendarObject(ctx context.Context, req *todov1.DeleteCalendarObjectRequest) (*todov1.EmptyResponse, error) {
+	authorID, projectID, err := validateCalendar(req.GetAuthorId(), req.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteCalendarObject(ctx, authorID, projectID, req.GetName(), req.GetIfMatch()); err != nil {
+		return nil, calDAVError(err)
+	}
+
+	return &todov1.EmptyResponse{}, nil
+}
+
+// validateCalendar parses the author and the project of a calendar, an empty project is the inbox.
+func validateCalendar(author, project string) (uuid.UUID, uuid.NullUUID, error) {
+	authorID, err := validateUID(author)
+	if err != nil {
+		return uuid.Nil, uuid.NullUUID{}, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(project)
+	if err != nil {
+		return uuid.Nil, uuid.NullUUID{}, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	return authorID, nullUID(projectID), nil
+}
+
+func calendarObjectsToProto(objects []*models.CalendarObject) []*todov1.CalendarObject {
+	protoObjects := make([]*todov1.CalendarObject, 0, len(objects))
+	for _, object := range objects {
+		protoObjects = append(protoObjects, &todov1.CalendarObject{Name: object.Name, Data: object.Data, Etag: object.ETag})
+	}
+
+	return protoObjects
+}
+
+func calDAVError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrCalendarObjectNotFound):
+		return status.Error(codes.NotFound, "calendar object not found")
+	case errors.Is(err, my_err.ErrProjectNotFound):
+		return status.Error(codes.NotFound, "project not found")
+	case errors.Is(err, my_err.ErrInvalidCalendarObject),
+		errors.Is(err, my_err.ErrInvalidStatus),
+		errors.Is(err, my_err.ErrInvalidDueDate),
+		errors.Is(err, my_err.ErrInvalidTimezone):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrCalendarUIDExists):
+		return status.Error(codes.AlreadyExists, "calendar object with given UID already exists")
+	case errors.Is(err, my_err.ErrETagMismatch):
+		return status.Error(codes.FailedPrecondition, "calendar object does not match the ETag")
+	case errors.Is(err, my_err.ErrInvalidSyncToken):
+		// OutOfRange tells a token that is not valid apart from other invalid arguments.
+		return status.Error(codes.OutOfRange, "invalid sync token")
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.Error(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,79 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type CalendarService interface {
+	RegenerateCalendarToken(ctx context.Context, authorID uuid.UUID, reminderMinutes int) (string, *models.CalendarFeed, error)
+	RevokeCalendarToken(ctx context.Context, authorID uuid.UUID) error
+	GetCalendarFeed(ctx context.Context, token string) ([]byte, string, error)
+}
+
+func (s *serverAPI) RegenerateCalendarToken(ctx context.Context, req *todov1.RegenerateCalendarTokenRequest) (*todov1.CalendarToken, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	token, feed, err := s.service.RegenerateCalendarToken(ctx, authorID, int(req.GetReminderMinutes()))
+	if err != nil {
+		return nil, calendarError(err)
+	}
+
+	return &todov1.CalendarToken{
+		Token:           token,
+		ReminderMinutes: int32(feed.ReminderMinutes),
+		CreatedAt:       feed.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *serverAPI) RevokeCalendarToken(ctx context.Context, req *todov1.RevokeCalendarTokenRequest) (*todov1.EmptyResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	if err := s.service.RevokeCalendarToken(ctx, authorID); err != nil {
+		return nil, calendarError(err)
+	}
+
+	return &todov1.EmptyResponse{}, nil
+}
+
+func (s *serverAPI) GetCalendarFeed(ctx context.Context, req *todov1.CalendarFeedRequest) (*todov1.CalendarFeed, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is empty")
+	}
+
+	data, etag, err := s.service.GetCalendarFeed(ctx, req.GetToken())
+	if err != nil {
+		return nil, calendarError(err)
+	}
+
+	return &todov1.CalendarFeed{Data: data, Etag: etag}, nil
+}
+
+func calendarError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrCalendarFeedNotFound):
+		return status.Error(codes.NotFound, "calendar feed not found")
+	case errors.Is(err, my_err.ErrInvalidCalendarFeed):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.Error(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,163 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type ChecklistService interface {
+	AddChecklistItem(ctx context.Context, taskID, authorID uuid.UUID, text string, position *int) (*models.ChecklistItem, error)
+	ToggleChecklistItem(ctx context.Context, itemID, authorID uuid.UUID) (*models.ChecklistItem, error)
+	ReorderChecklistItem(ctx context.Context, itemID, authorID uuid.UUID, position int) ([]*models.ChecklistItem, error)
+	RemoveChecklistItem(ctx context.Context, itemID, authorID uuid.UUID) ([]*models.ChecklistItem, error)
+}
+
+func (s *serverAPI) AddChecklistItem(ctx context.Context, req *todov1.AddChecklistItemRequest) (*todov1.ChecklistItem, error) {
+	if strings.TrimSpace(req.GetText()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "text is empty")
+	}
+
+	taskID, err := validateUID(req.GetTaskId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid task ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	var position *int
+	if req.Position != nil {
+		p := int(req.GetPosition())
+		position = &p
+	}
+
+	item, err := s.service.AddChecklistItem(ctx, taskID, authorID, req.GetText(), position)
+	if err != nil {
+		return nil, checklistError(err)
+	}
+
+	return checklistItemToProto(item), nil
+}
+
+func (s *serverAPI) ToggleChecklistItem(ctx context.Context, req *todov1.ChecklistItemRequest) (*todov1.ChecklistItem, error) {
+	itemID, authorID, err := validateChecklistItemRequest(req.GetItemId(), req.GetAuthorId())
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.service.ToggleChecklistItem(ctx, itemID, authorID)
+	if err != nil {
+		return nil, checklistError(err)
+	}
+
+	return checklistItemToProto(item), nil
+}
+
+func (s *serverAPI) ReorderChecklistItem(ctx context.Context, req *todov1.ReorderChecklistItemRequest) (*todov1.ChecklistResponse, error) {
+	itemID, authorID, err := validateChecklistItemRequest(req.GetItemId(), req.GetAuthorId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetPosition() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "position must not be negative")
+	}
+
+	items, err := s.service.ReorderChecklistItem(ctx, itemID, authorID, int(req.GetPosition()))
+	if err != nil {
+		return nil, checklistError(err)
+	}
+
+	return checklistResponse(items), nil
+}
+
+func (s *serverAPI) RemoveChecklistItem(ctx context.Context, req *todov1.ChecklistItemRequest) (*todov1.ChecklistResponse, error) {
+	itemID, authorID, err := validateChecklistItemRequest(req.GetItemId(), req.GetAuthorId())
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.service.RemoveChecklistItem(ctx, itemID, authorID)
+	if err != nil {
+		return nil, checklistError(err)
+	}
+
+	return checklistResponse(items), nil
+}
+
+func validateChecklistItemRequest(itemIDString, authorIDString string) (uuid.UUID, uuid.UUID, error) {
+	itemID, err := validateUID(itemIDString)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid item ID: %s", err))
+	}
+
+	authorID, err := validateUID(authorIDString)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	return itemID, authorID, nil
+}
+
+func checklistError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrTaskNotFound):
+		return status.Error(codes.NotFound, "task not found")
+	case errors.Is(err, my_err.ErrChecklistItemNotFound):
+		return status.Error(codes.NotFound, "checklist item not found")
+	case errors.Is(err, my_err.ErrEmptyField):
+		return status.Error(codes.InvalidArgument, "text is empty")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func checklistResponse(items []*models.ChecklistItem) *todov1.ChecklistResponse {
+	return &todov1.ChecklistResponse{
+		Items:   checklistToProto(items),
+		Summary: checklistSummaryToProto(models.SummarizeChecklist(items)),
+	}
+}
+
+func checklistToProto(items []*models.ChecklistItem) []*todov1.ChecklistItem {
+	if len(items) == 0 {
+		return nil
+	}
+
+	protoItems := make([]*todov1.ChecklistItem, len(items))
+	for idx, item := range items {
+		protoItems[idx] = checklistItemToProto(item)
+	}
+
+	return protoItems
+}
+
+func checklistItemToProto(item *models.ChecklistItem) *todov1.ChecklistItem {
+	return &todov1.ChecklistItem{
+		Id:       item.ID.String(),
+		TaskId:   item.TaskID.String(),
+		Text:     item.Text,
+		Checked:  item.Checked,
+		Position: int32(item.Position),
+	}
+}
+
+func checklistSummaryToProto(summary models.ChecklistSummary) *todov1.ChecklistSummary {
+	return &todov1.ChecklistSummary{
+		Done:  int32(summary.Done),
+		Total: int32(summary.Total),
+		Text:  summary.Text,
+	}
+}
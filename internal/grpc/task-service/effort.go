@@ -0,0 +1,81 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type EffortService interface {
+	SetTaskEstimate(ctx context.Context, taskID, authorID uuid.UUID, minutes *int) (*models.Task, error)
+}
+
+func (s *serverAPI) SetTaskEstimate(ctx context.Context, req *todov1.SetTaskEstimateRequest) (*todov1.Task, error) {
+	taskID, err := validateUID(req.GetTaskId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid task ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	var minutes *int
+	if req.EstimateMinutes != nil {
+		value := int(req.GetEstimateMinutes())
+		minutes = &value
+	}
+
+	task, err := s.service.SetTaskEstimate(ctx, taskID, authorID, minutes)
+	if err != nil {
+		switch {
+		case errors.Is(err, my_err.ErrTaskNotFound):
+			return nil, status.Error(codes.NotFound, "task not found")
+		case errors.Is(err, my_err.ErrInvalidEstimate):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return taskToProto(task), nil
+}
+
+func effortToProto(effort models.Effort) *todov1.Effort {
+	return &todov1.Effort{
+		EstimateMinutes:  int32(effort.EstimateMinutes),
+		ActualMinutes:    int32(effort.ActualMinutes),
+		VarianceMinutes:  int32(effort.VarianceMinutes),
+		RemainingMinutes: int32(effort.RemainingMinutes),
+	}
+}
+
+func projectEffortToProto(effort *models.ProjectEffort) *todov1.ProjectEffort {
+	protoEffort := &todov1.ProjectEffort{
+		Total:            effortToProto(effort.Effort),
+		EstimatedTasks:   int32(effort.EstimatedTasks),
+		UnestimatedTasks: int32(effort.UnestimatedTasks),
+		ByStatus:         make([]*todov1.StatusEffort, len(effort.ByStatus)),
+	}
+
+	for idx, byStatus := range effort.ByStatus {
+		protoEffort.ByStatus[idx] = &todov1.StatusEffort{
+			Status:           byStatus.Status,
+			Tasks:            int32(byStatus.Tasks),
+			EstimatedTasks:   int32(byStatus.EstimatedTasks),
+			EstimateMinutes:  int32(byStatus.EstimateMinutes),
+			ActualMinutes:    int32(byStatus.ActualMinutes),
+			RemainingMinutes: int32(byStatus.RemainingMinutes),
+		}
+	}
+
+	return protoEffort
+}
@@ -0,0 +1,71 @@
+package task_service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// exportChunkSize is the most export data sent per message. Exports are buffered up to it,
+// so a failure early in an export still reaches the client as the status of the call.
+const exportChunkSize = 32 * 1024
+
+type ExportService interface {
+	ExportTasks(ctx context.Context, authorID uuid.UUID, format, filter, timezone string, w io.Writer) error
+}
+
+func (s *serverAPI) ExportTasks(req *todov1.ExportTasksRequest, stream todov1.Todo_ExportTasksServer) error {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	w := bufio.NewWriterSize(&exportWriter{stream: stream}, exportChunkSize)
+
+	err = s.service.ExportTasks(stream.Context(), authorID, req.GetFormat(), req.GetFilter(), req.GetTimezone(), w)
+	if err != nil {
+		return exportError(err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return exportError(err)
+	}
+
+	return nil
+}
+
+// exportWriter sends every write as one chunk of the stream.
+type exportWriter struct {
+	stream todov1.Todo_ExportTasksServer
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	// Send may keep p, which the bufio.Writer reuses.
+	if err := w.stream.Send(&todov1.ExportChunk{Data: append([]byte(nil), p...)}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func exportError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrInvalidExportFormat),
+		errors.Is(err, my_err.ErrInvalidViewQuery),
+		errors.Is(err, my_err.ErrInvalidTimezone):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "export canceled")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,232 @@
+package task_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/idempotency"
+)
+
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, now time.Time) (*models.IdempotencyRecord, error)
+	CompleteIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error
+	ReleaseIdempotencyKey(ctx context.Context, scope, key string) error
+}
+
+// pendingTimeout bounds how long a request that never completes, because its server died, holds its key.
+const pendingTimeout = time.Minute
+
+// idempotentMethods are the unary mutations that honour an idempotency key, reads simply run again.
+// Streaming mutations refuse keys, see IdempotencyStreamInterceptor.
+var idempotentMethods = map[string]bool{
+	todov1.Todo_CreateTask_FullMethodName:                   true,
+	todov1.Todo_UpdateTask_FullMethodName:                   true,
+	todov1.Todo_DeleteTask_FullMethodName:                   true,
+	todov1.Todo_MoveTask_FullMethodName:                     true,
+	todov1.Todo_SetTaskEstimate_FullMethodName:              true,
+	todov1.Todo_QuickAdd_FullMethodName:                     true,
+	todov1.Todo_BatchCreateTasks_FullMethodName:             true,
+	todov1.Todo_BatchUpdateTasks_FullMethodName:             true,
+	todov1.Todo_BatchDeleteTasks_FullMethodName:             true,
+	todov1.Todo_DeleteAttachment_FullMethodName:             true,
+	todov1.Todo_AddChecklistItem_FullMethodName:             true,
+	todov1.Todo_ToggleChecklistItem_FullMethodName:          true,
+	todov1.Todo_ReorderChecklistItem_FullMethodName:         true,
+	todov1.Todo_RemoveChecklistItem_FullMethodName:          true,
+	todov1.Todo_CreateView_FullMethodName:                   true,
+	todov1.Todo_DeleteView_FullMethodName:                   true,
+	todov1.Todo_CreateProject_FullMethodName:                true,
+	todov1.Todo_StartTimer_FullMethodName:                   true,
+	todov1.Todo_StopTimer_FullMethodName:                    true,
+	todov1.Todo_LogTime_FullMethodName:                      true,
+	todov1.Todo_CreateTemplate_FullMethodName:               true,
+	todov1.Todo_DeleteTemplate_FullMethodName:               true,
+	todov1.Todo_InstantiateTemplate_FullMethodName:          true,
+	todov1.Todo_RevokeCalendarToken_FullMethodName:          true,
+	todov1.Todo_PutCalendarObject_FullMethodName:            true,
+	todov1.Todo_DeleteCalendarObject_FullMethodName:         true,
+	todov1.Webhooks_CreateWebhook_FullMethodName:            true,
+	todov1.Webhooks_UpdateWebhook_FullMethodName:            true,
+	todov1.Webhooks_DeleteWebhook_FullMethodName:            true,
+	todov1.Webhooks_RedeliverWebhookDelivery_FullMethodName: true,
+}
+
+// IdempotencyInterceptor answers a repeated request carrying the idempotency-key metadata with the
+// outcome of the first one, kept for ttl. A key reused with another request is rejected, and so is
+// a repeat arriving while the first request still runs. Transient failures are not kept, the
+// request runs again when retried.
+func IdempotencyInterceptor(store IdempotencyStore, ttl time.Duration, log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		const op = "grpc.IdempotencyInterceptor"
+
+		key := idempotencyKey(ctx)
+		if key == "" || !idempotentMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		if err := idempotency.ValidateKey(key); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("method", info.FullMethod),
+			slog.String("idempotency_key", key),
+		)
+
+		message, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		fingerprint, err := idempotency.Fingerprint(info.FullMethod, message)
+		if err != nil {
+			log.Error("failed to fingerprint request", slog.String("error", err.Error()))
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+
+		record := &models.IdempotencyRecord{
+			Key:         key,
+			Method:      info.FullMethod,
+			Fingerprint: fingerprint,
+		}
+		if scoped, ok := req.(interface{ GetAuthorId() string }); ok {
+			record.Scope = scoped.GetAuthorId()
+		}
+
+		now := time.Now()
+		record.ExpiresAt = now.Add(pendingTimeout)
+
+		existing, err := store.ReserveIdempotencyKey(ctx, record, now)
+		if err != nil {
+			log.Error("failed to reserve idempotency key", slog.String("error", err.Error()))
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+
+		if existing != nil {
+			log.Info("replaying request")
+			return replayIdempotent(existing, record)
+		}
+
+		resp, handlerErr := handler(ctx, req)
+
+		// The caller may be gone already, the outcome is kept for the retry it is about to send.
+		ctx = context.WithoutCancel(ctx)
+
+		if handlerErr != nil && transientCode(status.Code(handlerErr)) {
+			if err := store.ReleaseIdempotencyKey(ctx, record.Scope, record.Key); err != nil {
+				log.Error("failed to release idempotency key", slog.String("error", err.Error()))
+			}
+			return resp, handlerErr
+		}
+
+		record.Completed = true
+		record.ExpiresAt = time.Now().Add(ttl)
+
+		if handlerErr != nil {
+			st := status.Convert(handlerErr)
+			record.Code = uint32(st.Code())
+			record.Message = st.Message()
+		} else {
+			record.Response, err = marshalResponse(resp)
+		}
+
+		if err == nil {
+			err = store.CompleteIdempotencyKey(ctx, record)
+		}
+
+		// A key without its outcome is of no use to a replay, release it rather than hold it until pendingTimeout.
+		if err != nil {
+			log.Error("failed to store request outcome", slog.String("error", err.Error()))
+			if err := store.ReleaseIdempotencyKey(ctx, record.Scope, record.Key); err != nil {
+				log.Error("failed to release idempotency key", slog.String("error", err.Error()))
+			}
+		}
+
+		return resp, handlerErr
+	}
+}
+
+// IdempotencyStreamInterceptor refuses the idempotency-key metadata on calls that stream their
+// request, such as attachment uploads and imports. Such a request is only known once its stream
+// ends, after it has run, so a retry could not be answered with the first outcome and would run
+// again. Calls that only stream their response are reads and ignore the key.
+func IdempotencyStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.IsClientStream && idempotencyKey(ss.Context()) != "" {
+			return status.Error(codes.InvalidArgument, "idempotency keys are not supported on streaming calls")
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func idempotencyKey(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, idempotency.MetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func replayIdempotent(existing, record *models.IdempotencyRecord) (any, error) {
+	if existing.Method != record.Method || existing.Fingerprint != record.Fingerprint {
+		return nil, status.Error(codes.InvalidArgument, "idempotency key was already used for a different request")
+	}
+
+	if !existing.Completed {
+		return nil, status.Error(codes.Aborted, "a request with this idempotency key is still in progress")
+	}
+
+	if existing.Code != uint32(codes.OK) {
+		return nil, status.Error(codes.Code(existing.Code), existing.Message)
+	}
+
+	var response anypb.Any
+	if err := proto.Unmarshal(existing.Response, &response); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp, err := response.UnmarshalNew()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return resp, nil
+}
+
+func marshalResponse(resp any) ([]byte, error) {
+	message, ok := resp.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("response %T is not a protobuf message", resp)
+	}
+
+	response, err := anypb.New(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(response)
+}
+
+// transientCode tells the failures a retry may fix.
+func transientCode(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
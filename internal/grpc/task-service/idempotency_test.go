@@ -0,0 +1,149 @@
+package task_service_test
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	taskclient "github.com/SlashLight/todo-list/internal/clients/task-service/grpc"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	taskgrpc "github.com/SlashLight/todo-list/internal/grpc/task-service"
+	"github.com/SlashLight/todo-list/internal/lib/idempotency"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/internal/storage/blob/local"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+)
+
+// newClient serves a task service on memory storage with the interceptors of the app and
+// returns a client of it.
+func newClient(t *testing.T) *taskclient.Client {
+	t.Helper()
+
+	log := slog.New(slog.DiscardHandler)
+
+	blobs, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := memory.New()
+	service := task_service.New(s, blobs, nil, task_service.Settings{AttachmentQuota: 1 << 20}, log)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gRPCServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(taskgrpc.IdempotencyInterceptor(s, time.Hour, log)),
+		grpc.ChainStreamInterceptor(taskgrpc.IdempotencyStreamInterceptor()),
+	)
+	taskgrpc.RegisterServerAPI(gRPCServer, service)
+	go func() { _ = gRPCServer.Serve(l) }()
+	t.Cleanup(gRPCServer.Stop)
+
+	client, err := taskclient.New(l.Addr().String(), log, 0, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestIdempotencyKeyReplaysUnaryCalls(t *testing.T) {
+	client := newClient(t)
+	author := uuid.New()
+	ctx := idempotency.NewContext(t.Context(), "create-report")
+
+	first, err := client.CreateTask(ctx, author, uuid.Nil, "Write report", "", "", time.Time{}, "")
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	second, err := client.CreateTask(ctx, author, uuid.Nil, "Write report", "", "", time.Time{}, "")
+	if err != nil {
+		t.Fatalf("create task again: %v", err)
+	}
+	if first != second {
+		t.Errorf("want the retry answered with task %s, got %s", first, second)
+	}
+
+	_, err = client.CreateTask(ctx, author, uuid.Nil, "Send report", "", "", time.Time{}, "")
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("create another task with the key: want %v, got %v", codes.InvalidArgument, err)
+	}
+
+	tasks, err := client.GetTask(t.Context(), author)
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("want a single task, got %v", tasks)
+	}
+}
+
+func TestIdempotencyKeyRefusedOnStreams(t *testing.T) {
+	client := newClient(t)
+	author := uuid.New()
+
+	id, err := client.CreateTask(t.Context(), author, uuid.Nil, "Write report", "", "", time.Time{}, "")
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	taskID := uuid.MustParse(id)
+
+	ctx := idempotency.NewContext(t.Context(), "upload-notes")
+
+	_, err = client.UploadAttachment(ctx, taskID, author, "notes.txt", "text/plain", strings.NewReader("notes"))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("upload with a key: want %v, got %v", codes.InvalidArgument, err)
+	}
+
+	options := models.ImportOptions{Format: models.ImportCSV}
+	_, err = client.ImportTasks(ctx, author, options, strings.NewReader("title\nSend report\n"))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("import with a key: want %v, got %v", codes.InvalidArgument, err)
+	}
+
+	tasks, err := client.GetTask(t.Context(), author)
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("want the refused import to leave nothing, got %v", tasks)
+	}
+
+	attachments, err := client.ListAttachments(t.Context(), taskID, author)
+	if err != nil {
+		t.Fatalf("list attachments: %v", err)
+	}
+	if len(attachments) != 0 {
+		t.Fatalf("want the refused upload to leave nothing, got %v", attachments)
+	}
+
+	attachment, err := client.UploadAttachment(t.Context(), taskID, author, "notes.txt", "text/plain", strings.NewReader("notes"))
+	if err != nil {
+		t.Fatalf("upload without a key: %v", err)
+	}
+
+	// Downloads only stream their response, a key is of no matter to them.
+	_, body, err := client.DownloadAttachment(ctx, attachment.ID, author)
+	if err != nil {
+		t.Fatalf("download with a key: %v", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("read attachment: %v", err)
+	}
+	if string(data) != "notes" {
+		t.Errorf("want the uploaded data, got %q", data)
+	}
+}
@@ -0,0 +1,97 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type ImportService interface {
+	ImportTasks(ctx context.Context, authorID uuid.UUID, options models.ImportOptions, r io.Reader) (*models.ImportReport, error)
+}
+
+func (s *serverAPI) ImportTasks(stream todov1.Todo_ImportTasksServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "missing import options")
+	}
+
+	options := first.GetOptions()
+	if options == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry import options")
+	}
+
+	authorID, err := validateUID(options.GetAuthorId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(options.GetProjectId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	body := &uploadReader{recv: func() ([]byte, error) {
+		msg, err := stream.Recv()
+		return msg.GetChunk(), err
+	}}
+
+	report, err := s.service.ImportTasks(stream.Context(), authorID, models.ImportOptions{
+		Format:        options.GetFormat(),
+		ColumnMapping: options.GetColumnMapping(),
+		ProjectID:     nullUID(projectID),
+		Timezone:      options.GetTimezone(),
+		DryRun:        options.GetDryRun(),
+	}, body)
+	if err != nil {
+		return importError(err)
+	}
+
+	return stream.SendAndClose(importReportToProto(report))
+}
+
+func importError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrInvalidImport), errors.Is(err, my_err.ErrInvalidTimezone):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrProjectNotFound):
+		return status.Error(codes.NotFound, "project not found")
+	case errors.Is(err, my_err.ErrAlreadyImported):
+		return status.Error(codes.Aborted, "tasks of the file were imported concurrently")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "import canceled")
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.Error(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func importReportToProto(report *models.ImportReport) *todov1.ImportReport {
+	errs := make([]*todov1.ImportRowError, len(report.Errors))
+	for idx, rowErr := range report.Errors {
+		errs[idx] = &todov1.ImportRowError{
+			Row:        int32(rowErr.Row),
+			ExternalId: rowErr.ExternalID,
+			Message:    rowErr.Message,
+		}
+	}
+
+	return &todov1.ImportReport{
+		DryRun:     report.DryRun,
+		Rows:       int32(report.Rows),
+		Created:    int32(report.Created),
+		Duplicates: int32(report.Duplicates),
+		Committed:  report.Committed,
+		Errors:     errs,
+	}
+}
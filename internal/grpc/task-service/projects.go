@@ -0,0 +1,138 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type ProjectService interface {
+	CreateProject(ctx context.Context, authorID uuid.UUID, name string) (*models.Project, error)
+	ListProjects(ctx context.Context, authorID uuid.UUID) ([]*models.Project, error)
+	GetBoard(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, status string, limit int, pageToken string) (*models.Board, error)
+}
+
+func (s *serverAPI) CreateProject(ctx context.Context, req *todov1.CreateProjectRequest) (*todov1.Project, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is empty")
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	project, err := s.service.CreateProject(ctx, authorID, req.GetName())
+	if err != nil {
+		return nil, projectError(err)
+	}
+
+	return projectToProto(project), nil
+}
+
+func (s *serverAPI) ListProjects(ctx context.Context, req *todov1.ListProjectsRequest) (*todov1.ListProjectsResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projects, err := s.service.ListProjects(ctx, authorID)
+	if err != nil {
+		return nil, projectError(err)
+	}
+
+	protoProjects := make([]*todov1.Project, len(projects))
+	for idx, project := range projects {
+		protoProjects[idx] = projectToProto(project)
+	}
+
+	return &todov1.ListProjectsResponse{Projects: protoProjects}, nil
+}
+
+func (s *serverAPI) GetBoard(ctx context.Context, req *todov1.GetBoardRequest) (*todov1.Board, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(req.GetProjectId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	if req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	board, err := s.service.GetBoard(ctx, authorID, nullUID(projectID), req.GetStatus(), int(req.GetLimit()), req.GetPageToken())
+	if err != nil {
+		return nil, projectError(err)
+	}
+
+	protoBoard := &todov1.Board{Columns: make([]*todov1.BoardColumn, len(board.Columns))}
+	if board.ProjectID.Valid {
+		protoBoard.ProjectId = board.ProjectID.UUID.String()
+	}
+
+	for idx, column := range board.Columns {
+		protoTasks := make([]*todov1.Task, len(column.Tasks))
+		for i, task := range column.Tasks {
+			protoTasks[i] = taskToProto(task)
+		}
+
+		protoBoard.Columns[idx] = &todov1.BoardColumn{
+			Status:        column.Status,
+			Title:         column.Title,
+			WipLimit:      int32(column.WIPLimit),
+			Count:         int32(column.Count),
+			OverWipLimit:  column.OverWIPLimit,
+			Tasks:         protoTasks,
+			NextPageToken: column.NextPageToken,
+
+			EstimateMinutes:  int32(column.EstimateMinutes),
+			RemainingMinutes: int32(column.RemainingMinutes),
+		}
+	}
+
+	return protoBoard, nil
+}
+
+func projectError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrProjectNotFound):
+		return status.Error(codes.NotFound, "project not found")
+	case errors.Is(err, my_err.ErrProjectExists):
+		return status.Error(codes.AlreadyExists, "project with given name already exists")
+	case errors.Is(err, my_err.ErrInvalidProjectName),
+		errors.Is(err, my_err.ErrInvalidStatus),
+		errors.Is(err, my_err.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.Error(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func projectToProto(project *models.Project) *todov1.Project {
+	protoProject := &todov1.Project{
+		Id:        project.ID.String(),
+		Name:      project.Name,
+		CreatedAt: project.CreatedAt.Format(time.RFC3339),
+	}
+
+	if project.Effort != nil {
+		protoProject.Effort = projectEffortToProto(project.Effort)
+	}
+
+	return protoProject
+}
@@ -0,0 +1,78 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type QuickAddService interface {
+	QuickAdd(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, text, timezone string) (*models.Task, *models.QuickAddParse, error)
+}
+
+func (s *serverAPI) QuickAdd(ctx context.Context, req *todov1.QuickAddRequest) (*todov1.QuickAddResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(req.GetProjectId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	task, parse, err := s.service.QuickAdd(ctx, authorID, nullUID(projectID), req.GetText(), req.GetTimezone())
+	if err != nil {
+		switch {
+		case errors.Is(err, my_err.ErrEmptyTitle):
+			return nil, status.Error(codes.InvalidArgument, "text has no title left once parsed")
+		case errors.Is(err, my_err.ErrInvalidTimezone), errors.Is(err, my_err.ErrInvalidQuickAdd):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, my_err.ErrProjectNotFound):
+			return nil, status.Error(codes.NotFound, "project not found")
+		case errors.Is(err, my_err.ErrUserDeleted):
+			return nil, status.Error(codes.Unauthenticated, "user was deleted")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &todov1.QuickAddResponse{
+		Task:  taskToProto(task),
+		Parse: quickAddParseToProto(parse),
+	}, nil
+}
+
+func quickAddParseToProto(parse *models.QuickAddParse) *todov1.QuickAddParse {
+	protoParse := &todov1.QuickAddParse{
+		Title:      parse.Title,
+		DueDate:    parse.DueDate,
+		Recurrence: parse.Recurrence,
+		Tags:       parse.Tags,
+		Priority:   parse.Priority,
+		Assignee:   parse.Assignee,
+		Tokens:     make([]*todov1.QuickAddToken, len(parse.Tokens)),
+	}
+
+	if !parse.Deadline.IsZero() {
+		protoParse.Deadline = timestamppb.New(parse.Deadline)
+	}
+
+	for idx, token := range parse.Tokens {
+		protoParse.Tokens[idx] = &todov1.QuickAddToken{
+			Kind:  token.Kind,
+			Text:  token.Text,
+			Value: token.Value,
+		}
+	}
+
+	return protoParse
+}
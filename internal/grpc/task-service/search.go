@@ -0,0 +1,49 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func (s *serverAPI) SearchTasks(ctx context.Context, req *todov1.SearchTasksRequest) (*todov1.SearchTasksResponse, error) {
+	if strings.TrimSpace(req.GetQuery()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is empty")
+	}
+
+	if req.GetLimit() < 0 || req.GetOffset() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit and offset must not be negative")
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	hits, err := s.service.SearchTasks(ctx, authorID, req.GetQuery(), int(req.GetLimit()), int(req.GetOffset()))
+	if err != nil {
+		if errors.Is(err, my_err.ErrEmptyQuery) {
+			return nil, status.Error(codes.InvalidArgument, "query has no searchable words")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	protoHits := make([]*todov1.SearchHit, len(hits))
+	for idx, hit := range hits {
+		protoHits[idx] = &todov1.SearchHit{
+			Task:           taskToProto(hit.Task),
+			Rank:           hit.Rank,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.Snippet,
+		}
+	}
+
+	return &todov1.SearchTasksResponse{Hits: protoHits}, nil
+}
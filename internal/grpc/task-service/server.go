@@ -2,6 +2,7 @@ package task_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
 	"github.com/SlashLight/todo-list/internal/domain/models"
@@ -16,10 +18,30 @@ import (
 )
 
 type Service interface {
-	CreateTask(ctx context.Context, authorID uuid.UUID, title, description string, deadline time.Time) (string, error)
+	CreateTask(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, title, description, status string, deadline time.Time, dueDate string) (string, error)
 	GetTasks(ctx context.Context, authorID uuid.UUID) ([]*models.Task, error)
 	UpdateTask(ctx context.Context, newTask *models.Task) error
 	DeleteTask(ctx context.Context, taskID, authorID uuid.UUID) error
+	MoveTask(ctx context.Context, taskID, authorID, beforeID, afterID uuid.UUID, status string) (string, error)
+	SearchTasks(ctx context.Context, authorID uuid.UUID, query string, limit, offset int) ([]*models.SearchHit, error)
+
+	ChecklistService
+	ViewService
+	AttachmentService
+	ProjectService
+	TimeTrackingService
+	EffortService
+	QuickAddService
+	TemplateService
+	BatchService
+	ExportService
+	ImportService
+	CalendarService
+	CalDAVService
+	WatchService
+	SyncService
+	WebhookService
+	AccountService
 }
 
 type serverAPI struct {
@@ -29,23 +51,18 @@ type serverAPI struct {
 
 func RegisterServerAPI(gRPC *grpc.Server, service Service) {
 	todov1.RegisterTodoServer(gRPC, &serverAPI{service: service})
+	todov1.RegisterWebhooksServer(gRPC, &webhooksAPI{service: service})
+	todov1.RegisterAccountsServer(gRPC, &accountsAPI{service: service})
 }
 
-const timeLayout = time.RFC1123
-
 func (s *serverAPI) CreateTask(ctx context.Context, req *todov1.NewTaskRequest) (*todov1.NewTaskResponse, error) {
 	if req.GetTitle() == "" {
 		return nil, status.Error(codes.InvalidArgument, "title is empty")
 	}
 
-	var deadline time.Time
-	if req.GetDeadline() != "" {
-		var err error
-		deadline, err = time.Parse(timeLayout, req.GetDeadline())
-
-		if err != nil {
-			return nil, status.Error(codes.InvalidArgument, "deadline has invalid format")
-		}
+	deadline, err := deadlineFromProto(req.GetDeadline())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "deadline is out of range")
 	}
 
 	authorID, err := validateUID(req.GetAuthorId())
@@ -53,15 +70,28 @@ func (s *serverAPI) CreateTask(ctx context.Context, req *todov1.NewTaskRequest)
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
 	}
 
-	taskID, err := s.service.CreateTask(ctx, authorID, req.GetTitle(), req.GetDescription(), deadline)
+	projectID, err := validateOptionalUID(req.GetProjectId())
 	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	taskID, err := s.service.CreateTask(ctx, authorID, nullUID(projectID), req.GetTitle(), req.GetDescription(), req.GetStatus(), deadline, req.GetDueDate())
+	if err != nil {
+		switch {
+		case errors.Is(err, my_err.ErrProjectNotFound):
+			return nil, status.Error(codes.NotFound, "project not found")
+		case errors.Is(err, my_err.ErrInvalidDueDate), errors.Is(err, my_err.ErrInvalidStatus):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, my_err.ErrUserDeleted):
+			return nil, status.Error(codes.Unauthenticated, "user was deleted")
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
 	return &todov1.NewTaskResponse{TaskId: taskID}, nil
 }
 
-func (s *serverAPI) GetTasks(ctx context.Context, req *todov1.TaskRequest) (*todov1.TaskResponse, error) {
+func (s *serverAPI) GetTask(ctx context.Context, req *todov1.TaskRequest) (*todov1.TaskResponse, error) {
 	authorID, err := validateUID(req.GetAuthorId())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
@@ -74,19 +104,57 @@ func (s *serverAPI) GetTasks(ctx context.Context, req *todov1.TaskRequest) (*tod
 
 	protoTasks := make([]*todov1.Task, len(tasks))
 	for idx, task := range tasks {
-		protoTasks[idx] = &todov1.Task{
-			Id:          task.ID.String(),
-			AuthorId:    task.AuthorID.String(),
-			Title:       task.Title,
-			Description: task.Description,
-			Status:      task.Status,
-			Deadline:    task.Deadline.Format(timeLayout),
-		}
+		protoTasks[idx] = taskToProto(task)
 	}
 
 	return &todov1.TaskResponse{Tasks: protoTasks}, nil
 }
 
+func taskToProto(task *models.Task) *todov1.Task {
+	protoTask := &todov1.Task{
+		Id:             task.ID.String(),
+		AuthorId:       task.AuthorID.String(),
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         task.Status,
+		DueDate:        task.DueDate,
+		Priority:       task.Priority,
+		Tags:           task.Tags,
+		Recurrence:     task.Recurrence,
+		Assignee:       task.Assignee,
+		Position:       task.Position,
+		TrackedSeconds: task.TrackedSeconds,
+		Checklist:      checklistToProto(task.Checklist),
+	}
+
+	if task.ProjectID.Valid {
+		protoTask.ProjectId = task.ProjectID.UUID.String()
+	}
+
+	if task.ParentID.Valid {
+		protoTask.ParentId = task.ParentID.UUID.String()
+	}
+
+	if !task.Deadline.IsZero() {
+		protoTask.Deadline = timestamppb.New(task.Deadline)
+	}
+
+	if task.EstimateMinutes != nil {
+		estimate := int32(*task.EstimateMinutes)
+		protoTask.EstimateMinutes = &estimate
+	}
+
+	if task.Effort != nil {
+		protoTask.Effort = effortToProto(*task.Effort)
+	}
+
+	if task.ChecklistSummary != nil {
+		protoTask.ChecklistSummary = checklistSummaryToProto(*task.ChecklistSummary)
+	}
+
+	return protoTask
+}
+
 func (s *serverAPI) DeleteTask(ctx context.Context, req *todov1.DeleteRequest) (*todov1.EmptyResponse, error) {
 	id, err := validateUID(req.GetTaskId())
 	if err != nil {
@@ -100,11 +168,56 @@ func (s *serverAPI) DeleteTask(ctx context.Context, req *todov1.DeleteRequest) (
 
 	err = s.service.DeleteTask(ctx, id, authorID)
 	if err != nil {
-		//TODO ...
+		if errors.Is(err, my_err.ErrTaskNotFound) {
+			return nil, status.Error(codes.NotFound, "task not found")
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
-	return nil, nil
+	return &todov1.EmptyResponse{}, nil
+}
+
+func (s *serverAPI) MoveTask(ctx context.Context, req *todov1.MoveTaskRequest) (*todov1.MoveTaskResponse, error) {
+	id, err := validateUID(req.GetTaskId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid task ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	beforeID, err := validateOptionalUID(req.GetBeforeId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid before ID: %s", err))
+	}
+
+	afterID, err := validateOptionalUID(req.GetAfterId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid after ID: %s", err))
+	}
+
+	if beforeID == id || afterID == id {
+		return nil, status.Error(codes.InvalidArgument, "task cannot be its own neighbour")
+	}
+
+	position, err := s.service.MoveTask(ctx, id, authorID, beforeID, afterID, req.GetStatus())
+	if err != nil {
+		switch {
+		case errors.Is(err, my_err.ErrTaskNotFound):
+			return nil, status.Error(codes.NotFound, "task not found")
+		case errors.Is(err, my_err.ErrInvalidNeighbour):
+			return nil, status.Error(codes.InvalidArgument, "neighbour task is not in the same list")
+		case errors.Is(err, my_err.ErrStaleNeighbours):
+			return nil, status.Error(codes.FailedPrecondition, "neighbour tasks are no longer adjacent")
+		case errors.Is(err, my_err.ErrInvalidStatus):
+			return nil, status.Error(codes.InvalidArgument, "status is not a board column")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &todov1.MoveTaskResponse{Position: position}, nil
 }
 
 func (s *serverAPI) UpdateTask(ctx context.Context, req *todov1.UpdateRequest) (*todov1.EmptyResponse, error) {
@@ -115,15 +228,20 @@ func (s *serverAPI) UpdateTask(ctx context.Context, req *todov1.UpdateRequest) (
 
 	err = s.service.UpdateTask(ctx, newTask)
 	if err != nil {
-		//TODO ..
+		switch {
+		case errors.Is(err, my_err.ErrTaskNotFound):
+			return nil, status.Error(codes.NotFound, "task not found")
+		case errors.Is(err, my_err.ErrInvalidDueDate), errors.Is(err, my_err.ErrInvalidStatus):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
-	return nil, nil
+	return &todov1.EmptyResponse{}, nil
 }
 
 func validateNewTask(req *todov1.UpdateRequest) (*models.Task, error) {
-	var newTask *models.Task
+	newTask := &models.Task{}
 
 	id, err := validateUID(req.GetId())
 	if err != nil {
@@ -137,15 +255,13 @@ func validateNewTask(req *todov1.UpdateRequest) (*models.Task, error) {
 
 	newTask.ID = id
 	newTask.AuthorID = authorID
-	if req.GetNewDeadline() != "" {
-		newDeadline, err := time.Parse(timeLayout, req.GetNewDeadline())
-		if err != nil {
-			return nil, status.Error(codes.InvalidArgument, "wrong deadline format")
-		}
-
-		newTask.Deadline = newDeadline
+	newTask.Deadline, err = deadlineFromProto(req.GetNewDeadline())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "deadline is out of range")
 	}
 
+	newTask.DueDate = req.GetNewDueDate()
+
 	newTask.Description = req.GetNewDescription()
 	newTask.Title = req.GetNewTitle()
 	newTask.Status = req.GetNewStatus()
@@ -153,6 +269,19 @@ func validateNewTask(req *todov1.UpdateRequest) (*models.Task, error) {
 	return newTask, nil
 }
 
+// deadlineFromProto converts an optional timestamp, nil yields the zero time of a task without a deadline.
+func deadlineFromProto(ts *timestamppb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, err
+	}
+
+	return ts.AsTime(), nil
+}
+
 func validateUID(UIDString string) (uuid.UUID, error) {
 	if UIDString == "" {
 		return uuid.Nil, my_err.ErrEmptyField
@@ -165,3 +294,17 @@ func validateUID(UIDString string) (uuid.UUID, error) {
 
 	return authorID, nil
 }
+
+// validateOptionalUID is validateUID for fields that may be left empty, which yields uuid.Nil.
+func validateOptionalUID(UIDString string) (uuid.UUID, error) {
+	if UIDString == "" {
+		return uuid.Nil, nil
+	}
+
+	return validateUID(UIDString)
+}
+
+// nullUID maps the uuid.Nil of an empty optional field to an invalid uuid.NullUUID.
+func nullUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
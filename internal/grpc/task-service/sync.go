@@ -0,0 +1,153 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type SyncService interface {
+	Sync(ctx context.Context, authorID uuid.UUID, syncToken int64, clientTime time.Time, changes []models.TaskChange) (*models.SyncResult, error)
+}
+
+func (s *serverAPI) Sync(ctx context.Context, req *todov1.SyncRequest) (*todov1.SyncResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	clientTime, err := deadlineFromProto(req.GetClientTime())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "client time is out of range")
+	}
+
+	changes := make([]models.TaskChange, len(req.GetChanges()))
+	for idx, protoChange := range req.GetChanges() {
+		change, err := taskChangeFromProto(protoChange)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("change %d: %s", idx, err))
+		}
+		changes[idx] = change
+	}
+
+	result, err := s.service.Sync(ctx, authorID, req.GetSyncToken(), clientTime, changes)
+	if err != nil {
+		return nil, syncStatus(err).Err()
+	}
+
+	return syncResultToProto(result), nil
+}
+
+func taskChangeFromProto(protoChange *todov1.TaskChange) (models.TaskChange, error) {
+	taskID, err := validateUID(protoChange.GetTaskId())
+	if err != nil {
+		return models.TaskChange{}, fmt.Errorf("invalid task ID: %w", err)
+	}
+
+	changedAt, err := deadlineFromProto(protoChange.GetChangedAt())
+	if err != nil {
+		return models.TaskChange{}, errors.New("change time is out of range")
+	}
+
+	change := models.TaskChange{
+		ChangeID:  protoChange.GetChangeId(),
+		TaskID:    taskID,
+		Deleted:   protoChange.GetDeleted(),
+		Fields:    protoChange.GetFields(),
+		ChangedAt: changedAt,
+	}
+
+	if protoTask := protoChange.GetTask(); protoTask != nil {
+		projectID, err := validateOptionalUID(protoTask.GetProjectId())
+		if err != nil {
+			return models.TaskChange{}, fmt.Errorf("invalid project ID: %w", err)
+		}
+
+		deadline, err := deadlineFromProto(protoTask.GetDeadline())
+		if err != nil {
+			return models.TaskChange{}, errors.New("deadline is out of range")
+		}
+
+		change.Task = &models.Task{
+			ProjectID:   nullUID(projectID),
+			Title:       protoTask.GetTitle(),
+			Description: protoTask.GetDescription(),
+			Status:      protoTask.GetStatus(),
+			Deadline:    deadline,
+			DueDate:     protoTask.GetDueDate(),
+			Priority:    protoTask.GetPriority(),
+			Tags:        protoTask.GetTags(),
+			Recurrence:  protoTask.GetRecurrence(),
+		}
+	}
+
+	return change, nil
+}
+
+func syncResultToProto(result *models.SyncResult) *todov1.SyncResponse {
+	resp := &todov1.SyncResponse{
+		SyncToken:  result.SyncToken,
+		Full:       result.Full,
+		Changed:    make([]*todov1.Task, len(result.Changed)),
+		Deleted:    make([]string, len(result.Deleted)),
+		Results:    make([]*todov1.TaskChangeResult, len(result.Results)),
+		ServerTime: timestamppb.New(result.ServerTime),
+	}
+
+	for idx, task := range result.Changed {
+		resp.Changed[idx] = taskToProto(task)
+	}
+
+	for idx, taskID := range result.Deleted {
+		resp.Deleted[idx] = taskID.String()
+	}
+
+	for idx, changeResult := range result.Results {
+		resp.Results[idx] = &todov1.TaskChangeResult{
+			ChangeId:  changeResult.ChangeID,
+			TaskId:    changeResult.TaskID.String(),
+			Status:    changeResult.Status,
+			Conflicts: changeResult.Conflicts,
+		}
+
+		if changeResult.Err != nil {
+			st := syncStatus(changeResult.Err)
+			resp.Results[idx].Code = int32(st.Code())
+			resp.Results[idx].Error = st.Message()
+		}
+	}
+
+	return resp
+}
+
+// syncStatus maps the error of a sync, or of one of its changes, onto a gRPC status.
+func syncStatus(err error) *status.Status {
+	switch {
+	case errors.Is(err, my_err.ErrProjectNotFound):
+		return status.New(codes.NotFound, err.Error())
+	case errors.Is(err, my_err.ErrTaskIDTaken):
+		return status.New(codes.AlreadyExists, err.Error())
+	case errors.Is(err, my_err.ErrInvalidSyncToken):
+		// Like for CalDAV, OutOfRange tells a token that is not valid apart from other invalid arguments.
+		return status.New(codes.OutOfRange, "invalid sync token")
+	case errors.Is(err, my_err.ErrInvalidSync),
+		errors.Is(err, my_err.ErrEmptyTitle),
+		errors.Is(err, my_err.ErrInvalidDueDate),
+		errors.Is(err, my_err.ErrInvalidStatus):
+		return status.New(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.New(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.New(codes.Internal, "internal error")
+	}
+}
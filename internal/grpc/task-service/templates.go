@@ -0,0 +1,156 @@
+package task_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name, rawTasks string) (*models.Template, error)
+	ListTemplates(ctx context.Context, authorID uuid.UUID) ([]*models.Template, error)
+	DeleteTemplate(ctx context.Context, templateID, authorID uuid.UUID) error
+	InstantiateTemplate(ctx context.Context, templateID, authorID uuid.UUID, projectID uuid.NullUUID, start, timezone string, variables map[string]string) ([]*models.Task, error)
+}
+
+func (s *serverAPI) CreateTemplate(ctx context.Context, req *todov1.CreateTemplateRequest) (*todov1.Template, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is empty")
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(req.GetProjectId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	template, err := s.service.CreateTemplate(ctx, authorID, nullUID(projectID), req.GetName(), req.GetTasks())
+	if err != nil {
+		return nil, templateError(err)
+	}
+
+	return templateToProto(template)
+}
+
+func (s *serverAPI) ListTemplates(ctx context.Context, req *todov1.ListTemplatesRequest) (*todov1.ListTemplatesResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	templates, err := s.service.ListTemplates(ctx, authorID)
+	if err != nil {
+		return nil, templateError(err)
+	}
+
+	protoTemplates := make([]*todov1.Template, len(templates))
+	for idx, template := range templates {
+		protoTemplates[idx], err = templateToProto(template)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &todov1.ListTemplatesResponse{Templates: protoTemplates}, nil
+}
+
+func (s *serverAPI) DeleteTemplate(ctx context.Context, req *todov1.TemplateRequest) (*todov1.EmptyResponse, error) {
+	templateID, err := validateUID(req.GetTemplateId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid template ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	if err := s.service.DeleteTemplate(ctx, templateID, authorID); err != nil {
+		return nil, templateError(err)
+	}
+
+	return &todov1.EmptyResponse{}, nil
+}
+
+func (s *serverAPI) InstantiateTemplate(ctx context.Context, req *todov1.InstantiateTemplateRequest) (*todov1.TaskResponse, error) {
+	templateID, err := validateUID(req.GetTemplateId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid template ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(req.GetProjectId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	tasks, err := s.service.InstantiateTemplate(ctx, templateID, authorID, nullUID(projectID), req.GetStart(), req.GetTimezone(), req.GetVariables())
+	if err != nil {
+		return nil, templateError(err)
+	}
+
+	protoTasks := make([]*todov1.Task, len(tasks))
+	for idx, task := range tasks {
+		protoTasks[idx] = taskToProto(task)
+	}
+
+	return &todov1.TaskResponse{Tasks: protoTasks}, nil
+}
+
+func templateError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrTemplateNotFound):
+		return status.Error(codes.NotFound, "template not found")
+	case errors.Is(err, my_err.ErrProjectNotFound):
+		return status.Error(codes.NotFound, "project not found")
+	case errors.Is(err, my_err.ErrTemplateExists):
+		return status.Error(codes.AlreadyExists, "template with given name already exists")
+	case errors.Is(err, my_err.ErrInvalidTemplate), errors.Is(err, my_err.ErrInvalidTimezone):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrEmptyTitle):
+		return status.Error(codes.InvalidArgument, "a task title is empty once the variables are filled in")
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.Error(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func templateToProto(template *models.Template) (*todov1.Template, error) {
+	tasks, err := json.Marshal(template.Tasks)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	protoTemplate := &todov1.Template{
+		Id:        template.ID.String(),
+		Name:      template.Name,
+		Tasks:     string(tasks),
+		Variables: template.Variables,
+		CreatedAt: template.CreatedAt.Format(time.RFC3339),
+	}
+
+	if template.ProjectID.Valid {
+		protoTemplate.ProjectId = template.ProjectID.UUID.String()
+	}
+
+	return protoTemplate, nil
+}
@@ -0,0 +1,200 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type TimeTrackingService interface {
+	StartTimer(ctx context.Context, taskID, authorID uuid.UUID, note string) (*models.TimeEntry, error)
+	StopTimer(ctx context.Context, authorID uuid.UUID) (*models.TimeEntry, error)
+	LogTime(ctx context.Context, taskID, authorID uuid.UUID, startedAt time.Time, duration time.Duration, note string) (*models.TimeEntry, error)
+	GetTimeEntries(ctx context.Context, taskID, authorID uuid.UUID) ([]*models.TimeEntry, error)
+	TimeReport(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, from, to time.Time) (*models.TimeReport, error)
+}
+
+func (s *serverAPI) StartTimer(ctx context.Context, req *todov1.StartTimerRequest) (*todov1.TimeEntry, error) {
+	taskID, err := validateUID(req.GetTaskId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid task ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	entry, err := s.service.StartTimer(ctx, taskID, authorID, req.GetNote())
+	if err != nil {
+		return nil, timeTrackingError(err)
+	}
+
+	return timeEntryToProto(entry), nil
+}
+
+func (s *serverAPI) StopTimer(ctx context.Context, req *todov1.StopTimerRequest) (*todov1.TimeEntry, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	entry, err := s.service.StopTimer(ctx, authorID)
+	if err != nil {
+		return nil, timeTrackingError(err)
+	}
+
+	return timeEntryToProto(entry), nil
+}
+
+func (s *serverAPI) LogTime(ctx context.Context, req *todov1.LogTimeRequest) (*todov1.TimeEntry, error) {
+	taskID, err := validateUID(req.GetTaskId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid task ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	var startedAt time.Time
+	if req.GetStartedAt() != "" {
+		startedAt, err = time.Parse(time.RFC3339, req.GetStartedAt())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "started at has invalid format")
+		}
+	}
+
+	duration := time.Duration(req.GetDurationSeconds()) * time.Second
+
+	entry, err := s.service.LogTime(ctx, taskID, authorID, startedAt, duration, req.GetNote())
+	if err != nil {
+		return nil, timeTrackingError(err)
+	}
+
+	return timeEntryToProto(entry), nil
+}
+
+func (s *serverAPI) ListTimeEntries(ctx context.Context, req *todov1.ListTimeEntriesRequest) (*todov1.ListTimeEntriesResponse, error) {
+	taskID, err := validateUID(req.GetTaskId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid task ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	entries, err := s.service.GetTimeEntries(ctx, taskID, authorID)
+	if err != nil {
+		return nil, timeTrackingError(err)
+	}
+
+	protoEntries := make([]*todov1.TimeEntry, len(entries))
+	for idx, entry := range entries {
+		protoEntries[idx] = timeEntryToProto(entry)
+	}
+
+	return &todov1.ListTimeEntriesResponse{Entries: protoEntries}, nil
+}
+
+func (s *serverAPI) GetTimeReport(ctx context.Context, req *todov1.TimeReportRequest) (*todov1.TimeReport, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(req.GetProjectId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	from, err := time.Parse(time.RFC3339, req.GetFrom())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from has invalid format")
+	}
+
+	to, err := time.Parse(time.RFC3339, req.GetTo())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to has invalid format")
+	}
+
+	report, err := s.service.TimeReport(ctx, authorID, nullUID(projectID), from, to)
+	if err != nil {
+		return nil, timeTrackingError(err)
+	}
+
+	protoReport := &todov1.TimeReport{
+		From:         report.From.Format(time.RFC3339),
+		To:           report.To.Format(time.RFC3339),
+		TotalSeconds: report.TotalSeconds,
+		Projects:     make([]*todov1.ProjectTime, len(report.Projects)),
+	}
+
+	for idx, project := range report.Projects {
+		protoProject := &todov1.ProjectTime{
+			ProjectName: project.ProjectName,
+			Seconds:     project.Seconds,
+			Tasks:       make([]*todov1.TaskTime, len(project.Tasks)),
+		}
+		if project.ProjectID.Valid {
+			protoProject.ProjectId = project.ProjectID.UUID.String()
+		}
+
+		for i, task := range project.Tasks {
+			protoProject.Tasks[i] = &todov1.TaskTime{
+				TaskId:  task.TaskID.String(),
+				Title:   task.Title,
+				Seconds: task.Seconds,
+			}
+		}
+
+		protoReport.Projects[idx] = protoProject
+	}
+
+	return protoReport, nil
+}
+
+func timeTrackingError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrTaskNotFound):
+		return status.Error(codes.NotFound, "task not found")
+	case errors.Is(err, my_err.ErrProjectNotFound):
+		return status.Error(codes.NotFound, "project not found")
+	case errors.Is(err, my_err.ErrTimerRunning):
+		return status.Error(codes.AlreadyExists, "a timer is already running")
+	case errors.Is(err, my_err.ErrNoRunningTimer):
+		return status.Error(codes.FailedPrecondition, "no timer is running")
+	case errors.Is(err, my_err.ErrInvalidTimeEntry), errors.Is(err, my_err.ErrInvalidDateRange):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func timeEntryToProto(entry *models.TimeEntry) *todov1.TimeEntry {
+	protoEntry := &todov1.TimeEntry{
+		Id:              entry.ID.String(),
+		TaskId:          entry.TaskID.String(),
+		StartedAt:       entry.StartedAt.Format(time.RFC3339),
+		DurationSeconds: entry.DurationSeconds,
+		Note:            entry.Note,
+	}
+
+	if !entry.Running() {
+		protoEntry.EndedAt = entry.EndedAt.Format(time.RFC3339)
+	}
+
+	return protoEntry
+}
@@ -0,0 +1,132 @@
+package task_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type ViewService interface {
+	CreateView(ctx context.Context, authorID uuid.UUID, name, rawQuery string) (*models.View, error)
+	ListViews(ctx context.Context, authorID uuid.UUID) ([]*models.View, error)
+	RunView(ctx context.Context, authorID uuid.UUID, viewID, timezone string) ([]*models.Task, error)
+	DeleteView(ctx context.Context, authorID uuid.UUID, viewID string) error
+}
+
+func (s *serverAPI) CreateView(ctx context.Context, req *todov1.CreateViewRequest) (*todov1.View, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is empty")
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	view, err := s.service.CreateView(ctx, authorID, req.GetName(), req.GetQuery())
+	if err != nil {
+		return nil, viewError(err)
+	}
+
+	return viewToProto(view)
+}
+
+func (s *serverAPI) ListViews(ctx context.Context, req *todov1.ListViewsRequest) (*todov1.ListViewsResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	views, err := s.service.ListViews(ctx, authorID)
+	if err != nil {
+		return nil, viewError(err)
+	}
+
+	protoViews := make([]*todov1.View, len(views))
+	for idx, view := range views {
+		protoViews[idx], err = viewToProto(view)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &todov1.ListViewsResponse{Views: protoViews}, nil
+}
+
+func (s *serverAPI) RunView(ctx context.Context, req *todov1.RunViewRequest) (*todov1.TaskResponse, error) {
+	if req.GetViewId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "view ID is empty")
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	tasks, err := s.service.RunView(ctx, authorID, req.GetViewId(), req.GetTimezone())
+	if err != nil {
+		return nil, viewError(err)
+	}
+
+	protoTasks := make([]*todov1.Task, len(tasks))
+	for idx, task := range tasks {
+		protoTasks[idx] = taskToProto(task)
+	}
+
+	return &todov1.TaskResponse{Tasks: protoTasks}, nil
+}
+
+func (s *serverAPI) DeleteView(ctx context.Context, req *todov1.ViewRequest) (*todov1.EmptyResponse, error) {
+	if req.GetViewId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "view ID is empty")
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	if err := s.service.DeleteView(ctx, authorID, req.GetViewId()); err != nil {
+		return nil, viewError(err)
+	}
+
+	return &todov1.EmptyResponse{}, nil
+}
+
+func viewError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrViewNotFound):
+		return status.Error(codes.NotFound, "view not found")
+	case errors.Is(err, my_err.ErrViewExists):
+		return status.Error(codes.AlreadyExists, "view already exists")
+	case errors.Is(err, my_err.ErrInvalidViewQuery), errors.Is(err, my_err.ErrInvalidTimezone):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.Error(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func viewToProto(view *models.View) (*todov1.View, error) {
+	query, err := json.Marshal(view.Query)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &todov1.View{
+		Id:      view.ID,
+		Name:    view.Name,
+		Query:   string(query),
+		Builtin: view.Builtin,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type WatchService interface {
+	WatchTasks(ctx context.Context, authorID uuid.UUID, after uint64, resetOnExpiry bool, send func(models.TaskEvent) error) error
+}
+
+func (s *serverAPI) WatchTasks(req *todov1.WatchTasksRequest, stream todov1.Todo_WatchTasksServer) error {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	err = s.service.WatchTasks(stream.Context(), authorID, req.GetAfterSequence(), req.GetResetOnExpiry(), func(event models.TaskEvent) error {
+		return stream.Send(taskEventToProto(event))
+	})
+
+	return watchError(err)
+}
+
+func taskEventToProto(event models.TaskEvent) *todov1.TaskEvent {
+	protoEvent := &todov1.TaskEvent{
+		Sequence:   event.Seq,
+		Type:       event.Type,
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	}
+	if event.TaskID != uuid.Nil {
+		protoEvent.TaskId = event.TaskID.String()
+	}
+	if event.Task != nil {
+		protoEvent.Task = taskToProto(event.Task)
+	}
+
+	return protoEvent
+}
+
+func watchError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrEventsExpired):
+		// OutOfRange tells the watcher to reload its tasks before watching again.
+		return status.Error(codes.OutOfRange, "events after the sequence number are no longer kept")
+	case errors.Is(err, my_err.ErrWatcherLagging), errors.Is(err, my_err.ErrWatchersClosed):
+		// Unavailable tells the watcher to resume after the last event it got.
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "watch canceled")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,231 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	todov1 "github.com/SlashLight/todo-list/api/gen/go/todo"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, rawURL string, eventTypes []string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, authorID uuid.UUID) ([]*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhookID, authorID uuid.UUID, projectID uuid.NullUUID, rawURL string, eventTypes []string, enabled bool) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID, authorID uuid.UUID) error
+	ListWebhookDeliveries(ctx context.Context, webhookID, authorID uuid.UUID, limit int) ([]*models.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID, authorID uuid.UUID) (*models.WebhookDelivery, error)
+}
+
+// webhooksAPI serves the Webhooks service next to Todo.
+type webhooksAPI struct {
+	todov1.UnimplementedWebhooksServer
+	service Service
+}
+
+func (s *webhooksAPI) CreateWebhook(ctx context.Context, req *todov1.CreateWebhookRequest) (*todov1.Webhook, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(req.GetProjectId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	webhook, err := s.service.CreateWebhook(ctx, authorID, nullUID(projectID), req.GetUrl(), req.GetEventTypes())
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	protoWebhook := webhookToProto(webhook)
+	protoWebhook.Secret = webhook.Secret
+
+	return protoWebhook, nil
+}
+
+func (s *webhooksAPI) ListWebhooks(ctx context.Context, req *todov1.ListWebhooksRequest) (*todov1.ListWebhooksResponse, error) {
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	webhooks, err := s.service.ListWebhooks(ctx, authorID)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	protoWebhooks := make([]*todov1.Webhook, len(webhooks))
+	for idx, webhook := range webhooks {
+		protoWebhooks[idx] = webhookToProto(webhook)
+	}
+
+	return &todov1.ListWebhooksResponse{Webhooks: protoWebhooks}, nil
+}
+
+func (s *webhooksAPI) UpdateWebhook(ctx context.Context, req *todov1.UpdateWebhookRequest) (*todov1.Webhook, error) {
+	webhookID, err := validateUID(req.GetWebhookId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid webhook ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	projectID, err := validateOptionalUID(req.GetProjectId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid project ID: %s", err))
+	}
+
+	webhook, err := s.service.UpdateWebhook(ctx, webhookID, authorID, nullUID(projectID), req.GetUrl(), req.GetEventTypes(), req.GetEnabled())
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	return webhookToProto(webhook), nil
+}
+
+func (s *webhooksAPI) DeleteWebhook(ctx context.Context, req *todov1.WebhookRequest) (*todov1.EmptyResponse, error) {
+	webhookID, err := validateUID(req.GetWebhookId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid webhook ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	if err := s.service.DeleteWebhook(ctx, webhookID, authorID); err != nil {
+		return nil, webhookError(err)
+	}
+
+	return &todov1.EmptyResponse{}, nil
+}
+
+func (s *webhooksAPI) ListWebhookDeliveries(ctx context.Context, req *todov1.ListWebhookDeliveriesRequest) (*todov1.ListWebhookDeliveriesResponse, error) {
+	webhookID, err := validateUID(req.GetWebhookId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid webhook ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	deliveries, err := s.service.ListWebhookDeliveries(ctx, webhookID, authorID, int(req.GetLimit()))
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	protoDeliveries := make([]*todov1.WebhookDelivery, len(deliveries))
+	for idx, delivery := range deliveries {
+		protoDeliveries[idx] = webhookDeliveryToProto(delivery)
+	}
+
+	return &todov1.ListWebhookDeliveriesResponse{Deliveries: protoDeliveries}, nil
+}
+
+func (s *webhooksAPI) RedeliverWebhookDelivery(ctx context.Context, req *todov1.WebhookDeliveryRequest) (*todov1.WebhookDelivery, error) {
+	webhookID, err := validateUID(req.GetWebhookId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid webhook ID: %s", err))
+	}
+
+	deliveryID, err := validateUID(req.GetDeliveryId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid delivery ID: %s", err))
+	}
+
+	authorID, err := validateUID(req.GetAuthorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid author ID: %s", err))
+	}
+
+	delivery, err := s.service.RedeliverWebhookDelivery(ctx, webhookID, deliveryID, authorID)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	return webhookDeliveryToProto(delivery), nil
+}
+
+func webhookError(err error) error {
+	switch {
+	case errors.Is(err, my_err.ErrWebhookNotFound):
+		return status.Error(codes.NotFound, "webhook not found")
+	case errors.Is(err, my_err.ErrWebhookDeliveryNotFound):
+		return status.Error(codes.NotFound, "webhook delivery not found")
+	case errors.Is(err, my_err.ErrProjectNotFound):
+		return status.Error(codes.NotFound, "project not found")
+	case errors.Is(err, my_err.ErrWebhookDisabled):
+		return status.Error(codes.FailedPrecondition, "webhook is disabled")
+	case errors.Is(err, my_err.ErrInvalidWebhook):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, my_err.ErrUserDeleted):
+		return status.Error(codes.Unauthenticated, "user was deleted")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// webhookToProto leaves out the secret, which only the response of CreateWebhook carries.
+func webhookToProto(webhook *models.Webhook) *todov1.Webhook {
+	protoWebhook := &todov1.Webhook{
+		Id:             webhook.ID.String(),
+		Url:            webhook.URL,
+		EventTypes:     webhook.EventTypes,
+		Enabled:        webhook.Enabled,
+		Failures:       int32(webhook.Failures),
+		DisabledReason: webhook.DisabledReason,
+		CreatedAt:      timestamppb.New(webhook.CreatedAt),
+	}
+
+	if webhook.ProjectID.Valid {
+		protoWebhook.ProjectId = webhook.ProjectID.UUID.String()
+	}
+
+	return protoWebhook
+}
+
+func webhookDeliveryToProto(delivery *models.WebhookDelivery) *todov1.WebhookDelivery {
+	protoDelivery := &todov1.WebhookDelivery{
+		Id:             delivery.ID.String(),
+		WebhookId:      delivery.WebhookID.String(),
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       int32(delivery.Attempts),
+		NextAttemptAt:  optionalTimestamp(delivery.NextAttemptAt),
+		LastAttemptAt:  optionalTimestamp(delivery.LastAttemptAt),
+		ResponseStatus: int32(delivery.ResponseStatus),
+		Error:          delivery.Error,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+	}
+
+	if delivery.RedeliveryOf.Valid {
+		protoDelivery.RedeliveryOf = delivery.RedeliveryOf.UUID.String()
+	}
+
+	return protoDelivery
+}
+
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (api *APIGateway) HandleUploadAttachment(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleUploadAttachment"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	taskID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	// Parts are streamed straight to the task service instead of being buffered by ParseMultipartForm.
+	reader, err := r.MultipartReader()
+	if err != nil {
+		log.Error("failed to read multipart body", slog.String("error", err.Error()))
+		http.Error(w, "Expected multipart/form-data body", http.StatusBadRequest)
+		return
+	}
+
+	var attachments []*models.Attachment
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Error("failed to read multipart part", slog.String("error", err.Error()))
+			http.Error(w, "Invalid multipart body", http.StatusBadRequest)
+			return
+		}
+
+		if part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		attachment, err := api.Task.UploadAttachment(r.Context(), taskID, sess.UserID, part.FileName(), part.Header.Get("Content-Type"), part)
+		part.Close()
+		if err != nil {
+			log.Error("failed to upload attachment", slog.String("error", err.Error()))
+			http.Error(w, "Failed to upload attachment", httpStatus(err))
+			return
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	if len(attachments) == 0 {
+		http.Error(w, "No files in request", http.StatusBadRequest)
+		return
+	}
+
+	log.Info("Attachments uploaded successfully", "count", len(attachments))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(attachments); err != nil {
+		log.Error("failed to encode attachments", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleListAttachments(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleListAttachments"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	taskID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	attachments, err := api.Task.ListAttachments(r.Context(), taskID, sess.UserID)
+	if err != nil {
+		log.Error("failed to list attachments", slog.String("error", err.Error()))
+		http.Error(w, "Failed to list attachments", httpStatus(err))
+		return
+	}
+
+	if len(attachments) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(attachments); err != nil {
+		log.Error("failed to encode attachments", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode attachments", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (api *APIGateway) HandleDownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleDownloadAttachment"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	attachmentID, err := uuid.Parse(r.PathValue("attachmentID"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	attachment, body, err := api.Task.DownloadAttachment(r.Context(), attachmentID, sess.UserID)
+	if err != nil {
+		log.Error("failed to download attachment", slog.String("error", err.Error()))
+		http.Error(w, "Failed to download attachment", httpStatus(err))
+		return
+	}
+	defer body.Close()
+
+	if attachment.TaskID.String() != r.PathValue("id") {
+		http.Error(w, "Failed to download attachment", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	w.Header().Set("ETag", strconv.Quote(attachment.SHA256))
+
+	if _, err := io.Copy(w, body); err != nil {
+		log.Error("failed to stream attachment", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleDeleteAttachment"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	attachmentID, err := uuid.Parse(r.PathValue("attachmentID"))
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.Task.DeleteAttachment(r.Context(), attachmentID, sess.UserID); err != nil {
+		log.Error("failed to delete attachment", slog.String("error", err.Error()))
+		http.Error(w, "Failed to delete attachment", httpStatus(err))
+		return
+	}
+
+	log.Info("Attachment deleted successfully", "attachmentID", attachmentID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/status"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// batchSelector picks the tasks of a batch either by "ids" or by a "filter" in the view query language.
+type batchSelector struct {
+	IDs      []uuid.UUID     `json:"ids"`
+	Filter   json.RawMessage `json:"filter"`
+	Timezone string          `json:"timezone"`
+	Atomic   bool            `json:"atomic"`
+}
+
+func (s batchSelector) selector(sess *models.Session) models.TaskSelector {
+	selector := models.TaskSelector{
+		IDs:      s.IDs,
+		Filter:   string(s.Filter),
+		Timezone: s.Timezone,
+	}
+	if selector.Timezone == "" {
+		selector.Timezone = sess.Timezone
+	}
+
+	return selector
+}
+
+type batchItemResult struct {
+	TaskID string `json:"task_id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HandleBatchCreateTasks creates up to 500 tasks from {"tasks": [...], "atomic": true}.
+// Each task takes the fields of /tasks/create plus "priority" and "tags".
+func (api *APIGateway) HandleBatchCreateTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleBatchCreateTasks"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		Tasks []struct {
+			Title       string    `json:"title"`
+			Description string    `json:"description"`
+			Deadline    string    `json:"deadline"`
+			DueDate     string    `json:"due_date"`
+			ProjectID   uuid.UUID `json:"project_id"`
+			Priority    string    `json:"priority"`
+			Tags        []string  `json:"tags"`
+		} `json:"tasks"`
+		Atomic bool `json:"atomic"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tasks := make([]*models.Task, len(req.Tasks))
+	for i, item := range req.Tasks {
+		deadline, err := parseDeadline(item.Deadline, sess.Location())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid deadline of task %d", i), http.StatusBadRequest)
+			return
+		}
+
+		tasks[i] = &models.Task{
+			Title:       item.Title,
+			Description: item.Description,
+			Deadline:    deadline,
+			DueDate:     item.DueDate,
+			Priority:    item.Priority,
+			Tags:        item.Tags,
+		}
+		if item.ProjectID != uuid.Nil {
+			tasks[i].ProjectID = uuid.NullUUID{UUID: item.ProjectID, Valid: true}
+		}
+	}
+
+	results, err := api.Task.BatchCreateTasks(r.Context(), sess.UserID, tasks, req.Atomic)
+	if err != nil {
+		log.Error("failed to create tasks", slog.String("error", err.Error()))
+		http.Error(w, "Failed to create tasks", httpStatus(err))
+		return
+	}
+
+	log.Info("Tasks created", "tasks", len(results))
+	writeBatchResults(w, log, results, http.StatusCreated)
+}
+
+// HandleBatchUpdateTasks applies one patch to the selected tasks, for example
+// {"ids": [...], "patch": {"status": "done", "add_tags": ["q3"]}} or {"filter": {...}, "patch": {"due": {}}}.
+// An empty "due" object clears the deadline and due date of the tasks.
+func (api *APIGateway) HandleBatchUpdateTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleBatchUpdateTasks"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		batchSelector
+		Patch struct {
+			Status   *string `json:"status"`
+			Priority *string `json:"priority"`
+			Due      *struct {
+				Deadline string `json:"deadline"`
+				DueDate  string `json:"due_date"`
+			} `json:"due"`
+			AddTags    []string `json:"add_tags"`
+			RemoveTags []string `json:"remove_tags"`
+		} `json:"patch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	patch := models.TaskPatch{
+		Status:     req.Patch.Status,
+		Priority:   req.Patch.Priority,
+		AddTags:    req.Patch.AddTags,
+		RemoveTags: req.Patch.RemoveTags,
+	}
+	if req.Patch.Due != nil {
+		patch.SetDue = true
+		patch.DueDate = req.Patch.Due.DueDate
+		patch.Deadline, err = parseDeadline(req.Patch.Due.Deadline, sess.Location())
+		if err != nil {
+			http.Error(w, "Invalid deadline", http.StatusBadRequest)
+			return
+		}
+	}
+
+	results, err := api.Task.BatchUpdateTasks(r.Context(), sess.UserID, req.selector(sess), patch, req.Atomic)
+	if err != nil {
+		log.Error("failed to update tasks", slog.String("error", err.Error()))
+		http.Error(w, "Failed to update tasks", httpStatus(err))
+		return
+	}
+
+	log.Info("Tasks updated", "tasks", len(results))
+	writeBatchResults(w, log, results, http.StatusOK)
+}
+
+// HandleBatchDeleteTasks deletes the tasks selected by {"ids": [...]} or {"filter": {...}}.
+func (api *APIGateway) HandleBatchDeleteTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleBatchDeleteTasks"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req batchSelector
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	results, err := api.Task.BatchDeleteTasks(r.Context(), sess.UserID, req.selector(sess), req.Atomic)
+	if err != nil {
+		log.Error("failed to delete tasks", slog.String("error", err.Error()))
+		http.Error(w, "Failed to delete tasks", httpStatus(err))
+		return
+	}
+
+	log.Info("Tasks deleted", "tasks", len(results))
+	writeBatchResults(w, log, results, http.StatusNoContent)
+}
+
+// writeBatchResults reports every item with its own HTTP status, okStatus for the ones that went through.
+func writeBatchResults(w http.ResponseWriter, log *slog.Logger, results []models.BatchResult, okStatus int) {
+	resp := struct {
+		Results []batchItemResult `json:"results"`
+	}{Results: make([]batchItemResult, len(results))}
+
+	for i, result := range results {
+		resp.Results[i].Status = okStatus
+		if result.TaskID != uuid.Nil {
+			resp.Results[i].TaskID = result.TaskID.String()
+		}
+		if result.Err != nil {
+			resp.Results[i].Status = httpStatus(result.Err)
+			resp.Results[i].Error = status.Convert(result.Err).Message()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode batch results", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,644 @@
+package handlers
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+const (
+	calDAVRoot      = "/caldav/"
+	calDAVPrincipal = "/caldav/principal/"
+	calDAVHome      = "/caldav/calendars/"
+	// inboxCalendar is the path segment of the calendar of the tasks without a project.
+	inboxCalendar = "inbox"
+
+	// syncTokenPrefix makes sync tokens the URIs RFC 6578 asks for.
+	syncTokenPrefix = "http://todo-list/ns/sync/"
+
+	// maxCalendarObjectSize is the largest calendar object accepted by PUT, in bytes.
+	maxCalendarObjectSize = 1 << 20
+
+	calDAVMethods = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT"
+)
+
+type calDAVResourceKind int
+
+const (
+	calDAVRootResource calDAVResourceKind = iota
+	calDAVPrincipalResource
+	calDAVHomeResource
+	calDAVCalendarResource
+	calDAVObjectResource
+)
+
+// calDAVPath is a parsed CalDAV URL.
+type calDAVPath struct {
+	kind      calDAVResourceKind
+	projectID uuid.NullUUID
+	// name is the name of a calendar object.
+	name string
+}
+
+// davResource is a resource listed in a multistatus response.
+type davResource struct {
+	href       string
+	properties []davProperty
+}
+
+// HandleCalDAV serves the projects of the user as RFC 4791 calendars of to-dos, the inbox as
+// one more, so that calendar apps can sync tasks both ways:
+//
+//	/caldav/                         the root, which points apps at the principal
+//	/caldav/principal/               the user
+//	/caldav/calendars/               the calendar home
+//	/caldav/calendars/{inbox|id}/    a calendar
+//	/caldav/calendars/{...}/{name}   a task as a calendar object with a single VTODO
+//
+// Calendars support PROPFIND, the calendar-multiget, calendar-query and sync-collection
+// reports, and objects GET, PUT and DELETE with ETags. Calendars are only made by creating projects.
+func (api *APIGateway) HandleCalDAV(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleCalDAV"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path))
+
+	path, ok := parseCalDAVPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", calDAVMethods)
+		w.WriteHeader(http.StatusOK)
+	case "PROPFIND":
+		api.calDAVPropfind(w, r, log, sess, path)
+	case "REPORT":
+		api.calDAVReport(w, r, log, sess, path)
+	case http.MethodGet, http.MethodHead:
+		if path.kind != calDAVObjectResource {
+			w.Header().Set("Allow", calDAVMethods)
+			http.Error(w, "Only calendar objects can be downloaded", http.StatusMethodNotAllowed)
+			return
+		}
+		api.calDAVGet(w, r, log, sess, path)
+	case http.MethodPut:
+		if path.kind != calDAVObjectResource {
+			w.Header().Set("Allow", calDAVMethods)
+			http.Error(w, "Only calendar objects can be written", http.StatusMethodNotAllowed)
+			return
+		}
+		api.calDAVPut(w, r, log, sess, path)
+	case http.MethodDelete:
+		if path.kind != calDAVObjectResource {
+			http.Error(w, "Calendars are deleted with their projects", http.StatusForbidden)
+			return
+		}
+		api.calDAVDelete(w, r, log, sess, path)
+	case "MKCALENDAR", "MKCOL", "PROPPATCH":
+		http.Error(w, "Calendars are the projects of the account", http.StatusForbidden)
+	default:
+		w.Header().Set("Allow", calDAVMethods)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func parseCalDAVPath(p string) (calDAVPath, bool) {
+	rest, ok := strings.CutPrefix(p, calDAVRoot)
+	if !ok {
+		return calDAVPath{}, false
+	}
+
+	segments := strings.Split(strings.TrimSuffix(rest, "/"), "/")
+	switch {
+	case rest == "":
+		return calDAVPath{kind: calDAVRootResource}, true
+	case len(segments) == 1 && segments[0] == "principal":
+		return calDAVPath{kind: calDAVPrincipalResource}, true
+	case segments[0] != "calendars" || len(segments) > 3:
+		return calDAVPath{}, false
+	case len(segments) == 1:
+		return calDAVPath{kind: calDAVHomeResource}, true
+	}
+
+	path := calDAVPath{kind: calDAVCalendarResource}
+	if segments[1] != inboxCalendar {
+		projectID, err := uuid.Parse(segments[1])
+		if err != nil {
+			return calDAVPath{}, false
+		}
+		path.projectID = uuid.NullUUID{UUID: projectID, Valid: true}
+	}
+
+	if len(segments) == 3 {
+		if segments[2] == "" {
+			return calDAVPath{}, false
+		}
+		path.kind = calDAVObjectResource
+		path.name = segments[2]
+	}
+
+	return path, true
+}
+
+func calendarHref(projectID uuid.NullUUID) string {
+	if !projectID.Valid {
+		return calDAVHome + inboxCalendar + "/"
+	}
+
+	return calDAVHome + projectID.UUID.String() + "/"
+}
+
+func calendarObjectHref(projectID uuid.NullUUID, name string) string {
+	return calendarHref(projectID) + url.PathEscape(name)
+}
+
+func (api *APIGateway) calDAVPropfind(w http.ResponseWriter, r *http.Request, log *slog.Logger, sess *models.Session, path calDAVPath) {
+	var req propfindRequest
+	_, err := readDAVRequest(r, func(root xml.StartElement, d *xml.Decoder) error {
+		if root.Name != (xml.Name{Space: davNS, Local: "propfind"}) {
+			return errors.New("expected DAV:propfind")
+		}
+		return d.DecodeElement(&req, &root)
+	})
+	if err != nil && !errors.Is(err, errEmptyBody) {
+		http.Error(w, "Invalid PROPFIND body", http.StatusBadRequest)
+		return
+	}
+
+	// An infinite depth is served as 1, there is nothing deeper below a calendar.
+	children := r.Header.Get("Depth") != "0"
+
+	resources, err := api.calDAVResources(r.Context(), sess, path, children)
+	if err != nil {
+		code := httpStatus(err)
+		if code != http.StatusNotFound {
+			log.Error("failed to list resources", slog.String("error", err.Error()))
+		}
+		http.Error(w, "Failed to list resources", code)
+		return
+	}
+
+	ms := newMultistatus()
+	for _, resource := range resources {
+		found, missing := selectProperties(resource.properties, req.Prop, req.PropName != nil)
+		ms.response(resource.href, found, missing)
+	}
+
+	if err := ms.writeTo(w); err != nil {
+		log.Error("failed to write multistatus", slog.String("error", err.Error()))
+	}
+}
+
+var errCalendarNotFound = status.Error(codes.NotFound, "calendar not found")
+
+// calDAVResources returns the resource of the path with its children when children is set.
+func (api *APIGateway) calDAVResources(ctx context.Context, sess *models.Session, path calDAVPath, children bool) ([]davResource, error) {
+	switch path.kind {
+	case calDAVRootResource:
+		resources := []davResource{{href: calDAVRoot, properties: []davProperty{
+			{davName("resourcetype"), "<d:collection/>"},
+			{davName("displayname"), textValue("todo-list")},
+			{davName("current-user-principal"), hrefValue(calDAVPrincipal)},
+		}}}
+		if children {
+			resources = append(resources,
+				davResource{href: calDAVPrincipal, properties: principalProperties(sess)},
+				davResource{href: calDAVHome, properties: homeProperties()})
+		}
+		return resources, nil
+	case calDAVPrincipalResource:
+		return []davResource{{href: calDAVPrincipal, properties: principalProperties(sess)}}, nil
+	}
+
+	if path.kind == calDAVObjectResource {
+		objects, err := api.Task.GetCalendarObjects(ctx, sess.UserID, path.projectID, []string{path.name})
+		if err != nil {
+			return nil, err
+		}
+		if len(objects) == 0 {
+			return nil, status.Error(codes.NotFound, "calendar object not found")
+		}
+		return []davResource{{href: calendarObjectHref(path.projectID, path.name), properties: objectProperties(objects[0])}}, nil
+	}
+
+	calendars, syncToken, err := api.Task.ListCalendars(ctx, sess.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if path.kind == calDAVHomeResource {
+		resources := []davResource{{href: calDAVHome, properties: homeProperties()}}
+		if children {
+			for _, calendar := range calendars {
+				resources = append(resources, davResource{
+					href:       calendarHref(calendar.ProjectID),
+					properties: calendarProperties(calendar, syncToken),
+				})
+			}
+		}
+		return resources, nil
+	}
+
+	var resources []davResource
+	for _, calendar := range calendars {
+		if calendar.ProjectID == path.projectID {
+			resources = append(resources, davResource{href: calendarHref(calendar.ProjectID), properties: calendarProperties(calendar, syncToken)})
+		}
+	}
+	if resources == nil {
+		return nil, errCalendarNotFound
+	}
+
+	if children {
+		objects, err := api.Task.GetCalendarObjects(ctx, sess.UserID, path.projectID, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			resources = append(resources, davResource{href: calendarObjectHref(path.projectID, object.Name), properties: objectProperties(object)})
+		}
+	}
+
+	return resources, nil
+}
+
+func davName(local string) xml.Name {
+	return xml.Name{Space: davNS, Local: local}
+}
+
+func calDAVName(local string) xml.Name {
+	return xml.Name{Space: calDAVNS, Local: local}
+}
+
+func principalProperties(sess *models.Session) []davProperty {
+	return []davProperty{
+		{davName("resourcetype"), "<d:collection/><d:principal/>"},
+		{davName("displayname"), textValue(sess.Email)},
+		{davName("current-user-principal"), hrefValue(calDAVPrincipal)},
+		{davName("principal-URL"), hrefValue(calDAVPrincipal)},
+		{calDAVName("calendar-home-set"), hrefValue(calDAVHome)},
+		{calDAVName("calendar-user-address-set"), hrefValue("mailto:" + sess.Email)},
+	}
+}
+
+func homeProperties() []davProperty {
+	return []davProperty{
+		{davName("resourcetype"), "<d:collection/>"},
+		{davName("displayname"), textValue("Calendars")},
+		{davName("current-user-principal"), hrefValue(calDAVPrincipal)},
+		{davName("owner"), hrefValue(calDAVPrincipal)},
+	}
+}
+
+func calendarProperties(calendar models.CalendarCollection, syncToken int64) []davProperty {
+	return []davProperty{
+		{davName("resourcetype"), "<d:collection/><c:calendar/>"},
+		{davName("displayname"), textValue(calendar.Name)},
+		{davName("current-user-principal"), hrefValue(calDAVPrincipal)},
+		{davName("owner"), hrefValue(calDAVPrincipal)},
+		{davName("current-user-privilege-set"), "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+			"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>"},
+		{calDAVName("supported-calendar-component-set"), `<c:comp name="VTODO"/>`},
+		{davName("supported-report-set"), "<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><d:sync-collection/></d:report></d:supported-report>"},
+		{xml.Name{Space: calendarServerNS, Local: "getctag"}, strconv.FormatInt(calendar.CTag, 10)},
+		{davName("sync-token"), textValue(syncTokenPrefix + strconv.FormatInt(syncToken, 10))},
+	}
+}
+
+func objectProperties(object *models.CalendarObject) []davProperty {
+	return []davProperty{
+		{davName("resourcetype"), ""},
+		{davName("getetag"), textValue(object.ETag)},
+		{davName("getcontenttype"), "text/calendar; charset=utf-8; component=VTODO"},
+		{davName("getcontentlength"), strconv.Itoa(len(object.Data))},
+		{calDAVName("calendar-data"), textValue(string(object.Data))},
+	}
+}
+
+// selectProperties picks the properties asked for from the ones of a resource. Without a list
+// it returns them all, except for the calendar data that is only sent on request. Only the
+// names are returned for a DAV:propname request.
+func selectProperties(properties []davProperty, names propNames, namesOnly bool) ([]davProperty, []xml.Name) {
+	if names == nil {
+		var found []davProperty
+		for _, property := range properties {
+			if property.name == calDAVName("calendar-data") {
+				continue
+			}
+			if namesOnly {
+				property.value = ""
+			}
+			found = append(found, property)
+		}
+		return found, nil
+	}
+
+	var (
+		found   []davProperty
+		missing []xml.Name
+	)
+	for _, name := range names {
+		i := -1
+		for j, property := range properties {
+			if property.name == name {
+				i = j
+				break
+			}
+		}
+
+		if i < 0 {
+			missing = append(missing, name)
+		} else {
+			found = append(found, properties[i])
+		}
+	}
+
+	return found, missing
+}
+
+func (api *APIGateway) calDAVReport(w http.ResponseWriter, r *http.Request, log *slog.Logger, sess *models.Session, path calDAVPath) {
+	if path.kind != calDAVCalendarResource {
+		writeDAVError(w, http.StatusForbidden, davName("supported-report"))
+		return
+	}
+
+	var (
+		multiget calendarMultigetRequest
+		query    calendarQueryRequest
+		sync     syncCollectionRequest
+	)
+	report, err := readDAVRequest(r, func(root xml.StartElement, d *xml.Decoder) error {
+		switch root.Name {
+		case calDAVName("calendar-multiget"):
+			return d.DecodeElement(&multiget, &root)
+		case calDAVName("calendar-query"):
+			return d.DecodeElement(&query, &root)
+		case davName("sync-collection"):
+			return d.DecodeElement(&sync, &root)
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		http.Error(w, "Invalid REPORT body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	ms := newMultistatus()
+
+	switch report {
+	case calDAVName("calendar-multiget"):
+		hrefs := make(map[string]string, len(multiget.Hrefs))
+		names := make([]string, 0, len(multiget.Hrefs))
+		for _, href := range multiget.Hrefs {
+			if name, ok := calendarObjectName(href, path.projectID); ok {
+				hrefs[href] = name
+				names = append(names, name)
+			}
+		}
+
+		objects, err := api.Task.GetCalendarObjects(ctx, sess.UserID, path.projectID, names)
+		if err != nil {
+			api.calDAVError(w, log, "failed to get calendar objects", err)
+			return
+		}
+
+		byName := make(map[string]*models.CalendarObject, len(objects))
+		for _, object := range objects {
+			byName[object.Name] = object
+		}
+
+		for _, href := range multiget.Hrefs {
+			object, ok := byName[hrefs[href]]
+			if !ok {
+				ms.missing(href)
+				continue
+			}
+			found, missing := selectProperties(objectProperties(object), multiget.Prop, false)
+			ms.response(calendarObjectHref(path.projectID, object.Name), found, missing)
+		}
+	case calDAVName("calendar-query"):
+		todos, ok := queriesTodos(query.Filter.CompFilters)
+		if !ok {
+			writeDAVError(w, http.StatusForbidden, calDAVName("supported-filter"))
+			return
+		}
+
+		if todos {
+			objects, err := api.Task.GetCalendarObjects(ctx, sess.UserID, path.projectID, nil)
+			if err != nil {
+				api.calDAVError(w, log, "failed to get calendar objects", err)
+				return
+			}
+
+			for _, object := range objects {
+				found, missing := selectProperties(objectProperties(object), query.Prop, false)
+				ms.response(calendarObjectHref(path.projectID, object.Name), found, missing)
+			}
+		}
+	case davName("sync-collection"):
+		token, ok := parseSyncToken(sync.SyncToken)
+		if !ok {
+			writeDAVError(w, http.StatusForbidden, davName("valid-sync-token"))
+			return
+		}
+
+		changes, err := api.Task.SyncCalendarObjects(ctx, sess.UserID, path.projectID, token)
+		if err != nil {
+			if status.Code(err) == codes.OutOfRange {
+				writeDAVError(w, http.StatusForbidden, davName("valid-sync-token"))
+				return
+			}
+			api.calDAVError(w, log, "failed to sync calendar objects", err)
+			return
+		}
+
+		for _, object := range changes.Changed {
+			found, missing := selectProperties(objectProperties(object), sync.Prop, false)
+			ms.response(calendarObjectHref(path.projectID, object.Name), found, missing)
+		}
+		for _, name := range changes.Deleted {
+			ms.missing(calendarObjectHref(path.projectID, name))
+		}
+		ms.syncToken(syncTokenPrefix + strconv.FormatInt(changes.SyncToken, 10))
+	default:
+		writeDAVError(w, http.StatusForbidden, davName("supported-report"))
+		return
+	}
+
+	if err := ms.writeTo(w); err != nil {
+		log.Error("failed to write multistatus", slog.String("error", err.Error()))
+	}
+}
+
+// calendarObjectName returns the name of the object an href of a request points to, if it is in the calendar.
+func calendarObjectName(href string, projectID uuid.NullUUID) (string, bool) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+
+	name, ok := strings.CutPrefix(u.Path, calendarHref(projectID))
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+
+	return name, true
+}
+
+// queriesTodos evaluates the filter of a calendar-query: whether it selects every to-do or none.
+// Only filters by component are supported, ok is false for the others.
+func queriesTodos(filters []compFilter) (todos bool, ok bool) {
+	if len(filters) != 1 || filters[0].Name != "VCALENDAR" || len(filters[0].Other) > 0 {
+		return false, false
+	}
+
+	components := filters[0].CompFilters
+	switch {
+	case len(components) == 0:
+		return true, true
+	case len(components) > 1:
+		return false, false
+	case len(components[0].Other) > 0 || len(components[0].CompFilters) > 0:
+		return false, false
+	default:
+		return components[0].Name == "VTODO", true
+	}
+}
+
+// parseSyncToken reads the sync token of a sync-collection report, empty for a first sync.
+func parseSyncToken(token string) (int64, bool) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return 0, true
+	}
+
+	digits, ok := strings.CutPrefix(token, syncTokenPrefix)
+	if !ok {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, false
+	}
+
+	return seq, true
+}
+
+func (api *APIGateway) calDAVGet(w http.ResponseWriter, r *http.Request, log *slog.Logger, sess *models.Session, path calDAVPath) {
+	objects, err := api.Task.GetCalendarObjects(r.Context(), sess.UserID, path.projectID, []string{path.name})
+	if err != nil {
+		api.calDAVError(w, log, "failed to get calendar object", err)
+		return
+	}
+	if len(objects) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	object := objects[0]
+
+	w.Header().Set("ETag", object.ETag)
+	if etagMatches(r.Header.Get("If-None-Match"), object.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(object.Data)))
+	if _, err := w.Write(object.Data); err != nil {
+		log.Error("failed to write calendar object", slog.String("error", err.Error()))
+	}
+}
+
+// calDAVPut creates or replaces a task. The response has no ETag: the task keeps only what it has
+// fields for, so the object reads back different from what was written.
+func (api *APIGateway) calDAVPut(w http.ResponseWriter, r *http.Request, log *slog.Logger, sess *models.Session, path calDAVPath) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "text/calendar" {
+			writeDAVError(w, http.StatusUnsupportedMediaType, calDAVName("supported-calendar-data"))
+			return
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxCalendarObjectSize+1))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxCalendarObjectSize {
+		writeDAVError(w, http.StatusRequestEntityTooLarge, calDAVName("max-resource-size"))
+		return
+	}
+
+	created, err := api.Task.PutCalendarObject(r.Context(), sess.UserID, path.projectID, path.name, data,
+		r.Header.Get("If-Match"), r.Header.Get("If-None-Match"), sess.Timezone)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.AlreadyExists:
+			writeDAVError(w, http.StatusConflict, calDAVName("no-uid-conflict"))
+		case codes.InvalidArgument:
+			log.Info("invalid calendar object", slog.String("error", err.Error()))
+			http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+		default:
+			api.calDAVError(w, log, "failed to put calendar object", err)
+		}
+		return
+	}
+
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *APIGateway) calDAVDelete(w http.ResponseWriter, r *http.Request, log *slog.Logger, sess *models.Session, path calDAVPath) {
+	err := api.Task.DeleteCalendarObject(r.Context(), sess.UserID, path.projectID, path.name, r.Header.Get("If-Match"))
+	if err != nil {
+		api.calDAVError(w, log, "failed to delete calendar object", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// calDAVError answers with the status of a failed task service call, logging the unexpected ones.
+func (api *APIGateway) calDAVError(w http.ResponseWriter, log *slog.Logger, message string, err error) {
+	code := httpStatus(err)
+	if code >= http.StatusInternalServerError {
+		log.Error(message, slog.String("error", err.Error()))
+	}
+
+	http.Error(w, http.StatusText(code), code)
+}
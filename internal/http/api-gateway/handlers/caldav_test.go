@@ -0,0 +1,502 @@
+package handlers_test
+
+import (
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	taskclient "github.com/SlashLight/todo-list/internal/clients/task-service/grpc"
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	taskgrpc "github.com/SlashLight/todo-list/internal/grpc/task-service"
+	"github.com/SlashLight/todo-list/internal/http/api-gateway/handlers"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/internal/storage/blob/local"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+)
+
+const (
+	davNS    = "DAV:"
+	calDAVNS = "urn:ietf:params:xml:ns:caldav"
+
+	multistatusHeader = `<?xml version="1.0" encoding="utf-8"?>` + "\n" +
+		`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`
+)
+
+// calDAVServer serves the CalDAV handler of a gateway for the user of sess, in front of a task
+// service that keeps its data in memory and is reached over gRPC like in production.
+type calDAVServer struct {
+	*httptest.Server
+	service *task_service.Service
+	sess    *models.Session
+}
+
+func newCalDAVServer(t *testing.T) *calDAVServer {
+	t.Helper()
+
+	log := slog.New(slog.DiscardHandler)
+
+	blobs, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := memory.New()
+	service := task_service.New(s, blobs, nil, task_service.Settings{}, log)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gRPCServer := grpc.NewServer(grpc.ChainUnaryInterceptor(taskgrpc.IdempotencyInterceptor(s, time.Hour, log)))
+	taskgrpc.RegisterServerAPI(gRPCServer, service)
+	go func() { _ = gRPCServer.Serve(l) }()
+	t.Cleanup(gRPCServer.Stop)
+
+	client, err := taskclient.New(l.Addr().String(), log, 0, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess := &models.Session{UserID: uuid.New(), Email: "alice@example.com", Timezone: "Europe/Berlin"}
+	api := handlers.New(nil, client, log)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.HandleCalDAV(w, r.WithContext(models.ContextWithSession(r.Context(), sess)))
+	}))
+	t.Cleanup(server.Close)
+
+	return &calDAVServer{Server: server, service: service, sess: sess}
+}
+
+// do sends a request with the headers given as name and value pairs and returns the response
+// with its body read.
+func (s *calDAVServer) do(t *testing.T, method, path, body string, headers ...string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: read body: %v", method, path, err)
+	}
+
+	return resp, string(data)
+}
+
+// expect sends a request and fails the test unless it is answered with code.
+func (s *calDAVServer) expect(t *testing.T, code int, method, path, body string, headers ...string) (*http.Response, string) {
+	t.Helper()
+
+	resp, data := s.do(t, method, path, body, headers...)
+	if resp.StatusCode != code {
+		t.Fatalf("%s %s: want status %d, got %d: %s", method, path, code, resp.StatusCode, data)
+	}
+
+	return resp, data
+}
+
+// multistatus sends a PROPFIND or REPORT request and decodes its 207 Multi-Status answer.
+func (s *calDAVServer) multistatus(t *testing.T, method, path, body string, headers ...string) multistatus {
+	t.Helper()
+
+	resp, data := s.expect(t, http.StatusMultiStatus, method, path, body, headers...)
+	if contentType := resp.Header.Get("Content-Type"); contentType != "application/xml; charset=utf-8" {
+		t.Errorf("%s %s: want an XML body, got %q", method, path, contentType)
+	}
+	if !strings.HasPrefix(data, multistatusHeader) {
+		t.Errorf("%s %s: want the body to start with %s, got %s", method, path, multistatusHeader, data)
+	}
+
+	var ms multistatus
+	if err := xml.Unmarshal([]byte(data), &ms); err != nil {
+		t.Fatalf("%s %s: decode multistatus: %v\n%s", method, path, err, data)
+	}
+
+	return ms
+}
+
+type multistatus struct {
+	XMLName   xml.Name      `xml:"DAV: multistatus"`
+	Responses []davResponse `xml:"DAV: response"`
+	SyncToken string        `xml:"DAV: sync-token"`
+}
+
+type davResponse struct {
+	Href      string `xml:"DAV: href"`
+	Status    string `xml:"DAV: status"`
+	Propstats []struct {
+		Prop struct {
+			Props []struct {
+				XMLName xml.Name
+				Text    string `xml:",chardata"`
+				Inner   string `xml:",innerxml"`
+			} `xml:",any"`
+		} `xml:"DAV: prop"`
+		Status string `xml:"DAV: status"`
+	} `xml:"DAV: propstat"`
+}
+
+func (ms multistatus) hrefs() []string {
+	hrefs := make([]string, 0, len(ms.Responses))
+	for _, response := range ms.Responses {
+		hrefs = append(hrefs, response.Href)
+	}
+
+	return hrefs
+}
+
+func (ms multistatus) response(t *testing.T, href string) davResponse {
+	t.Helper()
+
+	for _, response := range ms.Responses {
+		if response.Href == href {
+			return response
+		}
+	}
+
+	t.Fatalf("no response for %s in %v", href, ms.hrefs())
+	return davResponse{}
+}
+
+// prop returns the value of a property as raw XML with the status of its propstat.
+func (r davResponse) prop(space, local string) (value, status string, ok bool) {
+	for _, propstat := range r.Propstats {
+		for _, prop := range propstat.Prop.Props {
+			if prop.XMLName.Space == space && prop.XMLName.Local == local {
+				return prop.Inner, propstat.Status, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// text returns the text of a property found with status 200.
+func (r davResponse) text(t *testing.T, space, local string) string {
+	t.Helper()
+
+	for _, propstat := range r.Propstats {
+		for _, prop := range propstat.Prop.Props {
+			if prop.XMLName.Space == space && prop.XMLName.Local == local {
+				if propstat.Status != "HTTP/1.1 200 OK" {
+					t.Fatalf("%s: want property %s %s found, got %s", r.Href, space, local, propstat.Status)
+				}
+				return prop.Text
+			}
+		}
+	}
+
+	t.Fatalf("%s: no property %s %s", r.Href, space, local)
+	return ""
+}
+
+func todo(uid, summary string) string {
+	return "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//Example//Client//EN\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:" + uid + "\r\n" +
+		"SUMMARY:" + summary + "\r\n" +
+		"DUE;TZID=Europe/Berlin:20260310T090000\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+}
+
+const calendarType = "text/calendar; charset=utf-8"
+
+func TestCalDAVPropfind(t *testing.T) {
+	s := newCalDAVServer(t)
+
+	t.Run("root", func(t *testing.T) {
+		resp, body := s.expect(t, http.StatusMultiStatus, "PROPFIND", "/caldav/", "", "Depth", "0")
+		want := multistatusHeader +
+			`<d:response><d:href>/caldav/</d:href><d:propstat><d:prop>` +
+			`<d:resourcetype><d:collection/></d:resourcetype>` +
+			`<d:displayname>todo-list</d:displayname>` +
+			`<d:current-user-principal><d:href>/caldav/principal/</d:href></d:current-user-principal>` +
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response></d:multistatus>`
+		if body != want {
+			t.Errorf("want body\n%s\ngot\n%s", want, body)
+		}
+		if contentType := resp.Header.Get("Content-Type"); contentType != "application/xml; charset=utf-8" {
+			t.Errorf("want an XML body, got %q", contentType)
+		}
+	})
+
+	t.Run("missing properties", func(t *testing.T) {
+		_, body := s.expect(t, http.StatusMultiStatus, "PROPFIND", "/caldav/principal/",
+			`<?xml version="1.0"?><d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`+
+				`<d:prop><c:calendar-home-set/><x:color xmlns:x="urn:example:&amp;"/></d:prop></d:propfind>`,
+			"Depth", "0")
+		want := multistatusHeader +
+			`<d:response><d:href>/caldav/principal/</d:href>` +
+			`<d:propstat><d:prop><c:calendar-home-set><d:href>/caldav/calendars/</d:href></c:calendar-home-set></d:prop>` +
+			`<d:status>HTTP/1.1 200 OK</d:status></d:propstat>` +
+			`<d:propstat><d:prop><x:color xmlns:x="urn:example:&amp;"/></d:prop>` +
+			`<d:status>HTTP/1.1 404 Not Found</d:status></d:propstat></d:response></d:multistatus>`
+		if body != want {
+			t.Errorf("want body\n%s\ngot\n%s", want, body)
+		}
+	})
+
+	t.Run("calendar home", func(t *testing.T) {
+		project, err := s.service.CreateProject(t.Context(), s.sess.UserID, "Work & Home")
+		if err != nil {
+			t.Fatalf("create project: %v", err)
+		}
+		projectHref := "/caldav/calendars/" + project.ID.String() + "/"
+
+		ms := s.multistatus(t, "PROPFIND", "/caldav/calendars/",
+			`<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">`+
+				`<d:prop><d:displayname/><d:resourcetype/><cs:getctag/><d:sync-token/></d:prop></d:propfind>`,
+			"Depth", "1")
+
+		hrefs := ms.hrefs()
+		if want := []string{"/caldav/calendars/", "/caldav/calendars/inbox/", projectHref}; strings.Join(hrefs, " ") != strings.Join(want, " ") {
+			t.Fatalf("want responses for %v, got %v", want, hrefs)
+		}
+
+		home := ms.response(t, "/caldav/calendars/")
+		if name := home.text(t, davNS, "displayname"); name != "Calendars" {
+			t.Errorf("home: want display name Calendars, got %q", name)
+		}
+		if _, status, _ := home.prop(davNS, "sync-token"); status != "HTTP/1.1 404 Not Found" {
+			t.Errorf("home: want no sync token, got %q", status)
+		}
+
+		calendar := ms.response(t, projectHref)
+		if name := calendar.text(t, davNS, "displayname"); name != "Work & Home" {
+			t.Errorf("calendar: want display name of the project, got %q", name)
+		}
+		if value, _, _ := calendar.prop(davNS, "resourcetype"); value != "<d:collection/><c:calendar/>" {
+			t.Errorf("calendar: want a calendar collection, got %q", value)
+		}
+		if token := calendar.text(t, davNS, "sync-token"); !strings.HasPrefix(token, "http://todo-list/ns/sync/") {
+			t.Errorf("calendar: want a sync token URI, got %q", token)
+		}
+		calendar.text(t, "http://calendarserver.org/ns/", "getctag")
+
+		inbox := ms.response(t, "/caldav/calendars/inbox/")
+		if name := inbox.text(t, davNS, "displayname"); name != "Inbox" {
+			t.Errorf("inbox: want display name Inbox, got %q", name)
+		}
+	})
+
+	t.Run("calendar object", func(t *testing.T) {
+		s.expect(t, http.StatusCreated, http.MethodPut, "/caldav/calendars/inbox/call.ics", todo("call-1", "Call Bob"),
+			"Content-Type", calendarType)
+		resp, _ := s.expect(t, http.StatusOK, http.MethodGet, "/caldav/calendars/inbox/call.ics", "")
+
+		ms := s.multistatus(t, "PROPFIND", "/caldav/calendars/inbox/", "", "Depth", "1")
+		object := ms.response(t, "/caldav/calendars/inbox/call.ics")
+		if etag := object.text(t, davNS, "getetag"); etag != resp.Header.Get("ETag") {
+			t.Errorf("want the ETag of GET %q, got %q", resp.Header.Get("ETag"), etag)
+		}
+		if _, _, ok := object.prop(calDAVNS, "calendar-data"); ok {
+			t.Errorf("want calendar data only on request")
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		s.expect(t, http.StatusNotFound, "PROPFIND", "/caldav/calendars/"+uuid.NewString()+"/", "", "Depth", "0")
+		s.expect(t, http.StatusNotFound, "PROPFIND", "/caldav/calendars/inbox/missing.ics", "", "Depth", "0")
+		s.expect(t, http.StatusBadRequest, "PROPFIND", "/caldav/", "<d:prop xmlns:d=\"DAV:\"/>", "Depth", "0")
+	})
+}
+
+func TestCalDAVPutConditional(t *testing.T) {
+	s := newCalDAVServer(t)
+	const path = "/caldav/calendars/inbox/report.ics"
+
+	s.expect(t, http.StatusPreconditionFailed, http.MethodPut, path, todo("report-1", "Write report"),
+		"Content-Type", calendarType, "If-Match", `"missing"`)
+	s.expect(t, http.StatusCreated, http.MethodPut, path, todo("report-1", "Write report"),
+		"Content-Type", calendarType, "If-None-Match", "*")
+	s.expect(t, http.StatusPreconditionFailed, http.MethodPut, path, todo("report-1", "Write the report"),
+		"Content-Type", calendarType, "If-None-Match", "*")
+
+	resp, data := s.expect(t, http.StatusOK, http.MethodGet, path, "")
+	etag := resp.Header.Get("ETag")
+	if etag == "" || !strings.Contains(data, "SUMMARY:Write report") {
+		t.Fatalf("get: want the task with an ETag, got %q:\n%s", etag, data)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != calendarType {
+		t.Errorf("get: want %q, got %q", calendarType, contentType)
+	}
+	s.expect(t, http.StatusNotModified, http.MethodGet, path, "", "If-None-Match", etag)
+
+	s.expect(t, http.StatusPreconditionFailed, http.MethodPut, path, todo("report-1", "Write the report"),
+		"Content-Type", calendarType, "If-Match", `"stale"`)
+	s.expect(t, http.StatusPreconditionFailed, http.MethodPut, path, todo("report-1", "Write the report"),
+		"Content-Type", calendarType, "If-None-Match", etag)
+	s.expect(t, http.StatusNoContent, http.MethodPut, path, todo("report-1", "Write the report"),
+		"Content-Type", calendarType, "If-Match", etag)
+
+	resp, data = s.expect(t, http.StatusOK, http.MethodGet, path, "")
+	if resp.Header.Get("ETag") == etag || !strings.Contains(data, "SUMMARY:Write the report") {
+		t.Errorf("get: want the new task with a new ETag, got %q:\n%s", resp.Header.Get("ETag"), data)
+	}
+	s.expect(t, http.StatusPreconditionFailed, http.MethodPut, path, todo("report-1", "Write it"),
+		"Content-Type", calendarType, "If-Match", etag)
+
+	_, body := s.expect(t, http.StatusConflict, http.MethodPut, "/caldav/calendars/inbox/copy.ics", todo("report-1", "Copy"),
+		"Content-Type", calendarType)
+	if !strings.Contains(body, "<c:no-uid-conflict/>") {
+		t.Errorf("put with a taken UID: want the no-uid-conflict precondition, got %s", body)
+	}
+	_, body = s.expect(t, http.StatusUnsupportedMediaType, http.MethodPut, "/caldav/calendars/inbox/note.ics", "note",
+		"Content-Type", "text/plain")
+	if !strings.Contains(body, "<c:supported-calendar-data/>") {
+		t.Errorf("put of text: want the supported-calendar-data precondition, got %s", body)
+	}
+	s.expect(t, http.StatusBadRequest, http.MethodPut, "/caldav/calendars/inbox/bad.ics", "BEGIN:VCALENDAR\r\n",
+		"Content-Type", calendarType)
+}
+
+func TestCalDAVDelete(t *testing.T) {
+	s := newCalDAVServer(t)
+	const path = "/caldav/calendars/inbox/report.ics"
+
+	s.expect(t, http.StatusCreated, http.MethodPut, path, todo("report-1", "Write report"), "Content-Type", calendarType)
+	resp, _ := s.expect(t, http.StatusOK, http.MethodGet, path, "")
+	etag := resp.Header.Get("ETag")
+
+	s.expect(t, http.StatusPreconditionFailed, http.MethodDelete, path, "", "If-Match", `"stale"`)
+	s.expect(t, http.StatusNoContent, http.MethodDelete, path, "", "If-Match", etag)
+	s.expect(t, http.StatusNotFound, http.MethodGet, path, "")
+	s.expect(t, http.StatusNotFound, http.MethodDelete, path, "")
+	s.expect(t, http.StatusForbidden, http.MethodDelete, "/caldav/calendars/inbox/", "")
+
+	tasks, err := s.service.GetTasks(t.Context(), s.sess.UserID)
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("want the task deleted, got %v", tasks)
+	}
+}
+
+func TestCalDAVCalendarQuery(t *testing.T) {
+	s := newCalDAVServer(t)
+
+	s.expect(t, http.StatusCreated, http.MethodPut, "/caldav/calendars/inbox/report.ics", todo("report-1", "Write report"),
+		"Content-Type", calendarType)
+	resp, data := s.expect(t, http.StatusOK, http.MethodGet, "/caldav/calendars/inbox/report.ics", "")
+
+	query := func(component string) string {
+		return `<?xml version="1.0" encoding="utf-8"?>` +
+			`<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">` +
+			`<d:prop><d:getetag/><c:calendar-data/></d:prop>` +
+			`<c:filter><c:comp-filter name="VCALENDAR">` + component + `</c:comp-filter></c:filter>` +
+			`</c:calendar-query>`
+	}
+
+	ms := s.multistatus(t, "REPORT", "/caldav/calendars/inbox/", query(`<c:comp-filter name="VTODO"/>`), "Depth", "1")
+	if hrefs := ms.hrefs(); len(hrefs) != 1 || hrefs[0] != "/caldav/calendars/inbox/report.ics" {
+		t.Fatalf("want the to-do, got %v", hrefs)
+	}
+	object := ms.Responses[0]
+	if etag := object.text(t, davNS, "getetag"); etag != resp.Header.Get("ETag") {
+		t.Errorf("want the ETag of GET %q, got %q", resp.Header.Get("ETag"), etag)
+	}
+	if calendarData := object.text(t, calDAVNS, "calendar-data"); calendarData != data {
+		t.Errorf("want the calendar data of GET\n%q\ngot\n%q", data, calendarData)
+	}
+
+	ms = s.multistatus(t, "REPORT", "/caldav/calendars/inbox/", query(`<c:comp-filter name="VEVENT"/>`), "Depth", "1")
+	if len(ms.Responses) != 0 {
+		t.Errorf("want no events, got %v", ms.hrefs())
+	}
+
+	_, body := s.expect(t, http.StatusForbidden, "REPORT", "/caldav/calendars/inbox/",
+		query(`<c:comp-filter name="VTODO"><c:time-range start="20260301T000000Z"/></c:comp-filter>`), "Depth", "1")
+	want := `<?xml version="1.0" encoding="utf-8"?>` + "\n" +
+		`<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><c:supported-filter/></d:error>`
+	if body != want {
+		t.Errorf("time-range filter: want body\n%s\ngot\n%s", want, body)
+	}
+
+	s.expect(t, http.StatusNotFound, "REPORT", "/caldav/calendars/"+uuid.NewString()+"/", query(""), "Depth", "1")
+}
+
+func TestCalDAVSyncCollection(t *testing.T) {
+	s := newCalDAVServer(t)
+	const calendar = "/caldav/calendars/inbox/"
+
+	sync := func(token string) string {
+		return `<?xml version="1.0" encoding="utf-8"?>` +
+			`<d:sync-collection xmlns:d="DAV:"><d:sync-token>` + token + `</d:sync-token>` +
+			`<d:sync-level>1</d:sync-level><d:prop><d:getetag/></d:prop></d:sync-collection>`
+	}
+
+	s.expect(t, http.StatusCreated, http.MethodPut, calendar+"report.ics", todo("report-1", "Write report"),
+		"Content-Type", calendarType)
+	s.expect(t, http.StatusCreated, http.MethodPut, calendar+"call.ics", todo("call-1", "Call Bob"),
+		"Content-Type", calendarType)
+
+	first := s.multistatus(t, "REPORT", calendar, sync(""))
+	if hrefs := strings.Join(first.hrefs(), " "); hrefs != calendar+"call.ics "+calendar+"report.ics" &&
+		hrefs != calendar+"report.ics "+calendar+"call.ics" {
+		t.Fatalf("first sync: want both to-dos, got %v", first.hrefs())
+	}
+	if !strings.HasPrefix(first.SyncToken, "http://todo-list/ns/sync/") {
+		t.Fatalf("first sync: want a sync token URI, got %q", first.SyncToken)
+	}
+
+	home := s.multistatus(t, "PROPFIND", calendar, `<d:propfind xmlns:d="DAV:"><d:prop><d:sync-token/></d:prop></d:propfind>`, "Depth", "0")
+	if token := home.response(t, calendar).text(t, davNS, "sync-token"); token != first.SyncToken {
+		t.Errorf("want the calendar to have the sync token of the report %q, got %q", first.SyncToken, token)
+	}
+
+	unchanged := s.multistatus(t, "REPORT", calendar, sync(first.SyncToken))
+	if len(unchanged.Responses) != 0 || unchanged.SyncToken != first.SyncToken {
+		t.Errorf("sync without changes: want no responses and the same token, got %v and %q", unchanged.hrefs(), unchanged.SyncToken)
+	}
+
+	s.expect(t, http.StatusNoContent, http.MethodPut, calendar+"call.ics", todo("call-1", "Call Bob back"),
+		"Content-Type", calendarType)
+	s.expect(t, http.StatusNoContent, http.MethodDelete, calendar+"report.ics", "")
+
+	second := s.multistatus(t, "REPORT", calendar, sync(first.SyncToken))
+	if len(second.Responses) != 2 {
+		t.Fatalf("second sync: want the changed and the deleted to-do, got %v", second.hrefs())
+	}
+	changed := second.response(t, calendar+"call.ics")
+	if changed.Status != "" {
+		t.Errorf("second sync: want the changed to-do with its properties, got status %q", changed.Status)
+	}
+	changed.text(t, davNS, "getetag")
+	if deleted := second.response(t, calendar+"report.ics"); deleted.Status != "HTTP/1.1 404 Not Found" || len(deleted.Propstats) != 0 {
+		t.Errorf("second sync: want the deleted to-do as 404, got %+v", deleted)
+	}
+	if second.SyncToken == first.SyncToken || second.SyncToken == "" {
+		t.Errorf("second sync: want a new sync token, got %q", second.SyncToken)
+	}
+
+	for _, token := range []string{"first", "http://todo-list/ns/sync/-1", "http://todo-list/ns/sync/999999"} {
+		_, body := s.expect(t, http.StatusForbidden, "REPORT", calendar, sync(token))
+		if !strings.Contains(body, "<d:valid-sync-token/>") {
+			t.Errorf("sync token %q: want the valid-sync-token precondition, got %s", token, body)
+		}
+	}
+}
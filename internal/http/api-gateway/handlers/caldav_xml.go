@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	davNS            = "DAV:"
+	calDAVNS         = "urn:ietf:params:xml:ns:caldav"
+	calendarServerNS = "http://calendarserver.org/ns/"
+
+	// maxDAVRequestBody is the largest WebDAV request body read, in bytes.
+	maxDAVRequestBody = 1 << 20
+)
+
+// davPrefixes are the prefixes the namespaces of responses are declared with.
+var davPrefixes = map[string]string{
+	davNS:            "d",
+	calDAVNS:         "c",
+	calendarServerNS: "cs",
+}
+
+// davProperty is a property of a resource with its value as XML.
+type davProperty struct {
+	name  xml.Name
+	value string
+}
+
+// propNames reads a DAV:prop element of a request: the names of the properties asked for.
+type propNames []xml.Name
+
+func (p *propNames) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type propfindRequest struct {
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     propNames `xml:"DAV: prop"`
+}
+
+type calendarMultigetRequest struct {
+	Prop  propNames `xml:"DAV: prop"`
+	Hrefs []string  `xml:"DAV: href"`
+}
+
+type calendarQueryRequest struct {
+	Prop   propNames `xml:"DAV: prop"`
+	Filter struct {
+		CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type compFilter struct {
+	Name        string       `xml:"name,attr"`
+	CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	// Other holds the filters this server does not evaluate.
+	Other []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+type syncCollectionRequest struct {
+	SyncToken string    `xml:"DAV: sync-token"`
+	SyncLevel string    `xml:"DAV: sync-level"`
+	Prop      propNames `xml:"DAV: prop"`
+}
+
+var errEmptyBody = errors.New("empty body")
+
+// readDAVRequest decodes the XML body of a request and returns the name of its root element.
+// A request without a body returns errEmptyBody.
+func readDAVRequest(r *http.Request, decode func(root xml.StartElement, d *xml.Decoder) error) (xml.Name, error) {
+	d := xml.NewDecoder(io.LimitReader(r.Body, maxDAVRequestBody))
+	for {
+		token, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return xml.Name{}, errEmptyBody
+		}
+		if err != nil {
+			return xml.Name{}, err
+		}
+
+		if root, ok := token.(xml.StartElement); ok {
+			return root.Name, decode(root, d)
+		}
+	}
+}
+
+// multistatus writes a DAV:multistatus response body.
+type multistatus struct {
+	b strings.Builder
+}
+
+func newMultistatus() *multistatus {
+	m := &multistatus{}
+	m.b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	m.b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="` + calDAVNS + `" xmlns:cs="` + calendarServerNS + `">`)
+
+	return m
+}
+
+// response writes the properties of a resource that were asked for, and the ones it does not
+// have in a 404 propstat.
+func (m *multistatus) response(href string, found []davProperty, missing []xml.Name) {
+	m.b.WriteString("<d:response><d:href>")
+	xmlText(&m.b, href)
+	m.b.WriteString("</d:href>")
+
+	if len(found) > 0 {
+		m.b.WriteString("<d:propstat><d:prop>")
+		for _, property := range found {
+			writeElement(&m.b, property.name, property.value)
+		}
+		m.b.WriteString("</d:prop>")
+		m.status(http.StatusOK)
+		m.b.WriteString("</d:propstat>")
+	}
+
+	if len(missing) > 0 {
+		m.b.WriteString("<d:propstat><d:prop>")
+		for _, name := range missing {
+			writeElement(&m.b, name, "")
+		}
+		m.b.WriteString("</d:prop>")
+		m.status(http.StatusNotFound)
+		m.b.WriteString("</d:propstat>")
+	}
+
+	m.b.WriteString("</d:response>")
+}
+
+// missing writes the response of a resource that is not there, or no longer is.
+func (m *multistatus) missing(href string) {
+	m.b.WriteString("<d:response><d:href>")
+	xmlText(&m.b, href)
+	m.b.WriteString("</d:href>")
+	m.status(http.StatusNotFound)
+	m.b.WriteString("</d:response>")
+}
+
+func (m *multistatus) syncToken(token string) {
+	m.b.WriteString("<d:sync-token>")
+	xmlText(&m.b, token)
+	m.b.WriteString("</d:sync-token>")
+}
+
+func (m *multistatus) status(code int) {
+	fmt.Fprintf(&m.b, "<d:status>HTTP/1.1 %d %s</d:status>", code, http.StatusText(code))
+}
+
+// writeTo closes the body and sends it with the 207 Multi-Status code.
+func (m *multistatus) writeTo(w http.ResponseWriter) error {
+	m.b.WriteString("</d:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, err := io.WriteString(w, m.b.String())
+
+	return err
+}
+
+// writeDAVError answers with a DAV:error body naming the precondition that failed.
+func writeDAVError(w http.ResponseWriter, code int, precondition xml.Name) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<d:error xmlns:d="DAV:" xmlns:c="` + calDAVNS + `">`)
+	writeElement(&b, precondition, "")
+	b.WriteString("</d:error>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = io.WriteString(w, b.String())
+}
+
+// writeElement writes an element with an XML value, prefixing the known namespaces and declaring the others.
+func writeElement(b *strings.Builder, name xml.Name, value string) {
+	tag := name.Local
+	declaration := ""
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		var escaped strings.Builder
+		xmlText(&escaped, name.Space)
+		declaration = ` xmlns:x="` + escaped.String() + `"`
+	}
+
+	if value == "" {
+		b.WriteString("<" + tag + declaration + "/>")
+		return
+	}
+
+	b.WriteString("<" + tag + declaration + ">" + value + "</" + tag + ">")
+}
+
+func xmlText(b *strings.Builder, text string) {
+	_ = xml.EscapeText(b, []byte(text))
+}
+
+// hrefValue is the value of a property that holds a single DAV:href.
+func hrefValue(href string) string {
+	var b strings.Builder
+	b.WriteString("<d:href>")
+	xmlText(&b, href)
+	b.WriteString("</d:href>")
+
+	return b.String()
+}
+
+// textValue is the value of a property that holds text.
+func textValue(text string) string {
+	var b strings.Builder
+	xmlText(&b, text)
+
+	return b.String()
+}
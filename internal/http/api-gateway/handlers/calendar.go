@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+type regenerateCalendarTokenRequest struct {
+	ReminderMinutes int `json:"reminder_minutes"`
+}
+
+type calendarTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+	*models.CalendarFeed
+}
+
+// HandleRegenerateCalendarToken makes a new calendar subscription URL for the user and revokes
+// the one before. The optional body {"reminder_minutes": 15} makes events alert before they are due.
+func (api *APIGateway) HandleRegenerateCalendarToken(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleRegenerateCalendarToken"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req regenerateCalendarTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, feed, err := api.Task.RegenerateCalendarToken(r.Context(), sess.UserID, req.ReminderMinutes)
+	if err != nil {
+		log.Error("failed to regenerate calendar token", slog.String("error", err.Error()))
+		http.Error(w, "Failed to regenerate calendar token", httpStatus(err))
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	log.Info("Calendar token regenerated successfully")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(calendarTokenResponse{
+		Token:        token,
+		URL:          scheme + "://" + r.Host + "/calendar/" + token + ".ics",
+		CalendarFeed: feed,
+	})
+	if err != nil {
+		log.Error("failed to encode calendar token", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleRevokeCalendarToken(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleRevokeCalendarToken"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	if err := api.Task.RevokeCalendarToken(r.Context(), sess.UserID); err != nil {
+		log.Error("failed to revoke calendar token", slog.String("error", err.Error()))
+		http.Error(w, "Failed to revoke calendar token", httpStatus(err))
+		return
+	}
+
+	log.Info("Calendar token revoked successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleCalendarFeed serves /calendar/{token}.ics to calendar apps, which cannot log in: the
+// token in the URL is the only credential. Polls with the ETag of the last response in
+// If-None-Match get 304 Not Modified while the calendar stays the same.
+func (api *APIGateway) HandleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleCalendarFeed"
+
+	log := api.log.With(
+		slog.String("op", op))
+
+	// Patterns cannot match part of a segment, so the extension is stripped here.
+	token, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+	if !ok || token == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, etag, err := api.Task.GetCalendarFeed(r.Context(), token)
+	if err != nil {
+		code := httpStatus(err)
+		if code != http.StatusNotFound {
+			log.Error("failed to get calendar feed", slog.String("error", err.Error()))
+		}
+		http.Error(w, "Failed to get calendar feed", code)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if _, err := w.Write(data); err != nil {
+		log.Error("failed to write calendar feed", slog.String("error", err.Error()))
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly as RFC 9110 asks.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
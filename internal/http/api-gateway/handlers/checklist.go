@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+type checklistResponse struct {
+	Items   []*models.ChecklistItem  `json:"items"`
+	Summary *models.ChecklistSummary `json:"summary"`
+}
+
+func (api *APIGateway) HandleAddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleAddChecklistItem"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	taskID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Text     string `json:"text"`
+		Position *int   `json:"position"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := api.Task.AddChecklistItem(r.Context(), taskID, sess.UserID, req.Text, req.Position)
+	if err != nil {
+		log.Error("failed to add checklist item", slog.String("error", err.Error()))
+		http.Error(w, "Failed to add checklist item", httpStatus(err))
+		return
+	}
+
+	log.Info("Checklist item added successfully", "itemID", item.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		log.Error("failed to encode checklist item", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleToggleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleToggleChecklistItem"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	itemID, err := uuid.Parse(r.PathValue("itemID"))
+	if err != nil {
+		http.Error(w, "Invalid checklist item ID", http.StatusBadRequest)
+		return
+	}
+
+	item, err := api.Task.ToggleChecklistItem(r.Context(), itemID, sess.UserID)
+	if err != nil {
+		log.Error("failed to toggle checklist item", slog.String("error", err.Error()))
+		http.Error(w, "Failed to toggle checklist item", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		log.Error("failed to encode checklist item", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode checklist item", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (api *APIGateway) HandleReorderChecklistItem(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleReorderChecklistItem"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	itemID, err := uuid.Parse(r.PathValue("itemID"))
+	if err != nil {
+		http.Error(w, "Invalid checklist item ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Position int `json:"position"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	items, summary, err := api.Task.ReorderChecklistItem(r.Context(), itemID, sess.UserID, req.Position)
+	if err != nil {
+		log.Error("failed to reorder checklist item", slog.String("error", err.Error()))
+		http.Error(w, "Failed to reorder checklist item", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(checklistResponse{Items: items, Summary: summary}); err != nil {
+		log.Error("failed to encode checklist", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode checklist", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (api *APIGateway) HandleRemoveChecklistItem(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleRemoveChecklistItem"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	itemID, err := uuid.Parse(r.PathValue("itemID"))
+	if err != nil {
+		http.Error(w, "Invalid checklist item ID", http.StatusBadRequest)
+		return
+	}
+
+	items, summary, err := api.Task.RemoveChecklistItem(r.Context(), itemID, sess.UserID)
+	if err != nil {
+		log.Error("failed to remove checklist item", slog.String("error", err.Error()))
+		http.Error(w, "Failed to remove checklist item", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(checklistResponse{Items: items, Summary: summary}); err != nil {
+		log.Error("failed to encode checklist", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode checklist", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/websocket"
+)
+
+const (
+	// heartbeatInterval is how often an event stream shows it is alive while no events come.
+	heartbeatInterval = 15 * time.Second
+	// maxPendingEvents is how many events a client may fall behind before its connection is dropped.
+	maxPendingEvents = 256
+	// eventWriteTimeout bounds the sending of one event to a client.
+	eventWriteTimeout = 10 * time.Second
+	// watchRetryDelay is the wait before watching again when the task service dropped the stream.
+	watchRetryDelay = time.Second
+	// sseRetryMillis is the reconnection delay EventSource clients are told to use.
+	sseRetryMillis = 3000
+)
+
+var errLagging = errors.New("client fell behind")
+
+// taskEventMessage is an event as clients get it.
+type taskEventMessage struct {
+	// ID is the sequence number clients resume after.
+	ID         string       `json:"id"`
+	Type       string       `json:"type"`
+	TaskID     string       `json:"task_id,omitempty"`
+	Task       *models.Task `json:"task,omitempty"`
+	OccurredAt time.Time    `json:"occurred_at"`
+}
+
+func newTaskEventMessage(event models.TaskEvent) taskEventMessage {
+	message := taskEventMessage{
+		ID:         strconv.FormatUint(event.Seq, 10),
+		Type:       event.Type,
+		Task:       event.Task,
+		OccurredAt: event.OccurredAt,
+	}
+	if event.TaskID != uuid.Nil {
+		message.TaskID = event.TaskID.String()
+	}
+
+	return message
+}
+
+// eventFilter selects the events of a connection by the project and the tags of their task.
+type eventFilter struct {
+	byProject bool
+	// project is invalid for the inbox.
+	project uuid.NullUUID
+	// tags select the tasks with any of them.
+	tags []string
+	// matched holds the tasks that match, so that the update taking one out of the filter is sent too.
+	matched map[uuid.UUID]bool
+}
+
+// parseEventFilter reads the filter of a connection from ?project=<id or inbox>&tag=a&tag=b.
+func parseEventFilter(query url.Values) (*eventFilter, error) {
+	filter := &eventFilter{matched: make(map[uuid.UUID]bool)}
+
+	if project := query.Get("project"); project != "" {
+		filter.byProject = true
+		if project != "inbox" {
+			id, err := uuid.Parse(project)
+			if err != nil {
+				return nil, fmt.Errorf("invalid project %q", project)
+			}
+			filter.project = uuid.NullUUID{UUID: id, Valid: true}
+		}
+	}
+
+	for _, tag := range query["tag"] {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" {
+			return nil, errors.New("empty tag")
+		}
+		filter.tags = append(filter.tags, tag)
+	}
+
+	return filter, nil
+}
+
+func (f *eventFilter) active() bool {
+	return f.byProject || len(f.tags) > 0
+}
+
+func (f *eventFilter) matches(task *models.Task) bool {
+	if f.byProject && task.ProjectID != f.project {
+		return false
+	}
+
+	if len(f.tags) == 0 {
+		return true
+	}
+	for _, tag := range task.Tags {
+		for _, want := range f.tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// seed marks the tasks that match before the first event.
+func (f *eventFilter) seed(tasks []*models.Task) {
+	for _, task := range tasks {
+		if f.matches(task) {
+			f.matched[task.ID] = true
+		}
+	}
+}
+
+// pass reports whether an event goes to the client. Deleted tasks cannot be matched, so their
+// events always pass and clients ignore the ones of tasks they do not know.
+func (f *eventFilter) pass(event models.TaskEvent) bool {
+	if !f.active() || event.Task == nil {
+		delete(f.matched, event.TaskID)
+		return true
+	}
+
+	if f.matches(event.Task) {
+		f.matched[event.TaskID] = true
+		return true
+	}
+
+	if f.matched[event.TaskID] {
+		delete(f.matched, event.TaskID)
+		return true
+	}
+
+	return false
+}
+
+// openEventStream reads the filter and the resume point of a stream request and seeds the filter.
+// It answers bad requests itself and returns ok false for them.
+func (api *APIGateway) openEventStream(w http.ResponseWriter, r *http.Request, log *slog.Logger, userID uuid.UUID, lastEventID string) (*eventFilter, uint64, bool) {
+	filter, err := parseEventFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	var after uint64
+	if lastEventID != "" {
+		if after, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			http.Error(w, "Invalid last event ID", http.StatusBadRequest)
+			return nil, 0, false
+		}
+	}
+
+	if filter.active() {
+		tasks, err := api.Task.GetTask(r.Context(), userID)
+		if err != nil {
+			log.Error("failed to get tasks", slog.String("error", err.Error()))
+			http.Error(w, "Failed to watch tasks", httpStatus(err))
+			return nil, 0, false
+		}
+		filter.seed(tasks)
+	}
+
+	return filter, after, true
+}
+
+// relayEvents passes the events of the user's tasks after the event numbered after through the
+// filter into events, until ctx is done. A stream the task service drops is watched again from
+// the last event seen, a client whose events fill up the channel ends the relay with errLagging.
+func (api *APIGateway) relayEvents(ctx context.Context, userID uuid.UUID, after uint64, filter *eventFilter, events chan<- models.TaskEvent) error {
+	for {
+		err := api.Task.WatchTasks(ctx, userID, after, true, func(event models.TaskEvent) error {
+			after = event.Seq
+			if !filter.pass(event) {
+				return nil
+			}
+
+			select {
+			case events <- event:
+				return nil
+			default:
+				return errLagging
+			}
+		})
+		if errors.Is(err, errLagging) || ctx.Err() != nil || status.Code(err) != codes.Unavailable {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+// HandleEvents streams the changes of the user's tasks as Server-Sent Events named created,
+// updated, deleted and reset, with the JSON of a taskEventMessage as data. A client reconnecting
+// with Last-Event-ID, or ?last_event_id= on its first connection, gets the events it missed, or a
+// reset event telling it to reload its tasks when they are no longer kept. ?project= and ?tag=
+// select the events, see parseEventFilter.
+func (api *APIGateway) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleEvents"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	filter, after, ok := api.openEventStream(w, r, log, sess.UserID, lastEventID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	events := make(chan models.TaskEvent, maxPendingEvents)
+	relayDone := make(chan error, 1)
+	go func() { relayDone <- api.relayEvents(ctx, sess.UserID, after, filter, events) }()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Proxies such as nginx would hold the events back otherwise.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(chunk string) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(eventWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if _, err := fmt.Fprint(w, chunk); err != nil {
+			return err
+		}
+
+		return rc.Flush()
+	}
+	sendEvent := func(event models.TaskEvent) error {
+		data, err := json.Marshal(newTaskEventMessage(event))
+		if err != nil {
+			return err
+		}
+
+		return send(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data))
+	}
+
+	if err := send(fmt.Sprintf("retry: %d\n\n", sseRetryMillis)); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			err = sendEvent(event)
+		case <-heartbeat.C:
+			err = send(": heartbeat\n\n")
+		case err := <-relayDone:
+			// The events relayed before the end still go out, a lagging client resumes after them.
+			for len(events) > 0 {
+				if sendEvent(<-events) != nil {
+					return
+				}
+			}
+			if err != nil && ctx.Err() == nil && !errors.Is(err, errLagging) {
+				log.Error("failed to watch tasks", slog.String("error", err.Error()))
+			}
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// HandleWebSocket pushes the events of HandleEvents over a WebSocket, one taskEventMessage per text
+// message. ?last_event_id= resumes after an event, ?project= and ?tag= select the events. Messages
+// from the client are ignored. A client that falls behind is closed with 1013 and should reconnect
+// with the ID of the last event it got.
+func (api *APIGateway) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleWebSocket"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	// The login cookie goes along with WebSocket requests from any page, only ours may use it.
+	// Upgrade refuses the others too, this spares them the event stream.
+	if !websocket.SameOrigin(r) {
+		http.Error(w, "Cross-origin WebSocket requests are not allowed", http.StatusForbidden)
+		return
+	}
+
+	filter, after, ok := api.openEventStream(w, r, log, sess.UserID, r.URL.Query().Get("last_event_id"))
+	if !ok {
+		return
+	}
+
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		if !errors.Is(err, websocket.ErrBadHandshake) && !errors.Is(err, websocket.ErrBadOrigin) {
+			log.Error("failed to upgrade connection", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	// The request context ends with the handler, which the connection outlives no longer.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	defer cancel()
+
+	// Reading answers the pings of the client and notices it leaving, or no longer answering ours.
+	conn.SetReadTimeout(2 * heartbeatInterval)
+	go func() {
+		defer cancel()
+		for {
+			if _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	events := make(chan models.TaskEvent, maxPendingEvents)
+	relayDone := make(chan error, 1)
+	go func() { relayDone <- api.relayEvents(ctx, sess.UserID, after, filter, events) }()
+
+	sendEvent := func(event models.TaskEvent) error {
+		data, err := json.Marshal(newTaskEventMessage(event))
+		if err != nil {
+			return err
+		}
+
+		return conn.WriteText(data, time.Now().Add(eventWriteTimeout))
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			err = sendEvent(event)
+		case <-heartbeat.C:
+			err = conn.Ping(time.Now().Add(eventWriteTimeout))
+		case <-ctx.Done():
+			_ = conn.Close(websocket.CloseGoingAway, "")
+			return
+		case err := <-relayDone:
+			for len(events) > 0 {
+				if sendEvent(<-events) != nil {
+					_ = conn.Close(websocket.CloseGoingAway, "")
+					return
+				}
+			}
+
+			switch {
+			case errors.Is(err, errLagging):
+				_ = conn.Close(websocket.CloseTryAgainLater, "fell behind, resume after the last event")
+			case ctx.Err() != nil:
+				_ = conn.Close(websocket.CloseGoingAway, "")
+			default:
+				log.Error("failed to watch tasks", slog.String("error", err.Error()))
+				_ = conn.Close(websocket.CloseInternalError, "")
+			}
+			return
+		}
+		if err != nil {
+			_ = conn.Close(websocket.CloseGoingAway, "")
+			return
+		}
+	}
+}
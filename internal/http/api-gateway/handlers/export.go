@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// exportFormats maps the formats of /tasks/export to their content type and file extension.
+var exportFormats = map[string]struct {
+	contentType string
+	extension   string
+}{
+	models.ExportJSONL:    {"application/x-ndjson", "jsonl"},
+	models.ExportCSV:      {"text/csv; charset=utf-8", "csv"},
+	models.ExportMarkdown: {"text/markdown; charset=utf-8", "md"},
+	models.ExportICS:      {"text/calendar; charset=utf-8", "ics"},
+}
+
+// HandleExportTasks streams the user's tasks as a file download, for example
+// /tasks/export?format=csv&filter={"status":["to-do"]}. format defaults to jsonl, filter is an
+// optional view query without sort, and timezone defaults to the one of the session.
+func (api *APIGateway) HandleExportTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleExportTasks"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = models.ExportJSONL
+	}
+
+	exportFormat, ok := exportFormats[format]
+	if !ok {
+		http.Error(w, "Unknown export format", http.StatusBadRequest)
+		return
+	}
+
+	timezone := query.Get("timezone")
+	if timezone == "" {
+		timezone = sess.Timezone
+	}
+
+	body, err := api.Task.ExportTasks(r.Context(), sess.UserID, format, query.Get("filter"), timezone)
+	if err != nil {
+		log.Error("failed to export tasks", slog.String("error", err.Error()))
+		http.Error(w, "Failed to export tasks", httpStatus(err))
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", exportFormat.contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "tasks." + exportFormat.extension}))
+
+	if _, err := io.Copy(w, body); err != nil {
+		log.Error("failed to stream export", slog.String("error", err.Error()))
+	}
+}
@@ -3,24 +3,90 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/SlashLight/todo-list/internal/domain/models"
 )
 
 type AuthAPI interface {
-	Register(ctx context.Context, email, password string) (string, error)
+	Register(ctx context.Context, email, password, timezone string) (string, error)
 	Login(ctx context.Context, email, password string) (string, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, timezone string) (string, error)
+	DeleteAccount(ctx context.Context, userID uuid.UUID) error
 }
 
 type TaskAPI interface {
-	CreateTask(ctx context.Context, authorID uuid.UUID, title, description, deadline string) (string, error)
+	CreateTask(ctx context.Context, authorID, projectID uuid.UUID, title, description, status string, deadline time.Time, dueDate string) (string, error)
 	GetTask(ctx context.Context, authorID uuid.UUID) ([]*models.Task, error)
-	UpdateTask(ctx context.Context, taskID, authorID uuid.UUID, title, description, status, deadline string) error
+	UpdateTask(ctx context.Context, taskID, authorID uuid.UUID, title, description, status string, deadline time.Time, dueDate string) error
 	DeleteTask(ctx context.Context, taskID, authorID uuid.UUID) error
+	MoveTask(ctx context.Context, taskID, authorID, beforeID, afterID uuid.UUID, status string) (string, error)
+	SetTaskEstimate(ctx context.Context, taskID, authorID uuid.UUID, minutes *int) (*models.Task, error)
+	SearchTasks(ctx context.Context, authorID uuid.UUID, query string, limit, offset int) ([]*models.SearchHit, error)
+	QuickAdd(ctx context.Context, authorID, projectID uuid.UUID, text, timezone string) (*models.Task, *models.QuickAddParse, error)
+	ExportTasks(ctx context.Context, authorID uuid.UUID, format, filter, timezone string) (io.ReadCloser, error)
+	ImportTasks(ctx context.Context, authorID uuid.UUID, options models.ImportOptions, r io.Reader) (*models.ImportReport, error)
+	WatchTasks(ctx context.Context, authorID uuid.UUID, after uint64, resetOnExpiry bool, handle func(models.TaskEvent) error) error
+	Sync(ctx context.Context, authorID uuid.UUID, syncToken int64, clientTime time.Time, changes []models.TaskChange) (*models.SyncResult, error)
+
+	RegenerateCalendarToken(ctx context.Context, authorID uuid.UUID, reminderMinutes int) (string, *models.CalendarFeed, error)
+	RevokeCalendarToken(ctx context.Context, authorID uuid.UUID) error
+	GetCalendarFeed(ctx context.Context, token string) ([]byte, string, error)
+
+	ListCalendars(ctx context.Context, authorID uuid.UUID) ([]models.CalendarCollection, int64, error)
+	GetCalendarObjects(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, names []string) ([]*models.CalendarObject, error)
+	SyncCalendarObjects(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, syncToken int64) (*models.CalendarChanges, error)
+	PutCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name string, data []byte, ifMatch, ifNoneMatch, timezone string) (bool, error)
+	DeleteCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name, ifMatch string) error
+
+	CreateView(ctx context.Context, authorID uuid.UUID, name, query string) (*models.View, error)
+	ListViews(ctx context.Context, authorID uuid.UUID) ([]*models.View, error)
+	RunView(ctx context.Context, authorID uuid.UUID, viewID, timezone string) ([]*models.Task, error)
+	DeleteView(ctx context.Context, authorID uuid.UUID, viewID string) error
+
+	CreateProject(ctx context.Context, authorID uuid.UUID, name string) (*models.Project, error)
+	ListProjects(ctx context.Context, authorID uuid.UUID) ([]*models.Project, error)
+	GetBoard(ctx context.Context, authorID, projectID uuid.UUID, status string, limit int, pageToken string) (*models.Board, error)
+
+	StartTimer(ctx context.Context, taskID, authorID uuid.UUID, note string) (*models.TimeEntry, error)
+	StopTimer(ctx context.Context, authorID uuid.UUID) (*models.TimeEntry, error)
+	LogTime(ctx context.Context, taskID, authorID uuid.UUID, startedAt time.Time, duration time.Duration, note string) (*models.TimeEntry, error)
+	ListTimeEntries(ctx context.Context, taskID, authorID uuid.UUID) ([]*models.TimeEntry, error)
+	GetTimeReport(ctx context.Context, authorID, projectID uuid.UUID, from, to time.Time) (*models.TimeReport, error)
+
+	AddChecklistItem(ctx context.Context, taskID, authorID uuid.UUID, text string, position *int) (*models.ChecklistItem, error)
+	ToggleChecklistItem(ctx context.Context, itemID, authorID uuid.UUID) (*models.ChecklistItem, error)
+	ReorderChecklistItem(ctx context.Context, itemID, authorID uuid.UUID, position int) ([]*models.ChecklistItem, *models.ChecklistSummary, error)
+	RemoveChecklistItem(ctx context.Context, itemID, authorID uuid.UUID) ([]*models.ChecklistItem, *models.ChecklistSummary, error)
+
+	UploadAttachment(ctx context.Context, taskID, authorID uuid.UUID, name, contentType string, r io.Reader) (*models.Attachment, error)
+	ListAttachments(ctx context.Context, taskID, authorID uuid.UUID) ([]*models.Attachment, error)
+	DownloadAttachment(ctx context.Context, attachmentID, authorID uuid.UUID) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, attachmentID, authorID uuid.UUID) error
+
+	CreateTemplate(ctx context.Context, authorID, projectID uuid.UUID, name, tasks string) (*models.Template, error)
+	ListTemplates(ctx context.Context, authorID uuid.UUID) ([]*models.Template, error)
+	DeleteTemplate(ctx context.Context, templateID, authorID uuid.UUID) error
+	InstantiateTemplate(ctx context.Context, templateID, authorID, projectID uuid.UUID, start, timezone string, variables map[string]string) ([]*models.Task, error)
+
+	CreateWebhook(ctx context.Context, authorID, projectID uuid.UUID, url string, eventTypes []string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, authorID uuid.UUID) ([]*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhookID, authorID, projectID uuid.UUID, url string, eventTypes []string, enabled bool) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID, authorID uuid.UUID) error
+	ListWebhookDeliveries(ctx context.Context, webhookID, authorID uuid.UUID, limit int) ([]*models.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID, authorID uuid.UUID) (*models.WebhookDelivery, error)
+
+	BatchCreateTasks(ctx context.Context, authorID uuid.UUID, tasks []*models.Task, atomic bool) ([]models.BatchResult, error)
+	BatchUpdateTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector, patch models.TaskPatch, atomic bool) ([]models.BatchResult, error)
+	BatchDeleteTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector, atomic bool) ([]models.BatchResult, error)
 }
 
 type APIGateway struct {
@@ -37,6 +103,12 @@ func New(authAPI AuthAPI, taskAPI TaskAPI, log *slog.Logger) *APIGateway {
 	}
 }
 
+// Login logs a user in for the middleware of clients that authenticate every request, see
+// middleware.BasicAuthMiddleware.
+func (api *APIGateway) Login(ctx context.Context, email, password string) (string, error) {
+	return api.Auth.Login(ctx, email, password)
+}
+
 func (api *APIGateway) HandleRegister(w http.ResponseWriter, r *http.Request) {
 	const op = "APIGateway.HandleRegister"
 
@@ -45,6 +117,8 @@ func (api *APIGateway) HandleRegister(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		// Timezone is the IANA name of the user's timezone, UTC when left out.
+		Timezone string `json:"timezone"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -54,11 +128,11 @@ func (api *APIGateway) HandleRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := api.Auth.Register(r.Context(), req.Email, req.Password)
+	id, err := api.Auth.Register(r.Context(), req.Email, req.Password, req.Timezone)
 	if err != nil {
 		//TODO: ...
 		log.Error("failed to register user", slog.String("error", err.Error()))
-		http.Error(w, "Failed to register user", http.StatusInternalServerError)
+		http.Error(w, "Failed to register user", httpStatus(err))
 		return
 	}
 
@@ -93,6 +167,74 @@ func (api *APIGateway) HandleLogin(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// HandleUpdateProfile sets the timezone of the user's profile from {"timezone": "Europe/Berlin"}.
+// The session timezone comes from the token, so the response carries a new one.
+func (api *APIGateway) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleUpdateProfile"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		Timezone string `json:"timezone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := api.Auth.UpdateProfile(r.Context(), sess.UserID, req.Timezone)
+	if err != nil {
+		log.Error("failed to update profile", slog.String("error", err.Error()))
+		http.Error(w, "Failed to update profile", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"token": token}); err != nil {
+		log.Error("failed to encode token", slog.String("error", err.Error()))
+	}
+}
+
+// HandleDeleteAccount deletes the user. The tasks and the rest of the user's data are deleted by
+// the task service shortly after, so the response is 202 Accepted.
+func (api *APIGateway) HandleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleDeleteAccount"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	if err := api.Auth.DeleteAccount(r.Context(), sess.UserID); err != nil {
+		log.Error("failed to delete account", slog.String("error", err.Error()))
+		http.Error(w, "Failed to delete account", httpStatus(err))
+		return
+	}
+
+	log.Info("Account deleted successfully")
+	w.Header().Set("Set-Cookie", "token=; HttpOnly; Secure; SameSite=Strict; Path=/; Max-Age=0")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// HandleCreateTask creates a task with either a deadline or an all-day due_date (YYYY-MM-DD), in the
+// board column of its status, to-do when it is left out.
+// A deadline without a UTC offset is a wall clock time in the user's timezone.
 func (api *APIGateway) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
 	const op = "APIGateway.HandleCreateTask"
 
@@ -112,6 +254,9 @@ func (api *APIGateway) HandleCreateTask(w http.ResponseWriter, r *http.Request)
 		Description string `json:"description"`
 		Status      string `json:"status"`
 		Deadline    string `json:"deadline"`
+		DueDate     string `json:"due_date"`
+		// ProjectID is left out for inbox tasks.
+		ProjectID uuid.UUID `json:"project_id"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Error("failed to decode request body", slog.String("error", err.Error()))
@@ -119,10 +264,16 @@ func (api *APIGateway) HandleCreateTask(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	taskID, err := api.Task.CreateTask(r.Context(), sess.UserID, req.Title, req.Description, req.Deadline)
+	deadline, err := parseDeadline(req.Deadline, sess.Location())
+	if err != nil {
+		http.Error(w, "Invalid deadline", http.StatusBadRequest)
+		return
+	}
+
+	taskID, err := api.Task.CreateTask(r.Context(), sess.UserID, req.ProjectID, req.Title, req.Description, req.Status, deadline, req.DueDate)
 	if err != nil {
 		log.Error("failed to create task", slog.String("error", err.Error()))
-		http.Error(w, "Failed to create task", http.StatusInternalServerError)
+		http.Error(w, "Failed to create task", httpStatus(err))
 		return
 	}
 
@@ -163,6 +314,95 @@ func (api *APIGateway) HandleGetTask(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleMoveTask reorders a task, typically after a drag and drop on a kanban board.
+// Setting status moves the card to another column.
+func (api *APIGateway) HandleMoveTask(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleMoveTask"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	taskID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Before uuid.UUID `json:"before"`
+		After  uuid.UUID `json:"after"`
+		Status string    `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	position, err := api.Task.MoveTask(r.Context(), taskID, sess.UserID, req.Before, req.After, req.Status)
+	if err != nil {
+		log.Error("failed to move task", slog.String("error", err.Error()))
+		http.Error(w, "Failed to move task", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"position": position}); err != nil {
+		log.Error("failed to encode position", slog.String("error", err.Error()))
+	}
+}
+
+// HandleSetTaskEstimate sets the estimate of a task from {"minutes": n}, a null or missing value clears it.
+func (api *APIGateway) HandleSetTaskEstimate(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleSetTaskEstimate"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	taskID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Minutes *int `json:"minutes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := api.Task.SetTaskEstimate(r.Context(), taskID, sess.UserID, req.Minutes)
+	if err != nil {
+		log.Error("failed to set task estimate", slog.String("error", err.Error()))
+		http.Error(w, "Failed to set task estimate", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(task); err != nil {
+		log.Error("failed to encode task", slog.String("error", err.Error()))
+	}
+}
+
 func (api *APIGateway) HandleUpdateTask(w http.ResponseWriter, r *http.Request) {
 	return
 }
@@ -170,3 +410,50 @@ func (api *APIGateway) HandleUpdateTask(w http.ResponseWriter, r *http.Request)
 func (api *APIGateway) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
 	return
 }
+
+// localDeadlineLayouts are accepted for deadlines that carry no UTC offset.
+var localDeadlineLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// parseDeadline reads an RFC 3339 deadline, or a local date and time taken in loc.
+// An empty value is the zero time of a task without a deadline.
+func parseDeadline(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if deadline, err := time.Parse(time.RFC3339, value); err == nil {
+		return deadline, nil
+	}
+
+	for _, layout := range localDeadlineLayouts {
+		if deadline, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return deadline, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("deadline %q is neither RFC 3339 nor a local date and time", value)
+}
+
+// httpStatus maps the gRPC status of a downstream call onto the closest HTTP status code.
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusRequestEntityTooLarge
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// maxImportField is the largest form field of an import accepted, in bytes.
+const maxImportField = 64 * 1024
+
+// HandleImportTasks imports a file of another to-do app from a multipart/form-data body. The
+// fields format (csv, todotxt, todoist or trello), mapping (a JSON object of task field to CSV
+// column), project_id, timezone and dry_run must come before the file part, which is streamed
+// to the task service. timezone defaults to the one of the session.
+//
+// The report of the import is returned with 201 when the tasks were created, 200 for a dry run
+// and 422 when invalid rows kept the import from writing anything.
+func (api *APIGateway) HandleImportTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleImportTasks"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		log.Error("failed to read multipart body", slog.String("error", err.Error()))
+		http.Error(w, "Expected multipart/form-data body", http.StatusBadRequest)
+		return
+	}
+
+	options := models.ImportOptions{Timezone: sess.Timezone}
+	var report *models.ImportReport
+	for report == nil {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			http.Error(w, "No file in request", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Error("failed to read multipart part", slog.String("error", err.Error()))
+			http.Error(w, "Invalid multipart body", http.StatusBadRequest)
+			return
+		}
+
+		if part.FileName() != "" {
+			report, err = api.Task.ImportTasks(r.Context(), sess.UserID, options, part)
+			part.Close()
+			if err != nil {
+				log.Error("failed to import tasks", slog.String("error", err.Error()))
+				http.Error(w, "Failed to import tasks", httpStatus(err))
+				return
+			}
+			continue
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxImportField))
+		part.Close()
+		if err != nil {
+			log.Error("failed to read multipart part", slog.String("error", err.Error()))
+			http.Error(w, "Invalid multipart body", http.StatusBadRequest)
+			return
+		}
+
+		if msg := setImportOption(&options, part.FormName(), string(value)); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+	}
+
+	code := http.StatusCreated
+	switch {
+	case len(report.Errors) > 0:
+		code = http.StatusUnprocessableEntity
+	case report.DryRun:
+		code = http.StatusOK
+	}
+
+	log.Info("Tasks imported", "created", report.Created, "duplicates", report.Duplicates, "errors", len(report.Errors))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("failed to encode import report", slog.String("error", err.Error()))
+	}
+}
+
+// setImportOption sets the option of a form field and returns the error message for an invalid
+// value. Unknown fields are ignored.
+func setImportOption(options *models.ImportOptions, name, value string) string {
+	switch name {
+	case "format":
+		options.Format = value
+	case "mapping":
+		if err := json.Unmarshal([]byte(value), &options.ColumnMapping); err != nil {
+			return "Invalid column mapping"
+		}
+	case "project_id":
+		if value == "" {
+			return ""
+		}
+		projectID, err := uuid.Parse(value)
+		if err != nil {
+			return "Invalid project ID"
+		}
+		options.ProjectID = uuid.NullUUID{UUID: projectID, Valid: true}
+	case "timezone":
+		if value != "" {
+			options.Timezone = value
+		}
+	case "dry_run":
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return "Invalid dry_run"
+		}
+		options.DryRun = dryRun
+	}
+
+	return ""
+}
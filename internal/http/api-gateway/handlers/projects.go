@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// inboxProjectID addresses the board of tasks that belong to no project.
+const inboxProjectID = "inbox"
+
+func (api *APIGateway) HandleCreateProject(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleCreateProject"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	project, err := api.Task.CreateProject(r.Context(), sess.UserID, req.Name)
+	if err != nil {
+		log.Error("failed to create project", slog.String("error", err.Error()))
+		http.Error(w, "Failed to create project", httpStatus(err))
+		return
+	}
+
+	log.Info("Project created successfully", "projectID", project.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(project); err != nil {
+		log.Error("failed to encode project", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleListProjects(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleListProjects"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	projects, err := api.Task.ListProjects(r.Context(), sess.UserID)
+	if err != nil {
+		log.Error("failed to list projects", slog.String("error", err.Error()))
+		http.Error(w, "Failed to list projects", httpStatus(err))
+		return
+	}
+
+	if len(projects) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(projects); err != nil {
+		log.Error("failed to encode projects", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode projects", http.StatusInternalServerError)
+		return
+	}
+}
+
+// HandleGetBoard returns the kanban board of a project, "inbox" as the ID selects tasks without a project.
+// Query parameters: limit caps the tasks per column; status with page_token fetches the next page of one column.
+func (api *APIGateway) HandleGetBoard(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleGetBoard"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var projectID uuid.UUID
+	if id := r.PathValue("id"); id != inboxProjectID {
+		projectID, err = uuid.Parse(id)
+		if err != nil {
+			http.Error(w, "Invalid project ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	query := r.URL.Query()
+
+	limit, err := intQueryParam(query.Get("limit"))
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	board, err := api.Task.GetBoard(r.Context(), sess.UserID, projectID, query.Get("status"), limit, query.Get("page_token"))
+	if err != nil {
+		log.Error("failed to get board", slog.String("error", err.Error()))
+		http.Error(w, "Failed to get board", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(board); err != nil {
+		log.Error("failed to encode board", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode board", http.StatusInternalServerError)
+		return
+	}
+}
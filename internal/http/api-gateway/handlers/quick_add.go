@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// HandleQuickAdd creates a task from {"text": "Pay rent every month on the 1st #home !high"}.
+// Dates are read in the session timezone unless the body names another one. The response
+// carries the task and the parse, so that a client can highlight the recognised tokens.
+func (api *APIGateway) HandleQuickAdd(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleQuickAdd"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		Text     string `json:"text"`
+		Timezone string `json:"timezone"`
+		// ProjectID is left out for inbox tasks.
+		ProjectID uuid.UUID `json:"project_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = sess.Timezone
+	}
+
+	task, parse, err := api.Task.QuickAdd(r.Context(), sess.UserID, req.ProjectID, req.Text, req.Timezone)
+	if err != nil {
+		log.Error("failed to quick add task", slog.String("error", err.Error()))
+		http.Error(w, "Failed to quick add task", httpStatus(err))
+		return
+	}
+
+	log.Info("Task quick added successfully", "taskID", task.ID)
+
+	resp := struct {
+		Task  *models.Task          `json:"task"`
+		Parse *models.QuickAddParse `json:"parse"`
+	}{task, parse}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode task", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (api *APIGateway) HandleSearchTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleSearchTasks"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	query := r.URL.Query()
+
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		http.Error(w, "Missing search query", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := intQueryParam(query.Get("limit"))
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	offset, err := intQueryParam(query.Get("offset"))
+	if err != nil {
+		http.Error(w, "Invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	hits, err := api.Task.SearchTasks(r.Context(), sess.UserID, q, limit, offset)
+	if err != nil {
+		log.Error("failed to search tasks", slog.String("error", err.Error()))
+		http.Error(w, "Failed to search tasks", httpStatus(err))
+		return
+	}
+
+	if len(hits) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(hits); err != nil {
+		log.Error("failed to encode search hits", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode search hits", http.StatusInternalServerError)
+		return
+	}
+}
+
+// intQueryParam parses an optional non-negative integer query parameter, empty means zero.
+func intQueryParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 0 {
+		return 0, strconv.ErrRange
+	}
+
+	return n, nil
+}
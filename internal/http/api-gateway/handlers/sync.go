@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/status"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// syncChange is an offline change as clients send it. The fields it sets are the keys of Fields:
+// "title", "description", "status", "due" as {"deadline": ..., "due_date": ...}, "priority",
+// "tags", "recurrence" and "project_id", null for the inbox.
+type syncChange struct {
+	ChangeID  string                     `json:"change_id"`
+	TaskID    uuid.UUID                  `json:"task_id"`
+	Deleted   bool                       `json:"deleted"`
+	Fields    map[string]json.RawMessage `json:"fields"`
+	ChangedAt time.Time                  `json:"changed_at"`
+}
+
+// taskChange reads the fields of the change, reading deadlines without an offset in loc.
+func (c syncChange) taskChange(loc *time.Location) (models.TaskChange, error) {
+	change := models.TaskChange{
+		ChangeID:  c.ChangeID,
+		TaskID:    c.TaskID,
+		Deleted:   c.Deleted,
+		ChangedAt: c.ChangedAt,
+	}
+	if len(c.Fields) == 0 {
+		return change, nil
+	}
+
+	task := &models.Task{}
+	for field, raw := range c.Fields {
+		var err error
+		switch field {
+		case models.SyncFieldTitle:
+			err = json.Unmarshal(raw, &task.Title)
+		case models.SyncFieldDescription:
+			err = json.Unmarshal(raw, &task.Description)
+		case models.SyncFieldStatus:
+			err = json.Unmarshal(raw, &task.Status)
+		case models.SyncFieldDue:
+			var due struct {
+				Deadline string `json:"deadline"`
+				DueDate  string `json:"due_date"`
+			}
+			if err = json.Unmarshal(raw, &due); err == nil {
+				task.DueDate = due.DueDate
+				task.Deadline, err = parseDeadline(due.Deadline, loc)
+			}
+		case models.SyncFieldPriority:
+			err = json.Unmarshal(raw, &task.Priority)
+		case models.SyncFieldTags:
+			err = json.Unmarshal(raw, &task.Tags)
+		case models.SyncFieldRecurrence:
+			err = json.Unmarshal(raw, &task.Recurrence)
+		case models.SyncFieldProject:
+			err = json.Unmarshal(raw, &task.ProjectID)
+		}
+		if err != nil {
+			return change, fmt.Errorf("invalid %s: %w", field, err)
+		}
+
+		// Unknown fields go on for the task service to reject the change alone.
+		change.Fields = append(change.Fields, field)
+	}
+	slices.Sort(change.Fields)
+	change.Task = task
+
+	return change, nil
+}
+
+type syncChangeResult struct {
+	ChangeID  string   `json:"change_id,omitempty"`
+	TaskID    string   `json:"task_id"`
+	Status    string   `json:"status"`
+	Conflicts []string `json:"conflicts,omitempty"`
+	// Code is the HTTP status of a rejected change.
+	Code  int    `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// HandleSync applies the changes a client made offline and returns the changes since its last sync:
+// {"sync_token": 0, "client_time": "...", "changes": [{"change_id": "1", "task_id": "...",
+// "changed_at": "...", "fields": {"title": "..."}}, {"task_id": "...", "deleted": true}]}.
+// Clients create tasks with IDs they generate, see syncChange for the fields.
+func (api *APIGateway) HandleSync(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleSync"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		SyncToken  int64        `json:"sync_token"`
+		ClientTime time.Time    `json:"client_time"`
+		Changes    []syncChange `json:"changes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	changes := make([]models.TaskChange, len(req.Changes))
+	for i, item := range req.Changes {
+		if changes[i], err = item.taskChange(sess.Location()); err != nil {
+			http.Error(w, fmt.Sprintf("Change %d: %s", i, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := api.Task.Sync(r.Context(), sess.UserID, req.SyncToken, req.ClientTime, changes)
+	if err != nil {
+		log.Error("failed to sync tasks", slog.String("error", err.Error()))
+		http.Error(w, "Failed to sync tasks", httpStatus(err))
+		return
+	}
+
+	resp := struct {
+		SyncToken  int64              `json:"sync_token"`
+		Full       bool               `json:"full"`
+		Changed    []*models.Task     `json:"changed"`
+		Deleted    []uuid.UUID        `json:"deleted"`
+		Results    []syncChangeResult `json:"results"`
+		ServerTime time.Time          `json:"server_time"`
+	}{
+		SyncToken:  result.SyncToken,
+		Full:       result.Full,
+		Changed:    result.Changed,
+		Deleted:    result.Deleted,
+		Results:    make([]syncChangeResult, len(result.Results)),
+		ServerTime: result.ServerTime,
+	}
+
+	for i, changeResult := range result.Results {
+		resp.Results[i] = syncChangeResult{
+			ChangeID:  changeResult.ChangeID,
+			TaskID:    changeResult.TaskID.String(),
+			Status:    changeResult.Status,
+			Conflicts: changeResult.Conflicts,
+		}
+		if changeResult.Err != nil {
+			resp.Results[i].Code = httpStatus(changeResult.Err)
+			resp.Results[i].Error = status.Convert(changeResult.Err).Message()
+		}
+	}
+
+	log.Info("Tasks synced", "changes", len(changes), "changed", len(resp.Changed), "deleted", len(resp.Deleted))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode sync result", slog.String("error", err.Error()))
+	}
+}
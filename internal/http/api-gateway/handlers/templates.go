@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// HandleCreateTemplate saves a template from {"name": "...", "tasks": [...], "project_id": "..."}.
+// The project is optional, see models.TemplateTask for the shape of the tasks.
+func (api *APIGateway) HandleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleCreateTemplate"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		Name      string          `json:"name"`
+		Tasks     json.RawMessage `json:"tasks"`
+		ProjectID uuid.UUID       `json:"project_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Tasks) == 0 {
+		req.Tasks = json.RawMessage("[]")
+	}
+
+	template, err := api.Task.CreateTemplate(r.Context(), sess.UserID, req.ProjectID, req.Name, string(req.Tasks))
+	if err != nil {
+		log.Error("failed to create template", slog.String("error", err.Error()))
+		http.Error(w, "Failed to create template", httpStatus(err))
+		return
+	}
+
+	log.Info("Template created successfully", "templateID", template.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(template); err != nil {
+		log.Error("failed to encode template", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleListTemplates(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleListTemplates"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	templates, err := api.Task.ListTemplates(r.Context(), sess.UserID)
+	if err != nil {
+		log.Error("failed to list templates", slog.String("error", err.Error()))
+		http.Error(w, "Failed to list templates", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(templates); err != nil {
+		log.Error("failed to encode templates", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode templates", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (api *APIGateway) HandleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleDeleteTemplate"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	templateID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.Task.DeleteTemplate(r.Context(), templateID, sess.UserID); err != nil {
+		log.Error("failed to delete template", slog.String("error", err.Error()))
+		http.Error(w, "Failed to delete template", httpStatus(err))
+		return
+	}
+
+	log.Info("Template deleted successfully", "templateID", templateID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleInstantiateTemplate creates the tasks of a template from
+// {"start": "2026-11-02", "variables": {"name": "Alice"}, "project_id": "...", "timezone": "..."}.
+// Every field is optional: start defaults to today and the timezone to the one of the session.
+func (api *APIGateway) HandleInstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleInstantiateTemplate"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	templateID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Start     string            `json:"start"`
+		Timezone  string            `json:"timezone"`
+		Variables map[string]string `json:"variables"`
+		ProjectID uuid.UUID         `json:"project_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = sess.Timezone
+	}
+
+	tasks, err := api.Task.InstantiateTemplate(r.Context(), templateID, sess.UserID, req.ProjectID, req.Start, req.Timezone, req.Variables)
+	if err != nil {
+		log.Error("failed to instantiate template", slog.String("error", err.Error()))
+		http.Error(w, "Failed to instantiate template", httpStatus(err))
+		return
+	}
+
+	log.Info("Template instantiated successfully", "templateID", templateID, "tasks", len(tasks))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(tasks); err != nil {
+		log.Error("failed to encode tasks", slog.String("error", err.Error()))
+	}
+}
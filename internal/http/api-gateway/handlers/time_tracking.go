@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+const reportDateLayout = "2006-01-02"
+
+func (api *APIGateway) HandleStartTimer(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleStartTimer"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	taskID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional, a timer can be started without a note.
+	var req struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	entry, err := api.Task.StartTimer(r.Context(), taskID, sess.UserID, req.Note)
+	if err != nil {
+		log.Error("failed to start timer", slog.String("error", err.Error()))
+		http.Error(w, "Failed to start timer", httpStatus(err))
+		return
+	}
+
+	log.Info("Timer started successfully", "entryID", entry.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(entry); err != nil {
+		log.Error("failed to encode time entry", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleStopTimer(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleStopTimer"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	entry, err := api.Task.StopTimer(r.Context(), sess.UserID)
+	if err != nil {
+		log.Error("failed to stop timer", slog.String("error", err.Error()))
+		http.Error(w, "Failed to stop timer", httpStatus(err))
+		return
+	}
+
+	log.Info("Timer stopped successfully", "entryID", entry.ID)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entry); err != nil {
+		log.Error("failed to encode time entry", slog.String("error", err.Error()))
+	}
+}
+
+// HandleLogTime records work done without a timer. started_at is RFC 3339 and defaults to
+// duration_minutes before now.
+func (api *APIGateway) HandleLogTime(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleLogTime"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	taskID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		StartedAt       time.Time `json:"started_at"`
+		DurationMinutes int       `json:"duration_minutes"`
+		Note            string    `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+
+	entry, err := api.Task.LogTime(r.Context(), taskID, sess.UserID, req.StartedAt, duration, req.Note)
+	if err != nil {
+		log.Error("failed to log time", slog.String("error", err.Error()))
+		http.Error(w, "Failed to log time", httpStatus(err))
+		return
+	}
+
+	log.Info("Time logged successfully", "entryID", entry.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(entry); err != nil {
+		log.Error("failed to encode time entry", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleListTimeEntries(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleListTimeEntries"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	taskID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := api.Task.ListTimeEntries(r.Context(), taskID, sess.UserID)
+	if err != nil {
+		log.Error("failed to list time entries", slog.String("error", err.Error()))
+		http.Error(w, "Failed to list time entries", httpStatus(err))
+		return
+	}
+
+	if len(entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		log.Error("failed to encode time entries", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode time entries", http.StatusInternalServerError)
+		return
+	}
+}
+
+// HandleTimeReport sums the user's tracked time per project and task. from and to are
+// inclusive dates (YYYY-MM-DD) taken in the IANA timezone tz, the timezone of the user's profile by default.
+// project_id narrows the report to one project.
+func (api *APIGateway) HandleTimeReport(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleTimeReport"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	query := r.URL.Query()
+
+	loc := sess.Location()
+	if timezone := query.Get("tz"); timezone != "" {
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			http.Error(w, "Invalid timezone", http.StatusBadRequest)
+			return
+		}
+	}
+
+	from, err := time.ParseInLocation(reportDateLayout, query.Get("from"), loc)
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+
+	to, err := time.ParseInLocation(reportDateLayout, query.Get("to"), loc)
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	var projectID uuid.UUID
+	if id := query.Get("project_id"); id != "" {
+		projectID, err = uuid.Parse(id)
+		if err != nil {
+			http.Error(w, "Invalid project ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := api.Task.GetTimeReport(r.Context(), sess.UserID, projectID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		log.Error("failed to get time report", slog.String("error", err.Error()))
+		http.Error(w, "Failed to get time report", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error("failed to encode time report", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode time report", http.StatusInternalServerError)
+		return
+	}
+}
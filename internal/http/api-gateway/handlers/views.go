@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func (api *APIGateway) HandleCreateView(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleCreateView"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		Name  string          `json:"name"`
+		Query json.RawMessage `json:"query"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Query) == 0 {
+		req.Query = json.RawMessage("{}")
+	}
+
+	view, err := api.Task.CreateView(r.Context(), sess.UserID, req.Name, string(req.Query))
+	if err != nil {
+		log.Error("failed to create view", slog.String("error", err.Error()))
+		http.Error(w, "Failed to create view", httpStatus(err))
+		return
+	}
+
+	log.Info("View created successfully", "viewID", view.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(view); err != nil {
+		log.Error("failed to encode view", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleListViews(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleListViews"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	views, err := api.Task.ListViews(r.Context(), sess.UserID)
+	if err != nil {
+		log.Error("failed to list views", slog.String("error", err.Error()))
+		http.Error(w, "Failed to list views", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(views); err != nil {
+		log.Error("failed to encode views", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode views", http.StatusInternalServerError)
+		return
+	}
+}
+
+// HandleRunView returns the tasks of a view. The tz query parameter holds the IANA timezone
+// used for relative date filters like "today", the timezone of the user's profile by default.
+func (api *APIGateway) HandleRunView(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleRunView"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	timezone := r.URL.Query().Get("tz")
+	if timezone == "" {
+		timezone = sess.Timezone
+	}
+
+	tasks, err := api.Task.RunView(r.Context(), sess.UserID, r.PathValue("id"), timezone)
+	if err != nil {
+		log.Error("failed to run view", slog.String("error", err.Error()))
+		http.Error(w, "Failed to run view", httpStatus(err))
+		return
+	}
+
+	if len(tasks) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tasks); err != nil {
+		log.Error("failed to encode tasks", slog.String("error", err.Error()))
+		http.Error(w, "Failed to encode tasks", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (api *APIGateway) HandleDeleteView(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleDeleteView"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	if err := api.Task.DeleteView(r.Context(), sess.UserID, r.PathValue("id")); err != nil {
+		log.Error("failed to delete view", slog.String("error", err.Error()))
+		http.Error(w, "Failed to delete view", httpStatus(err))
+		return
+	}
+
+	log.Info("View deleted successfully", "viewID", r.PathValue("id"))
+	w.WriteHeader(http.StatusNoContent)
+}
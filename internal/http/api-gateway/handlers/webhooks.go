@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// HandleCreateWebhook registers an endpoint from {"url": "...", "event_types": ["created"], "project_id": "..."}.
+// Event types are created, updated and deleted, every type when left out, and the project is optional.
+// The response is the only one carrying the secret the payloads are signed with. Deliveries are
+// retried and may arrive out of order, their payloads tell when the event occurred.
+func (api *APIGateway) HandleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleCreateWebhook"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	var req struct {
+		URL        string    `json:"url"`
+		EventTypes []string  `json:"event_types"`
+		ProjectID  uuid.UUID `json:"project_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := api.Task.CreateWebhook(r.Context(), sess.UserID, req.ProjectID, req.URL, req.EventTypes)
+	if err != nil {
+		log.Error("failed to create webhook", slog.String("error", err.Error()))
+		http.Error(w, "Failed to create webhook", httpStatus(err))
+		return
+	}
+
+	resp := struct {
+		*models.Webhook
+		Secret string `json:"secret"`
+	}{Webhook: webhook, Secret: webhook.Secret}
+
+	log.Info("Webhook created successfully", "webhookID", webhook.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("failed to encode webhook", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleListWebhooks"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	webhooks, err := api.Task.ListWebhooks(r.Context(), sess.UserID)
+	if err != nil {
+		log.Error("failed to list webhooks", slog.String("error", err.Error()))
+		http.Error(w, "Failed to list webhooks", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(webhooks); err != nil {
+		log.Error("failed to encode webhooks", slog.String("error", err.Error()))
+	}
+}
+
+// HandleUpdateWebhook replaces the endpoint and filters of a webhook from the same body as
+// HandleCreateWebhook with an "enabled" flag, true when left out. Enabling a webhook that was
+// disabled after failing clears its failures.
+func (api *APIGateway) HandleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleUpdateWebhook"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	webhookID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		URL        string    `json:"url"`
+		EventTypes []string  `json:"event_types"`
+		ProjectID  uuid.UUID `json:"project_id"`
+		Enabled    *bool     `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.String("error", err.Error()))
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	enabled := req.Enabled == nil || *req.Enabled
+
+	webhook, err := api.Task.UpdateWebhook(r.Context(), webhookID, sess.UserID, req.ProjectID, req.URL, req.EventTypes, enabled)
+	if err != nil {
+		log.Error("failed to update webhook", slog.String("error", err.Error()))
+		http.Error(w, "Failed to update webhook", httpStatus(err))
+		return
+	}
+
+	log.Info("Webhook updated successfully", "webhookID", webhookID)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(webhook); err != nil {
+		log.Error("failed to encode webhook", slog.String("error", err.Error()))
+	}
+}
+
+func (api *APIGateway) HandleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleDeleteWebhook"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	webhookID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := api.Task.DeleteWebhook(r.Context(), webhookID, sess.UserID); err != nil {
+		log.Error("failed to delete webhook", slog.String("error", err.Error()))
+		http.Error(w, "Failed to delete webhook", httpStatus(err))
+		return
+	}
+
+	log.Info("Webhook deleted successfully", "webhookID", webhookID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleListWebhookDeliveries returns the delivery log of a webhook, the latest first.
+// The limit query parameter defaults to 50, at most 200 are returned.
+func (api *APIGateway) HandleListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleListWebhookDeliveries"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	webhookID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := api.Task.ListWebhookDeliveries(r.Context(), webhookID, sess.UserID, limit)
+	if err != nil {
+		log.Error("failed to list webhook deliveries", slog.String("error", err.Error()))
+		http.Error(w, "Failed to list webhook deliveries", httpStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(deliveries); err != nil {
+		log.Error("failed to encode webhook deliveries", slog.String("error", err.Error()))
+	}
+}
+
+// HandleRedeliverWebhookDelivery queues the payload of a logged delivery to be posted again.
+func (api *APIGateway) HandleRedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	const op = "APIGateway.HandleRedeliverWebhookDelivery"
+
+	sess, err := models.SessionFromContext(r.Context())
+	if err != nil {
+		api.log.Error("failed to get session from context", slog.String("error", err.Error()))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	log := api.log.With(
+		slog.String("op", op),
+		slog.String("userID", sess.UserID.String()))
+
+	webhookID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	deliveryID, err := uuid.Parse(r.PathValue("deliveryID"))
+	if err != nil {
+		http.Error(w, "Invalid delivery ID", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := api.Task.RedeliverWebhookDelivery(r.Context(), webhookID, deliveryID, sess.UserID)
+	if err != nil {
+		log.Error("failed to redeliver webhook delivery", slog.String("error", err.Error()))
+		http.Error(w, "Failed to redeliver webhook delivery", httpStatus(err))
+		return
+	}
+
+	log.Info("Webhook delivery queued again", "webhookID", webhookID, "deliveryID", delivery.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(delivery); err != nil {
+		log.Error("failed to encode webhook delivery", slog.String("error", err.Error()))
+	}
+}
@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -10,20 +9,40 @@ import (
 	"github.com/SlashLight/todo-list/internal/lib/jwt"
 )
 
+// tokenCookie is the cookie the gateway sets at login.
+const tokenCookie = "token"
+
 func AuthMiddleware(next http.Handler, secretKey string) http.Handler {
+	return authMiddleware(next, secretKey, false)
+}
+
+// StreamAuthMiddleware authenticates like AuthMiddleware, or with the token cookie set at login
+// when a request has no Authorization header: browsers cannot add headers to EventSource and
+// WebSocket requests. It is meant for endpoints that change nothing.
+func StreamAuthMiddleware(next http.Handler, secretKey string) http.Handler {
+	return authMiddleware(next, secretKey, true)
+}
+
+func authMiddleware(next http.Handler, secretKey string, allowCookie bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tokenString string
+
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "missing authorization header", http.StatusUnauthorized)
-			return
-		}
-
-		headerParts := strings.Split(authHeader, " ")
-		if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-			http.Error(w, "invalid authorization header format", http.StatusUnauthorized)
-			return
+			cookie, err := r.Cookie(tokenCookie)
+			if !allowCookie || err != nil {
+				http.Error(w, "missing authorization header", http.StatusUnauthorized)
+				return
+			}
+			tokenString = cookie.Value
+		} else {
+			headerParts := strings.Split(authHeader, " ")
+			if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+				http.Error(w, "invalid authorization header format", http.StatusUnauthorized)
+				return
+			}
+			tokenString = headerParts[1]
 		}
-		tokenString := headerParts[1]
 
 		sess, err := jwt.ParseToken(tokenString, secretKey)
 		if err != nil {
@@ -31,7 +50,7 @@ func AuthMiddleware(next http.Handler, secretKey string) http.Handler {
 			return
 		}
 
-		ctx := models.ContextWithSession(context.Background(), sess)
+		ctx := models.ContextWithSession(r.Context(), sess)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/jwt"
+)
+
+// Authenticator logs a user in with email and password, returning a session token.
+type Authenticator interface {
+	Login(ctx context.Context, email, password string) (string, error)
+}
+
+// BasicAuthMiddleware authenticates clients that cannot hold a bearer token, such as calendar apps,
+// with their email and password. Every request logs in again.
+func BasicAuthMiddleware(next http.Handler, auth Authenticator, secretKey string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		email, password, ok := r.BasicAuth()
+		if !ok {
+			basicAuthChallenge(w, "missing authorization header")
+			return
+		}
+
+		token, err := auth.Login(r.Context(), email, password)
+		if err != nil {
+			basicAuthChallenge(w, "invalid email or password")
+			return
+		}
+
+		sess, err := jwt.ParseToken(token, secretKey)
+		if err != nil {
+			basicAuthChallenge(w, "invalid token")
+			return
+		}
+
+		ctx := models.ContextWithSession(r.Context(), sess)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func basicAuthChallenge(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="todo-list", charset="UTF-8"`)
+	http.Error(w, message, http.StatusUnauthorized)
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/SlashLight/todo-list/internal/lib/idempotency"
+)
+
+// IdempotencyMiddleware passes the Idempotency-Key header on to the task service calls of the request.
+// Attachment uploads and imports stream their body to the task service, which answers them 400 when
+// they carry a key: it cannot replay them.
+func IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotency.Header)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := idempotency.ValidateKey(key); err != nil {
+			http.Error(w, "invalid idempotency key", http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(idempotency.NewContext(r.Context(), key)))
+	})
+}
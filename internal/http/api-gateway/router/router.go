@@ -7,21 +7,139 @@ import (
 )
 
 type API interface {
+	middleware.Authenticator
+
 	HandleLogin(w http.ResponseWriter, r *http.Request)
 	HandleRegister(w http.ResponseWriter, r *http.Request)
+	HandleUpdateProfile(w http.ResponseWriter, r *http.Request)
+	HandleDeleteAccount(w http.ResponseWriter, r *http.Request)
 
 	HandleCreateTask(w http.ResponseWriter, r *http.Request)
+	HandleQuickAdd(w http.ResponseWriter, r *http.Request)
 	HandleGetTask(w http.ResponseWriter, r *http.Request)
+	HandleSearchTasks(w http.ResponseWriter, r *http.Request)
+	HandleExportTasks(w http.ResponseWriter, r *http.Request)
+	HandleImportTasks(w http.ResponseWriter, r *http.Request)
+	HandleEvents(w http.ResponseWriter, r *http.Request)
+	HandleWebSocket(w http.ResponseWriter, r *http.Request)
+	HandleSync(w http.ResponseWriter, r *http.Request)
+
+	HandleRegenerateCalendarToken(w http.ResponseWriter, r *http.Request)
+	HandleRevokeCalendarToken(w http.ResponseWriter, r *http.Request)
+	HandleCalendarFeed(w http.ResponseWriter, r *http.Request)
+	HandleCalDAV(w http.ResponseWriter, r *http.Request)
+	HandleMoveTask(w http.ResponseWriter, r *http.Request)
+	HandleSetTaskEstimate(w http.ResponseWriter, r *http.Request)
+	HandleBatchCreateTasks(w http.ResponseWriter, r *http.Request)
+	HandleBatchUpdateTasks(w http.ResponseWriter, r *http.Request)
+	HandleBatchDeleteTasks(w http.ResponseWriter, r *http.Request)
+
+	HandleCreateView(w http.ResponseWriter, r *http.Request)
+	HandleListViews(w http.ResponseWriter, r *http.Request)
+	HandleRunView(w http.ResponseWriter, r *http.Request)
+	HandleDeleteView(w http.ResponseWriter, r *http.Request)
+
+	HandleCreateProject(w http.ResponseWriter, r *http.Request)
+	HandleListProjects(w http.ResponseWriter, r *http.Request)
+	HandleGetBoard(w http.ResponseWriter, r *http.Request)
+
+	HandleStartTimer(w http.ResponseWriter, r *http.Request)
+	HandleStopTimer(w http.ResponseWriter, r *http.Request)
+	HandleLogTime(w http.ResponseWriter, r *http.Request)
+	HandleListTimeEntries(w http.ResponseWriter, r *http.Request)
+	HandleTimeReport(w http.ResponseWriter, r *http.Request)
+
+	HandleAddChecklistItem(w http.ResponseWriter, r *http.Request)
+	HandleToggleChecklistItem(w http.ResponseWriter, r *http.Request)
+	HandleReorderChecklistItem(w http.ResponseWriter, r *http.Request)
+	HandleRemoveChecklistItem(w http.ResponseWriter, r *http.Request)
+
+	HandleUploadAttachment(w http.ResponseWriter, r *http.Request)
+	HandleListAttachments(w http.ResponseWriter, r *http.Request)
+	HandleDownloadAttachment(w http.ResponseWriter, r *http.Request)
+	HandleDeleteAttachment(w http.ResponseWriter, r *http.Request)
+
+	HandleCreateTemplate(w http.ResponseWriter, r *http.Request)
+	HandleListTemplates(w http.ResponseWriter, r *http.Request)
+	HandleDeleteTemplate(w http.ResponseWriter, r *http.Request)
+	HandleInstantiateTemplate(w http.ResponseWriter, r *http.Request)
+
+	HandleCreateWebhook(w http.ResponseWriter, r *http.Request)
+	HandleListWebhooks(w http.ResponseWriter, r *http.Request)
+	HandleUpdateWebhook(w http.ResponseWriter, r *http.Request)
+	HandleDeleteWebhook(w http.ResponseWriter, r *http.Request)
+	HandleListWebhookDeliveries(w http.ResponseWriter, r *http.Request)
+	HandleRedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request)
 }
 
-func New(api API, secret string) *http.ServeMux {
+func New(api API, secret string) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/auth/login", api.HandleLogin)
 	mux.HandleFunc("/auth/register", api.HandleRegister)
+	mux.Handle("PUT /auth/profile", middleware.AuthMiddleware(http.HandlerFunc(api.HandleUpdateProfile), secret))
+	mux.Handle("DELETE /auth/account", middleware.AuthMiddleware(http.HandlerFunc(api.HandleDeleteAccount), secret))
 
 	mux.Handle("/tasks/create", middleware.AuthMiddleware(http.HandlerFunc(api.HandleCreateTask), secret))
+	mux.Handle("POST /tasks/quick", middleware.AuthMiddleware(http.HandlerFunc(api.HandleQuickAdd), secret))
 	mux.Handle("/tasks/get", middleware.AuthMiddleware(http.HandlerFunc(api.HandleGetTask), secret))
+	mux.Handle("GET /tasks/search", middleware.AuthMiddleware(http.HandlerFunc(api.HandleSearchTasks), secret))
+	mux.Handle("GET /tasks/export", middleware.AuthMiddleware(http.HandlerFunc(api.HandleExportTasks), secret))
+	mux.Handle("POST /tasks/import", middleware.AuthMiddleware(http.HandlerFunc(api.HandleImportTasks), secret))
+	// Browsers cannot set headers on EventSource and WebSocket requests, the login cookie does.
+	mux.Handle("GET /events", middleware.StreamAuthMiddleware(http.HandlerFunc(api.HandleEvents), secret))
+	mux.Handle("GET /ws", middleware.StreamAuthMiddleware(http.HandlerFunc(api.HandleWebSocket), secret))
+	mux.Handle("POST /sync", middleware.AuthMiddleware(http.HandlerFunc(api.HandleSync), secret))
+
+	mux.Handle("POST /calendar/token", middleware.AuthMiddleware(http.HandlerFunc(api.HandleRegenerateCalendarToken), secret))
+	mux.Handle("DELETE /calendar/token", middleware.AuthMiddleware(http.HandlerFunc(api.HandleRevokeCalendarToken), secret))
+	// The feed is authorized by the secret token in its URL.
+	mux.HandleFunc("GET /calendar/{file}", api.HandleCalendarFeed)
+	// Calendar apps cannot hold a bearer token, they log in with every request.
+	mux.Handle("/caldav/", middleware.BasicAuthMiddleware(http.HandlerFunc(api.HandleCalDAV), api, secret))
+	mux.Handle("/.well-known/caldav", http.RedirectHandler("/caldav/", http.StatusMovedPermanently))
+	mux.Handle("POST /tasks/{id}/move", middleware.AuthMiddleware(http.HandlerFunc(api.HandleMoveTask), secret))
+	mux.Handle("PUT /tasks/{id}/estimate", middleware.AuthMiddleware(http.HandlerFunc(api.HandleSetTaskEstimate), secret))
+	mux.Handle("POST /tasks/batch/create", middleware.AuthMiddleware(http.HandlerFunc(api.HandleBatchCreateTasks), secret))
+	mux.Handle("POST /tasks/batch/update", middleware.AuthMiddleware(http.HandlerFunc(api.HandleBatchUpdateTasks), secret))
+	mux.Handle("POST /tasks/batch/delete", middleware.AuthMiddleware(http.HandlerFunc(api.HandleBatchDeleteTasks), secret))
+
+	mux.Handle("POST /views", middleware.AuthMiddleware(http.HandlerFunc(api.HandleCreateView), secret))
+	mux.Handle("GET /views", middleware.AuthMiddleware(http.HandlerFunc(api.HandleListViews), secret))
+	mux.Handle("GET /views/{id}/tasks", middleware.AuthMiddleware(http.HandlerFunc(api.HandleRunView), secret))
+	mux.Handle("DELETE /views/{id}", middleware.AuthMiddleware(http.HandlerFunc(api.HandleDeleteView), secret))
+
+	mux.Handle("POST /projects", middleware.AuthMiddleware(http.HandlerFunc(api.HandleCreateProject), secret))
+	mux.Handle("GET /projects", middleware.AuthMiddleware(http.HandlerFunc(api.HandleListProjects), secret))
+	mux.Handle("GET /projects/{id}/board", middleware.AuthMiddleware(http.HandlerFunc(api.HandleGetBoard), secret))
+
+	mux.Handle("POST /tasks/{id}/timer", middleware.AuthMiddleware(http.HandlerFunc(api.HandleStartTimer), secret))
+	mux.Handle("POST /timer/stop", middleware.AuthMiddleware(http.HandlerFunc(api.HandleStopTimer), secret))
+	mux.Handle("POST /tasks/{id}/time", middleware.AuthMiddleware(http.HandlerFunc(api.HandleLogTime), secret))
+	mux.Handle("GET /tasks/{id}/time", middleware.AuthMiddleware(http.HandlerFunc(api.HandleListTimeEntries), secret))
+	mux.Handle("GET /reports/time", middleware.AuthMiddleware(http.HandlerFunc(api.HandleTimeReport), secret))
+
+	mux.Handle("POST /tasks/{id}/checklist", middleware.AuthMiddleware(http.HandlerFunc(api.HandleAddChecklistItem), secret))
+	mux.Handle("POST /tasks/{id}/checklist/{itemID}/toggle", middleware.AuthMiddleware(http.HandlerFunc(api.HandleToggleChecklistItem), secret))
+	mux.Handle("PUT /tasks/{id}/checklist/{itemID}/position", middleware.AuthMiddleware(http.HandlerFunc(api.HandleReorderChecklistItem), secret))
+	mux.Handle("DELETE /tasks/{id}/checklist/{itemID}", middleware.AuthMiddleware(http.HandlerFunc(api.HandleRemoveChecklistItem), secret))
+
+	mux.Handle("POST /tasks/{id}/attachments", middleware.AuthMiddleware(http.HandlerFunc(api.HandleUploadAttachment), secret))
+	mux.Handle("GET /tasks/{id}/attachments", middleware.AuthMiddleware(http.HandlerFunc(api.HandleListAttachments), secret))
+	mux.Handle("GET /tasks/{id}/attachments/{attachmentID}", middleware.AuthMiddleware(http.HandlerFunc(api.HandleDownloadAttachment), secret))
+	mux.Handle("DELETE /tasks/{id}/attachments/{attachmentID}", middleware.AuthMiddleware(http.HandlerFunc(api.HandleDeleteAttachment), secret))
+
+	mux.Handle("POST /templates", middleware.AuthMiddleware(http.HandlerFunc(api.HandleCreateTemplate), secret))
+	mux.Handle("GET /templates", middleware.AuthMiddleware(http.HandlerFunc(api.HandleListTemplates), secret))
+	mux.Handle("DELETE /templates/{id}", middleware.AuthMiddleware(http.HandlerFunc(api.HandleDeleteTemplate), secret))
+	mux.Handle("POST /templates/{id}/instantiate", middleware.AuthMiddleware(http.HandlerFunc(api.HandleInstantiateTemplate), secret))
+
+	mux.Handle("POST /webhooks", middleware.AuthMiddleware(http.HandlerFunc(api.HandleCreateWebhook), secret))
+	mux.Handle("GET /webhooks", middleware.AuthMiddleware(http.HandlerFunc(api.HandleListWebhooks), secret))
+	mux.Handle("PUT /webhooks/{id}", middleware.AuthMiddleware(http.HandlerFunc(api.HandleUpdateWebhook), secret))
+	mux.Handle("DELETE /webhooks/{id}", middleware.AuthMiddleware(http.HandlerFunc(api.HandleDeleteWebhook), secret))
+	mux.Handle("GET /webhooks/{id}/deliveries", middleware.AuthMiddleware(http.HandlerFunc(api.HandleListWebhookDeliveries), secret))
+	mux.Handle("POST /webhooks/{id}/deliveries/{deliveryID}/redeliver", middleware.AuthMiddleware(http.HandlerFunc(api.HandleRedeliverWebhookDelivery), secret))
 
-	return mux
+	return middleware.IdempotencyMiddleware(mux)
 }
@@ -0,0 +1,50 @@
+// Package highlight turns search matches marked by the storage into HTML that is safe to render.
+//
+// The highlight functions of the databases wrap matches in markers of our choosing but leave the
+// text around them as it is. Marking with <mark> directly would pass tags typed into a task title
+// through as HTML, so the storage marks with Start and End instead and HTML escapes the text.
+package highlight
+
+import (
+	"html"
+	"strings"
+)
+
+// Start and End enclose a match. They are private use characters, which nothing typed into a task
+// means anything by.
+const (
+	Start = "\uE000"
+	End   = "\uE001"
+)
+
+// HTML escapes the marked text and wraps the matches in <mark> elements. Markers out of place,
+// typed in by users, are dropped, so the elements always balance.
+func HTML(marked string) string {
+	var b strings.Builder
+	open := false
+	for {
+		i := strings.IndexAny(marked, Start+End)
+		if i < 0 {
+			break
+		}
+
+		b.WriteString(html.EscapeString(marked[:i]))
+		switch {
+		case strings.HasPrefix(marked[i:], Start) && !open:
+			b.WriteString("<mark>")
+			open = true
+		case strings.HasPrefix(marked[i:], End) && open:
+			b.WriteString("</mark>")
+			open = false
+		}
+		// Start and End are as long as each other.
+		marked = marked[i+len(Start):]
+	}
+	b.WriteString(html.EscapeString(marked))
+
+	if open {
+		b.WriteString("</mark>")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package highlight
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		marked string
+		want   string
+	}{
+		{"plain", "Quarterly report", "Quarterly report"},
+		{"match", Start + "Quarterly" + End + " report", "<mark>Quarterly</mark> report"},
+		{"markup", `<script>` + Start + "alert" + End + `("x")</script>`,
+			"&lt;script&gt;<mark>alert</mark>(&#34;x&#34;)&lt;/script&gt;"},
+		{"stray end", "a" + End + "b", "ab"},
+		{"nested start", Start + "a" + Start + "b" + End, "<mark>ab</mark>"},
+		{"unclosed", Start + "a", "<mark>a</mark>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTML(tt.marked); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
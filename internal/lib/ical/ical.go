@@ -0,0 +1,148 @@
+// Package ical writes RFC 5545 iCalendar data: CRLF line endings, lines folded at 75 octets
+// and escaped text values.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxLineOctets = 75
+
+// Writer writes the components and properties of a calendar. It stops at the first error,
+// which Flush returns.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+func (w *Writer) Begin(component string) {
+	w.line("BEGIN:" + component)
+}
+
+func (w *Writer) End(component string) {
+	w.line("END:" + component)
+}
+
+// Property writes a property with a value taken as is, params are already formatted like "VALUE=DATE".
+func (w *Writer) Property(name, value string, params ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, param := range params {
+		b.WriteByte(';')
+		b.WriteString(param)
+	}
+	b.WriteByte(':')
+	b.WriteString(value)
+
+	w.line(b.String())
+}
+
+// Text writes a property with a TEXT value, which it escapes.
+func (w *Writer) Text(name, value string) {
+	w.Property(name, EscapeText(value))
+}
+
+// Err returns the first error met.
+func (w *Writer) Err() error {
+	return w.err
+}
+
+// Flush writes out buffered data and returns the first error met.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.err = w.w.Flush()
+	return w.err
+}
+
+// line writes a content line, folding it without splitting a UTF-8 sequence.
+func (w *Writer) line(line string) {
+	if w.err != nil {
+		return
+	}
+
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		w.write(line[:cut])
+		w.write("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts against their length.
+		limit = maxLineOctets - 1
+	}
+
+	w.write(line)
+	w.write("\r\n")
+}
+
+func (w *Writer) write(s string) {
+	if w.err != nil {
+		return
+	}
+
+	_, w.err = w.w.WriteString(s)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func EscapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// DateTime formats t as a UTC DATE-TIME.
+func DateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// Date formats t as a DATE.
+func Date(t time.Time) string {
+	return t.Format("20060102")
+}
+
+// Duration formats d as a DURATION such as "-PT15M" or "P1DT2H", to the second.
+func Duration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteByte('P')
+
+	seconds := int64(d / time.Second)
+	days, hours, minutes := seconds/86400, seconds/3600%24, seconds/60%60
+	seconds %= 60
+
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 || days == 0 {
+		b.WriteByte('T')
+		// An hour and a second need the minute in between.
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes > 0 || (hours > 0 && seconds > 0) {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if seconds > 0 || (days == 0 && hours == 0 && minutes == 0) {
+			fmt.Fprintf(&b, "%dS", seconds)
+		}
+	}
+
+	return b.String()
+}
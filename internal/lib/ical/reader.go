@@ -0,0 +1,228 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxObjectSize is the largest calendar object Parse reads, in bytes.
+const maxObjectSize = 1 << 20
+
+var ErrInvalidData = errors.New("invalid iCalendar data")
+
+// Property is a content line. Names and parameter names are upper case, values are as written:
+// see UnescapeText for TEXT values.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Property returns the first property called name.
+func (c *Component) Property(name string) (Property, bool) {
+	for _, property := range c.Properties {
+		if property.Name == name {
+			return property, true
+		}
+	}
+
+	return Property{}, false
+}
+
+// PropertyValues returns the values of every property called name, in order.
+func (c *Component) PropertyValues(name string) []string {
+	var values []string
+	for _, property := range c.Properties {
+		if property.Name == name {
+			values = append(values, property.Value)
+		}
+	}
+
+	return values
+}
+
+// Parse reads one component, usually a VCALENDAR, with the components nested in it.
+func Parse(r io.Reader) (*Component, error) {
+	scanner := bufio.NewScanner(io.LimitReader(r, maxObjectSize))
+	scanner.Buffer(make([]byte, 0, 4096), maxObjectSize)
+
+	var (
+		lines []string
+		err   error
+	)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		// A line starting with white space continues the one before.
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidData, err)
+	}
+
+	var (
+		root  *Component
+		stack []*Component
+	)
+	for number, line := range lines {
+		property, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrInvalidData, number+1, err)
+		}
+
+		switch property.Name {
+		case "BEGIN":
+			component := &Component{Name: strings.ToUpper(property.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			} else if root != nil {
+				return nil, fmt.Errorf("%w: more than one top level component", ErrInvalidData)
+			} else {
+				root = component
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalidData, number+1, property.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%w: line %d: property outside of a component", ErrInvalidData, number+1)
+			}
+			component := stack[len(stack)-1]
+			component.Properties = append(component.Properties, property)
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("%w: no component", ErrInvalidData)
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("%w: %s is not closed", ErrInvalidData, stack[len(stack)-1].Name)
+	}
+
+	return root, nil
+}
+
+// parseLine splits a content line into name, parameters and value. Parameter values may be
+// quoted, which lets them hold the ';', ':' and ',' that delimit the line otherwise.
+func parseLine(line string) (Property, error) {
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return Property{}, errors.New("missing property name")
+	}
+
+	property := Property{Name: strings.ToUpper(line[:end])}
+	rest := line[end:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return Property{}, fmt.Errorf("invalid parameter of %s", property.Name)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			closing := strings.IndexByte(rest[1:], '"')
+			if closing < 0 {
+				return Property{}, fmt.Errorf("unterminated quote in parameter %s", name)
+			}
+			value, rest = rest[1:closing+1], rest[closing+2:]
+		} else {
+			stop := strings.IndexAny(rest, ";:")
+			if stop < 0 {
+				return Property{}, fmt.Errorf("missing value of %s", property.Name)
+			}
+			value, rest = rest[:stop], rest[stop:]
+		}
+
+		if property.Params == nil {
+			property.Params = make(map[string]string)
+		}
+		property.Params[name] = value
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return Property{}, fmt.Errorf("missing value of %s", property.Name)
+	}
+	property.Value = rest[1:]
+
+	return property, nil
+}
+
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func UnescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
+
+// SplitText splits a list of TEXT values, such as CATEGORIES, at the commas that are not escaped.
+func SplitText(value string) []string {
+	var (
+		values []string
+		start  int
+	)
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, UnescapeText(value[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(values, UnescapeText(value[start:]))
+}
+
+// ParseTime reads a DATE or DATE-TIME property. DATE values report allDay and are midnight in
+// UTC. A DATE-TIME is UTC when it ends in Z, in its TZID when that is a zone known by name,
+// and in loc otherwise.
+func ParseTime(property Property, loc *time.Location) (t time.Time, allDay bool, err error) {
+	value := property.Value
+
+	if property.Params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err = time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %s is not a date", ErrInvalidData, property.Name)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+	} else {
+		if tzid, ok := property.Params["TZID"]; ok {
+			if zone, zoneErr := time.LoadLocation(strings.TrimPrefix(tzid, "/")); zoneErr == nil {
+				loc = zone
+			}
+		}
+		t, err = time.ParseInLocation("20060102T150405", value, loc)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %s is not a date and time", ErrInvalidData, property.Name)
+	}
+
+	return t, false, nil
+}
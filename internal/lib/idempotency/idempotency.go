@@ -0,0 +1,98 @@
+// Package idempotency carries idempotency keys from HTTP requests through gRPC calls.
+// A request repeated with the same key gets the outcome of the first one instead of running again.
+// Only unary calls do: calls that stream their request, such as attachment uploads, refuse keys.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Header is the HTTP header clients put the key in.
+	Header = "Idempotency-Key"
+	// MetadataKey is the gRPC metadata key the key travels in.
+	MetadataKey = "idempotency-key"
+
+	maxKeyLength = 255
+)
+
+var ErrInvalidKey = errors.New("idempotency: key must be 1 to 255 printable ASCII characters")
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+func FromContext(ctx context.Context) string {
+	key, _ := ctx.Value(contextKey{}).(string)
+	return key
+}
+
+// ValidateKey rejects keys that do not belong in logs and storage.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > maxKeyLength {
+		return ErrInvalidKey
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return ErrInvalidKey
+		}
+	}
+
+	return nil
+}
+
+// Fingerprint hashes a request, two requests of a method with the same fingerprint are the same request.
+func Fingerprint(method string, req proto.Message) (string, error) {
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write(payload)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// UnaryClientInterceptor sends the key of the context along with every call. Calls without one
+// get a fresh key, so that the retries of a call, which must come after this interceptor, share it.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if len(md.Get(MetadataKey)) == 0 {
+			key := FromContext(ctx)
+			if key == "" {
+				key = uuid.NewString()
+			}
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, key)
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor sends the key of the context along with calls that stream their request,
+// for the server to refuse it rather than have the caller believe a retry is safe. Unlike unary
+// calls, streams without a key in the context get none.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if key := FromContext(ctx); key != "" && desc.ClientStreams {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, key)
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
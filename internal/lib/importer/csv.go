@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// csvFields are the task fields a CSV column can be mapped to.
+var csvFields = []string{"external_id", "title", "description", "status", "priority", "deadline", "due_date", "tags", "recurrence", "parent_id"}
+
+// ReadCSV reads a CSV file with a header row. mapping names the column of a field, a field
+// left out of it is read from the column named like the field, or "id" for external_id,
+// when there is one. Rows are numbered by the line they start on.
+func ReadCSV(r io.Reader, mapping map[string]string, loc *time.Location) ([]models.ImportRow, error) {
+	for field := range mapping {
+		if !slices.Contains(csvFields, field) {
+			return nil, fmt.Errorf("%w: unknown field %q in the column mapping", ErrInvalidFile, field)
+		}
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file has no header row", ErrInvalidFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int)
+	for _, field := range csvFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+			if field == "external_id" {
+				name = "id"
+			}
+		}
+
+		index := -1
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), name) {
+				index = i
+				break
+			}
+		}
+
+		switch {
+		case index >= 0:
+			columns[field] = index
+		case mapped:
+			return nil, fmt.Errorf("%w: there is no column %q for %s", ErrInvalidFile, name, field)
+		}
+	}
+
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("%w: there is no title column", ErrInvalidFile)
+	}
+
+	var rows []models.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, csvRow(line, record, columns, loc))
+	}
+
+	return rows, nil
+}
+
+func csvRow(line int, record []string, columns map[string]int, loc *time.Location) models.ImportRow {
+	value := func(field string) string {
+		index, ok := columns[field]
+		if !ok || index >= len(record) {
+			return ""
+		}
+
+		return unguard(strings.TrimSpace(record[index]))
+	}
+
+	row := models.ImportRow{
+		Row:              line,
+		ExternalID:       value("external_id"),
+		ParentExternalID: value("parent_id"),
+		Task: models.Task{
+			Title:       value("title"),
+			Description: value("description"),
+			Status:      strings.ToLower(value("status")),
+			Priority:    strings.ToLower(value("priority")),
+			DueDate:     value("due_date"),
+			Recurrence:  value("recurrence"),
+		},
+	}
+
+	for _, label := range strings.FieldsFunc(value("tags"), func(r rune) bool { return r == ',' || r == ' ' }) {
+		row.Task.Tags = append(row.Task.Tags, tag(label))
+	}
+
+	if deadline := value("deadline"); deadline != "" {
+		row.Task.Deadline, row.Err = parseDeadline(deadline, loc)
+	}
+
+	return row
+}
+
+// unguard drops the quote that CSV exports put in front of text that looks like a formula.
+func unguard(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+
+	return value
+}
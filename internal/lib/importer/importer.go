@@ -0,0 +1,37 @@
+// Package importer reads tasks out of the files of other to-do apps. Every reader returns one
+// models.ImportRow per task, in file order; a row it cannot make sense of carries an error
+// instead of failing the file. Validation beyond the shape of the file is left to the caller.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidFile = errors.New("invalid file")
+
+// tag turns a label of another app into a tag, which cannot contain spaces.
+func tag(label string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(label, unicode.IsSpace), "-"))
+}
+
+// localLayouts are the deadline layouts without an offset, taken in the timezone of the import.
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// parseDeadline reads an RFC 3339 timestamp, or a local date and time in loc.
+func parseDeadline(value string, loc *time.Location) (time.Time, error) {
+	if deadline, err := time.Parse(time.RFC3339, value); err == nil {
+		return deadline.UTC(), nil
+	}
+
+	for _, layout := range localLayouts {
+		if deadline, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return deadline.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("deadline %q is neither RFC 3339 nor a local date and time", value)
+}
@@ -0,0 +1,313 @@
+package importer_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/importer"
+)
+
+// errRow stands for the error of a row that could not be read, only whether there is one is compared.
+var errRow = errors.New("row error")
+
+// compareRows fails the test unless the rows are equal, comparing only whether they have an error.
+func compareRows(t *testing.T, got, want []models.ImportRow) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("want %d rows, got %d: %+v", len(want), len(got), got)
+	}
+
+	for i := range want {
+		if (got[i].Err != nil) != (want[i].Err != nil) {
+			t.Errorf("row %d: want error %v, got %v", i, want[i].Err, got[i].Err)
+		}
+
+		gotRow, wantRow := got[i], want[i]
+		gotRow.Err, wantRow.Err = nil, nil
+		if !reflect.DeepEqual(gotRow, wantRow) {
+			t.Errorf("row %d:\nwant %+v\ngot  %+v", i, wantRow, gotRow)
+		}
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		mapping map[string]string
+		want    []models.ImportRow
+	}{
+		{
+			name: "columns named like the fields",
+			data: "id,Title,description,status,priority,deadline,due_date,tags,recurrence,parent_id\n" +
+				"1,Write report,first draft,In-Progress,HIGH,2030-01-02 15:04,,\"work, Deep Work\",FREQ=WEEKLY,\n" +
+				"2,'=SUM(A1),,,,2030-01-02T15:04:05Z,2030-01-03,,,1\n",
+			want: []models.ImportRow{
+				{Row: 2, ExternalID: "1", Task: models.Task{
+					Title: "Write report", Description: "first draft", Status: "in-progress", Priority: "high",
+					Deadline: time.Date(2030, 1, 2, 14, 4, 0, 0, time.UTC), Tags: []string{"work", "deep", "work"},
+					Recurrence: "FREQ=WEEKLY",
+				}},
+				{Row: 3, ExternalID: "2", ParentExternalID: "1", Task: models.Task{
+					Title: "=SUM(A1)", Deadline: time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC), DueDate: "2030-01-03",
+				}},
+			},
+		},
+		{
+			name:    "column mapping",
+			data:    "Key,Name,Notes,description\nK-1,Plan trip,ignored,\n",
+			mapping: map[string]string{"external_id": "Key", "title": "name"},
+			want:    []models.ImportRow{{Row: 2, ExternalID: "K-1", Task: models.Task{Title: "Plan trip"}}},
+		},
+		{
+			name: "rows numbered by their first line",
+			data: "title,description\n\"Write\nreport\",\"two\nlines\"\nShort,\n",
+			want: []models.ImportRow{
+				{Row: 2, Task: models.Task{Title: "Write\nreport", Description: "two\nlines"}},
+				{Row: 5, Task: models.Task{Title: "Short"}},
+			},
+		},
+		{
+			name: "short records and invalid deadlines",
+			data: "title,deadline\nNo deadline\nBad deadline,tomorrow\n",
+			want: []models.ImportRow{
+				{Row: 2, Task: models.Task{Title: "No deadline"}},
+				{Row: 3, Task: models.Task{Title: "Bad deadline"}, Err: errRow},
+			},
+		},
+		{
+			name: "header only",
+			data: "title\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := importer.ReadCSV(strings.NewReader(tt.data), tt.mapping, berlin)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			compareRows(t, rows, tt.want)
+		})
+	}
+}
+
+// todoTxtID is the ID of a todo.txt line without an id: extension.
+func todoTxtID(text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hash[:8])
+}
+
+func TestReadTodoTxt(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []models.ImportRow
+	}{
+		{
+			name: "done with dates and extensions",
+			data: "x 2030-01-02 2030-01-01 Pay rent +home @errands due:2030-01-05 id:rent\n",
+			want: []models.ImportRow{{Row: 1, ExternalID: "rent", Task: models.Task{
+				Title: "Pay rent", Status: models.StatusDone, Tags: []string{"home", "errands"}, DueDate: "2030-01-05",
+			}}},
+		},
+		{
+			name: "priorities and recurrence",
+			data: "(A) Call Bob rec:2w\n\n  \n(C) 2030-01-01 Water plants rec:+1d\npri:B Plan trip\n",
+			want: []models.ImportRow{
+				{Row: 1, ExternalID: todoTxtID("(A) Call Bob rec:2w"), Task: models.Task{
+					Title: "Call Bob", Status: models.StatusToDo, Priority: models.PriorityHigh, Recurrence: "FREQ=WEEKLY;INTERVAL=2",
+				}},
+				{Row: 4, ExternalID: todoTxtID("(C) 2030-01-01 Water plants rec:+1d"), Task: models.Task{
+					Title: "Water plants", Status: models.StatusToDo, Priority: models.PriorityLow, Recurrence: "FREQ=DAILY",
+				}},
+				{Row: 5, ExternalID: todoTxtID("pri:B Plan trip"), Task: models.Task{
+					Title: "Plan trip", Status: models.StatusToDo, Priority: models.PriorityMedium,
+				}},
+			},
+		},
+		{
+			name: "unknown extensions stay in the title",
+			data: "Read book due:someday url:https://example.com rec:often\n",
+			want: []models.ImportRow{{Row: 1, ExternalID: todoTxtID("Read book due:someday url:https://example.com rec:often"),
+				Task: models.Task{Title: "Read book due:someday url:https://example.com", Status: models.StatusToDo}, Err: errRow}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := importer.ReadTodoTxt(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			compareRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestReadTodoist(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want []models.ImportRow
+	}{
+		{
+			name: "tasks API",
+			data: `[
+				{"id": "100", "content": "Write report", "description": "first draft", "labels": ["Deep Work"], "priority": 4,
+				 "due": {"date": "2030-01-02"}},
+				{"id": 200, "parent_id": 100, "content": "Outline", "is_completed": true, "priority": 1,
+				 "due": {"date": "2030-01-02T09:00:00"}},
+				{"id": "300", "parent_id": null, "content": "Standup", "priority": 2,
+				 "due": {"date": "2030-01-02", "datetime": "2030-01-02T09:00:00Z", "string": "every day", "is_recurring": true}}
+			]`,
+			want: []models.ImportRow{
+				{Row: 1, ExternalID: "100", Task: models.Task{
+					Title: "Write report", Description: "first draft", Status: models.StatusToDo, Priority: models.PriorityHigh,
+					Tags: []string{"deep-work"}, DueDate: "2030-01-02",
+				}},
+				{Row: 2, ExternalID: "200", ParentExternalID: "100", Task: models.Task{
+					Title: "Outline", Status: models.StatusDone, Priority: models.PriorityNone,
+					Deadline: time.Date(2030, 1, 2, 8, 0, 0, 0, time.UTC),
+				}},
+				{Row: 3, ExternalID: "300", Task: models.Task{
+					Title: "Standup", Status: models.StatusToDo, Priority: models.PriorityLow,
+					Deadline: time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC), Recurrence: "FREQ=DAILY",
+				}},
+			},
+		},
+		{
+			name: "sync API backup",
+			data: `{"projects": [], "items": [{"id": "1", "content": "Old task", "checked": true, "priority": 3}]}`,
+			want: []models.ImportRow{{Row: 1, ExternalID: "1", Task: models.Task{
+				Title: "Old task", Status: models.StatusDone, Priority: models.PriorityMedium,
+			}}},
+		},
+		{
+			name: "invalid due time",
+			data: `[{"id": "1", "content": "Soon", "due": {"date": "2030-01-02T25:00"}}]`,
+			want: []models.ImportRow{{Row: 1, ExternalID: "1", Task: models.Task{
+				Title: "Soon", Status: models.StatusToDo, Priority: models.PriorityNone,
+			}, Err: errRow}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := importer.ReadTodoist(strings.NewReader(tt.data), berlin)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			compareRows(t, rows, tt.want)
+		})
+	}
+}
+
+func TestReadTrello(t *testing.T) {
+	data := `{
+		"lists": [
+			{"id": "l1", "name": "To Do"},
+			{"id": "l2", "name": "Doing"},
+			{"id": "l3", "name": "Done"},
+			{"id": "l4", "name": "Old", "closed": true}
+		],
+		"labels": [{"id": "b1", "name": "Urgent Stuff"}, {"id": "b2", "name": "", "color": "green"}, {"id": "b3"}],
+		"checklists": [
+			{"id": "c1", "checkItems": [
+				{"name": "Second", "state": "incomplete", "pos": 2},
+				{"name": "First", "state": "complete", "pos": 1}
+			]},
+			{"id": "c2", "checkItems": [{"name": "Third", "state": "incomplete", "pos": 1}]}
+		],
+		"cards": [
+			{"id": "a1", "name": "Write report", "desc": "first draft", "idList": "l1", "idLabels": ["b1", "b2", "b3"],
+			 "idChecklists": ["c1", "c2"], "due": "2030-01-02T15:04:05.000Z"},
+			{"id": "a2", "name": "Review", "idList": "l2"},
+			{"id": "a3", "name": "Archived", "idList": "l1", "closed": true},
+			{"id": "a4", "name": "In archived list", "idList": "l4"},
+			{"id": "a5", "name": "Ship", "idList": "l1", "dueComplete": true, "due": "soon"},
+			{"id": "a6", "name": "Release", "idList": "l3"}
+		]
+	}`
+
+	rows, err := importer.ReadTrello(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	compareRows(t, rows, []models.ImportRow{
+		{Row: 1, ExternalID: "a1", Task: models.Task{
+			Title: "Write report", Description: "first draft", Status: models.StatusToDo, Tags: []string{"urgent-stuff", "green"},
+			Deadline:  time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC),
+			Checklist: []*models.ChecklistItem{{Text: "First", Checked: true}, {Text: "Second"}, {Text: "Third"}},
+		}},
+		{Row: 2, ExternalID: "a2", Task: models.Task{Title: "Review", Status: models.StatusInProgress}},
+		{Row: 5, ExternalID: "a5", Task: models.Task{Title: "Ship", Status: models.StatusDone}, Err: errRow},
+		{Row: 6, ExternalID: "a6", Task: models.Task{Title: "Release", Status: models.StatusDone}},
+	})
+}
+
+func TestReadMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		read func(data string) ([]models.ImportRow, error)
+		data string
+	}{
+		{name: "empty CSV", read: readCSV(nil), data: ""},
+		{name: "CSV without title column", read: readCSV(nil), data: "id,description\n1,draft\n"},
+		{name: "CSV mapping to unknown field", read: readCSV(map[string]string{"owner": "Owner"}), data: "title\nWrite\n"},
+		{name: "CSV mapping to missing column", read: readCSV(map[string]string{"title": "Name"}), data: "title\nWrite\n"},
+		{name: "CSV with bare quote", read: readCSV(nil), data: "title\nWrite \"report\"\n"},
+		{name: "CSV with unterminated quote", read: readCSV(nil), data: "title\n\"Write report\n"},
+		{name: "todo.txt line too long", read: readTodoTxt, data: strings.Repeat("a", 70*1024)},
+		{name: "Todoist not JSON", read: readTodoist, data: "id,content\n"},
+		{name: "Todoist ID neither string nor number", read: readTodoist, data: `[{"id": true, "content": "Write"}]`},
+		{name: "Todoist backup items not a list", read: readTodoist, data: `{"items": {"id": "1"}}`},
+		{name: "Trello truncated", read: readTrello, data: `{"cards": [{"id": "a1"`},
+		{name: "Trello cards not a list", read: readTrello, data: `{"cards": "a1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tt.read(tt.data)
+			if !errors.Is(err, importer.ErrInvalidFile) {
+				t.Errorf("want %v, got %v with %d rows", importer.ErrInvalidFile, err, len(rows))
+			}
+		})
+	}
+}
+
+func readCSV(mapping map[string]string) func(data string) ([]models.ImportRow, error) {
+	return func(data string) ([]models.ImportRow, error) {
+		return importer.ReadCSV(strings.NewReader(data), mapping, time.UTC)
+	}
+}
+
+func readTodoist(data string) ([]models.ImportRow, error) {
+	return importer.ReadTodoist(strings.NewReader(data), time.UTC)
+}
+
+func readTodoTxt(data string) ([]models.ImportRow, error) {
+	return importer.ReadTodoTxt(strings.NewReader(data))
+}
+
+func readTrello(data string) ([]models.ImportRow, error) {
+	return importer.ReadTrello(strings.NewReader(data))
+}
@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/quickadd"
+)
+
+type todoistItem struct {
+	ID          todoistID `json:"id"`
+	ParentID    todoistID `json:"parent_id"`
+	Content     string    `json:"content"`
+	Description string    `json:"description"`
+	// IsCompleted comes from the REST API, Checked from backups of the sync API.
+	IsCompleted bool     `json:"is_completed"`
+	Checked     bool     `json:"checked"`
+	Labels      []string `json:"labels"`
+	// Priority runs from 1 (normal) to 4 (urgent).
+	Priority int `json:"priority"`
+	Due      *struct {
+		Date        string `json:"date"`
+		Datetime    string `json:"datetime"`
+		String      string `json:"string"`
+		IsRecurring bool   `json:"is_recurring"`
+	} `json:"due"`
+}
+
+// todoistID reads the IDs of Todoist, which are strings in the current API and numbers in older ones.
+type todoistID string
+
+func (id *todoistID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*id = ""
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = todoistID(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("todoist id %s is neither a string nor a number", data)
+	}
+	*id = todoistID(n.String())
+
+	return nil
+}
+
+// ReadTodoist reads the task list returned by the Todoist tasks API, or a backup of the sync API
+// with its tasks under "items". Floating due times are taken in loc, and the due string of a
+// recurring task is read like a quick add to find its recurrence. Rows are numbered by item.
+func ReadTodoist(r io.Reader, loc *time.Location) ([]models.ImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	var items []todoistItem
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var backup struct {
+			Items []todoistItem `json:"items"`
+		}
+		err = json.Unmarshal(data, &backup)
+		items = backup.Items
+	} else {
+		err = json.Unmarshal(data, &items)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	rows := make([]models.ImportRow, len(items))
+	for i, item := range items {
+		rows[i] = todoistRow(i+1, item, loc)
+	}
+
+	return rows, nil
+}
+
+func todoistRow(number int, item todoistItem, loc *time.Location) models.ImportRow {
+	row := models.ImportRow{
+		Row:              number,
+		ExternalID:       string(item.ID),
+		ParentExternalID: string(item.ParentID),
+		Task: models.Task{
+			Title:       item.Content,
+			Description: item.Description,
+			Status:      models.StatusToDo,
+			Priority:    todoistPriority(item.Priority),
+		},
+	}
+
+	if item.IsCompleted || item.Checked {
+		row.Task.Status = models.StatusDone
+	}
+
+	for _, label := range item.Labels {
+		row.Task.Tags = append(row.Task.Tags, tag(label))
+	}
+
+	if item.Due == nil {
+		return row
+	}
+
+	due := item.Due.Datetime
+	if due == "" {
+		due = item.Due.Date
+	}
+
+	if strings.Contains(due, "T") {
+		row.Task.Deadline, row.Err = parseDeadline(due, loc)
+	} else {
+		row.Task.DueDate = due
+	}
+
+	if item.Due.IsRecurring {
+		row.Task.Recurrence = quickadd.Parse(item.Due.String, time.Now().In(loc)).Recurrence
+	}
+
+	return row
+}
+
+func todoistPriority(priority int) string {
+	switch priority {
+	case 4:
+		return models.PriorityHigh
+	case 3:
+		return models.PriorityMedium
+	case 2:
+		return models.PriorityLow
+	default:
+		return models.PriorityNone
+	}
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+var (
+	todoTxtPriority   = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtRecurrence = regexp.MustCompile(`^\+?(\d*)([dwmy])$`)
+)
+
+var todoTxtFrequencies = map[string]string{"d": "DAILY", "w": "WEEKLY", "m": "MONTHLY", "y": "YEARLY"}
+
+// ReadTodoTxt reads a todo.txt file. Projects and contexts become tags, priority A is high,
+// B medium and the others low, and the due:, rec: and pri: extensions are understood.
+// A line without an id: extension is identified by its text. Rows are numbered by line.
+func ReadTodoTxt(r io.Reader) ([]models.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+
+	var rows []models.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		rows = append(rows, todoTxtRow(line, text))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	return rows, nil
+}
+
+func todoTxtRow(line int, text string) models.ImportRow {
+	hash := sha256.Sum256([]byte(text))
+	row := models.ImportRow{
+		Row:        line,
+		ExternalID: hex.EncodeToString(hash[:8]),
+		Task:       models.Task{Status: models.StatusToDo},
+	}
+
+	words := strings.Fields(text)
+
+	if words[0] == "x" {
+		row.Task.Status = models.StatusDone
+		words = words[1:]
+	} else if match := todoTxtPriority.FindStringSubmatch(words[0]); match != nil {
+		row.Task.Priority = todoTxtPriorityOf(match[1])
+		words = words[1:]
+	}
+
+	// Completion and creation dates come next, this service keeps neither.
+	for range 2 {
+		if len(words) > 0 && isDate(words[0]) {
+			words = words[1:]
+		}
+	}
+
+	var title []string
+	for _, word := range words {
+		key, value, isExtension := strings.Cut(word, ":")
+
+		switch {
+		case len(word) > 1 && (word[0] == '+' || word[0] == '@'):
+			row.Task.Tags = append(row.Task.Tags, tag(word[1:]))
+		case isExtension && key == "due" && isDate(value):
+			row.Task.DueDate = value
+		case isExtension && key == "pri" && todoTxtPriority.MatchString("("+value+")"):
+			row.Task.Priority = todoTxtPriorityOf(value)
+		case isExtension && key == "id" && value != "":
+			row.ExternalID = value
+		case isExtension && key == "rec":
+			recurrence, err := todoTxtRRule(value)
+			if err != nil {
+				row.Err = err
+			}
+			row.Task.Recurrence = recurrence
+		default:
+			title = append(title, word)
+		}
+	}
+
+	row.Task.Title = strings.Join(title, " ")
+
+	return row
+}
+
+func todoTxtPriorityOf(letter string) string {
+	switch letter {
+	case "A":
+		return models.PriorityHigh
+	case "B":
+		return models.PriorityMedium
+	default:
+		return models.PriorityLow
+	}
+}
+
+// todoTxtRRule turns a rec: value such as "2w" or "+1m" into an RRULE.
+func todoTxtRRule(value string) (string, error) {
+	match := todoTxtRecurrence.FindStringSubmatch(value)
+	if match == nil {
+		return "", fmt.Errorf("recurrence %q is not a number of days, weeks, months or years", value)
+	}
+
+	rule := "FREQ=" + todoTxtFrequencies[match[2]]
+	if interval, _ := strconv.Atoi(match[1]); interval > 1 {
+		rule += ";INTERVAL=" + strconv.Itoa(interval)
+	}
+
+	return rule, nil
+}
+
+func isDate(value string) bool {
+	_, err := time.Parse(time.DateOnly, value)
+	return err == nil
+}
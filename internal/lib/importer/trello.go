@@ -0,0 +1,135 @@
+package importer
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+type trelloBoard struct {
+	Cards []struct {
+		ID           string   `json:"id"`
+		Name         string   `json:"name"`
+		Desc         string   `json:"desc"`
+		Closed       bool     `json:"closed"`
+		Due          string   `json:"due"`
+		DueComplete  bool     `json:"dueComplete"`
+		IDList       string   `json:"idList"`
+		IDLabels     []string `json:"idLabels"`
+		IDChecklists []string `json:"idChecklists"`
+	} `json:"cards"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Checklists []struct {
+		ID         string            `json:"id"`
+		CheckItems []trelloCheckItem `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+type trelloCheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+// ReadTrello reads the JSON export of a Trello board. Archived cards and the cards of archived
+// lists are left out. A card is done when its due date is marked complete or its list is named
+// like "Done", in progress when its list is named like "Doing" or "In progress". Labels become
+// tags and checklists are merged into the checklist of the task. Rows are numbered by card.
+func ReadTrello(r io.Reader) ([]models.ImportRow, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	lists := make(map[string]string)
+	closedLists := make(map[string]bool)
+	for _, list := range board.Lists {
+		lists[list.ID] = strings.ToLower(list.Name)
+		closedLists[list.ID] = list.Closed
+	}
+
+	labels := make(map[string]string)
+	for _, label := range board.Labels {
+		name := label.Name
+		if name == "" {
+			name = label.Color
+		}
+		labels[label.ID] = tag(name)
+	}
+
+	checklists := make(map[string][]*models.ChecklistItem)
+	for _, checklist := range board.Checklists {
+		items := slices.Clone(checklist.CheckItems)
+		slices.SortStableFunc(items, func(a, b trelloCheckItem) int {
+			return cmp.Compare(a.Pos, b.Pos)
+		})
+
+		for _, item := range items {
+			checklists[checklist.ID] = append(checklists[checklist.ID], &models.ChecklistItem{
+				Text:    item.Name,
+				Checked: item.State == "complete",
+			})
+		}
+	}
+
+	var rows []models.ImportRow
+	for i, card := range board.Cards {
+		if card.Closed || closedLists[card.IDList] {
+			continue
+		}
+
+		row := models.ImportRow{
+			Row:        i + 1,
+			ExternalID: card.ID,
+			Task: models.Task{
+				Title:       card.Name,
+				Description: card.Desc,
+				Status:      trelloStatus(lists[card.IDList], card.DueComplete),
+			},
+		}
+
+		for _, id := range card.IDLabels {
+			if label, ok := labels[id]; ok && label != "" {
+				row.Task.Tags = append(row.Task.Tags, label)
+			}
+		}
+
+		for _, id := range card.IDChecklists {
+			row.Task.Checklist = append(row.Task.Checklist, checklists[id]...)
+		}
+
+		if card.Due != "" {
+			row.Task.Deadline, row.Err = parseDeadline(card.Due, time.UTC)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func trelloStatus(list string, dueComplete bool) string {
+	switch {
+	case dueComplete || strings.Contains(list, "done") || strings.Contains(list, "complete"):
+		return models.StatusDone
+	case strings.Contains(list, "doing") || strings.Contains(list, "progress"):
+		return models.StatusInProgress
+	default:
+		return models.StatusToDo
+	}
+}
@@ -18,6 +18,7 @@ func NewToken(user *models.User, secret string, duration time.Duration) (string,
 	claims := token.Claims.(jwt.MapClaims)
 	claims["uid"] = user.ID
 	claims["email"] = user.Email
+	claims["tz"] = user.Timezone
 	claims["exp"] = time.Now().Add(duration).Unix()
 
 	tokenString, err := token.SignedString([]byte(secret))
@@ -45,9 +46,12 @@ func ParseToken(tokenString, secret string) (*models.Session, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse user ID: %w", err)
 		}
+		// Tokens issued before profiles had a timezone carry no tz claim.
+		timezone, _ := claims["tz"].(string)
 		session := &models.Session{
-			UserID: userID,
-			Email:  claims["email"].(string),
+			UserID:   userID,
+			Email:    claims["email"].(string),
+			Timezone: timezone,
 		}
 		return session, nil
 	}
@@ -0,0 +1,228 @@
+// Package pubsub fans task events out to the watchers of their author within the process.
+// Events are numbered in publishing order and the latest ones are kept, so that a watcher
+// reconnecting can resume after the last event it saw.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+var (
+	// ErrExpired is returned to a watcher resuming after an event that is no longer kept.
+	ErrExpired = errors.New("pubsub: events after the sequence number are no longer kept")
+	// ErrLagging ends the subscription of a watcher that does not keep up with its events.
+	ErrLagging = errors.New("pubsub: subscriber fell behind")
+	// ErrClosed ends the subscriptions of a closed broker.
+	ErrClosed = errors.New("pubsub: broker closed")
+)
+
+// Broker numbers published events, keeps the latest historySize of them and hands them to subscribers.
+type Broker struct {
+	mu sync.Mutex
+	// seq is the number of the last published event.
+	seq uint64
+	// history is a ring of the kept events, oldest at start.
+	history     []models.TaskEvent
+	start       int
+	subscribers map[uuid.UUID]map[*Subscription]struct{}
+	closed      bool
+}
+
+func New(historySize int) *Broker {
+	return &Broker{
+		// Numbering from the clock puts the events of a restarted process past the ones of the process
+		// before, so that resuming after one of those fails instead of skipping events.
+		seq:         uint64(time.Now().UnixMicro()),
+		history:     make([]models.TaskEvent, 0, max(historySize, 1)),
+		subscribers: make(map[uuid.UUID]map[*Subscription]struct{}),
+	}
+}
+
+// Publish numbers the events and delivers them to the subscribers of their authors.
+// Subscribers share the tasks of the events, which must not be modified afterwards.
+func (b *Broker) Publish(events ...models.TaskEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, event := range events {
+		b.seq++
+		event.Seq = b.seq
+
+		if len(b.history) < cap(b.history) {
+			b.history = append(b.history, event)
+		} else {
+			b.history[b.start] = event
+			b.start = (b.start + 1) % len(b.history)
+		}
+
+		for sub := range b.subscribers[event.AuthorID] {
+			// A subscriber further behind than the history could not have resumed from there either.
+			if !sub.push(event, cap(b.history)) {
+				b.remove(sub)
+			}
+		}
+	}
+}
+
+// Subscribe returns the events of the author published after the event numbered after,
+// starting with the kept ones. An after of 0 subscribes to the events to come only.
+// When the events after it are no longer kept, the subscription fails with ErrExpired, or with
+// resetOnExpiry starts with a reset event and goes on with the events to come.
+func (b *Broker) Subscribe(authorID uuid.UUID, after uint64, resetOnExpiry bool) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	sub := &Subscription{broker: b, authorID: authorID, ready: make(chan struct{}, 1)}
+
+	switch {
+	case after == 0:
+	case after > b.seq || after < b.oldest()-1:
+		if !resetOnExpiry {
+			return nil, ErrExpired
+		}
+
+		// Subscribing under the lock puts every event after the reset in the subscription.
+		sub.queue = append(sub.queue, models.TaskEvent{Seq: b.seq, Type: models.TaskEventReset, AuthorID: authorID, OccurredAt: time.Now().UTC()})
+	default:
+		for i := range b.history {
+			event := b.history[(b.start+i)%len(b.history)]
+			if event.Seq > after && event.AuthorID == authorID {
+				sub.queue = append(sub.queue, event)
+			}
+		}
+	}
+	if len(sub.queue) > 0 {
+		sub.ready <- struct{}{}
+	}
+
+	if b.subscribers[authorID] == nil {
+		b.subscribers[authorID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[authorID][sub] = struct{}{}
+
+	return sub, nil
+}
+
+// Close ends every subscription and refuses new ones, so that watchers do not hold up a shutdown.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			sub.end(ErrClosed)
+		}
+	}
+	clear(b.subscribers)
+}
+
+// oldest is the number of the oldest kept event, or of the next one when none is kept.
+func (b *Broker) oldest() uint64 {
+	if len(b.history) == 0 {
+		return b.seq + 1
+	}
+
+	return b.history[b.start].Seq
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	delete(b.subscribers[sub.authorID], sub)
+	if len(b.subscribers[sub.authorID]) == 0 {
+		delete(b.subscribers, sub.authorID)
+	}
+}
+
+// Subscription queues the events of one watcher until it takes them with Next.
+type Subscription struct {
+	broker   *Broker
+	authorID uuid.UUID
+
+	mu    sync.Mutex
+	queue []models.TaskEvent
+	err   error
+	// ready holds a token while the queue has events or the subscription ended.
+	ready chan struct{}
+}
+
+// Next waits for the next event. Once the events queued before a subscription ended are taken,
+// it returns the reason it ended.
+func (s *Subscription) Next(ctx context.Context) (models.TaskEvent, error) {
+	for {
+		s.mu.Lock()
+		if len(s.queue) > 0 {
+			event := s.queue[0]
+			s.queue = s.queue[1:]
+			if len(s.queue) > 0 || s.err != nil {
+				s.signal()
+			}
+			s.mu.Unlock()
+
+			return event, nil
+		}
+		if s.err != nil {
+			err := s.err
+			s.mu.Unlock()
+
+			return models.TaskEvent{}, err
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return models.TaskEvent{}, ctx.Err()
+		case <-s.ready:
+		}
+	}
+}
+
+// Close stops the delivery of events to the subscription.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.remove(s)
+}
+
+// push queues an event, or ends the subscription and reports false when limit events are already queued.
+func (s *Subscription) push(event models.TaskEvent, limit int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) >= limit {
+		s.err = ErrLagging
+		s.signal()
+		return false
+	}
+
+	s.queue = append(s.queue, event)
+	s.signal()
+
+	return true
+}
+
+func (s *Subscription) end(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+	s.signal()
+}
+
+func (s *Subscription) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,208 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/pubsub"
+)
+
+func event(author uuid.UUID, eventType string) models.TaskEvent {
+	return models.TaskEvent{Type: eventType, AuthorID: author, TaskID: uuid.New()}
+}
+
+// next takes the queued events of the subscription, which must be there already.
+func next(t *testing.T, sub *pubsub.Subscription, n int) []models.TaskEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	var events []models.TaskEvent
+	for range n {
+		event, err := sub.Next(ctx)
+		if err != nil {
+			t.Fatalf("next after %d events: %v", len(events), err)
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func seqs(events []models.TaskEvent) []uint64 {
+	var seqs []uint64
+	for _, event := range events {
+		seqs = append(seqs, event.Seq)
+	}
+
+	return seqs
+}
+
+func TestSubscribeToEventsToCome(t *testing.T) {
+	b := pubsub.New(10)
+	author := uuid.New()
+
+	b.Publish(event(author, models.TaskEventCreated))
+
+	sub, err := b.Subscribe(author, 0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer sub.Close()
+
+	b.Publish(event(uuid.New(), models.TaskEventCreated), event(author, models.TaskEventUpdated))
+
+	got := next(t, sub, 1)
+	if got[0].Type != models.TaskEventUpdated || got[0].AuthorID != author {
+		t.Errorf("want the update of the author alone, got %+v", got)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := sub.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("want no more events, got %v", err)
+	}
+}
+
+func TestSubscribeResumes(t *testing.T) {
+	b := pubsub.New(10)
+	author, other := uuid.New(), uuid.New()
+
+	watcher, err := b.Subscribe(author, 0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	b.Publish(event(author, models.TaskEventCreated), event(other, models.TaskEventCreated),
+		event(author, models.TaskEventUpdated), event(author, models.TaskEventDeleted))
+	seen := next(t, watcher, 3)
+	watcher.Close()
+
+	if seen[0].Seq >= seen[1].Seq || seen[1].Seq >= seen[2].Seq {
+		t.Fatalf("want events numbered in publishing order, got %v", seqs(seen))
+	}
+
+	// The watcher reconnects after the first event it saw.
+	resumed, err := b.Subscribe(author, seen[0].Seq, false)
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	defer resumed.Close()
+
+	b.Publish(event(author, models.TaskEventCreated))
+
+	got := next(t, resumed, 3)
+	if got[0].Seq != seen[1].Seq || got[1].Seq != seen[2].Seq || got[2].Seq != seen[2].Seq+1 {
+		t.Errorf("want the kept events after %d then the new one, got %v", seen[0].Seq, seqs(got))
+	}
+
+	// Resuming after the last event is resuming with nothing missed.
+	upToDate, err := b.Subscribe(author, got[2].Seq, false)
+	if err != nil {
+		t.Fatalf("resume after last event: %v", err)
+	}
+	defer upToDate.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := upToDate.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("want no events after the last one, got %v", err)
+	}
+}
+
+func TestSubscribeExpired(t *testing.T) {
+	b := pubsub.New(2)
+	author := uuid.New()
+
+	watcher, err := b.Subscribe(author, 0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	for range 2 {
+		b.Publish(event(author, models.TaskEventCreated))
+		next(t, watcher, 1)
+	}
+	b.Publish(event(author, models.TaskEventCreated), event(author, models.TaskEventUpdated))
+	published := next(t, watcher, 2)
+	watcher.Close()
+	first, last := published[0].Seq-2, published[1].Seq
+
+	// Only the last two events are kept, resuming right before them still works.
+	sub, err := b.Subscribe(author, first+1, false)
+	if err != nil {
+		t.Fatalf("resume before oldest kept event: %v", err)
+	}
+	if got := next(t, sub, 2); got[0].Seq != first+2 || got[1].Seq != last {
+		t.Errorf("want the kept events, got %v", seqs(got))
+	}
+	sub.Close()
+
+	tests := []struct {
+		name  string
+		after uint64
+	}{
+		{"after a dropped event", first},
+		// The numbers of another process, which kept events this one never had.
+		{"after an event to come", last + 100},
+	}
+
+	for _, tt := range tests {
+		if _, err := b.Subscribe(author, tt.after, false); !errors.Is(err, pubsub.ErrExpired) {
+			t.Errorf("%s: want %v, got %v", tt.name, pubsub.ErrExpired, err)
+		}
+
+		sub, err := b.Subscribe(author, tt.after, true)
+		if err != nil {
+			t.Fatalf("%s: subscribe with reset: %v", tt.name, err)
+		}
+
+		b.Publish(event(author, models.TaskEventDeleted))
+
+		got := next(t, sub, 2)
+		if got[0].Type != models.TaskEventReset || got[0].Seq != got[1].Seq-1 || got[1].Type != models.TaskEventDeleted {
+			t.Errorf("%s: want a reset at the last event then the events to come, got %+v", tt.name, got)
+		}
+		sub.Close()
+	}
+}
+
+func TestSubscriberLagging(t *testing.T) {
+	b := pubsub.New(2)
+	author := uuid.New()
+
+	sub, err := b.Subscribe(author, 0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	b.Publish(event(author, models.TaskEventCreated), event(author, models.TaskEventUpdated), event(author, models.TaskEventDeleted))
+
+	// The events queued before falling behind are still taken.
+	next(t, sub, 2)
+	if _, err := sub.Next(t.Context()); !errors.Is(err, pubsub.ErrLagging) {
+		t.Errorf("want %v, got %v", pubsub.ErrLagging, err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	b := pubsub.New(2)
+	author := uuid.New()
+
+	sub, err := b.Subscribe(author, 0, false)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	b.Close()
+
+	if _, err := sub.Next(t.Context()); !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("want %v, got %v", pubsub.ErrClosed, err)
+	}
+	if _, err := b.Subscribe(author, 0, false); !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("subscribe to closed broker: want %v, got %v", pubsub.ErrClosed, err)
+	}
+}
@@ -0,0 +1,309 @@
+package quickadd
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday, "thursday": time.Thursday,
+	"friday": time.Friday, "saturday": time.Saturday, "sunday": time.Sunday,
+}
+
+var shortWeekdays = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "tues": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// datePrefixes may lead a date, as in "due on friday". They only count when a date follows.
+var datePrefixes = map[string]bool{"on": true, "by": true, "due": true}
+
+// weekday reads a day name. Abbreviations are only accepted when short is set, where
+// the caller knows a day is expected, so that "sat" or "sun" in a title are left alone.
+func weekday(key string, short bool) (time.Weekday, bool) {
+	if day, ok := weekdays[key]; ok {
+		return day, true
+	}
+
+	if short {
+		day, ok := shortWeekdays[key]
+		return day, ok
+	}
+
+	return 0, false
+}
+
+// matchDate recognises a calendar day and returns the words consumed and the day at midnight.
+func (p *parser) matchDate(i int) (int, time.Time) {
+	j := i
+	for j < i+2 && datePrefixes[p.key(j)] {
+		j++
+	}
+	prefixed := j > i
+
+	today := midnight(p.now)
+	key := p.key(j)
+
+	switch key {
+	case "today":
+		return j - i + 1, today
+	case "tomorrow", "tmrw", "tmr":
+		return j - i + 1, today.AddDate(0, 0, 1)
+	case "next":
+		switch next := p.key(j + 1); next {
+		case "week":
+			return j - i + 2, nextWeekday(today, time.Monday)
+		case "month":
+			return j - i + 2, time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location())
+		case "year":
+			return j - i + 2, time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location())
+		default:
+			if day, ok := weekday(next, true); ok {
+				return j - i + 2, nextWeekday(today, day)
+			}
+		}
+		return 0, time.Time{}
+	case "this":
+		if day, ok := weekday(p.key(j+1), true); ok {
+			return j - i + 2, nextWeekday(today, day)
+		}
+		return 0, time.Time{}
+	case "in":
+		return p.matchOffset(i, j, today)
+	}
+
+	if day, ok := weekday(key, prefixed); ok {
+		return j - i + 1, nextWeekday(today, day)
+	}
+
+	if date, err := time.ParseInLocation(time.DateOnly, key, today.Location()); err == nil {
+		return j - i + 1, date
+	}
+
+	// "the 15th" is only a date after a prefix, "fix the 1st floor light" is not.
+	if key == "the" && prefixed {
+		if day, ok := ordinal(p.key(j + 1)); ok {
+			return j - i + 2, nextMonthDay(today, day)
+		}
+		return 0, time.Time{}
+	}
+
+	return p.matchMonthDay(i, j, today)
+}
+
+// matchOffset handles "in 3 days", "in a week" and the like, starting at the "in" word j.
+func (p *parser) matchOffset(i, j int, today time.Time) (int, time.Time) {
+	count, ok := number(p.key(j + 1))
+	if !ok {
+		return 0, time.Time{}
+	}
+
+	switch unit := strings.TrimSuffix(p.key(j+2), "s"); unit {
+	case "day":
+		return j - i + 3, today.AddDate(0, 0, count)
+	case "week":
+		return j - i + 3, today.AddDate(0, 0, 7*count)
+	case "month":
+		return j - i + 3, today.AddDate(0, count, 0)
+	case "year":
+		return j - i + 3, today.AddDate(count, 0, 0)
+	}
+
+	return 0, time.Time{}
+}
+
+// matchMonthDay handles "may 1", "may 1st 2027", "1 may" and "1st of may", starting at word j.
+func (p *parser) matchMonthDay(i, j int, today time.Time) (int, time.Time) {
+	var (
+		month time.Month
+		day   int
+		n     int
+	)
+
+	if m, ok := months[p.key(j)]; ok {
+		d, ok := dayOfMonth(p.key(j + 1))
+		if !ok {
+			return 0, time.Time{}
+		}
+		month, day, n = m, d, 2
+	} else if d, ok := dayOfMonth(p.key(j)); ok {
+		k := j + 1
+		if p.key(k) == "of" {
+			k++
+		}
+		m, ok := months[p.key(k)]
+		if !ok {
+			return 0, time.Time{}
+		}
+		month, day, n = m, d, k-j+1
+	} else {
+		return 0, time.Time{}
+	}
+
+	year := today.Year()
+	explicitYear := false
+	if y, err := strconv.Atoi(p.key(j + n)); err == nil && y >= 1970 && y <= 9999 && len(p.key(j+n)) == 4 {
+		year, explicitYear = y, true
+		n++
+	}
+
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Day() != day {
+		// February 30th and friends.
+		return 0, time.Time{}
+	}
+
+	if !explicitYear && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+
+	return j - i + n, date
+}
+
+// matchTime recognises a time of day such as "9am", "9:30 pm", "21:00", "at 9" or "noon".
+func (p *parser) matchTime(i int) (int, int, int) {
+	j := i
+	prefixed := p.key(j) == "at"
+	if prefixed {
+		j++
+	}
+
+	key := p.key(j)
+	if key == "noon" {
+		return j - i + 1, 12, 0
+	}
+
+	n := 1
+	meridiem := ""
+	switch {
+	case strings.HasSuffix(key, "am"), strings.HasSuffix(key, "pm"):
+		key, meridiem = key[:len(key)-2], key[len(key)-2:]
+	case p.key(j+1) == "am", p.key(j+1) == "pm":
+		meridiem = p.key(j + 1)
+		n++
+	}
+
+	hourText, minuteText, hasMinutes := strings.Cut(key, ":")
+	hour, err := strconv.Atoi(hourText)
+	if err != nil || hourText == "" || len(hourText) > 2 {
+		return 0, 0, 0
+	}
+
+	minute := 0
+	if hasMinutes {
+		if len(minuteText) != 2 {
+			return 0, 0, 0
+		}
+		if minute, err = strconv.Atoi(minuteText); err != nil || minute > 59 {
+			return 0, 0, 0
+		}
+	}
+
+	switch {
+	case meridiem != "":
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	case hasMinutes || prefixed:
+		// A bare number is only a time after "at", "buy 2 apples" is not.
+		if hour > 23 {
+			return 0, 0, 0
+		}
+	default:
+		return 0, 0, 0
+	}
+
+	return j - i + n, hour, minute
+}
+
+// nextWeekday returns the next given weekday after today, a week ahead when today is that day.
+func nextWeekday(today time.Time, day time.Weekday) time.Time {
+	days := (int(day) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+
+	return today.AddDate(0, 0, days)
+}
+
+// nextMonthDay returns the next date falling on the given day of the month, today included.
+// Months that are too short for the day are skipped.
+func nextMonthDay(today time.Time, day int) time.Time {
+	for i := 0; i < 12; i++ {
+		date := time.Date(today.Year(), today.Month()+time.Month(i), day, 0, 0, 0, 0, today.Location())
+		if date.Day() == day && !date.Before(today) {
+			return date
+		}
+	}
+
+	return today
+}
+
+// number reads a positive count written as digits, or "a"/"an" for one.
+func number(key string) (int, bool) {
+	if key == "a" || key == "an" {
+		return 1, true
+	}
+
+	n, err := strconv.Atoi(key)
+	if err != nil || n < 1 || n > 999 {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// ordinal reads "1st", "2nd", "23rd" or "15th" as a day of the month.
+func ordinal(key string) (int, bool) {
+	if len(key) < 3 {
+		return 0, false
+	}
+
+	switch key[len(key)-2:] {
+	case "st", "nd", "rd", "th":
+	default:
+		return 0, false
+	}
+
+	day, err := strconv.Atoi(key[:len(key)-2])
+	if err != nil || day < 1 || day > 31 {
+		return 0, false
+	}
+
+	return day, true
+}
+
+// dayOfMonth reads a day of the month written as a number or an ordinal.
+func dayOfMonth(key string) (int, bool) {
+	if day, ok := ordinal(key); ok {
+		return day, true
+	}
+
+	day, err := strconv.Atoi(key)
+	if err != nil || day < 1 || day > 31 || len(key) > 2 {
+		return 0, false
+	}
+
+	return day, true
+}
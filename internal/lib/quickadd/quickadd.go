@@ -0,0 +1,286 @@
+// Package quickadd parses a one-line task description such as
+// "Pay rent every month on the 1st #home !high @alice 9am" into the parts of a task.
+// It understands a small English grammar of dates, times, recurrences, tags, priorities
+// and assignees. Every word it does not understand stays in the title, and text in
+// double quotes is always kept as title.
+package quickadd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// Parse interprets input relative to now, whose location is the user's timezone.
+func Parse(input string, now time.Time) models.QuickAddParse {
+	p := &parser{input: input, words: split(input), now: now}
+	p.used = make([]bool, len(p.words))
+
+	for i := 0; i < len(p.words); {
+		if n := p.quoted(i); n > 0 {
+			i += n
+			continue
+		}
+
+		if n := p.match(i); n > 0 {
+			i += n
+			continue
+		}
+
+		i++
+	}
+
+	return p.finish()
+}
+
+type word struct {
+	text string
+	// key is the lowercased word without trailing punctuation, used for matching.
+	key        string
+	start, end int
+	literal    bool
+}
+
+type parser struct {
+	input string
+	words []word
+	used  []bool
+	now   time.Time
+
+	result models.QuickAddParse
+
+	date    time.Time
+	hasDate bool
+
+	hour, minute int
+	hasTime      bool
+
+	rule rule
+}
+
+// split cuts the input into whitespace separated words, remembering where each one is.
+func split(input string) []word {
+	var words []word
+
+	start := -1
+	for i, r := range input {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				words = append(words, newWord(input, start, i))
+				start = -1
+			}
+			continue
+		}
+
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, newWord(input, start, len(input)))
+	}
+
+	return words
+}
+
+func newWord(input string, start, end int) word {
+	text := input[start:end]
+
+	return word{
+		text:  text,
+		key:   strings.ToLower(strings.TrimRight(text, ",.;:?")),
+		start: start,
+		end:   end,
+	}
+}
+
+func (p *parser) key(i int) string {
+	if i < 0 || i >= len(p.words) || p.words[i].literal {
+		return ""
+	}
+
+	return p.words[i].key
+}
+
+// quoted marks a run of words in double quotes as literal title text.
+func (p *parser) quoted(i int) int {
+	if !strings.HasPrefix(p.words[i].text, `"`) {
+		return 0
+	}
+
+	for j := i; j < len(p.words); j++ {
+		text := p.words[j].text
+		if (j > i || len(text) > 1) && strings.HasSuffix(text, `"`) {
+			for k := i; k <= j; k++ {
+				p.words[k].literal = true
+			}
+			return j - i + 1
+		}
+	}
+
+	return 0
+}
+
+// match tries every grammar rule at word i and returns the number of words it consumed.
+func (p *parser) match(i int) int {
+	if n := p.matchSigil(i); n > 0 {
+		return n
+	}
+
+	if p.rule.freq == "" {
+		if n, r := p.matchRecurrence(i); n > 0 {
+			p.rule = r
+			p.consume(i, n, models.QuickAddRecurrence, r.String())
+			return n
+		}
+	}
+
+	if !p.hasDate {
+		if n, date := p.matchDate(i); n > 0 {
+			p.date, p.hasDate = date, true
+			p.consume(i, n, models.QuickAddDate, date.Format(time.DateOnly))
+			return n
+		}
+	}
+
+	if !p.hasTime {
+		if n, hour, minute := p.matchTime(i); n > 0 {
+			p.hour, p.minute, p.hasTime = hour, minute, true
+			p.consume(i, n, models.QuickAddTime, fmt.Sprintf("%02d:%02d", hour, minute))
+			return n
+		}
+	}
+
+	return 0
+}
+
+func (p *parser) consume(i, n int, kind, value string) {
+	for k := i; k < i+n; k++ {
+		p.used[k] = true
+	}
+
+	p.result.Tokens = append(p.result.Tokens, models.QuickAddToken{
+		Kind:  kind,
+		Text:  p.input[p.words[i].start:p.words[i+n-1].end],
+		Value: value,
+	})
+}
+
+var priorities = map[string]string{
+	"!high": models.PriorityHigh, "!h": models.PriorityHigh, "!1": models.PriorityHigh, "!!!": models.PriorityHigh,
+	"!medium": models.PriorityMedium, "!med": models.PriorityMedium, "!m": models.PriorityMedium, "!2": models.PriorityMedium, "!!": models.PriorityMedium,
+	"!low": models.PriorityLow, "!l": models.PriorityLow, "!3": models.PriorityLow,
+}
+
+// matchSigil recognises the single word #tag, !priority and @assignee markers.
+func (p *parser) matchSigil(i int) int {
+	key := p.key(i)
+
+	switch {
+	case strings.HasPrefix(key, "#") && validName(key[1:]):
+		tag := key[1:]
+		if !contains(p.result.Tags, tag) {
+			p.result.Tags = append(p.result.Tags, tag)
+		}
+		p.consume(i, 1, models.QuickAddTag, tag)
+		return 1
+	case strings.HasPrefix(key, "@") && validName(key[1:]) && p.result.Assignee == "":
+		p.result.Assignee = key[1:]
+		p.consume(i, 1, models.QuickAddAssignee, key[1:])
+		return 1
+	case priorities[key] != "" && p.result.Priority == "":
+		p.result.Priority = priorities[key]
+		p.consume(i, 1, models.QuickAddPriority, priorities[key])
+		return 1
+	}
+
+	return 0
+}
+
+const maxNameLength = 50
+
+func validName(name string) bool {
+	if name == "" || len(name) > maxNameLength {
+		return false
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_./", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// finish resolves the date, time and recurrence into a deadline or an all-day due date.
+func (p *parser) finish() models.QuickAddParse {
+	result := p.result
+
+	var title []string
+	for i, w := range p.words {
+		if p.used[i] {
+			continue
+		}
+
+		text := w.text
+		if w.literal {
+			text = strings.TrimPrefix(text, `"`)
+			text = strings.TrimSuffix(text, `"`)
+		}
+		if text != "" {
+			title = append(title, text)
+		}
+	}
+	result.Title = strings.Join(title, " ")
+
+	if result.Priority == "" {
+		result.Priority = models.PriorityNone
+	}
+
+	// A recurring task starts on a day its rule falls on, so "every month on the 1st tomorrow"
+	// starts on the 1st after tomorrow.
+	today := midnight(p.now)
+	date := p.rule.first(today)
+	if p.hasDate {
+		date = p.rule.first(p.date)
+	}
+
+	switch {
+	case p.hasTime:
+		deadline := at(date, p.hour, p.minute)
+		// A bare time that already passed today means the next time it comes round.
+		if !p.hasDate && deadline.Before(p.now) {
+			deadline = at(p.rule.first(today.AddDate(0, 0, 1)), p.hour, p.minute)
+		}
+		result.Deadline = deadline.UTC()
+	case p.hasDate || p.rule.freq != "":
+		result.DueDate = date.Format(time.DateOnly)
+	}
+
+	result.Recurrence = p.rule.String()
+
+	return result
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func at(date time.Time, hour, minute int) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location())
+}
@@ -0,0 +1,159 @@
+package quickadd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	// A Wednesday morning, the Sunday after it clocks go forward in New York.
+	wednesday := time.Date(2026, time.March, 4, 10, 0, 0, 0, newYork)
+	utc := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		now   time.Time
+		want  models.QuickAddParse
+	}{
+		// No tokens.
+		{"plain title", "Buy milk", wednesday, models.QuickAddParse{Title: "Buy milk"}},
+		{"empty", "", wednesday, models.QuickAddParse{}},
+		{"bare number", "buy 2 apples", wednesday, models.QuickAddParse{Title: "buy 2 apples"}},
+		{"ordinal without prefix", "fix the 1st floor light", wednesday, models.QuickAddParse{Title: "fix the 1st floor light"}},
+		{"adverb", "write weekly report", wednesday, models.QuickAddParse{Title: "write weekly report"}},
+		{"short day name", "sat down with sun", wednesday, models.QuickAddParse{Title: "sat down with sun"}},
+		{"quoted", `"Call @bob tomorrow #now"`, wednesday, models.QuickAddParse{Title: "Call @bob tomorrow #now"}},
+		{"lone sigils", "# ! @", wednesday, models.QuickAddParse{Title: "# ! @"}},
+
+		// Tags, priorities and assignees.
+		{"sigils", "Review PR #work #code !high @alice", wednesday,
+			models.QuickAddParse{Title: "Review PR", Tags: []string{"work", "code"}, Priority: models.PriorityHigh, Assignee: "alice"}},
+		{"repeated tag", "Plan trip #travel #Travel", wednesday,
+			models.QuickAddParse{Title: "Plan trip", Tags: []string{"travel"}}},
+		{"second priority", "Plan trip !low !high", wednesday,
+			models.QuickAddParse{Title: "Plan trip !high", Priority: models.PriorityLow}},
+		{"priority marks", "Fix outage !!!", wednesday,
+			models.QuickAddParse{Title: "Fix outage", Priority: models.PriorityHigh}},
+		{"second assignee", "email @alice @bob", wednesday,
+			models.QuickAddParse{Title: "email @bob", Assignee: "alice"}},
+
+		// Relative dates without a time.
+		{"today", "Submit report today", wednesday, models.QuickAddParse{Title: "Submit report", DueDate: "2026-03-04"}},
+		{"tomorrow", "Submit report tomorrow", wednesday, models.QuickAddParse{Title: "Submit report", DueDate: "2026-03-05"}},
+		{"weekday", "Submit report friday", wednesday, models.QuickAddParse{Title: "Submit report", DueDate: "2026-03-06"}},
+		{"same weekday", "Submit report on wednesday", wednesday, models.QuickAddParse{Title: "Submit report", DueDate: "2026-03-11"}},
+		{"next week", "Submit report next week", wednesday, models.QuickAddParse{Title: "Submit report", DueDate: "2026-03-09"}},
+		{"offset", "Submit report in 3 days", wednesday, models.QuickAddParse{Title: "Submit report", DueDate: "2026-03-07"}},
+		{"month day", "Submit report may 1", wednesday, models.QuickAddParse{Title: "Submit report", DueDate: "2026-05-01"}},
+		{"past month day", "Submit report 1st of feb", wednesday, models.QuickAddParse{Title: "Submit report", DueDate: "2027-02-01"}},
+
+		// Relative dates with a time.
+		{"tomorrow at", "Submit report tomorrow 9am", wednesday, models.QuickAddParse{Title: "Submit report", Deadline: utc(5, 14, 0)}},
+		{"time later today", "Submit report 5pm", wednesday, models.QuickAddParse{Title: "Submit report", Deadline: utc(4, 22, 0)}},
+		{"time passed today", "Submit report at 9", wednesday, models.QuickAddParse{Title: "Submit report", Deadline: utc(5, 14, 0)}},
+		{"weekday at", "Submit report friday 9:30 pm", wednesday, models.QuickAddParse{Title: "Submit report", Deadline: utc(7, 2, 30)}},
+		{"noon", "Lunch noon", wednesday, models.QuickAddParse{Title: "Lunch", Deadline: utc(4, 17, 0)}},
+
+		// Recurrences.
+		{"daily", "Water plants every day", wednesday,
+			models.QuickAddParse{Title: "Water plants", DueDate: "2026-03-04", Recurrence: "FREQ=DAILY"}},
+		{"interval", "Backup every 2 weeks", wednesday,
+			models.QuickAddParse{Title: "Backup", DueDate: "2026-03-04", Recurrence: "FREQ=WEEKLY;INTERVAL=2"}},
+		{"every other", "Review budget every other month", wednesday,
+			models.QuickAddParse{Title: "Review budget", DueDate: "2026-03-04", Recurrence: "FREQ=MONTHLY;INTERVAL=2"}},
+		{"weekdays at", "Standup every weekday 9am", wednesday,
+			models.QuickAddParse{Title: "Standup", Deadline: utc(5, 14, 0), Recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"}},
+		{"day list", "Gym every mon and thu", wednesday,
+			models.QuickAddParse{Title: "Gym", DueDate: "2026-03-05", Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH"}},
+		{"week on day", "Team sync every week on friday", wednesday,
+			models.QuickAddParse{Title: "Team sync", DueDate: "2026-03-06", Recurrence: "FREQ=WEEKLY;BYDAY=FR"}},
+		{"month day rule", "Invoice every 15th", wednesday,
+			models.QuickAddParse{Title: "Invoice", DueDate: "2026-03-15", Recurrence: "FREQ=MONTHLY;BYMONTHDAY=15"}},
+		{"month on day", "Pay rent every month on the 1st #home !high @alice 9am", wednesday,
+			models.QuickAddParse{Title: "Pay rent", Deadline: time.Date(2026, time.April, 1, 13, 0, 0, 0, time.UTC),
+				Recurrence: "FREQ=MONTHLY;BYMONTHDAY=1", Tags: []string{"home"}, Priority: models.PriorityHigh, Assignee: "alice"}},
+		{"date off the rule", "Pay rent every month on the 1st tomorrow 9am", wednesday,
+			models.QuickAddParse{Title: "Pay rent", Deadline: time.Date(2026, time.April, 1, 13, 0, 0, 0, time.UTC), Recurrence: "FREQ=MONTHLY;BYMONTHDAY=1"}},
+		{"date on the rule", "Gym every mon and thu next week", wednesday,
+			models.QuickAddParse{Title: "Gym", DueDate: "2026-03-09", Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH"}},
+
+		// Timezones: dates are the user's, deadlines are UTC instants across DST changes.
+		{"before spring forward", "Call mom saturday 9am", wednesday, models.QuickAddParse{Title: "Call mom", Deadline: utc(7, 14, 0)}},
+		{"after spring forward", "Call mom sunday 9am", wednesday, models.QuickAddParse{Title: "Call mom", Deadline: utc(8, 13, 0)}},
+		{"late evening", "Call mom today", time.Date(2026, time.March, 4, 23, 30, 0, 0, newYork),
+			models.QuickAddParse{Title: "Call mom", DueDate: "2026-03-04"}},
+		{"after fall back", "Call mom tomorrow 9am", time.Date(2026, time.October, 24, 12, 0, 0, 0, berlin),
+			models.QuickAddParse{Title: "Call mom", Deadline: time.Date(2026, time.October, 25, 8, 0, 0, 0, time.UTC)}},
+		{"other timezone", "Call mom tomorrow 9am", time.Date(2026, time.March, 4, 10, 0, 0, 0, berlin),
+			models.QuickAddParse{Title: "Call mom", Deadline: utc(5, 8, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.input, tt.now)
+
+			want := tt.want
+			if want.Priority == "" {
+				want.Priority = models.PriorityNone
+			}
+
+			if got.Title != want.Title {
+				t.Errorf("title: want %q, got %q", want.Title, got.Title)
+			}
+			if !got.Deadline.Equal(want.Deadline) {
+				t.Errorf("deadline: want %v, got %v", want.Deadline, got.Deadline)
+			}
+			if got.DueDate != want.DueDate {
+				t.Errorf("due date: want %q, got %q", want.DueDate, got.DueDate)
+			}
+			if got.Recurrence != want.Recurrence {
+				t.Errorf("recurrence: want %q, got %q", want.Recurrence, got.Recurrence)
+			}
+			if !slices.Equal(got.Tags, want.Tags) {
+				t.Errorf("tags: want %q, got %q", want.Tags, got.Tags)
+			}
+			if got.Priority != want.Priority {
+				t.Errorf("priority: want %q, got %q", want.Priority, got.Priority)
+			}
+			if got.Assignee != want.Assignee {
+				t.Errorf("assignee: want %q, got %q", want.Assignee, got.Assignee)
+			}
+		})
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	now := time.Date(2026, time.March, 4, 10, 0, 0, 0, time.UTC)
+
+	got := Parse("Pay rent every month on the 1st #home tomorrow at 9am", now).Tokens
+
+	want := []models.QuickAddToken{
+		{Kind: models.QuickAddRecurrence, Text: "every month on the 1st", Value: "FREQ=MONTHLY;BYMONTHDAY=1"},
+		{Kind: models.QuickAddTag, Text: "#home", Value: "home"},
+		{Kind: models.QuickAddDate, Text: "tomorrow", Value: "2026-03-05"},
+		{Kind: models.QuickAddTime, Text: "at 9am", Value: "09:00"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,169 @@
+package quickadd
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+	freqYearly  = "YEARLY"
+)
+
+// rule is the subset of an RFC 5545 recurrence rule the parser can produce.
+type rule struct {
+	freq       string
+	interval   int
+	byDay      []time.Weekday
+	byMonthDay int
+}
+
+var byDayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var workWeek = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// String renders the rule as an RRULE value, empty for the zero rule.
+func (r rule) String() string {
+	if r.freq == "" {
+		return ""
+	}
+
+	parts := []string{"FREQ=" + r.freq}
+	if r.interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.interval))
+	}
+
+	if len(r.byDay) > 0 {
+		days := make([]string, len(r.byDay))
+		for i, day := range r.byDay {
+			days[i] = byDayCodes[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if r.byMonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.byMonthDay))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// first returns the first day on or after from that the rule falls on.
+// Rules without a day constraint, and the zero rule, start on from itself.
+func (r rule) first(from time.Time) time.Time {
+	switch {
+	case len(r.byDay) > 0:
+		for i := 0; i < 7; i++ {
+			date := from.AddDate(0, 0, i)
+			for _, day := range r.byDay {
+				if date.Weekday() == day {
+					return date
+				}
+			}
+		}
+	case r.byMonthDay > 0:
+		return nextMonthDay(from, r.byMonthDay)
+	}
+
+	return from
+}
+
+var units = map[string]string{
+	"day": freqDaily, "week": freqWeekly, "month": freqMonthly, "year": freqYearly,
+}
+
+// matchRecurrence recognises "every day", "every 2 weeks", "every other day", "every weekday",
+// "every mon and thu", "every 15th" and "every month on the 1st". Bare adverbs like "weekly"
+// are left alone, they are too common in titles such as "write weekly report".
+func (p *parser) matchRecurrence(i int) (int, rule) {
+	if p.key(i) != "every" {
+		return 0, rule{}
+	}
+
+	r := rule{interval: 1}
+	j := i + 1
+
+	if p.key(j) == "other" {
+		r.interval = 2
+		j++
+	} else if n, err := strconv.Atoi(p.key(j)); err == nil && n > 1 && n < 1000 {
+		r.interval = n
+		j++
+	}
+
+	key := p.key(j)
+	if freq, ok := units[strings.TrimSuffix(key, "s")]; ok {
+		r.freq = freq
+		j++
+	} else if r.interval > 1 {
+		return 0, rule{}
+	} else if key == "weekday" || key == "weekdays" {
+		r.freq, r.byDay = freqWeekly, workWeek
+		j++
+	} else if days, n := p.dayList(j); n > 0 {
+		r.freq, r.byDay = freqWeekly, days
+		j += n
+	} else if day, n := p.monthDay(j); n > 0 {
+		r.freq, r.byMonthDay = freqMonthly, day
+		j += n
+	} else {
+		return 0, rule{}
+	}
+
+	// Qualifiers such as "every month on the 1st" or "every week on monday".
+	switch {
+	case r.freq == freqMonthly && r.byMonthDay == 0 && p.key(j) == "on":
+		if day, n := p.monthDay(j + 1); n > 0 {
+			r.byMonthDay = day
+			j += n + 1
+		}
+	case r.freq == freqWeekly && len(r.byDay) == 0 && p.key(j) == "on":
+		if days, n := p.dayList(j + 1); n > 0 {
+			r.byDay = days
+			j += n + 1
+		}
+	}
+
+	return j - i, r
+}
+
+// dayList reads "monday", "mon, wed" or "tuesday and thursday".
+func (p *parser) dayList(j int) ([]time.Weekday, int) {
+	var days []time.Weekday
+
+	k := j
+	for {
+		day, ok := weekday(strings.TrimSuffix(p.key(k), "s"), true)
+		if !ok {
+			break
+		}
+		days = append(days, day)
+		k++
+
+		if p.key(k) == "and" {
+			if _, ok := weekday(strings.TrimSuffix(p.key(k+1), "s"), true); ok {
+				k++
+			}
+		}
+	}
+
+	return days, k - j
+}
+
+// monthDay reads "15th" or "the 15th".
+func (p *parser) monthDay(j int) (int, int) {
+	n := 0
+	if p.key(j) == "the" {
+		n++
+	}
+
+	day, ok := ordinal(p.key(j + n))
+	if !ok {
+		return 0, 0
+	}
+
+	return day, n + 1
+}
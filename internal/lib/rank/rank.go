@@ -0,0 +1,140 @@
+// Package rank implements lexorank-style fractional keys: strings that sort
+// lexicographically and always leave room for a new key between any two of them,
+// so moving an item in an ordered list only rewrites that item's key.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits are ordered by their byte value, so keys compare correctly as plain strings.
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+var ErrInvalidRange = errors.New("rank: lower key must sort before upper key")
+
+// Between returns a key that sorts strictly between a and b. An empty a means
+// "before everything", an empty b means "after everything".
+// Valid keys never end in the smallest digit, which is what guarantees there is room between any two of them.
+func Between(a, b string) (string, error) {
+	if !valid(a) || !valid(b) {
+		return "", ErrInvalidRange
+	}
+
+	if b != "" && a >= b {
+		return "", ErrInvalidRange
+	}
+
+	return midpoint(a, b), nil
+}
+
+// Spread returns n evenly spaced keys of no more digits than it takes to tell n keys apart, used to
+// rebalance a list whose keys grew too long.
+func Spread(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	width, span := 1, base
+	for span <= n {
+		width++
+		span *= base
+	}
+
+	keys := make([]string, n)
+	step := span / (n + 1)
+	for i := range keys {
+		keys[i] = encode((i+1)*step, width)
+	}
+
+	return keys
+}
+
+// After returns n ascending keys that all sort after key and before any key that sorts after it,
+// used to append several items at once without every key growing one digit longer than the last.
+func After(key string, n int) ([]string, error) {
+	if !valid(key) {
+		return nil, ErrInvalidRange
+	}
+
+	keys := Spread(n)
+	for i := range keys {
+		keys[i] = key + keys[i]
+	}
+
+	return keys, nil
+}
+
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix and only work on the part where the keys differ.
+		n := 0
+		for n < len(b) && digitAt(a, n) == index(b[n]) {
+			n++
+		}
+
+		if n > 0 {
+			return b[:n] + midpoint(tail(a, n), b[n:])
+		}
+	}
+
+	lo := digitAt(a, 0)
+	hi := base
+	if b != "" {
+		hi = index(b[0])
+	}
+
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+
+	// The first digits are adjacent. A longer b still leaves room right at its first digit,
+	// otherwise keep a's first digit and look for room after the rest of a.
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	return string(digits[lo]) + midpoint(tail(a, 1), "")
+}
+
+func encode(value, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = digits[value%base]
+		value /= base
+	}
+
+	return strings.TrimRight(string(buf), digits[:1])
+}
+
+func valid(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if index(key[i]) < 0 {
+			return false
+		}
+	}
+
+	return !strings.HasSuffix(key, digits[:1])
+}
+
+func digitAt(key string, i int) int {
+	if i >= len(key) {
+		return 0
+	}
+
+	return index(key[i])
+}
+
+func tail(key string, n int) string {
+	if n >= len(key) {
+		return ""
+	}
+
+	return key[n:]
+}
+
+func index(c byte) int {
+	return strings.IndexByte(digits, c)
+}
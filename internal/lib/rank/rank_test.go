@@ -0,0 +1,174 @@
+package rank_test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/SlashLight/todo-list/internal/lib/rank"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		want    string
+		wantErr error
+	}{
+		{name: "empty list", a: "", b: "", want: "V"},
+		{name: "before the first key", a: "", b: "1", want: "0V"},
+		{name: "after the last key", a: "1", b: "", want: "V"},
+		{name: "after the largest digit", a: "z", b: "", want: "zV"},
+		{name: "room at the first digit", a: "1", b: "z", want: "V"},
+		{name: "adjacent keys", a: "1", b: "2", want: "1V"},
+		{name: "adjacent keys of different length", a: "0V", b: "1", want: "0k"},
+		{name: "key and its extension", a: "1", b: "11", want: "10V"},
+		{name: "adjacent keys at the maximum length", a: strings.Repeat("V", 32), b: strings.Repeat("V", 31) + "W", want: strings.Repeat("V", 33)},
+		{name: "after a key at the maximum length", a: strings.Repeat("z", 32), b: "", want: strings.Repeat("z", 32) + "V"},
+		{name: "equal keys", a: "V", b: "V", wantErr: rank.ErrInvalidRange},
+		{name: "keys out of order", a: "W", b: "V", wantErr: rank.ErrInvalidRange},
+		{name: "key ending in the smallest digit", a: "V0", b: "", wantErr: rank.ErrInvalidRange},
+		{name: "smallest digit as upper key", a: "", b: "0", wantErr: rank.ErrInvalidRange},
+		{name: "unknown digit", a: "V-", b: "", wantErr: rank.ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		got, err := rank.Between(tt.a, tt.b)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: want error %v, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: want %q, got %q", tt.name, tt.want, got)
+		}
+		if err == nil && (got <= tt.a || tt.b != "" && got >= tt.b) {
+			t.Errorf("%s: %q does not sort between %q and %q", tt.name, got, tt.a, tt.b)
+		}
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		n       int
+		want    []string
+		wantErr error
+	}{
+		{name: "after a key", key: "V", n: 3, want: []string{"VF", "VU", "Vj"}},
+		{name: "empty list", key: "", n: 2, want: []string{"K", "e"}},
+		{name: "after a key at the maximum length", key: strings.Repeat("z", 32), n: 1, want: []string{strings.Repeat("z", 32) + "V"}},
+		{name: "no keys", key: "V", n: 0},
+		{name: "key ending in the smallest digit", key: "V0", n: 1, wantErr: rank.ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		got, err := rank.After(tt.key, tt.n)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: want error %v, got %v", tt.name, tt.wantErr, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: want %q, got %q", tt.name, tt.want, got)
+		}
+	}
+
+	// Every key sorts after the key it follows and before the next key of the list.
+	keys, err := rank.After("V", 100)
+	if err != nil {
+		t.Fatalf("after: %v", err)
+	}
+	if len(keys) != 100 || !slices.IsSorted(keys) || keys[0] <= "V" || keys[len(keys)-1] >= "W" {
+		t.Errorf("want 100 ascending keys between %q and %q, got %q", "V", "W", keys)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		n             int
+		first, last   string
+		wantMaxLength int
+	}{
+		{n: 1, first: "V", last: "V", wantMaxLength: 1},
+		{n: 2, first: "K", last: "e", wantMaxLength: 1},
+		{n: 61, first: "1", last: "z", wantMaxLength: 1},
+		{n: 62, first: "0z", last: "z", wantMaxLength: 2},
+		{n: 3843, first: "01", last: "zz", wantMaxLength: 2},
+		{n: 3844, first: "00z", last: "z", wantMaxLength: 3},
+	}
+
+	for _, tt := range tests {
+		keys := rank.Spread(tt.n)
+		if len(keys) != tt.n || keys[0] != tt.first || keys[len(keys)-1] != tt.last {
+			t.Errorf("%d keys: want %d keys from %q to %q, got %d from %q to %q",
+				tt.n, tt.n, tt.first, tt.last, len(keys), keys[0], keys[len(keys)-1])
+			continue
+		}
+
+		for i, key := range keys {
+			if len(key) > tt.wantMaxLength {
+				t.Errorf("%d keys: want keys of at most %d digits, got %q", tt.n, tt.wantMaxLength, key)
+				break
+			}
+			if i > 0 && keys[i-1] >= key {
+				t.Errorf("%d keys: %q does not sort after %q", tt.n, key, keys[i-1])
+				break
+			}
+			// The keys leave room before and after each of them.
+			if _, err := rank.Between(key, ""); err != nil {
+				t.Errorf("%d keys: %q is not a valid key: %v", tt.n, key, err)
+				break
+			}
+		}
+	}
+
+	if keys := rank.Spread(0); keys != nil {
+		t.Errorf("no keys: want none, got %q", keys)
+	}
+}
+
+// TestInsertedKeysStayOrdered inserts keys at random places of a list the way moves and appends do,
+// and checks the list stays strictly ordered.
+func TestInsertedKeysStayOrdered(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	var keys []string
+	for range 5000 {
+		if r.IntN(10) == 0 {
+			last := ""
+			if len(keys) > 0 {
+				last = keys[len(keys)-1]
+			}
+
+			appended, err := rank.After(last, 1+r.IntN(5))
+			if err != nil {
+				t.Fatalf("after %q: %v", last, err)
+			}
+			keys = append(keys, appended...)
+			continue
+		}
+
+		i := r.IntN(len(keys) + 1)
+		var lower, upper string
+		if i > 0 {
+			lower = keys[i-1]
+		}
+		if i < len(keys) {
+			upper = keys[i]
+		}
+
+		key, err := rank.Between(lower, upper)
+		if err != nil {
+			t.Fatalf("between %q and %q: %v", lower, upper, err)
+		}
+		keys = slices.Insert(keys, i, key)
+	}
+
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] >= keys[i] {
+			t.Fatalf("key %d: %q does not sort after %q", i, keys[i], keys[i-1])
+		}
+	}
+}
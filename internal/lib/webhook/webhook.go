@@ -0,0 +1,214 @@
+// Package webhook posts signed JSON payloads to the endpoints users register.
+//
+// Every request carries the event type, the delivery ID and a signature header of the form
+// "t=<unix time>,v1=<hex HMAC-SHA256>", where the HMAC with the webhook secret is taken over
+// the time, a dot and the body. Receivers recompute it and reject old times to stop replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	EventHeader     = "X-Todo-Event"
+	DeliveryHeader  = "X-Todo-Delivery"
+	SignatureHeader = "X-Todo-Signature"
+
+	userAgent = "todo-list-webhooks/1"
+	// maxResponseSize is how much of a response is read before the connection is given up.
+	maxResponseSize = 64 << 10
+)
+
+var (
+	// ErrForbiddenAddress is returned for endpoints on loopback, private, link-local and reserved
+	// addresses when those are not allowed.
+	ErrForbiddenAddress = errors.New("webhook: address is not allowed")
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+)
+
+// Message is one payload to post.
+type Message struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// Client posts messages without following redirects.
+type Client struct {
+	http *http.Client
+}
+
+// New returns a client giving up on a request after timeout. Unless allowPrivate is set it refuses
+// to connect to addresses of the host and its networks, which users could otherwise reach through it.
+func New(timeout time.Duration, allowPrivate bool) *Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		// A proxy would connect on our behalf, past the address check.
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConnsPerHost:   2,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	return &Client{
+		http: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts the message and returns the HTTP status of the response. Any response is a nil
+// error, telling a delivered message from one the endpoint refused is up to the caller.
+func (c *Client) Send(ctx context.Context, msg Message) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.URL, bytes.NewReader(msg.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, msg.Event)
+	req.Header.Set(DeliveryHeader, msg.DeliveryID)
+	req.Header.Set(SignatureHeader, Sign(msg.Secret, time.Now(), msg.Body))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Reading the rest lets the connection be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	return resp.StatusCode, nil
+}
+
+// NewSecret returns a random secret to sign the payloads of a webhook with.
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// Sign returns the signature header of body sent at the given time.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac(secret, timestamp, body))
+}
+
+// Verify checks the signature header of body, which must have been sent at most tolerance before now.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var (
+		timestamp string
+		sums      [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sum, err := hex.DecodeString(value); err == nil {
+				sums = append(sums, sum)
+			}
+		}
+	}
+
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(sentAt, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp out of tolerance", ErrInvalidSignature)
+	}
+
+	expected := mac(secret, timestamp, body)
+	for _, sum := range sums {
+		if hmac.Equal(sum, expected) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+func mac(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// reservedNetworks are the networks reaching the host or its providers that the net.IP methods
+// leave out: "this network", which Linux routes to the host, and the shared address space of
+// carrier-grade NAT, which cloud providers use for their internal services.
+var reservedNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return network
+}
@@ -0,0 +1,155 @@
+package webhook_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/lib/webhook"
+)
+
+const secret = "whsec_test"
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"created"}`)
+	sentAt := time.Unix(1700000000, 0)
+	header := webhook.Sign(secret, sentAt, body)
+
+	if !strings.HasPrefix(header, "t=1700000000,v1=") {
+		t.Fatalf("want the time and the signature in the header, got %q", header)
+	}
+
+	_, sum, _ := strings.Cut(header, ",v1=")
+	// The signature of another body, for a rotated secret, which receivers accept along with the current one.
+	otherSum := strings.TrimPrefix(webhook.Sign(secret, sentAt, []byte("{}")), "t=1700000000,v1=")
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		valid  bool
+	}{
+		{"valid", secret, header, body, sentAt.Add(time.Minute), true},
+		{"several signatures", secret, "t=1700000000,v1=" + otherSum + ",v1=" + sum, body, sentAt, true},
+		{"spaces after commas", secret, "t=1700000000, v1=" + sum, body, sentAt, true},
+		{"other secret", "whsec_other", header, body, sentAt, false},
+		{"other body", secret, header, []byte(`{"type":"deleted"}`), sentAt, false},
+		{"other time", secret, "t=1700000001,v1=" + sum, body, sentAt, false},
+		{"too old", secret, header, body, sentAt.Add(6 * time.Minute), false},
+		{"too far ahead", secret, header, body, sentAt.Add(-6 * time.Minute), false},
+		{"no time", secret, "v1=" + sum, body, sentAt, false},
+		{"no signature", secret, "t=1700000000", body, sentAt, false},
+		{"malformed signature", secret, "t=1700000000,v1=zz", body, sentAt, false},
+		{"empty", secret, "", body, sentAt, false},
+	}
+
+	for _, tt := range tests {
+		err := webhook.Verify(tt.secret, tt.header, tt.body, tt.now, 5*time.Minute)
+		if tt.valid && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, webhook.ErrInvalidSignature) {
+			t.Errorf("%s: want %v, got %v", tt.name, webhook.ErrInvalidSignature, err)
+		}
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := webhook.NewSecret()
+	if err != nil {
+		t.Fatalf("new secret: %v", err)
+	}
+	second, err := webhook.NewSecret()
+	if err != nil {
+		t.Fatalf("new secret: %v", err)
+	}
+
+	if !strings.HasPrefix(first, "whsec_") || len(first) < 40 || first == second {
+		t.Errorf("want random prefixed secrets, got %q and %q", first, second)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var (
+		got     *http.Request
+		gotBody []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	msg := webhook.Message{
+		URL:        server.URL,
+		Secret:     secret,
+		Event:      "created",
+		DeliveryID: "delivery-1",
+		Body:       []byte(`{"type":"created"}`),
+	}
+
+	code, err := webhook.New(time.Second, true).Send(t.Context(), msg)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if code != http.StatusAccepted {
+		t.Errorf("want status %d, got %d", http.StatusAccepted, code)
+	}
+
+	if got.Method != http.MethodPost || got.Header.Get(webhook.EventHeader) != "created" ||
+		got.Header.Get(webhook.DeliveryHeader) != "delivery-1" || string(gotBody) != string(msg.Body) {
+		t.Errorf("want the message posted, got %s %v %q", got.Method, got.Header, gotBody)
+	}
+	if err := webhook.Verify(secret, got.Header.Get(webhook.SignatureHeader), gotBody, time.Now(), time.Minute); err != nil {
+		t.Errorf("want a valid signature: %v", err)
+	}
+}
+
+func TestSendDoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	code, err := webhook.New(time.Second, true).Send(t.Context(), webhook.Message{URL: server.URL})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if code != http.StatusFound {
+		t.Errorf("want the redirect returned, got status %d", code)
+	}
+}
+
+func TestSendRefusesForbiddenAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := webhook.New(time.Second, false)
+
+	for _, url := range []string{
+		server.URL,
+		"http://[::1]:9/",
+		"http://0.0.0.0:9/",
+		"http://0.1.2.3:9/",
+		"http://10.0.0.1:9/",
+		"http://100.64.0.1:9/",
+		"http://100.127.255.254:9/",
+		"http://169.254.169.254:9/",
+		"http://172.16.0.1:9/",
+		"http://192.168.1.1:9/",
+		"http://[fd00::1]:9/",
+		"http://[fe80::1]:9/",
+		"http://[::ffff:100.64.0.1]:9/",
+	} {
+		if _, err := client.Send(t.Context(), webhook.Message{URL: url}); !errors.Is(err, webhook.ErrForbiddenAddress) {
+			t.Errorf("%s: want %v, got %v", url, webhook.ErrForbiddenAddress, err)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+// Package websocket implements the server side of the WebSocket protocol (RFC 6455) as far as
+// the gateway needs it to push messages: the handshake, unfragmented messages out, and any
+// messages and control frames in.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Close codes of RFC 6455 section 7.4.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+	CloseTryAgainLater   = 1013
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+
+	// acceptGUID is appended to the key of a handshake to compute its accept value.
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// MaxMessageSize is the largest message read from a client, in bytes.
+	MaxMessageSize = 64 * 1024
+)
+
+var (
+	ErrBadHandshake = errors.New("websocket: not a websocket handshake")
+	ErrBadOrigin    = errors.New("websocket: cross-origin handshake")
+	ErrClosed       = errors.New("websocket: connection closed")
+)
+
+// CloseError is returned by ReadMessage once the client closed the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed by client with %d %s", e.Code, e.Reason)
+}
+
+// Conn is an upgraded connection. One goroutine may read while others write.
+type Conn struct {
+	conn        net.Conn
+	r           *bufio.Reader
+	readTimeout time.Duration
+
+	// mu serialises the frames of writers and of the reader answering control frames.
+	mu        sync.Mutex
+	closeSent bool
+}
+
+// Upgrade answers a WebSocket handshake and takes the connection over from the HTTP server.
+// A request that is not a handshake gets an error response and ErrBadHandshake, one from a page of
+// another origin a 403 and ErrBadOrigin.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !SameOrigin(r) {
+		http.Error(w, "Cross-origin WebSocket requests are not allowed", http.StatusForbidden)
+		return nil, ErrBadOrigin
+	}
+
+	if r.Method != http.MethodGet || !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "WebSocket handshake expected", http.StatusBadRequest)
+		return nil, ErrBadHandshake
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, ErrBadHandshake
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "Invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, ErrBadHandshake
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "Connection cannot be upgraded", http.StatusInternalServerError)
+		return nil, fmt.Errorf("websocket: hijack: %w", err)
+	}
+
+	// The server may have set deadlines for the HTTP request.
+	_ = conn.SetDeadline(time.Time{})
+
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: write handshake: %w", err)
+	}
+
+	return &Conn{conn: conn, r: rw.Reader}, nil
+}
+
+// SameOrigin reports whether the handshake comes from a page of the host it is sent to, or from a
+// client other than a browser, which sends no Origin. Browsers send cookies along with handshakes
+// from any page and leave it to the server to refuse the pages of other origins.
+func SameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return u.Host != "" && strings.EqualFold(u.Host, r.Host)
+}
+
+// headerHasToken reports whether the comma separated header holds token, ignoring case.
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SetReadTimeout makes ReadMessage fail when the client sends no frame, pongs included, for d.
+func (c *Conn) SetReadTimeout(d time.Duration) {
+	c.readTimeout = d
+}
+
+// WriteText sends a text message, giving up at deadline.
+func (c *Conn) WriteText(data []byte, deadline time.Time) error {
+	return c.writeFrame(opText, data, deadline)
+}
+
+// Ping sends a ping, which the client answers with a pong.
+func (c *Conn) Ping(deadline time.Time) error {
+	return c.writeFrame(opPing, nil, deadline)
+}
+
+// Close sends a close frame, unless one was sent already, and closes the connection.
+func (c *Conn) Close(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+
+	err := c.writeFrame(opClose, payload, time.Now().Add(time.Second))
+	if closeErr := c.conn.Close(); err == nil || errors.Is(err, ErrClosed) {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte, deadline time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeSent {
+		return ErrClosed
+	}
+	if opcode == opClose {
+		c.closeSent = true
+	}
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+
+	buffers := net.Buffers{header, payload}
+	_, err := buffers.WriteTo(c.conn)
+
+	return err
+}
+
+// ReadMessage returns the next text or binary message of the client, answering pings and
+// ignoring pongs on the way. A close from the client is answered and returned as a *CloseError.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var (
+		message    []byte
+		fragmented bool
+	)
+
+	for {
+		if c.readTimeout > 0 {
+			if err := c.conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
+				return nil, err
+			}
+		}
+
+		fin, opcode, payload, err := c.readFrame(MaxMessageSize - len(message))
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload, time.Now().Add(time.Second)); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			closeErr := &CloseError{Code: CloseNormal}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			_ = c.Close(closeErr.Code, "")
+
+			return nil, closeErr
+		case opText, opBinary, opContinuation:
+			if (opcode == opContinuation) != fragmented {
+				_ = c.Close(CloseProtocolError, "unexpected continuation")
+				return nil, fmt.Errorf("websocket: unexpected opcode %d", opcode)
+			}
+
+			message = append(message, payload...)
+			if fin {
+				return message, nil
+			}
+			fragmented = true
+		default:
+			_ = c.Close(CloseProtocolError, "unknown opcode")
+			return nil, fmt.Errorf("websocket: unknown opcode %d", opcode)
+		}
+	}
+}
+
+// readFrame reads one frame of at most limit payload bytes and unmasks it.
+func (c *Conn) readFrame(limit int) (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+
+	if header[0]&0x70 != 0 || !masked {
+		_ = c.Close(CloseProtocolError, "")
+		return false, 0, nil, errors.New("websocket: invalid frame header")
+	}
+
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.r, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.r, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+
+	if opcode >= opClose && (length > 125 || !fin) {
+		_ = c.Close(CloseProtocolError, "")
+		return false, 0, nil, errors.New("websocket: invalid control frame")
+	}
+	if opcode < opClose && length > uint64(limit) {
+		_ = c.Close(CloseMessageTooBig, "")
+		return false, 0, nil, errors.New("websocket: message too big")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
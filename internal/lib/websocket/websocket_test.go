@@ -0,0 +1,428 @@
+package websocket_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/lib/websocket"
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// client is the client side of a connection, writing frames by hand.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// newConn upgrades a connection to a test server and returns both of its ends.
+func newConn(t *testing.T) (*websocket.Conn, *client) {
+	t.Helper()
+
+	conns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		conns <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	c := dial(t, server, http.Header{})
+	resp, err := http.ReadResponse(c.r, nil)
+	if err != nil {
+		t.Fatalf("read handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("want %d, got %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+
+	conn := <-conns
+	t.Cleanup(func() { conn.Close(websocket.CloseNormal, "") })
+
+	return conn, c
+}
+
+// dial sends a handshake to the server with the RFC 6455 sample key, the header added to it.
+func dial(t *testing.T, server *httptest.Server, header http.Header) *client {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = header
+	for name, value := range map[string]string{
+		"Connection":            "keep-alive, Upgrade",
+		"Upgrade":               "websocket",
+		"Sec-WebSocket-Version": "13",
+		"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+	} {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// writeFrame writes a frame masked the way clients must, unless masked is false.
+func (c *client) writeFrame(fin bool, opcode byte, payload []byte, masked bool) {
+	c.t.Helper()
+
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first, 0}
+	switch n := len(payload); {
+	case n < 126:
+		frame[1] = byte(n)
+	case n <= 0xffff:
+		frame[1] = 126
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame[1] = 127
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	data := payload
+	if masked {
+		frame[1] |= 0x80
+		mask := []byte{0x37, 0xfa, 0x21, 0x3d}
+		frame = append(frame, mask...)
+		data = make([]byte, len(payload))
+		for i := range payload {
+			data[i] = payload[i] ^ mask[i%4]
+		}
+	}
+
+	if _, err := c.conn.Write(append(frame, data...)); err != nil {
+		c.t.Fatalf("write frame: %v", err)
+	}
+}
+
+// readFrame reads a frame of the server, which must not mask it.
+func (c *client) readFrame() (fin bool, opcode byte, payload []byte) {
+	c.t.Helper()
+
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		c.t.Fatalf("read frame: %v", err)
+	}
+	if header[1]&0x80 != 0 {
+		c.t.Fatal("want the frames of the server unmasked")
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.r, extended[:]); err != nil {
+			c.t.Fatalf("read frame length: %v", err)
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.r, extended[:]); err != nil {
+			c.t.Fatalf("read frame length: %v", err)
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		c.t.Fatalf("read frame payload: %v", err)
+	}
+
+	return header[0]&0x80 != 0, header[0] & 0x0f, payload
+}
+
+// readClose reads a close frame and returns its code.
+func (c *client) readClose() int {
+	c.t.Helper()
+
+	_, opcode, payload := c.readFrame()
+	if opcode != opClose || len(payload) < 2 {
+		c.t.Fatalf("want a close frame, got opcode %d with %q", opcode, payload)
+	}
+
+	return int(binary.BigEndian.Uint16(payload))
+}
+
+func TestUpgrade(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     http.Header
+		wantStatus int
+		wantErr    error
+	}{
+		{name: "handshake", header: http.Header{}, wantStatus: http.StatusSwitchingProtocols},
+		{name: "same origin", header: http.Header{"Origin": {"http://HOST"}}, wantStatus: http.StatusSwitchingProtocols},
+		{name: "other origin", header: http.Header{"Origin": {"https://evil.example"}}, wantStatus: http.StatusForbidden, wantErr: websocket.ErrBadOrigin},
+		{name: "opaque origin", header: http.Header{"Origin": {"null"}}, wantStatus: http.StatusForbidden, wantErr: websocket.ErrBadOrigin},
+		{name: "no upgrade", header: http.Header{"Upgrade": {"h2c"}}, wantStatus: http.StatusBadRequest, wantErr: websocket.ErrBadHandshake},
+		{name: "old version", header: http.Header{"Sec-Websocket-Version": {"8"}}, wantStatus: http.StatusUpgradeRequired, wantErr: websocket.ErrBadHandshake},
+		{name: "short key", header: http.Header{"Sec-Websocket-Key": {"c2hvcnQ="}}, wantStatus: http.StatusBadRequest, wantErr: websocket.ErrBadHandshake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := make(chan error, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := websocket.Upgrade(w, r)
+				if err == nil {
+					conn.Close(websocket.CloseNormal, "")
+				}
+				errs <- err
+			}))
+			defer server.Close()
+
+			if origin := tt.header.Get("Origin"); origin != "" {
+				tt.header.Set("Origin", strings.Replace(origin, "HOST", server.Listener.Addr().String(), 1))
+			}
+
+			c := dial(t, server, tt.header)
+			resp, err := http.ReadResponse(c.r, nil)
+			if err != nil {
+				t.Fatalf("read handshake response: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("want %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if err := <-errs; !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil {
+				// The accept value of the sample key of RFC 6455 section 1.3.
+				if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+					t.Errorf("want the accept value of the key, got %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	for _, size := range []int{0, 125, 126, 0xffff, 0x10000} {
+		conn, c := newConn(t)
+
+		data := bytes.Repeat([]byte("a"), size)
+		go func() { _ = conn.WriteText(data, time.Now().Add(5*time.Second)) }()
+
+		fin, opcode, payload := c.readFrame()
+		if !fin || opcode != opText || !bytes.Equal(payload, data) {
+			t.Errorf("size %d: want a final text frame with the data, got fin %v, opcode %d, %d bytes", size, fin, opcode, len(payload))
+		}
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames func(c *client)
+		want   string
+	}{
+		{
+			name:   "text",
+			frames: func(c *client) { c.writeFrame(true, opText, []byte("hello"), true) },
+			want:   "hello",
+		},
+		{
+			name:   "extended length",
+			frames: func(c *client) { c.writeFrame(true, opBinary, bytes.Repeat([]byte("b"), 300), true) },
+			want:   strings.Repeat("b", 300),
+		},
+		{
+			name: "fragmented",
+			frames: func(c *client) {
+				c.writeFrame(false, opText, []byte("hel"), true)
+				c.writeFrame(false, opContinuation, []byte("lo "), true)
+				// Control frames may come between the fragments.
+				c.writeFrame(true, opPong, nil, true)
+				c.writeFrame(true, opContinuation, []byte("world"), true)
+			},
+			want: "hello world",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, c := newConn(t)
+
+			tt.frames(c)
+			message, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatalf("read message: %v", err)
+			}
+			if string(message) != tt.want {
+				t.Errorf("want %q, got %q", tt.want, message)
+			}
+		})
+	}
+}
+
+func TestReadMessageAnswersPing(t *testing.T) {
+	conn, c := newConn(t)
+
+	c.writeFrame(true, opPing, []byte("are you there"), true)
+	c.writeFrame(true, opText, []byte("hello"), true)
+
+	messages := make(chan []byte, 1)
+	go func() {
+		message, _ := conn.ReadMessage()
+		messages <- message
+	}()
+
+	fin, opcode, payload := c.readFrame()
+	if !fin || opcode != opPong || string(payload) != "are you there" {
+		t.Errorf("want a pong with the payload of the ping, got opcode %d with %q", opcode, payload)
+	}
+	if message := <-messages; string(message) != "hello" {
+		t.Errorf("want the message after the ping, got %q", message)
+	}
+}
+
+func TestPing(t *testing.T) {
+	conn, c := newConn(t)
+
+	if err := conn.Ping(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	if _, opcode, _ := c.readFrame(); opcode != opPing {
+		t.Errorf("want a ping, got opcode %d", opcode)
+	}
+}
+
+func TestReadMessageRefusesFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		frames   func(c *client)
+		wantCode int
+	}{
+		{
+			name:     "unmasked",
+			frames:   func(c *client) { c.writeFrame(true, opText, []byte("hello"), false) },
+			wantCode: websocket.CloseProtocolError,
+		},
+		{
+			name:     "continuation first",
+			frames:   func(c *client) { c.writeFrame(true, opContinuation, []byte("hello"), true) },
+			wantCode: websocket.CloseProtocolError,
+		},
+		{
+			name: "new message while fragmented",
+			frames: func(c *client) {
+				c.writeFrame(false, opText, []byte("hel"), true)
+				c.writeFrame(true, opText, []byte("lo"), true)
+			},
+			wantCode: websocket.CloseProtocolError,
+		},
+		{
+			name:     "fragmented control frame",
+			frames:   func(c *client) { c.writeFrame(false, opPing, nil, true) },
+			wantCode: websocket.CloseProtocolError,
+		},
+		{
+			name:     "unknown opcode",
+			frames:   func(c *client) { c.writeFrame(true, 0x3, nil, true) },
+			wantCode: websocket.CloseProtocolError,
+		},
+		{
+			name: "too big",
+			frames: func(c *client) {
+				c.writeFrame(false, opText, bytes.Repeat([]byte("a"), websocket.MaxMessageSize), true)
+				c.writeFrame(true, opContinuation, []byte("a"), true)
+			},
+			wantCode: websocket.CloseMessageTooBig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, c := newConn(t)
+
+			go tt.frames(c)
+			if _, err := conn.ReadMessage(); err == nil {
+				t.Fatal("want the frame refused")
+			}
+			if code := c.readClose(); code != tt.wantCode {
+				t.Errorf("want close code %d, got %d", tt.wantCode, code)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Run("by client", func(t *testing.T) {
+		conn, c := newConn(t)
+
+		c.writeFrame(true, opClose, append(binary.BigEndian.AppendUint16(nil, websocket.CloseGoingAway), "bye"...), true)
+
+		var closeErr *websocket.CloseError
+		if _, err := conn.ReadMessage(); !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseGoingAway || closeErr.Reason != "bye" {
+			t.Fatalf("want the close of the client, got %v", err)
+		}
+		if code := c.readClose(); code != websocket.CloseGoingAway {
+			t.Errorf("want the close answered with its code, got %d", code)
+		}
+		if err := conn.WriteText([]byte("late"), time.Now().Add(time.Second)); !errors.Is(err, websocket.ErrClosed) {
+			t.Errorf("write after close: want %v, got %v", websocket.ErrClosed, err)
+		}
+	})
+
+	t.Run("by server", func(t *testing.T) {
+		conn, c := newConn(t)
+
+		if err := conn.Close(websocket.CloseTryAgainLater, "fell behind"); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+
+		_, opcode, payload := c.readFrame()
+		if opcode != opClose || binary.BigEndian.Uint16(payload) != websocket.CloseTryAgainLater || string(payload[2:]) != "fell behind" {
+			t.Errorf("want a close frame with the code and reason, got opcode %d with %q", opcode, payload)
+		}
+		if _, err := c.r.ReadByte(); !errors.Is(err, io.EOF) {
+			t.Errorf("want the connection closed after the close frame, got %v", err)
+		}
+	})
+}
+
+func TestReadTimeout(t *testing.T) {
+	conn, _ := newConn(t)
+	conn.SetReadTimeout(50 * time.Millisecond)
+
+	var netErr net.Error
+	if _, err := conn.ReadMessage(); !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("want a timeout, got %v", err)
+	}
+}
@@ -17,6 +17,8 @@ import (
 
 type UserSaver interface {
 	Register(ctx context.Context, user *models.User) error
+	SetTimezone(ctx context.Context, userID uuid.UUID, timezone string) (*models.User, error)
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
 }
 
 type UserProvider interface {
@@ -45,7 +47,8 @@ func New(userSaver UserSaver, userGetter UserProvider, log *slog.Logger, tokenTT
 	}
 }
 
-func (uc *Service) Register(ctx context.Context, email, password string) (string, error) {
+// Register creates a user. An empty timezone registers the user in UTC.
+func (uc *Service) Register(ctx context.Context, email, password, timezone string) (string, error) {
 	const op = "auth.Register"
 
 	log := uc.logger.With(
@@ -55,9 +58,14 @@ func (uc *Service) Register(ctx context.Context, email, password string) (string
 
 	log.Info("registering user")
 
+	timezone, err := validateTimezone(timezone)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
 	hashedPass, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Error("failed to generate password hash", err)
+		log.Error("failed to generate password hash", slog.String("error", err.Error()))
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -65,6 +73,7 @@ func (uc *Service) Register(ctx context.Context, email, password string) (string
 		ID:           uuid.New(),
 		Email:        email,
 		PasswordHash: string(hashedPass),
+		Timezone:     timezone,
 	}
 
 	err = uc.userSaver.Register(ctx, user)
@@ -84,18 +93,18 @@ func (s *Service) Login(ctx context.Context, email string, pass string) (string,
 	user, err := s.UserProvider.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, my_err.ErrUserNotFound) {
-			s.logger.Warn("user not found", err)
+			s.logger.Warn("user not found", slog.String("error", err.Error()))
 
 			return "", fmt.Errorf("%s, %w", op, ErrInvalidCredentials)
 		}
 
-		s.logger.Error("failed to get user", err)
+		s.logger.Error("failed to get user", slog.String("error", err.Error()))
 
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(pass)); err != nil {
-		s.logger.Info("invalid credentials", err)
+		s.logger.Info("invalid credentials", slog.String("error", err.Error()))
 
 		return "", fmt.Errorf("%s, %w", op, ErrInvalidCredentials)
 	}
@@ -104,10 +113,87 @@ func (s *Service) Login(ctx context.Context, email string, pass string) (string,
 
 	token, err := jwt.NewToken(user, s.tokenSecret, s.tokenTTL)
 	if err != nil {
-		s.logger.Error("failed to generate token", err)
+		s.logger.Error("failed to generate token", slog.String("error", err.Error()))
 
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	return token, err
 }
+
+// UpdateProfile changes the user's timezone and returns a token carrying it.
+func (s *Service) UpdateProfile(ctx context.Context, userID uuid.UUID, timezone string) (string, error) {
+	const op = "auth.UpdateProfile"
+
+	log := s.logger.With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+
+	log.Info("updating profile")
+
+	timezone, err := validateTimezone(timezone)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.userSaver.SetTimezone(ctx, userID, timezone)
+	if err != nil {
+		if !errors.Is(err, my_err.ErrUserNotFound) {
+			log.Error("failed to update timezone", slog.String("error", err.Error()))
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := jwt.NewToken(user, s.tokenSecret, s.tokenTTL)
+	if err != nil {
+		log.Error("failed to generate token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// DeleteAccount deletes the user. The deletion is published as a user.deleted event, on which the
+// task service deletes the data of the user.
+func (s *Service) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
+	const op = "auth.DeleteAccount"
+
+	log := s.logger.With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+
+	log.Info("deleting account")
+
+	if err := s.userSaver.DeleteUser(ctx, userID); err != nil {
+		if !errors.Is(err, my_err.ErrUserNotFound) {
+			log.Error("failed to delete user", slog.String("error", err.Error()))
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// validateTimezone returns the IANA name of the timezone, UTC for an empty one.
+// "Local" is rejected because it means the server's zone, not the user's.
+func validateTimezone(timezone string) (string, error) {
+	if timezone == "" {
+		return "UTC", nil
+	}
+
+	if timezone == "Local" {
+		return "", fmt.Errorf("%w: %q", my_err.ErrInvalidTimezone, timezone)
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", my_err.ErrInvalidTimezone, timezone)
+	}
+
+	return loc.String(), nil
+}
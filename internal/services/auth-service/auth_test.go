@@ -0,0 +1,133 @@
+package auth_service_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/lib/jwt"
+	auth_service "github.com/SlashLight/todo-list/internal/services/auth-service"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const secret = "my_very_secret_key"
+
+func newService() *auth_service.Service {
+	storage := memory.New()
+
+	return auth_service.New(storage, storage, slog.New(slog.DiscardHandler), time.Hour, secret)
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	ctx := t.Context()
+	service := newService()
+
+	userID, err := service.Register(ctx, "ann@example.com", "password", "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	token, err := service.Login(ctx, "ann@example.com", "password")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	session, err := jwt.ParseToken(token, secret)
+	if err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+	if session.UserID.String() != userID || session.Email != "ann@example.com" || session.Timezone != "Europe/Berlin" {
+		t.Errorf("session: got %+v, want user %s in Europe/Berlin", session, userID)
+	}
+
+	if _, err := service.Login(ctx, "ann@example.com", "wrong"); !errors.Is(err, auth_service.ErrInvalidCredentials) {
+		t.Errorf("login with wrong password: want %v, got %v", auth_service.ErrInvalidCredentials, err)
+	}
+	if _, err := service.Login(ctx, "bob@example.com", "password"); !errors.Is(err, auth_service.ErrInvalidCredentials) {
+		t.Errorf("login of unknown user: want %v, got %v", auth_service.ErrInvalidCredentials, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	ctx := t.Context()
+	service := newService()
+
+	if _, err := service.Register(ctx, "ann@example.com", "password", ""); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		timezone string
+		want     error
+	}{
+		{"taken email", "ann@example.com", "UTC", my_err.ErrUserExists},
+		{"unknown timezone", "bob@example.com", "Mars/Olympus", my_err.ErrInvalidTimezone},
+		{"server timezone", "bob@example.com", "Local", my_err.ErrInvalidTimezone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Register(ctx, tt.email, "password", tt.timezone); !errors.Is(err, tt.want) {
+				t.Errorf("want %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	ctx := t.Context()
+	service := newService()
+
+	userID, err := service.Register(ctx, "ann@example.com", "password", "")
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	token, err := service.UpdateProfile(ctx, uuid.MustParse(userID), "America/New_York")
+	if err != nil {
+		t.Fatalf("update profile: %v", err)
+	}
+
+	session, err := jwt.ParseToken(token, secret)
+	if err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+	if session.Timezone != "America/New_York" {
+		t.Errorf("timezone: want America/New_York, got %q", session.Timezone)
+	}
+
+	if _, err := service.UpdateProfile(ctx, uuid.New(), "UTC"); !errors.Is(err, my_err.ErrUserNotFound) {
+		t.Errorf("update unknown user: want %v, got %v", my_err.ErrUserNotFound, err)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	ctx := t.Context()
+	service := newService()
+
+	userID, err := service.Register(ctx, "ann@example.com", "password", "")
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if err := service.DeleteAccount(ctx, uuid.MustParse(userID)); err != nil {
+		t.Fatalf("delete account: %v", err)
+	}
+
+	if _, err := service.Login(ctx, "ann@example.com", "password"); !errors.Is(err, auth_service.ErrInvalidCredentials) {
+		t.Errorf("login of deleted user: want %v, got %v", auth_service.ErrInvalidCredentials, err)
+	}
+	if err := service.DeleteAccount(ctx, uuid.MustParse(userID)); !errors.Is(err, my_err.ErrUserNotFound) {
+		t.Errorf("delete deleted account: want %v, got %v", my_err.ErrUserNotFound, err)
+	}
+
+	// The email is free again.
+	if _, err := service.Register(ctx, "ann@example.com", "password", ""); err != nil {
+		t.Errorf("register again: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+// Package outbox_relay publishes the domain events recorded in the outbox.
+//
+// Events are recorded in the transaction of the change that raised them and published at least
+// once, in order for every aggregate: an event failing to publish holds back the later events of
+// its aggregate until it is published, while the events of other aggregates go on. Consumers tell
+// a redelivered event by its sequence number.
+package outbox_relay
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+const (
+	// pruneInterval is how often the dispatched events past the retention are deleted.
+	pruneInterval = time.Hour
+	// maxFailuresInRow ends a pass, as the publisher is likely down rather than refusing some events.
+	maxFailuresInRow = 3
+)
+
+type OutboxProvider interface {
+	GetPendingOutboxEvents(ctx context.Context, afterSeq int64, limit int) ([]models.OutboxEvent, error)
+	MarkOutboxEventDispatched(ctx context.Context, seq int64, at time.Time) error
+	PruneOutbox(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventPublisher hands an event to its consumers. It is called with one event at a time and a nil
+// error means the event was delivered and is not published again.
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+type Relay struct {
+	provider  OutboxProvider
+	publisher EventPublisher
+	batchSize int
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+// New returns a relay reading batchSize events at a time and keeping dispatched events for retention.
+func New(provider OutboxProvider, publisher EventPublisher, batchSize int, retention time.Duration, log *slog.Logger) *Relay {
+	return &Relay{
+		provider:  provider,
+		publisher: publisher,
+		batchSize: max(batchSize, 1),
+		retention: retention,
+		logger:    log,
+	}
+}
+
+type aggregateKey struct {
+	aggregateType string
+	aggregateID   uuid.UUID
+}
+
+// Dispatch publishes the pending events in the order they were recorded and marks them dispatched.
+// It returns how many events it published. The events held back by an event failing to publish are
+// retried by the next pass, which also happens to the rest after failures in a row.
+func (r *Relay) Dispatch(ctx context.Context) (int, error) {
+	const op = "outbox.Dispatch"
+
+	log := r.logger.With(slog.String("op", op))
+
+	var (
+		published     int
+		afterSeq      int64
+		failuresInRow int
+	)
+	// failed holds the aggregates with an event that failed to publish in this pass.
+	failed := make(map[aggregateKey]struct{})
+	for {
+		events, err := r.provider.GetPendingOutboxEvents(ctx, afterSeq, r.batchSize)
+		if err != nil {
+			log.Error("failed to get pending events", slog.String("error", err.Error()))
+			return published, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, event := range events {
+			key := aggregateKey{aggregateType: event.AggregateType, aggregateID: event.AggregateID}
+			if _, ok := failed[key]; ok {
+				continue
+			}
+
+			if err := r.publisher.Publish(ctx, event); err != nil {
+				log.Warn("failed to publish event",
+					slog.Int64("seq", event.Seq),
+					slog.String("type", event.Type),
+					slog.String("error", err.Error()))
+				failed[key] = struct{}{}
+
+				if failuresInRow++; failuresInRow == maxFailuresInRow {
+					return published, nil
+				}
+				continue
+			}
+			failuresInRow = 0
+
+			// An event published but not marked is published again, which at least once allows.
+			if err := r.provider.MarkOutboxEventDispatched(ctx, event.Seq, time.Now()); err != nil {
+				log.Error("failed to mark event dispatched", slog.Int64("seq", event.Seq), slog.String("error", err.Error()))
+				return published, fmt.Errorf("%s: %w", op, err)
+			}
+			published++
+		}
+
+		if len(events) < r.batchSize {
+			return published, nil
+		}
+		afterSeq = events[len(events)-1].Seq
+	}
+}
+
+// Prune deletes the events dispatched longer than the retention ago.
+func (r *Relay) Prune(ctx context.Context) error {
+	const op = "outbox.Prune"
+
+	log := r.logger.With(slog.String("op", op))
+
+	pruned, err := r.provider.PruneOutbox(ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		log.Error("failed to prune outbox", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pruned > 0 {
+		log.Info("pruned outbox", slog.Int64("events", pruned))
+	}
+
+	return nil
+}
+
+// Run calls Dispatch every interval and Prune every hour until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = r.Dispatch(ctx)
+		case <-pruneTicker.C:
+			_ = r.Prune(ctx)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package outbox_relay_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	outbox_relay "github.com/SlashLight/todo-list/internal/services/outbox-relay"
+)
+
+// outbox keeps the events in seq order, the way the storages do.
+type outbox struct {
+	events     []models.OutboxEvent
+	dispatched map[int64]time.Time
+	markErr    error
+}
+
+func newOutbox(events ...models.OutboxEvent) *outbox {
+	return &outbox{events: events, dispatched: make(map[int64]time.Time)}
+}
+
+func (o *outbox) GetPendingOutboxEvents(ctx context.Context, afterSeq int64, limit int) ([]models.OutboxEvent, error) {
+	var pending []models.OutboxEvent
+	for _, event := range o.events {
+		if _, ok := o.dispatched[event.Seq]; !ok && event.Seq > afterSeq && len(pending) < limit {
+			pending = append(pending, event)
+		}
+	}
+
+	return pending, nil
+}
+
+func (o *outbox) MarkOutboxEventDispatched(ctx context.Context, seq int64, at time.Time) error {
+	if o.markErr != nil {
+		return o.markErr
+	}
+	o.dispatched[seq] = at
+
+	return nil
+}
+
+func (o *outbox) PruneOutbox(ctx context.Context, before time.Time) (int64, error) {
+	var pruned int64
+	for seq, at := range o.dispatched {
+		if at.Before(before) {
+			o.events = slices.DeleteFunc(o.events, func(event models.OutboxEvent) bool { return event.Seq == seq })
+			delete(o.dispatched, seq)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
+
+// publisher records the seqs it published and refuses the events of the failing aggregates.
+type publisher struct {
+	published []int64
+	failing   map[uuid.UUID]bool
+	attempts  int
+}
+
+func (p *publisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	p.attempts++
+	if p.failing[event.AggregateID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.Seq)
+
+	return nil
+}
+
+func newEvent(seq int64, aggregateID uuid.UUID) models.OutboxEvent {
+	return models.OutboxEvent{
+		Seq:           seq,
+		AggregateType: "task",
+		AggregateID:   aggregateID,
+		Type:          models.EventTaskUpdated,
+		OccurredAt:    time.Now(),
+	}
+}
+
+func newRelay(o *outbox, p *publisher, batchSize int) *outbox_relay.Relay {
+	return outbox_relay.New(o, p, batchSize, time.Hour, slog.New(slog.DiscardHandler))
+}
+
+func TestDispatch(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	o := newOutbox(newEvent(1, first), newEvent(2, second), newEvent(4, first), newEvent(5, second), newEvent(9, first))
+	p := &publisher{}
+
+	// A batch smaller than the outbox takes several pages.
+	published, err := newRelay(o, p, 2).Dispatch(t.Context())
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if published != 5 {
+		t.Errorf("want 5 events published, got %d", published)
+	}
+	if want := []int64{1, 2, 4, 5, 9}; !slices.Equal(p.published, want) {
+		t.Errorf("want events published in seq order %v, got %v", want, p.published)
+	}
+	for _, event := range o.events {
+		if _, ok := o.dispatched[event.Seq]; !ok {
+			t.Errorf("want event %d marked dispatched", event.Seq)
+		}
+	}
+
+	published, err = newRelay(o, p, 2).Dispatch(t.Context())
+	if err != nil {
+		t.Fatalf("dispatch again: %v", err)
+	}
+	if published != 0 || len(p.published) != 5 {
+		t.Errorf("want dispatched events not published again, got %d more", published)
+	}
+}
+
+func TestDispatchHoldsBackAggregateOfFailedEvent(t *testing.T) {
+	failing, other := uuid.New(), uuid.New()
+	o := newOutbox(newEvent(1, failing), newEvent(2, other), newEvent(3, failing), newEvent(4, other))
+	p := &publisher{failing: map[uuid.UUID]bool{failing: true}}
+	relay := newRelay(o, p, 10)
+
+	published, err := relay.Dispatch(t.Context())
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if published != 2 || !slices.Equal(p.published, []int64{2, 4}) {
+		t.Errorf("want the events of the other aggregate published, got %v", p.published)
+	}
+	if p.attempts != 3 {
+		t.Errorf("want event 3 held back without an attempt, got %d attempts", p.attempts)
+	}
+	for _, seq := range []int64{1, 3} {
+		if _, ok := o.dispatched[seq]; ok {
+			t.Errorf("want event %d left pending", seq)
+		}
+	}
+
+	p.failing = nil
+	if _, err := relay.Dispatch(t.Context()); err != nil {
+		t.Fatalf("dispatch again: %v", err)
+	}
+	if want := []int64{2, 4, 1, 3}; !slices.Equal(p.published, want) {
+		t.Errorf("want the held back events published in order, got %v", p.published)
+	}
+}
+
+func TestDispatchEndsPassAfterFailuresInRow(t *testing.T) {
+	var events []models.OutboxEvent
+	failing := make(map[uuid.UUID]bool)
+	for seq := range int64(5) {
+		event := newEvent(seq+1, uuid.New())
+		failing[event.AggregateID] = true
+		events = append(events, event)
+	}
+	p := &publisher{failing: failing}
+
+	published, err := newRelay(newOutbox(events...), p, 10).Dispatch(t.Context())
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if published != 0 || p.attempts != 3 {
+		t.Errorf("want the pass ended after 3 failures in a row, got %d attempts", p.attempts)
+	}
+}
+
+func TestDispatchFailsToMark(t *testing.T) {
+	o := newOutbox(newEvent(1, uuid.New()), newEvent(2, uuid.New()))
+	o.markErr = errors.New("database is locked")
+	p := &publisher{}
+
+	published, err := newRelay(o, p, 10).Dispatch(t.Context())
+	if !errors.Is(err, o.markErr) {
+		t.Fatalf("want %v, got %v", o.markErr, err)
+	}
+	// The event is published again by the next pass, which at least once allows.
+	if published != 0 || !slices.Equal(p.published, []int64{1}) {
+		t.Errorf("want the pass ended at the event it failed to mark, got %v", p.published)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	o := newOutbox(newEvent(1, uuid.New()), newEvent(2, uuid.New()))
+	o.dispatched[1] = time.Now().Add(-2 * time.Hour)
+	o.dispatched[2] = time.Now()
+
+	if err := newRelay(o, &publisher{}, 10).Prune(t.Context()); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if len(o.events) != 1 || o.events[0].Seq != 2 {
+		t.Errorf("want the events dispatched past the retention pruned, got %v", o.events)
+	}
+}
@@ -0,0 +1,62 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// AccountProvider keeps the references to the users of the auth service, which the data of every
+// user hangs off.
+type AccountProvider interface {
+	SaveUserReference(ctx context.Context, userID uuid.UUID) error
+	DeleteUserData(ctx context.Context, userID uuid.UUID) ([]*models.Attachment, error)
+}
+
+// ApplyUserEvent follows a change to a user of the auth service: a created user gets a reference
+// their data can be saved under, a deleted one loses it with all their data and is refused from then
+// on. The auth service publishes its events at least once, so applying an event again changes
+// nothing. Other events are ignored.
+func (ts *Service) ApplyUserEvent(ctx context.Context, eventType string, userID uuid.UUID) error {
+	const op = "task.ApplyUserEvent"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("type", eventType),
+		slog.String("user_id", userID.String()),
+	)
+
+	switch eventType {
+	case models.EventUserCreated:
+		log.Info("saving user reference")
+
+		err := ts.AccountProvider.SaveUserReference(ctx, userID)
+		if errors.Is(err, my_err.ErrUserDeleted) {
+			// A user.created delivered again after the user.deleted.
+			log.Info("user was deleted")
+			return nil
+		}
+		if err != nil {
+			log.Error("failed to save user reference", slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	case models.EventUserDeleted:
+		log.Info("deleting user data")
+
+		attachments, err := ts.AccountProvider.DeleteUserData(ctx, userID)
+		if err != nil {
+			log.Error("failed to delete user data", slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		ts.removeBlobs(ctx, attachments)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package task_service_test
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func TestAddAttachmentQuota(t *testing.T) {
+	ctx := t.Context()
+	service, root := newService(t, task_service.Settings{AttachmentQuota: 10})
+	author := uuid.New()
+	taskID := createTask(t, service, author)
+
+	first, err := service.AddAttachment(ctx, taskID, author, "a.txt", "text/plain", strings.NewReader("123456"))
+	if err != nil {
+		t.Fatalf("add attachment: %v", err)
+	}
+
+	// The upload is cut off as soon as it goes past the quota, and its blob removed.
+	if _, err := service.AddAttachment(ctx, taskID, author, "b.txt", "text/plain", strings.NewReader("123456")); !errors.Is(err, my_err.ErrQuotaExceeded) {
+		t.Fatalf("add attachment past quota: want %v, got %v", my_err.ErrQuotaExceeded, err)
+	}
+	if want, got := []string{first.StorageKey}, blobKeys(t, root); !slices.Equal(got, want) {
+		t.Errorf("blobs after rejected upload: want %v, got %v", want, got)
+	}
+
+	if _, err := service.AddAttachment(ctx, taskID, author, "c.txt", "text/plain", strings.NewReader("1234")); err != nil {
+		t.Fatalf("add attachment up to quota: %v", err)
+	}
+	if _, err := service.AddAttachment(ctx, taskID, author, "d.txt", "text/plain", strings.NewReader("")); !errors.Is(err, my_err.ErrQuotaExceeded) {
+		t.Errorf("add attachment with quota used up: want %v, got %v", my_err.ErrQuotaExceeded, err)
+	}
+
+	// Quotas are per user.
+	other := uuid.New()
+	if _, err := service.AddAttachment(ctx, createTask(t, service, other), other, "a.txt", "text/plain", strings.NewReader("123456")); err != nil {
+		t.Errorf("add attachment of other user: %v", err)
+	}
+}
+
+func TestAttachmentBlobsRemoved(t *testing.T) {
+	ctx := t.Context()
+	service, root := newService(t, task_service.Settings{AttachmentQuota: 1 << 20})
+	author, other := uuid.New(), uuid.New()
+
+	add := func(taskID, author uuid.UUID) *models.Attachment {
+		t.Helper()
+
+		attachment, err := service.AddAttachment(ctx, taskID, author, "scan.pdf", "application/pdf", strings.NewReader("%PDF"))
+		if err != nil {
+			t.Fatalf("add attachment: %v", err)
+		}
+
+		return attachment
+	}
+
+	first, second := createTask(t, service, author), createTask(t, service, author)
+	deleted := add(first, author)
+	taskAttachment := add(first, author)
+	userAttachment := add(second, author)
+	kept := add(createTask(t, service, other), other)
+
+	if err := service.DeleteAttachment(ctx, deleted.ID, author); err != nil {
+		t.Fatalf("delete attachment: %v", err)
+	}
+	if want, got := sortedKeys(taskAttachment, userAttachment, kept), blobKeys(t, root); !slices.Equal(got, want) {
+		t.Errorf("blobs after deleting an attachment: want %v, got %v", want, got)
+	}
+
+	if err := service.DeleteTask(ctx, first, author); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	if want, got := sortedKeys(userAttachment, kept), blobKeys(t, root); !slices.Equal(got, want) {
+		t.Errorf("blobs after deleting a task: want %v, got %v", want, got)
+	}
+
+	if err := service.ApplyUserEvent(ctx, models.EventUserDeleted, author); err != nil {
+		t.Fatalf("apply user.deleted: %v", err)
+	}
+	if want, got := sortedKeys(kept), blobKeys(t, root); !slices.Equal(got, want) {
+		t.Errorf("blobs after deleting a user: want %v, got %v", want, got)
+	}
+}
+
+func createTask(t *testing.T, service *task_service.Service, author uuid.UUID) uuid.UUID {
+	t.Helper()
+
+	id, err := service.CreateTask(t.Context(), author, uuid.NullUUID{}, "Scan", "", "", time.Time{}, "")
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	return uuid.MustParse(id)
+}
+
+// blobKeys lists the keys of the blobs under root in order.
+func blobKeys(t *testing.T, root string) []string {
+	t.Helper()
+
+	var keys []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		key, err := filepath.Rel(root, path)
+		keys = append(keys, filepath.ToSlash(key))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func sortedKeys(attachments ...*models.Attachment) []string {
+	var keys []string
+	for _, attachment := range attachments {
+		keys = append(keys, attachment.StorageKey)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,415 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/rank"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const maxBatchSize = 500
+
+type BatchProvider interface {
+	BatchCreateTasks(ctx context.Context, tasks []*models.Task, atomic bool) ([]error, error)
+	BatchUpdateTasks(ctx context.Context, author uuid.UUID, taskIDs []uuid.UUID, positions []string, patch models.TaskPatch, atomic bool) ([]error, error)
+	BatchDeleteTasks(ctx context.Context, author uuid.UUID, taskIDs []uuid.UUID, atomic bool) ([]error, error)
+}
+
+// BatchCreateTasks creates the tasks in one transaction, each at the bottom of its to-do column.
+// Only title, description, project, deadline, due date, priority and tags are taken from the tasks.
+//
+// With atomic set, the first invalid or failing task aborts the batch and its error is returned.
+// Otherwise every valid task is created and the results report the others one by one.
+func (ts *Service) BatchCreateTasks(ctx context.Context, authorID uuid.UUID, tasks []*models.Task, atomic bool) ([]models.BatchResult, error) {
+	const op = "task.BatchCreateTasks"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.Int("tasks", len(tasks)),
+	)
+
+	log.Info("creating tasks")
+
+	if len(tasks) == 0 || len(tasks) > maxBatchSize {
+		return nil, fmt.Errorf("%s: %w: a batch holds 1 to %d tasks", op, my_err.ErrInvalidBatch, maxBatchSize)
+	}
+
+	results := make([]models.BatchResult, len(tasks))
+	projects := make(map[uuid.UUID]error)
+	groups := make(map[models.RankGroup][]int)
+	var (
+		valid   []*models.Task
+		indexes []int
+	)
+
+	for idx, input := range tasks {
+		task := &models.Task{
+			ID:          uuid.New(),
+			AuthorID:    authorID,
+			ProjectID:   input.ProjectID,
+			Title:       strings.TrimSpace(input.Title),
+			Description: input.Description,
+			Status:      models.StatusToDo,
+			Deadline:    input.Deadline.UTC(),
+			DueDate:     input.DueDate,
+			Priority:    input.Priority,
+		}
+		err := ts.validateBatchTask(ctx, task, input.Tags, projects)
+		if err != nil {
+			if atomic {
+				return nil, fmt.Errorf("%s: item %d: %w", op, idx, err)
+			}
+			results[idx].Err = err
+			continue
+		}
+
+		group := models.RankGroup{AuthorID: authorID, ProjectID: task.ProjectID, Status: task.Status}
+		groups[group] = append(groups[group], len(valid))
+		valid = append(valid, task)
+		indexes = append(indexes, idx)
+	}
+
+	if err := ts.appendPositions(ctx, groups, func(i int, position string) { valid[i].Position = position }); err != nil {
+		log.Error("failed to compute task positions", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	errs, err := ts.BatchProvider.BatchCreateTasks(ctx, valid, atomic)
+	if err != nil {
+		if !errors.Is(err, my_err.ErrEmptyTitle) {
+			log.Error("failed to create tasks", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mergeBatchErrors(results, indexes, errs)
+	created := make([]uuid.UUID, 0, len(valid))
+	for i, idx := range indexes {
+		if results[idx].Err == nil {
+			results[idx].TaskID = valid[i].ID
+			created = append(created, valid[i].ID)
+		}
+	}
+	ts.publishTasks(ctx, models.TaskEventCreated, authorID, created...)
+
+	return results, nil
+}
+
+// validateBatchTask checks a task of a batch, projects caches the lookups of its project.
+func (ts *Service) validateBatchTask(ctx context.Context, task *models.Task, tags []string, projects map[uuid.UUID]error) error {
+	if task.Title == "" {
+		return my_err.ErrEmptyTitle
+	}
+
+	if err := validateDue(task.Deadline, task.DueDate); err != nil {
+		return err
+	}
+
+	if task.Priority == "" {
+		task.Priority = models.PriorityNone
+	}
+	if !validPriority(task.Priority) {
+		return fmt.Errorf("%w: unknown priority %q", my_err.ErrInvalidBatch, task.Priority)
+	}
+
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return fmt.Errorf("%w: %s", my_err.ErrInvalidBatch, err)
+	}
+	task.Tags = normalized
+
+	if task.ProjectID.Valid {
+		err, ok := projects[task.ProjectID.UUID]
+		if !ok {
+			err = ts.ProjectProvider.ProjectExists(ctx, task.ProjectID.UUID, task.AuthorID)
+			projects[task.ProjectID.UUID] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BatchUpdateTasks applies patch to the selected tasks in one transaction. Tasks whose status
+// changes move to the bottom of their new column. See BatchCreateTasks for atomic.
+func (ts *Service) BatchUpdateTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector, patch models.TaskPatch, atomic bool) ([]models.BatchResult, error) {
+	const op = "task.BatchUpdateTasks"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("updating tasks")
+
+	patch, err := ts.validatePatch(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ids, tasks, err := ts.selectTasks(ctx, authorID, selector)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	results := make([]models.BatchResult, len(ids))
+	groups := make(map[models.RankGroup][]int)
+	var (
+		validIDs []uuid.UUID
+		indexes  []int
+	)
+
+	for idx, id := range ids {
+		results[idx].TaskID = id
+
+		task, ok := tasks[id]
+		if !ok {
+			if atomic {
+				return nil, fmt.Errorf("%s: item %d: %w", op, idx, my_err.ErrTaskNotFound)
+			}
+			results[idx].Err = my_err.ErrTaskNotFound
+			continue
+		}
+
+		if patch.Status != nil && *patch.Status != task.Status {
+			group := models.RankGroup{AuthorID: authorID, ProjectID: task.ProjectID, Status: *patch.Status}
+			groups[group] = append(groups[group], len(validIDs))
+		}
+
+		validIDs = append(validIDs, id)
+		indexes = append(indexes, idx)
+	}
+
+	// Tasks that keep their status keep their position too.
+	positions := make([]string, len(validIDs))
+	if err := ts.appendPositions(ctx, groups, func(i int, position string) { positions[i] = position }); err != nil {
+		log.Error("failed to compute task positions", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	errs, err := ts.BatchProvider.BatchUpdateTasks(ctx, authorID, validIDs, positions, patch, atomic)
+	if err != nil {
+		if !errors.Is(err, my_err.ErrTaskNotFound) {
+			log.Error("failed to update tasks", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mergeBatchErrors(results, indexes, errs)
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, succeededIDs(validIDs, errs)...)
+
+	return results, nil
+}
+
+// validatePatch checks the patch and returns it with normalised tags.
+func (ts *Service) validatePatch(patch models.TaskPatch) (models.TaskPatch, error) {
+	if patch.Status == nil && patch.Priority == nil && !patch.SetDue && len(patch.AddTags) == 0 && len(patch.RemoveTags) == 0 {
+		return patch, fmt.Errorf("%w: the patch changes nothing", my_err.ErrInvalidBatch)
+	}
+
+	if patch.Status != nil {
+		if _, ok := ts.boardColumn(*patch.Status); !ok {
+			return patch, fmt.Errorf("%w: %q", my_err.ErrInvalidStatus, *patch.Status)
+		}
+	}
+
+	if patch.Priority != nil && !validPriority(*patch.Priority) {
+		return patch, fmt.Errorf("%w: unknown priority %q", my_err.ErrInvalidBatch, *patch.Priority)
+	}
+
+	if patch.SetDue {
+		patch.Deadline = patch.Deadline.UTC()
+		if err := validateDue(patch.Deadline, patch.DueDate); err != nil {
+			return patch, err
+		}
+	}
+
+	var err error
+	if patch.AddTags, err = normalizeTags(patch.AddTags); err != nil {
+		return patch, fmt.Errorf("%w: %s", my_err.ErrInvalidBatch, err)
+	}
+	if patch.RemoveTags, err = normalizeTags(patch.RemoveTags); err != nil {
+		return patch, fmt.Errorf("%w: %s", my_err.ErrInvalidBatch, err)
+	}
+
+	return patch, nil
+}
+
+// BatchDeleteTasks deletes the selected tasks in one transaction, see BatchCreateTasks for atomic.
+// The attachments of deleted tasks are removed from the blob store once the transaction commits.
+func (ts *Service) BatchDeleteTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector, atomic bool) ([]models.BatchResult, error) {
+	const op = "task.BatchDeleteTasks"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("deleting tasks")
+
+	ids, tasks, err := ts.selectTasks(ctx, authorID, selector)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	results := make([]models.BatchResult, len(ids))
+	attachments := make(map[uuid.UUID][]*models.Attachment)
+	var (
+		validIDs []uuid.UUID
+		indexes  []int
+	)
+
+	for idx, id := range ids {
+		results[idx].TaskID = id
+
+		if _, ok := tasks[id]; !ok {
+			if atomic {
+				return nil, fmt.Errorf("%s: item %d: %w", op, idx, my_err.ErrTaskNotFound)
+			}
+			results[idx].Err = my_err.ErrTaskNotFound
+			continue
+		}
+
+		// Remember where the blobs live before the cascade removes their metadata.
+		if attachments[id], err = ts.AttachmentProvider.GetAttachments(ctx, id, authorID); err != nil {
+			log.Error("failed to get task attachments", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		validIDs = append(validIDs, id)
+		indexes = append(indexes, idx)
+	}
+
+	errs, err := ts.BatchProvider.BatchDeleteTasks(ctx, authorID, validIDs, atomic)
+	if err != nil {
+		if !errors.Is(err, my_err.ErrTaskNotFound) {
+			log.Error("failed to delete tasks", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i, id := range validIDs {
+		if errs[i] == nil {
+			ts.removeBlobs(ctx, attachments[id])
+		}
+	}
+
+	mergeBatchErrors(results, indexes, errs)
+	ts.publishTasks(ctx, models.TaskEventDeleted, authorID, succeededIDs(validIDs, errs)...)
+
+	return results, nil
+}
+
+// selectTasks resolves a selector into task IDs in request order, along with the author's tasks by ID.
+// IDs that are not among the tasks belong to someone else or do not exist.
+func (ts *Service) selectTasks(ctx context.Context, authorID uuid.UUID, selector models.TaskSelector) ([]uuid.UUID, map[uuid.UUID]*models.Task, error) {
+	if (len(selector.IDs) > 0) == (selector.Filter != "") {
+		return nil, nil, fmt.Errorf("%w: select tasks either by ID or with a filter", my_err.ErrInvalidBatch)
+	}
+
+	if len(selector.IDs) > maxBatchSize {
+		return nil, nil, fmt.Errorf("%w: a batch holds at most %d tasks", my_err.ErrInvalidBatch, maxBatchSize)
+	}
+
+	var (
+		query models.ViewQuery
+		loc   *time.Location
+		err   error
+	)
+	if selector.Filter != "" {
+		if query, err = ParseViewQuery(selector.Filter); err != nil {
+			return nil, nil, err
+		}
+		if loc, err = time.LoadLocation(selector.Timezone); err != nil {
+			return nil, nil, fmt.Errorf("%w: %q", my_err.ErrInvalidTimezone, selector.Timezone)
+		}
+	}
+
+	tasks, err := ts.TaskProvider.GetTask(ctx, authorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	if selector.Filter == "" {
+		return selector.IDs, byID, nil
+	}
+
+	matched := filterTasks(tasks, query, time.Now().In(loc))
+	if len(matched) > maxBatchSize {
+		return nil, nil, fmt.Errorf("%w: the filter matches %d tasks, a batch holds at most %d", my_err.ErrInvalidBatch, len(matched), maxBatchSize)
+	}
+
+	ids := make([]uuid.UUID, len(matched))
+	for idx, task := range matched {
+		ids[idx] = task.ID
+	}
+
+	return ids, byID, nil
+}
+
+// appendPositions hands out positions at the bottom of each rank group. The groups hold
+// item indexes in the order their items should end up in.
+func (ts *Service) appendPositions(ctx context.Context, groups map[models.RankGroup][]int, set func(i int, position string)) error {
+	for group, items := range groups {
+		last, err := ts.TaskProvider.LastTaskPosition(ctx, group)
+		if err != nil {
+			return err
+		}
+
+		positions, err := rank.After(last, len(items))
+		if err != nil {
+			return err
+		}
+
+		for idx, i := range items {
+			set(i, positions[idx])
+		}
+	}
+
+	return nil
+}
+
+// mergeBatchErrors copies the per-item errors of the storage, which only saw the valid items,
+// onto the results of the whole batch. indexes maps a storage item to its result.
+func mergeBatchErrors(results []models.BatchResult, indexes []int, errs []error) {
+	for i, err := range errs {
+		results[indexes[i]].Err = err
+	}
+}
+
+// succeededIDs returns the IDs whose item of a batch did not fail.
+func succeededIDs(ids []uuid.UUID, errs []error) []uuid.UUID {
+	succeeded := make([]uuid.UUID, 0, len(ids))
+	for i, id := range ids {
+		if errs[i] == nil {
+			succeeded = append(succeeded, id)
+		}
+	}
+
+	return succeeded
+}
+
+func validPriority(priority string) bool {
+	switch priority {
+	case models.PriorityNone, models.PriorityLow, models.PriorityMedium, models.PriorityHigh:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,188 @@
+package task_service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	defaultBoardLimit = 50
+	maxBoardLimit     = 200
+)
+
+var defaultBoardColumns = []models.BoardColumn{
+	{Status: models.StatusToDo, Title: "To do"},
+	{Status: models.StatusInProgress, Title: "In progress"},
+	{Status: models.StatusDone, Title: "Done"},
+}
+
+// ValidateBoardColumns checks that the configured columns use distinct statuses the task schema knows about.
+func ValidateBoardColumns(columns []models.BoardColumn) error {
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		switch column.Status {
+		case models.StatusToDo, models.StatusInProgress, models.StatusDone:
+		default:
+			return fmt.Errorf("%w: %q", my_err.ErrInvalidStatus, column.Status)
+		}
+
+		if seen[column.Status] {
+			return fmt.Errorf("duplicate board column %q", column.Status)
+		}
+		seen[column.Status] = true
+
+		if column.WIPLimit < 0 {
+			return fmt.Errorf("board column %q: wip limit must not be negative", column.Status)
+		}
+	}
+
+	return nil
+}
+
+// GetBoard returns the kanban board of a project, an invalid projectID selects the inbox.
+// Every column holds the first limit tasks in position order. When status is set only that
+// column is returned, starting after pageToken. WIP limits are advisory: a column over its
+// limit is flagged, moves into it are not refused.
+func (ts *Service) GetBoard(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, status string, limit int, pageToken string) (*models.Board, error) {
+	const op = "task.GetBoard"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("getting board")
+
+	if limit <= 0 {
+		limit = defaultBoardLimit
+	}
+	limit = min(limit, maxBoardLimit)
+
+	columns := ts.boardColumns
+	if status != "" {
+		column, ok := ts.boardColumn(status)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidStatus, status)
+		}
+		columns = []models.BoardColumn{column}
+	}
+
+	var after models.BoardCursor
+	if pageToken != "" {
+		if status == "" {
+			return nil, fmt.Errorf("%s: %w: status is required with a page token", op, my_err.ErrInvalidCursor)
+		}
+
+		var err error
+		if after, err = decodeBoardCursor(pageToken); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if projectID.Valid {
+		if err := ts.ProjectProvider.ProjectExists(ctx, projectID.UUID, authorID); err != nil {
+			if !errors.Is(err, my_err.ErrProjectNotFound) {
+				log.Error("failed to check project", slog.String("error", err.Error()))
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	counts, err := ts.ProjectProvider.CountBoardTasks(ctx, authorID, projectID)
+	if err != nil {
+		log.Error("failed to count board tasks", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	efforts, err := ts.effortByProject(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get board effort", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	columnEffort := make(map[string]models.StatusEffort)
+	for _, effort := range efforts[projectID] {
+		columnEffort[effort.Status] = effort
+	}
+
+	board := &models.Board{ProjectID: projectID, Columns: make([]*models.BoardColumnPage, 0, len(columns))}
+	for _, column := range columns {
+		group := models.RankGroup{AuthorID: authorID, ProjectID: projectID, Status: column.Status}
+
+		// One extra task tells whether there is a next page.
+		tasks, err := ts.ProjectProvider.GetColumnTasks(ctx, group, after, limit+1)
+		if err != nil {
+			log.Error("failed to get column tasks", slog.String("status", column.Status), slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		page := &models.BoardColumnPage{
+			Status:       column.Status,
+			Title:        column.Title,
+			WIPLimit:     column.WIPLimit,
+			Count:        counts[column.Status],
+			OverWIPLimit: column.WIPLimit > 0 && counts[column.Status] > column.WIPLimit,
+
+			EstimateMinutes:  columnEffort[column.Status].EstimateMinutes,
+			RemainingMinutes: columnEffort[column.Status].RemainingMinutes,
+		}
+
+		if len(tasks) > limit {
+			tasks = tasks[:limit]
+			last := tasks[len(tasks)-1]
+			page.NextPageToken = encodeBoardCursor(models.BoardCursor{Position: last.Position, TaskID: last.ID})
+		}
+
+		for _, task := range tasks {
+			summarizeTask(task)
+		}
+		page.Tasks = tasks
+
+		board.Columns = append(board.Columns, page)
+	}
+
+	return board, nil
+}
+
+func (ts *Service) boardColumn(status string) (models.BoardColumn, bool) {
+	for _, column := range ts.boardColumns {
+		if column.Status == status {
+			return column, true
+		}
+	}
+
+	return models.BoardColumn{}, false
+}
+
+// Page tokens are opaque to clients, they carry the position and ID of the last task of the previous page.
+func encodeBoardCursor(cursor models.BoardCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor.Position + ":" + cursor.TaskID.String()))
+}
+
+func decodeBoardCursor(token string) (models.BoardCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return models.BoardCursor{}, my_err.ErrInvalidCursor
+	}
+
+	position, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return models.BoardCursor{}, my_err.ErrInvalidCursor
+	}
+
+	taskID, err := uuid.Parse(id)
+	if err != nil {
+		return models.BoardCursor{}, my_err.ErrInvalidCursor
+	}
+
+	return models.BoardCursor{Position: position, TaskID: taskID}, nil
+}
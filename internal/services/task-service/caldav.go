@@ -0,0 +1,563 @@
+package task_service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/ical"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	inboxCalendarName = "Inbox"
+	// maxCalendarObjectName is the longest resource name a client may choose, in bytes.
+	maxCalendarObjectName = 255
+)
+
+type CalDAVProvider interface {
+	GetCalendarSyncToken(ctx context.Context, author uuid.UUID) (int64, error)
+	GetCalendarCTags(ctx context.Context, author uuid.UUID) (map[uuid.NullUUID]int64, error)
+	GetCalendarObjects(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID) ([]*models.CalendarObject, error)
+	GetCalendarObject(ctx context.Context, author uuid.UUID, name string) (*models.CalendarObject, error)
+	GetCalendarChanges(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID, after, upTo int64) ([]models.CalendarChange, error)
+	CreateCalendarObject(ctx context.Context, object *models.CalendarObject) error
+	UpdateCalendarObject(ctx context.Context, task *models.Task, changeSeq int64) error
+}
+
+// ListCalendars returns the CalDAV collections of the author, the inbox first and then the projects,
+// with the sync token of the whole account.
+func (ts *Service) ListCalendars(ctx context.Context, authorID uuid.UUID) ([]models.CalendarCollection, int64, error) {
+	const op = "task.ListCalendars"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("listing calendars")
+
+	token, err := ts.CalDAVProvider.GetCalendarSyncToken(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get sync token", slog.String("error", err.Error()))
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ctags, err := ts.CalDAVProvider.GetCalendarCTags(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get calendar tags", slog.String("error", err.Error()))
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	projects, err := ts.ProjectProvider.GetProjects(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get projects", slog.String("error", err.Error()))
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	calendars := []models.CalendarCollection{{Name: inboxCalendarName, CTag: ctags[uuid.NullUUID{}]}}
+	for _, project := range projects {
+		projectID := uuid.NullUUID{UUID: project.ID, Valid: true}
+		calendars = append(calendars, models.CalendarCollection{ProjectID: projectID, Name: project.Name, CTag: ctags[projectID]})
+	}
+
+	return calendars, token, nil
+}
+
+// GetCalendarObjects renders the tasks of a collection with the given names, every task of it
+// when names is nil. Names that are not in the collection are left out.
+func (ts *Service) GetCalendarObjects(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, names []string) ([]*models.CalendarObject, error) {
+	const op = "task.GetCalendarObjects"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	if err := ts.calendarExists(ctx, authorID, projectID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var objects []*models.CalendarObject
+	if names == nil {
+		all, err := ts.CalDAVProvider.GetCalendarObjects(ctx, authorID, projectID)
+		if err != nil {
+			log.Error("failed to get calendar objects", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		objects = all
+	}
+
+	for _, name := range names {
+		object, err := ts.CalDAVProvider.GetCalendarObject(ctx, authorID, name)
+		if err != nil {
+			if errors.Is(err, my_err.ErrCalendarObjectNotFound) {
+				continue
+			}
+			log.Error("failed to get calendar object", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if object.Task.ProjectID == projectID {
+			objects = append(objects, object)
+		}
+	}
+
+	for _, object := range objects {
+		if err := renderCalendarObject(object); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return objects, nil
+}
+
+// SyncCalendarObjects returns what changed in a collection since the sync token a client got
+// before, 0 for a first sync that returns every task.
+func (ts *Service) SyncCalendarObjects(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, syncToken int64) (*models.CalendarChanges, error) {
+	const op = "task.SyncCalendarObjects"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.Int64("sync_token", syncToken),
+	)
+
+	log.Info("syncing calendar")
+
+	if err := ts.calendarExists(ctx, authorID, projectID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	current, err := ts.CalDAVProvider.GetCalendarSyncToken(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get sync token", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if syncToken < 0 || syncToken > current {
+		return nil, fmt.Errorf("%s: %w", op, my_err.ErrInvalidSyncToken)
+	}
+
+	// Clients that synced before pruned deletions would never hear of them, they have to start over.
+	horizon, err := ts.SyncProvider.GetChangeHorizon(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get change horizon", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if syncToken != 0 && syncToken < horizon {
+		return nil, fmt.Errorf("%s: %w", op, my_err.ErrInvalidSyncToken)
+	}
+
+	changes := &models.CalendarChanges{SyncToken: current}
+	if syncToken == current {
+		return changes, nil
+	}
+
+	objects, err := ts.CalDAVProvider.GetCalendarObjects(ctx, authorID, projectID)
+	if err != nil {
+		log.Error("failed to get calendar objects", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if syncToken == 0 {
+		changes.Changed = objects
+	} else {
+		log := log.With(slog.Int64("up_to", current))
+
+		changed, err := ts.CalDAVProvider.GetCalendarChanges(ctx, authorID, projectID, syncToken, current)
+		if err != nil {
+			log.Error("failed to get calendar changes", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		byID := make(map[uuid.UUID]*models.CalendarObject, len(objects))
+		for _, object := range objects {
+			byID[object.Task.ID] = object
+		}
+
+		for _, change := range changed {
+			// A task in the collection now changed, whichever its last change was: it may have
+			// left the collection and come back.
+			if object, ok := byID[change.TaskID]; ok {
+				changes.Changed = append(changes.Changed, object)
+			} else if change.Deleted && change.Name != "" {
+				changes.Deleted = append(changes.Deleted, change.Name)
+			}
+		}
+
+		slices.SortFunc(changes.Changed, func(a, b *models.CalendarObject) int { return cmp.Compare(a.Name, b.Name) })
+		slices.Sort(changes.Deleted)
+	}
+
+	for _, object := range changes.Changed {
+		if err := renderCalendarObject(object); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return changes, nil
+}
+
+// PutCalendarObject creates or replaces the task stored under name with the VTODO of a
+// calendar object, reading floating times in timezone. ifMatch and ifNoneMatch are the
+// conditional headers of the request, empty when absent. It reports whether the task was created.
+func (ts *Service) PutCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name string, r io.Reader, ifMatch, ifNoneMatch, timezone string) (bool, error) {
+	const op = "task.PutCalendarObject"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.String("name", name),
+	)
+
+	log.Info("putting calendar object")
+
+	if name == "" || len(name) > maxCalendarObjectName || strings.ContainsAny(name, "/\\") {
+		return false, fmt.Errorf("%s: %w: invalid name %q", op, my_err.ErrInvalidCalendarObject, name)
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidTimezone, timezone)
+	}
+
+	if err := ts.calendarExists(ctx, authorID, projectID); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	existing, err := ts.currentCalendarObject(ctx, authorID, projectID, name)
+	if err != nil {
+		log.Error("failed to get calendar object", slog.String("error", err.Error()))
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkETag(existing, ifMatch, ifNoneMatch); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	todo, err := readTodo(r)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	uid, _ := todo.Property("UID")
+	if uid.Value == "" {
+		return false, fmt.Errorf("%s: %w: the VTODO has no UID", op, my_err.ErrInvalidCalendarObject)
+	}
+
+	task := &models.Task{ID: uuid.New(), AuthorID: authorID, ProjectID: projectID}
+	if existing != nil {
+		if uid.Value != existing.UID {
+			return false, fmt.Errorf("%s: %w: the UID cannot change", op, my_err.ErrInvalidCalendarObject)
+		}
+		copied := *existing.Task
+		task = &copied
+	}
+	status := task.Status
+
+	if err := ts.applyTodo(task, todo, loc); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Like any other move between columns, a new status puts the task at the bottom of its column.
+	if existing == nil || task.Status != status {
+		position, err := ts.nextPosition(ctx, models.RankGroup{AuthorID: authorID, ProjectID: projectID, Status: task.Status})
+		if err != nil {
+			log.Error("failed to compute task position", slog.String("error", err.Error()))
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		task.Position = position
+	}
+
+	if existing != nil {
+		// The update fails when the task changed after the entity tag was checked, the client
+		// matched a state that is gone.
+		if err := ts.CalDAVProvider.UpdateCalendarObject(ctx, task, existing.ChangeSeq); err != nil {
+			if !errors.Is(err, my_err.ErrETagMismatch) {
+				log.Error("failed to update calendar object", slog.String("error", err.Error()))
+			}
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+
+		ts.publishTasks(ctx, models.TaskEventUpdated, authorID, task.ID)
+
+		return false, nil
+	}
+
+	object := &models.CalendarObject{Task: task, Name: name, UID: uid.Value}
+	if err := ts.CalDAVProvider.CreateCalendarObject(ctx, object); err != nil {
+		if !errors.Is(err, my_err.ErrCalendarUIDExists) {
+			log.Error("failed to create calendar object", slog.String("error", err.Error()))
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventCreated, authorID, task.ID)
+
+	return true, nil
+}
+
+// DeleteCalendarObject deletes the task stored under name, if it still has the ETag ifMatch
+// when that is not empty.
+func (ts *Service) DeleteCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name, ifMatch string) error {
+	const op = "task.DeleteCalendarObject"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.String("name", name),
+	)
+
+	log.Info("deleting calendar object")
+
+	existing, err := ts.currentCalendarObject(ctx, authorID, projectID, name)
+	if err != nil {
+		log.Error("failed to get calendar object", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if existing == nil {
+		return fmt.Errorf("%s: %w", op, my_err.ErrCalendarObjectNotFound)
+	}
+
+	if err := checkETag(existing, ifMatch, ""); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := ts.DeleteTask(ctx, existing.Task.ID, authorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// calendarExists checks that the collection of a project is there, the one of the inbox always is.
+func (ts *Service) calendarExists(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID) error {
+	if !projectID.Valid {
+		return nil
+	}
+
+	return ts.ProjectProvider.ProjectExists(ctx, projectID.UUID, authorID)
+}
+
+// currentCalendarObject returns the rendered object stored under name in the collection, nil when
+// there is none. A name taken in another collection cannot be reused.
+func (ts *Service) currentCalendarObject(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name string) (*models.CalendarObject, error) {
+	object, err := ts.CalDAVProvider.GetCalendarObject(ctx, authorID, name)
+	if err != nil {
+		if errors.Is(err, my_err.ErrCalendarObjectNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if object.Task.ProjectID != projectID {
+		return nil, fmt.Errorf("%w: %q is the name of a task in another calendar", my_err.ErrInvalidCalendarObject, name)
+	}
+
+	if err := renderCalendarObject(object); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// checkETag evaluates If-Match and If-None-Match against the current object, nil when there is none.
+func checkETag(object *models.CalendarObject, ifMatch, ifNoneMatch string) error {
+	if ifMatch != "" && (object == nil || !etagListMatches(ifMatch, object.ETag)) {
+		return my_err.ErrETagMismatch
+	}
+
+	if ifNoneMatch != "" && object != nil && etagListMatches(ifNoneMatch, object.ETag) {
+		return my_err.ErrETagMismatch
+	}
+
+	return nil
+}
+
+// etagListMatches reports whether an entity tag list, or "*", holds etag. Weak tags never match.
+func etagListMatches(list, etag string) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readTodo parses a calendar object, which must hold exactly one VTODO. Time zone definitions
+// are allowed next to it: times are resolved by zone name, not by the definitions.
+func readTodo(r io.Reader) (*ical.Component, error) {
+	calendar, err := ical.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", my_err.ErrInvalidCalendarObject, err)
+	}
+
+	if calendar.Name != "VCALENDAR" {
+		return nil, fmt.Errorf("%w: expected a VCALENDAR, got %s", my_err.ErrInvalidCalendarObject, calendar.Name)
+	}
+
+	var todo *ical.Component
+	for _, component := range calendar.Components {
+		switch component.Name {
+		case "VTIMEZONE":
+		case "VTODO":
+			if todo != nil {
+				return nil, fmt.Errorf("%w: more than one VTODO", my_err.ErrInvalidCalendarObject)
+			}
+			todo = component
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported, only VTODO", my_err.ErrInvalidCalendarObject, component.Name)
+		}
+	}
+
+	if todo == nil {
+		return nil, fmt.Errorf("%w: no VTODO", my_err.ErrInvalidCalendarObject)
+	}
+
+	return todo, nil
+}
+
+// applyTodo sets the fields of the task that a VTODO carries. Properties the task has no field
+// for are dropped, and a missing property clears its field.
+func (ts *Service) applyTodo(task *models.Task, todo *ical.Component, loc *time.Location) error {
+	summary, _ := todo.Property("SUMMARY")
+	task.Title = strings.TrimSpace(ical.UnescapeText(summary.Value))
+	if task.Title == "" {
+		return fmt.Errorf("%w: %w", my_err.ErrInvalidCalendarObject, my_err.ErrEmptyTitle)
+	}
+
+	description, _ := todo.Property("DESCRIPTION")
+	task.Description = ical.UnescapeText(description.Value)
+
+	status, ok := todo.Property("STATUS")
+	switch {
+	case ok:
+		task.Status = todoStatus(status.Value)
+	case len(todo.PropertyValues("COMPLETED")) > 0:
+		task.Status = models.StatusDone
+	default:
+		task.Status = models.StatusToDo
+	}
+	if _, ok := ts.boardColumn(task.Status); !ok {
+		return fmt.Errorf("%w: %q", my_err.ErrInvalidStatus, task.Status)
+	}
+
+	task.Priority = models.PriorityNone
+	if priority, ok := todo.Property("PRIORITY"); ok {
+		level, err := strconv.Atoi(priority.Value)
+		if err != nil || level < 0 || level > 9 {
+			return fmt.Errorf("%w: PRIORITY must be 0 to 9", my_err.ErrInvalidCalendarObject)
+		}
+		task.Priority = todoPriority(level)
+	}
+
+	task.Deadline, task.DueDate = time.Time{}, ""
+	if due, ok := todo.Property("DUE"); ok {
+		t, allDay, err := ical.ParseTime(due, loc)
+		if err != nil {
+			return fmt.Errorf("%w: %w", my_err.ErrInvalidCalendarObject, err)
+		}
+		if allDay {
+			task.DueDate = t.Format(time.DateOnly)
+		} else {
+			task.Deadline = t.UTC()
+		}
+	}
+
+	rule, _ := todo.Property("RRULE")
+	task.Recurrence = rule.Value
+
+	// Tags cannot hold spaces, categories can.
+	var categories []string
+	for _, value := range todo.PropertyValues("CATEGORIES") {
+		for _, category := range ical.SplitText(value) {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, strings.Join(strings.Fields(category), "-"))
+			}
+		}
+	}
+	tags, err := normalizeTags(categories)
+	if err != nil {
+		return fmt.Errorf("%w: %s", my_err.ErrInvalidCalendarObject, err)
+	}
+	task.Tags = tags
+
+	return nil
+}
+
+// todoStatus maps VTODO statuses onto task statuses, a cancelled to-do is done.
+func todoStatus(status string) string {
+	switch strings.ToUpper(status) {
+	case "COMPLETED", "CANCELLED":
+		return models.StatusDone
+	case "IN-PROCESS":
+		return models.StatusInProgress
+	default:
+		return models.StatusToDo
+	}
+}
+
+// todoPriority maps the 1 (highest) to 9 (lowest) scale onto priorities, 0 is undefined.
+func todoPriority(level int) string {
+	switch {
+	case level == 0:
+		return models.PriorityNone
+	case level < 5:
+		return models.PriorityHigh
+	case level == 5:
+		return models.PriorityMedium
+	default:
+		return models.PriorityLow
+	}
+}
+
+// renderCalendarObject fills in the calendar data and ETag of an object. The data is stamped with
+// the last change to the task, so it only changes with the task.
+func renderCalendarObject(object *models.CalendarObject) error {
+	var buf bytes.Buffer
+	stamp := ical.DateTime(time.Unix(object.ModifiedAt, 0))
+
+	w := ical.NewWriter(&buf)
+	w.Begin("VCALENDAR")
+	w.Property("VERSION", "2.0")
+	w.Property("PRODID", icsProductID)
+	w.Begin("VTODO")
+	writeTodoProperties(w, object.Task, object.UID, stamp)
+	w.Property("LAST-MODIFIED", stamp)
+	w.End("VTODO")
+	w.End("VCALENDAR")
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	object.Data = buf.Bytes()
+	object.ETag = entityTag(object.Data)
+
+	return nil
+}
+
+// entityTag is a strong ETag of data: the quoted base64url of the first 16 bytes of its SHA-256.
+func entityTag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
@@ -0,0 +1,139 @@
+package task_service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	calendarName = "Tasks"
+	// calendarRefresh is how often subscribed calendar apps are asked to poll the feed.
+	calendarRefresh = time.Hour
+	// maxReminderMinutes caps the reminder of a feed at a week.
+	maxReminderMinutes = 7 * 24 * 60
+)
+
+type CalendarProvider interface {
+	SaveCalendarFeed(ctx context.Context, feed *models.CalendarFeed) error
+	GetCalendarFeed(ctx context.Context, tokenHash string) (*models.CalendarFeed, error)
+	DeleteCalendarFeed(ctx context.Context, author uuid.UUID) error
+}
+
+// RegenerateCalendarToken makes a new secret token for the calendar feed of the author and
+// revokes the one before. Events of open tasks alert reminderMinutes before they are due,
+// not at all when it is 0. The token is only ever returned here.
+func (ts *Service) RegenerateCalendarToken(ctx context.Context, authorID uuid.UUID, reminderMinutes int) (string, *models.CalendarFeed, error) {
+	const op = "task.RegenerateCalendarToken"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("regenerating calendar token")
+
+	if reminderMinutes < 0 || reminderMinutes > maxReminderMinutes {
+		return "", nil, fmt.Errorf("%s: %w: reminder must be 0 to %d minutes", op, my_err.ErrInvalidCalendarFeed, maxReminderMinutes)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Error("failed to generate calendar token", slog.String("error", err.Error()))
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	feed := &models.CalendarFeed{
+		AuthorID:        authorID,
+		TokenHash:       calendarTokenHash(token),
+		ReminderMinutes: reminderMinutes,
+		// Calendar data is stamped with the creation of the feed, so that unchanged tasks render
+		// the same calendar and keep its ETag.
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := ts.CalendarProvider.SaveCalendarFeed(ctx, feed); err != nil {
+		log.Error("failed to save calendar feed", slog.String("error", err.Error()))
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, feed, nil
+}
+
+// RevokeCalendarToken turns the calendar feed of the author off.
+func (ts *Service) RevokeCalendarToken(ctx context.Context, authorID uuid.UUID) error {
+	const op = "task.RevokeCalendarToken"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("revoking calendar token")
+
+	if err := ts.CalendarProvider.DeleteCalendarFeed(ctx, authorID); err != nil {
+		if !errors.Is(err, my_err.ErrCalendarFeedNotFound) {
+			log.Error("failed to delete calendar feed", slog.String("error", err.Error()))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetCalendarFeed renders the calendar of the feed with the given token: the open tasks that
+// fall due, as a VTODO and a VEVENT each. The ETag returned with it only changes with the calendar.
+func (ts *Service) GetCalendarFeed(ctx context.Context, token string) ([]byte, string, error) {
+	const op = "task.GetCalendarFeed"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+	)
+
+	feed, err := ts.CalendarProvider.GetCalendarFeed(ctx, calendarTokenHash(token))
+	if err != nil {
+		if !errors.Is(err, my_err.ErrCalendarFeedNotFound) {
+			log.Error("failed to get calendar feed", slog.String("error", err.Error()))
+		}
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("author_id", feed.AuthorID.String()))
+
+	var buf bytes.Buffer
+	encoder := newICSEncoder(&buf, feed.CreatedAt)
+	encoder.reminder = time.Duration(feed.ReminderMinutes) * time.Minute
+	encoder.subscribe(calendarName, calendarRefresh)
+
+	due := func(task *models.Task) bool {
+		return task.Status != models.StatusDone && (task.DueDate != "" || !task.Deadline.IsZero())
+	}
+
+	if _, err := ts.encodeTasks(ctx, log, feed.AuthorID, encoder, due, 0); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return buf.Bytes(), entityTag(buf.Bytes()), nil
+}
+
+func calendarTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,134 @@
+package task_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type ChecklistProvider interface {
+	AddChecklistItem(ctx context.Context, author uuid.UUID, item *models.ChecklistItem, position *int) error
+	ToggleChecklistItem(ctx context.Context, itemID, author uuid.UUID) (*models.ChecklistItem, error)
+	MoveChecklistItem(ctx context.Context, itemID, author uuid.UUID, position int) ([]*models.ChecklistItem, error)
+	RemoveChecklistItem(ctx context.Context, itemID, author uuid.UUID) ([]*models.ChecklistItem, error)
+	GetChecklist(ctx context.Context, taskID, author uuid.UUID) ([]*models.ChecklistItem, error)
+	GetChecklistItem(ctx context.Context, itemID, author uuid.UUID) (*models.ChecklistItem, error)
+}
+
+func (ts *Service) AddChecklistItem(ctx context.Context, taskID, authorID uuid.UUID, text string, position *int) (*models.ChecklistItem, error) {
+	const op = "task.AddChecklistItem"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("task_id", taskID.String()),
+	)
+
+	log.Info("adding checklist item")
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("%s: %w", op, my_err.ErrEmptyField)
+	}
+
+	item := &models.ChecklistItem{
+		ID:     uuid.New(),
+		TaskID: taskID,
+		Text:   text,
+	}
+
+	if err := ts.ChecklistProvider.AddChecklistItem(ctx, authorID, item, position); err != nil {
+		log.Error("failed to add checklist item", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, taskID)
+
+	return item, nil
+}
+
+func (ts *Service) ToggleChecklistItem(ctx context.Context, itemID, authorID uuid.UUID) (*models.ChecklistItem, error) {
+	const op = "task.ToggleChecklistItem"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("item_id", itemID.String()),
+	)
+
+	log.Info("toggling checklist item")
+
+	item, err := ts.ChecklistProvider.ToggleChecklistItem(ctx, itemID, authorID)
+	if err != nil {
+		log.Error("failed to toggle checklist item", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, item.TaskID)
+
+	return item, nil
+}
+
+func (ts *Service) ReorderChecklistItem(ctx context.Context, itemID, authorID uuid.UUID, position int) ([]*models.ChecklistItem, error) {
+	const op = "task.ReorderChecklistItem"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("item_id", itemID.String()),
+	)
+
+	log.Info("reordering checklist item")
+
+	items, err := ts.ChecklistProvider.MoveChecklistItem(ctx, itemID, authorID, position)
+	if err != nil {
+		log.Error("failed to reorder checklist item", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(items) > 0 {
+		ts.publishTasks(ctx, models.TaskEventUpdated, authorID, items[0].TaskID)
+	}
+
+	return items, nil
+}
+
+func (ts *Service) RemoveChecklistItem(ctx context.Context, itemID, authorID uuid.UUID) ([]*models.ChecklistItem, error) {
+	const op = "task.RemoveChecklistItem"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("item_id", itemID.String()),
+	)
+
+	log.Info("removing checklist item")
+
+	// The checklist left may be empty, the item tells whose it was.
+	item, err := ts.ChecklistProvider.GetChecklistItem(ctx, itemID, authorID)
+	if err != nil {
+		log.Error("failed to get checklist item", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	items, err := ts.ChecklistProvider.RemoveChecklistItem(ctx, itemID, authorID)
+	if err != nil {
+		log.Error("failed to remove checklist item", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, item.TaskID)
+
+	return items, nil
+}
+
+func summarizeChecklist(task *models.Task) {
+	if len(task.Checklist) == 0 {
+		return
+	}
+
+	summary := models.SummarizeChecklist(task.Checklist)
+	task.ChecklistSummary = &summary
+}
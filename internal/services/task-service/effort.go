@@ -0,0 +1,72 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// maxEstimateMinutes caps estimates at 1000 hours, anything bigger should be split up.
+const maxEstimateMinutes = 1000 * 60
+
+// SetTaskEstimate sets the estimate of a task in minutes, nil clears it.
+func (ts *Service) SetTaskEstimate(ctx context.Context, taskID, authorID uuid.UUID, minutes *int) (*models.Task, error) {
+	const op = "task.SetTaskEstimate"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("task_id", taskID.String()),
+	)
+
+	log.Info("setting task estimate")
+
+	if minutes != nil && (*minutes < 0 || *minutes > maxEstimateMinutes) {
+		return nil, fmt.Errorf("%s: %w: must be between 0 and %d minutes", op, my_err.ErrInvalidEstimate, maxEstimateMinutes)
+	}
+
+	if err := ts.TaskProvider.SetTaskEstimate(ctx, taskID, authorID, minutes); err != nil {
+		if !errors.Is(err, my_err.ErrTaskNotFound) {
+			log.Error("failed to set task estimate", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, taskID)
+
+	task, err := ts.TaskProvider.GetTaskByID(ctx, taskID, authorID)
+	if err != nil {
+		log.Error("failed to get task", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	summarizeTask(task)
+
+	return task, nil
+}
+
+// effortByProject groups the author's per-status effort by project, the inbox is keyed by an invalid ID.
+func (ts *Service) effortByProject(ctx context.Context, authorID uuid.UUID) (map[uuid.NullUUID][]models.StatusEffort, error) {
+	efforts, err := ts.TaskProvider.GetEffortByStatus(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	byProject := make(map[uuid.NullUUID][]models.StatusEffort)
+	for _, effort := range efforts {
+		byProject[effort.ProjectID] = append(byProject[effort.ProjectID], effort)
+	}
+
+	return byProject, nil
+}
+
+// summarizeTask fills the fields derived from a task's checklist, estimate and tracked time.
+func summarizeTask(task *models.Task) {
+	summarizeChecklist(task)
+	task.Effort = models.SummarizeEffort(task)
+}
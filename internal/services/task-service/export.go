@@ -0,0 +1,119 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// exportPageSize is how many tasks an export holds in memory at a time.
+const exportPageSize = 200
+
+// ExportTasks writes the author's tasks to w in one of the models.Export* formats, in board order.
+// filter is an optional view query the tasks must match; its sort is not supported since
+// tasks are streamed page by page. timezone is used for due filters and for local times.
+func (ts *Service) ExportTasks(ctx context.Context, authorID uuid.UUID, format, filter, timezone string, w io.Writer) error {
+	const op = "task.ExportTasks"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.String("format", format),
+	)
+
+	log.Info("exporting tasks")
+
+	var query models.ViewQuery
+	if filter != "" {
+		var err error
+		query, err = ParseViewQuery(filter)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if len(query.Sort) > 0 {
+		return fmt.Errorf("%s: %w: exports keep the board order, sort is not supported", op, my_err.ErrInvalidViewQuery)
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidTimezone, timezone)
+	}
+
+	now := time.Now()
+	encoder, err := newTaskEncoder(format, w, loc, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	exported, err := ts.encodeTasks(ctx, log, authorID, encoder, matchTasks(query, now.In(loc)), query.Limit)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, exportWriteError(log, err))
+	}
+
+	log.Info("tasks exported", slog.Int("tasks", exported))
+
+	return nil
+}
+
+// encodeTasks pages through the author's tasks in board order and encodes the ones that match,
+// up to limit of them when it is positive. It returns how many were encoded.
+func (ts *Service) encodeTasks(ctx context.Context, log *slog.Logger, authorID uuid.UUID, encoder taskEncoder, matches func(task *models.Task) bool, limit int) (int, error) {
+	var (
+		after   models.BoardCursor
+		encoded int
+	)
+
+	for {
+		tasks, err := ts.TaskProvider.GetTaskPage(ctx, authorID, after, exportPageSize)
+		if err != nil {
+			log.Error("failed to get tasks", slog.String("error", err.Error()))
+			return encoded, err
+		}
+
+		for _, task := range tasks {
+			if !matches(task) {
+				continue
+			}
+
+			summarizeTask(task)
+			if err := encoder.Encode(task); err != nil {
+				return encoded, exportWriteError(log, err)
+			}
+
+			encoded++
+			if limit > 0 && encoded == limit {
+				return encoded, nil
+			}
+		}
+
+		if len(tasks) < exportPageSize {
+			return encoded, nil
+		}
+
+		last := tasks[len(tasks)-1]
+		after = models.BoardCursor{Position: last.Position, TaskID: last.ID}
+	}
+}
+
+// exportWriteError logs a failed write, unless the reader of the export simply went away.
+func exportWriteError(log *slog.Logger, err error) error {
+	if !errors.Is(err, context.Canceled) {
+		log.Error("failed to write export", slog.String("error", err.Error()))
+	}
+
+	return err
+}
@@ -0,0 +1,335 @@
+package task_service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/ical"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// taskEncoder writes tasks one at a time, Close writes whatever the format needs after the last one.
+type taskEncoder interface {
+	Encode(task *models.Task) error
+	Close() error
+}
+
+// newTaskEncoder returns the encoder of format. Local times are written in loc, now stamps calendar entries.
+func newTaskEncoder(format string, w io.Writer, loc *time.Location, now time.Time) (taskEncoder, error) {
+	switch format {
+	case models.ExportJSONL:
+		return &jsonlEncoder{encoder: json.NewEncoder(w)}, nil
+	case models.ExportCSV:
+		return newCSVEncoder(w, loc), nil
+	case models.ExportMarkdown:
+		return &markdownEncoder{w: w, loc: loc}, nil
+	case models.ExportICS:
+		return newICSEncoder(w, now), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", my_err.ErrInvalidExportFormat, format)
+	}
+}
+
+type jsonlEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(task *models.Task) error {
+	return e.encoder.Encode(task)
+}
+
+func (e *jsonlEncoder) Close() error {
+	return nil
+}
+
+var csvHeader = []string{
+	"id", "title", "description", "status", "priority", "project_id", "parent_id", "deadline", "due_date",
+	"tags", "recurrence", "assignee", "estimate_minutes", "tracked_seconds",
+}
+
+type csvEncoder struct {
+	w   *csv.Writer
+	loc *time.Location
+	err error
+}
+
+func newCSVEncoder(w io.Writer, loc *time.Location) *csvEncoder {
+	e := &csvEncoder{w: csv.NewWriter(w), loc: loc}
+	e.err = e.w.Write(csvHeader)
+
+	return e
+}
+
+func (e *csvEncoder) Encode(task *models.Task) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	var projectID, parentID, deadline, estimate string
+	if task.ProjectID.Valid {
+		projectID = task.ProjectID.UUID.String()
+	}
+	if task.ParentID.Valid {
+		parentID = task.ParentID.UUID.String()
+	}
+	if !task.Deadline.IsZero() {
+		deadline = task.Deadline.In(e.loc).Format(time.RFC3339)
+	}
+	if task.EstimateMinutes != nil {
+		estimate = strconv.Itoa(*task.EstimateMinutes)
+	}
+
+	return e.w.Write([]string{
+		task.ID.String(),
+		csvText(task.Title),
+		csvText(task.Description),
+		task.Status,
+		task.Priority,
+		projectID,
+		parentID,
+		deadline,
+		task.DueDate,
+		csvText(strings.Join(task.Tags, " ")),
+		task.Recurrence,
+		csvText(task.Assignee),
+		estimate,
+		strconv.FormatInt(task.TrackedSeconds, 10),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvText keeps spreadsheets from evaluating user text as a formula, by quoting it the way
+// they quote text themselves.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+type markdownEncoder struct {
+	w   io.Writer
+	loc *time.Location
+}
+
+// Encode writes a task as a checklist item such as
+// "- [ ] Title (due 2026-11-02 15:00, high priority, in progress) #tag", followed by its
+// description and checklist, indented under it.
+func (e *markdownEncoder) Encode(task *models.Task) error {
+	var b strings.Builder
+
+	b.WriteString("- ")
+	b.WriteString(markdownCheckbox(task.Status == models.StatusDone))
+	b.WriteString(markdownLine(task.Title))
+
+	var details []string
+	switch {
+	case task.DueDate != "":
+		details = append(details, "due "+task.DueDate)
+	case !task.Deadline.IsZero():
+		details = append(details, "due "+task.Deadline.In(e.loc).Format("2006-01-02 15:04"))
+	}
+	if task.Priority != "" && task.Priority != models.PriorityNone {
+		details = append(details, task.Priority+" priority")
+	}
+	if task.Status == models.StatusInProgress {
+		details = append(details, "in progress")
+	}
+	if len(details) > 0 {
+		b.WriteString(" (" + strings.Join(details, ", ") + ")")
+	}
+
+	for _, tag := range task.Tags {
+		b.WriteString(" #" + tag)
+	}
+	b.WriteByte('\n')
+
+	for _, line := range strings.Split(task.Description, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	for _, item := range task.Checklist {
+		b.WriteString("  - " + markdownCheckbox(item.Checked) + markdownLine(item.Text) + "\n")
+	}
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *markdownEncoder) Close() error {
+	return nil
+}
+
+func markdownCheckbox(checked bool) string {
+	if checked {
+		return "[x] "
+	}
+
+	return "[ ] "
+}
+
+// markdownLine keeps text on the line of its list item.
+func markdownLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// icsProductID identifies this service as the author of calendar data.
+const icsProductID = "-//SlashLight//todo-list//EN"
+
+type icsEncoder struct {
+	w     *ical.Writer
+	stamp string
+	// reminder is how long before the due time the events of open tasks alert, none when zero.
+	reminder time.Duration
+}
+
+func newICSEncoder(w io.Writer, now time.Time) *icsEncoder {
+	e := &icsEncoder{w: ical.NewWriter(w), stamp: ical.DateTime(now)}
+
+	e.w.Begin("VCALENDAR")
+	e.w.Property("VERSION", "2.0")
+	e.w.Property("PRODID", icsProductID)
+	e.w.Property("CALSCALE", "GREGORIAN")
+
+	return e
+}
+
+// subscribe names the calendar and asks the apps subscribed to it to poll every refresh.
+// It must be called before the first task is encoded.
+func (e *icsEncoder) subscribe(name string, refresh time.Duration) {
+	e.w.Text("X-WR-CALNAME", name)
+	e.w.Property("REFRESH-INTERVAL", ical.Duration(refresh), "VALUE=DURATION")
+	e.w.Property("X-PUBLISHED-TTL", ical.Duration(refresh))
+}
+
+// Encode writes the task as a VTODO. A task that falls due also gets a VEVENT at that time,
+// for the calendar apps that do not show to-dos.
+func (e *icsEncoder) Encode(task *models.Task) error {
+	due, dueParams := icsDue(task)
+
+	e.w.Begin("VTODO")
+	writeTodoProperties(e.w, task, task.ID.String()+"@todo-list", e.stamp)
+	e.w.End("VTODO")
+
+	if due != "" {
+		e.w.Begin("VEVENT")
+		e.w.Property("UID", task.ID.String()+"-due@todo-list")
+		e.w.Property("DTSTAMP", e.stamp)
+		e.w.Text("SUMMARY", task.Title)
+		e.w.Property("DTSTART", due, dueParams...)
+		if task.DueDate != "" {
+			end, _ := time.Parse(time.DateOnly, task.DueDate)
+			e.w.Property("DTEND", ical.Date(end.AddDate(0, 0, 1)), dueParams...)
+		}
+		if task.Recurrence != "" {
+			e.w.Property("RRULE", task.Recurrence)
+		}
+		e.w.Property("TRANSP", "TRANSPARENT")
+		// Only the event alerts, apps showing the to-do as well would alert twice.
+		if e.reminder > 0 && task.Status != models.StatusDone {
+			e.w.Begin("VALARM")
+			e.w.Property("ACTION", "DISPLAY")
+			e.w.Text("DESCRIPTION", task.Title)
+			e.w.Property("TRIGGER", ical.Duration(-e.reminder))
+			e.w.End("VALARM")
+		}
+		e.w.End("VEVENT")
+	}
+
+	return e.w.Err()
+}
+
+// writeTodoProperties writes the properties of the VTODO of a task.
+func writeTodoProperties(w *ical.Writer, task *models.Task, uid, stamp string) {
+	due, dueParams := icsDue(task)
+
+	w.Property("UID", uid)
+	w.Property("DTSTAMP", stamp)
+	w.Text("SUMMARY", task.Title)
+	if task.Description != "" {
+		w.Text("DESCRIPTION", task.Description)
+	}
+	w.Property("STATUS", icsStatus(task.Status))
+	if priority := icsPriority(task.Priority); priority != "" {
+		w.Property("PRIORITY", priority)
+	}
+	if len(task.Tags) > 0 {
+		categories := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			categories[i] = ical.EscapeText(tag)
+		}
+		w.Property("CATEGORIES", strings.Join(categories, ","))
+	}
+	if due != "" {
+		// A recurrence rule needs a start to count from.
+		if task.Recurrence != "" {
+			w.Property("DTSTART", due, dueParams...)
+			w.Property("RRULE", task.Recurrence)
+		}
+		w.Property("DUE", due, dueParams...)
+	}
+}
+
+func (e *icsEncoder) Close() error {
+	e.w.End("VCALENDAR")
+	return e.w.Flush()
+}
+
+// icsDue formats the due date of a task as a DATE or the deadline as a UTC DATE-TIME,
+// with the parameters the property needs.
+func icsDue(task *models.Task) (string, []string) {
+	switch {
+	case task.DueDate != "":
+		date, err := time.Parse(time.DateOnly, task.DueDate)
+		if err != nil {
+			return "", nil
+		}
+		return ical.Date(date), []string{"VALUE=DATE"}
+	case !task.Deadline.IsZero():
+		return ical.DateTime(task.Deadline), nil
+	default:
+		return "", nil
+	}
+}
+
+func icsStatus(status string) string {
+	switch status {
+	case models.StatusDone:
+		return "COMPLETED"
+	case models.StatusInProgress:
+		return "IN-PROCESS"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+// icsPriority maps priorities onto the 1 (highest) to 9 (lowest) scale, none leaves it undefined.
+func icsPriority(priority string) string {
+	switch priority {
+	case models.PriorityHigh:
+		return "1"
+	case models.PriorityMedium:
+		return "5"
+	case models.PriorityLow:
+		return "9"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,4 @@
+package task_service
+
+// WebhookRetryDelay exposes the retry backoff to the tests, waiting it out in them would take hours.
+var WebhookRetryDelay = webhookRetryDelay
@@ -0,0 +1,355 @@
+package task_service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/importer"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// maxImportSize is the largest import file accepted, in bytes.
+const maxImportSize = 10 << 20
+
+type ImportProvider interface {
+	GetImportedTasks(ctx context.Context, author uuid.UUID, source string) (map[string]uuid.UUID, error)
+	ImportTasks(ctx context.Context, source string, tasks []*models.Task, externalIDs []string) error
+}
+
+// ImportTasks creates the tasks of an import file in one transaction, at the bottom of their
+// columns in file order. Rows imported from the same format before are skipped as duplicates.
+//
+// Every row is validated first and the report lists the rows that are not valid. A single one
+// keeps the import from writing anything, as does options.DryRun. Only a file that cannot be
+// read at all, or a failure of the storage, returns an error.
+func (ts *Service) ImportTasks(ctx context.Context, authorID uuid.UUID, options models.ImportOptions, r io.Reader) (*models.ImportReport, error) {
+	const op = "task.ImportTasks"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.String("format", options.Format),
+	)
+
+	log.Info("importing tasks")
+
+	loc, err := time.LoadLocation(options.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidTimezone, options.Timezone)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(data) > maxImportSize {
+		return nil, fmt.Errorf("%s: %w: the file is larger than %d MiB", op, my_err.ErrInvalidImport, maxImportSize>>20)
+	}
+
+	rows, err := readImport(options, bytes.NewReader(data), loc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if options.ProjectID.Valid {
+		if err := ts.ProjectProvider.ProjectExists(ctx, options.ProjectID.UUID, authorID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	imported, err := ts.ImportProvider.GetImportedTasks(ctx, authorID, options.Format)
+	if err != nil {
+		log.Error("failed to get imported tasks", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := &models.ImportReport{DryRun: options.DryRun, Rows: len(rows)}
+	plan := &importPlan{
+		authorID:  authorID,
+		projectID: options.ProjectID,
+		imported:  imported,
+		rows:      make(map[string]int),
+		report:    report,
+	}
+
+	for _, row := range rows {
+		plan.add(ts, row)
+	}
+	plan.link()
+	slices.SortStableFunc(report.Errors, func(a, b models.ImportError) int { return cmp.Compare(a.Row, b.Row) })
+
+	if len(report.Errors) > 0 {
+		log.Info("import has invalid rows", slog.Int("errors", len(report.Errors)))
+		if options.DryRun {
+			report.Created = len(plan.tasks) - plan.rejected
+		}
+		return report, nil
+	}
+
+	report.Created = len(plan.tasks)
+	if options.DryRun || len(plan.tasks) == 0 {
+		report.Committed = !options.DryRun
+		return report, nil
+	}
+
+	// Positions follow the file, while parents are stored before their subtasks.
+	groups := make(map[models.RankGroup][]int)
+	for i, task := range plan.tasks {
+		group := models.RankGroup{AuthorID: authorID, ProjectID: task.ProjectID, Status: task.Status}
+		groups[group] = append(groups[group], i)
+	}
+	if err := ts.appendPositions(ctx, groups, func(i int, position string) { plan.tasks[i].Position = position }); err != nil {
+		log.Error("failed to compute task positions", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tasks := make([]*models.Task, 0, len(plan.order))
+	externalIDs := make([]string, 0, len(plan.order))
+	for _, i := range plan.order {
+		tasks = append(tasks, plan.tasks[i])
+		externalIDs = append(externalIDs, plan.externalIDs[i])
+	}
+
+	if err := ts.ImportProvider.ImportTasks(ctx, options.Format, tasks, externalIDs); err != nil {
+		if !errors.Is(err, my_err.ErrAlreadyImported) {
+			log.Error("failed to import tasks", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	taskIDs := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	ts.publishTasks(ctx, models.TaskEventCreated, authorID, taskIDs...)
+
+	report.Committed = true
+
+	return report, nil
+}
+
+// readImport reads the rows of the file with the reader of its format.
+func readImport(options models.ImportOptions, r io.Reader, loc *time.Location) ([]models.ImportRow, error) {
+	var (
+		rows []models.ImportRow
+		err  error
+	)
+
+	switch options.Format {
+	case models.ImportCSV:
+		rows, err = importer.ReadCSV(r, options.ColumnMapping, loc)
+	case models.ImportTodoTxt:
+		rows, err = importer.ReadTodoTxt(r)
+	case models.ImportTodoist:
+		rows, err = importer.ReadTodoist(r, loc)
+	case models.ImportTrello:
+		rows, err = importer.ReadTrello(r)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", my_err.ErrInvalidImport, options.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", my_err.ErrInvalidImport, err)
+	}
+
+	return rows, nil
+}
+
+// importPlan collects the valid tasks of an import and the errors of the other rows.
+type importPlan struct {
+	authorID  uuid.UUID
+	projectID uuid.NullUUID
+	// imported maps the external IDs of earlier imports to their tasks.
+	imported map[string]uuid.UUID
+	// rows maps the external IDs of the file to their index in tasks, -1 for invalid rows.
+	rows map[string]int
+
+	tasks       []*models.Task
+	externalIDs []string
+	parents     []string
+	numbers     []int
+	// rejected counts the tasks that turned out invalid after they were added, because of their parent.
+	rejected int
+	// order lists the tasks with parents first, see link.
+	order []int
+
+	report *models.ImportReport
+}
+
+func (p *importPlan) fail(row models.ImportRow, err error) {
+	p.report.Errors = append(p.report.Errors, models.ImportError{
+		Row:        row.Row,
+		ExternalID: row.ExternalID,
+		Message:    err.Error(),
+	})
+}
+
+// add validates a row and adds its task to the plan, or reports why it cannot be imported.
+func (p *importPlan) add(ts *Service, row models.ImportRow) {
+	if row.Err != nil {
+		p.fail(row, row.Err)
+		return
+	}
+
+	if row.ExternalID != "" {
+		if _, ok := p.imported[row.ExternalID]; ok {
+			p.report.Duplicates++
+			return
+		}
+		if _, ok := p.rows[row.ExternalID]; ok {
+			p.fail(row, fmt.Errorf("%w: the file has the ID %q twice", my_err.ErrInvalidImport, row.ExternalID))
+			return
+		}
+	}
+
+	task := row.Task
+	task.ID = uuid.New()
+	task.AuthorID = p.authorID
+	task.ProjectID = p.projectID
+	task.Title = strings.TrimSpace(task.Title)
+	task.Deadline = task.Deadline.UTC()
+
+	err := ts.validateImportTask(&task)
+	if row.ExternalID != "" {
+		p.rows[row.ExternalID] = len(p.tasks)
+		if err != nil {
+			p.rows[row.ExternalID] = -1
+		}
+	}
+	if err != nil {
+		p.fail(row, err)
+		return
+	}
+
+	p.tasks = append(p.tasks, &task)
+	p.externalIDs = append(p.externalIDs, row.ExternalID)
+	p.parents = append(p.parents, row.ParentExternalID)
+	p.numbers = append(p.numbers, row.Row)
+}
+
+// validateImportTask checks a task read from a file and fills in the defaults of a new task.
+func (ts *Service) validateImportTask(task *models.Task) error {
+	if task.Title == "" {
+		return my_err.ErrEmptyTitle
+	}
+
+	if err := validateDue(task.Deadline, task.DueDate); err != nil {
+		return err
+	}
+
+	if task.Status == "" {
+		task.Status = models.StatusToDo
+	}
+	if _, ok := ts.boardColumn(task.Status); !ok {
+		return fmt.Errorf("%w: %q", my_err.ErrInvalidStatus, task.Status)
+	}
+
+	if task.Priority == "" {
+		task.Priority = models.PriorityNone
+	}
+	if !validPriority(task.Priority) {
+		return fmt.Errorf("%w: unknown priority %q", my_err.ErrInvalidImport, task.Priority)
+	}
+
+	tags, err := normalizeTags(task.Tags)
+	if err != nil {
+		return fmt.Errorf("%w: %s", my_err.ErrInvalidImport, err)
+	}
+	task.Tags = tags
+
+	checklist := task.Checklist
+	task.Checklist = nil
+	for _, item := range checklist {
+		text := strings.TrimSpace(item.Text)
+		if text == "" {
+			continue
+		}
+
+		task.Checklist = append(task.Checklist, &models.ChecklistItem{
+			ID:       uuid.New(),
+			TaskID:   task.ID,
+			Text:     text,
+			Checked:  item.Checked,
+			Position: len(task.Checklist),
+		})
+	}
+
+	return nil
+}
+
+// link points subtasks at their parents, which are either earlier imports or tasks of the same
+// file, and orders the tasks so that parents come first. Subtasks of a missing parent and
+// cycles of parents are reported.
+func (p *importPlan) link() {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make([]int, len(p.tasks))
+	invalid := make([]bool, len(p.tasks))
+
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch state[i] {
+		case visiting:
+			return false
+		case done:
+			return !invalid[i]
+		}
+		state[i] = visiting
+
+		ok := true
+		parent := p.parents[i]
+		if taskID, imported := p.imported[parent]; parent != "" && imported {
+			p.tasks[i].ParentID = uuid.NullUUID{UUID: taskID, Valid: true}
+		} else if parent != "" {
+			j, inFile := p.rows[parent]
+			switch {
+			case !inFile:
+				ok = false
+				p.reject(i, fmt.Errorf("%w: parent %q is not in the file", my_err.ErrInvalidImport, parent))
+			case j < 0:
+				ok = false
+				p.reject(i, fmt.Errorf("%w: parent %q is not valid", my_err.ErrInvalidImport, parent))
+			case !visit(j):
+				ok = false
+				if state[j] == visiting {
+					p.reject(i, fmt.Errorf("%w: the task is its own ancestor", my_err.ErrInvalidImport))
+				} else {
+					p.reject(i, fmt.Errorf("%w: parent %q is not valid", my_err.ErrInvalidImport, parent))
+				}
+			default:
+				p.tasks[i].ParentID = uuid.NullUUID{UUID: p.tasks[j].ID, Valid: true}
+			}
+		}
+
+		state[i] = done
+		invalid[i] = !ok
+		if ok {
+			p.order = append(p.order, i)
+		}
+
+		return ok
+	}
+
+	for i := range p.tasks {
+		visit(i)
+	}
+}
+
+func (p *importPlan) reject(i int, err error) {
+	p.rejected++
+	p.fail(models.ImportRow{Row: p.numbers[i], ExternalID: p.externalIDs[i]}, err)
+}
@@ -0,0 +1,164 @@
+package task_service_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const importFile = "id,title,status,parent_id\n" +
+	"1,Write report,in-progress,\n" +
+	"2,Outline,done,1\n"
+
+func TestImportTasks(t *testing.T) {
+	ctx := t.Context()
+	service, _ := newService(t, task_service.Settings{})
+	author := uuid.New()
+	options := models.ImportOptions{Format: models.ImportCSV, Timezone: "UTC"}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+		data   string
+		want   models.ImportReport
+		// wantTasks are the sorted titles of the tasks of the author after the import.
+		wantTasks []string
+	}{
+		{
+			name:   "dry run",
+			dryRun: true,
+			data:   importFile,
+			want:   models.ImportReport{DryRun: true, Rows: 2, Created: 2},
+		},
+		{
+			name:      "import",
+			data:      importFile,
+			want:      models.ImportReport{Rows: 2, Created: 2, Committed: true},
+			wantTasks: []string{"Outline", "Write report"},
+		},
+		{
+			name:      "import again",
+			data:      importFile,
+			want:      models.ImportReport{Rows: 2, Duplicates: 2, Committed: true},
+			wantTasks: []string{"Outline", "Write report"},
+		},
+		{
+			name:      "dry run of new and imported rows",
+			dryRun:    true,
+			data:      importFile + "3,Send report,,\n",
+			want:      models.ImportReport{DryRun: true, Rows: 3, Created: 1, Duplicates: 2},
+			wantTasks: []string{"Outline", "Write report"},
+		},
+		{
+			name: "invalid rows",
+			data: "id,title,status,parent_id\n" +
+				"3,Send report,,\n" +
+				"4,,,\n" +
+				"5,Archive,archived,\n" +
+				"6,Proofread,,9\n",
+			want: models.ImportReport{Rows: 4, Errors: []models.ImportError{
+				{Row: 3, ExternalID: "4"}, {Row: 4, ExternalID: "5"}, {Row: 5, ExternalID: "6"},
+			}},
+			wantTasks: []string{"Outline", "Write report"},
+		},
+		{
+			name: "subtask of an earlier import",
+			data: "id,title,status,parent_id\n" +
+				"7,Proofread,,1\n",
+			want:      models.ImportReport{Rows: 1, Created: 1, Committed: true},
+			wantTasks: []string{"Outline", "Proofread", "Write report"},
+		},
+	}
+
+	for _, tt := range tests {
+		options.DryRun = tt.dryRun
+
+		report, err := service.ImportTasks(ctx, author, options, strings.NewReader(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		for i := range report.Errors {
+			if report.Errors[i].Message == "" {
+				t.Errorf("%s: want a message for the error of row %d", tt.name, report.Errors[i].Row)
+			}
+			report.Errors[i].Message = ""
+		}
+		if !equalReports(*report, tt.want) {
+			t.Errorf("%s: want report %+v, got %+v", tt.name, tt.want, *report)
+		}
+
+		tasks, err := service.GetTasks(ctx, author)
+		if err != nil {
+			t.Fatalf("%s: get tasks: %v", tt.name, err)
+		}
+		var titles []string
+		for _, task := range tasks {
+			titles = append(titles, task.Title)
+		}
+		slices.Sort(titles)
+		if !slices.Equal(titles, tt.wantTasks) {
+			t.Errorf("%s: want tasks %q, got %q", tt.name, tt.wantTasks, titles)
+		}
+	}
+
+	tasks, err := service.GetTasks(ctx, author)
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	byTitle := make(map[string]*models.Task)
+	for _, task := range tasks {
+		byTitle[task.Title] = task
+	}
+	parent, outline, proofread := byTitle["Write report"], byTitle["Outline"], byTitle["Proofread"]
+	if parent.Status != models.StatusInProgress || outline.Status != models.StatusDone {
+		t.Errorf("want the statuses of the file, got %q and %q", parent.Status, outline.Status)
+	}
+	if outline.ParentID.UUID != parent.ID || proofread.ParentID.UUID != parent.ID {
+		t.Errorf("want the subtasks under their parent %s, got %v and %v", parent.ID, outline.ParentID, proofread.ParentID)
+	}
+}
+
+func equalReports(a, b models.ImportReport) bool {
+	if a.DryRun != b.DryRun || a.Rows != b.Rows || a.Created != b.Created || a.Duplicates != b.Duplicates ||
+		a.Committed != b.Committed || len(a.Errors) != len(b.Errors) {
+		return false
+	}
+	for i := range a.Errors {
+		if a.Errors[i] != b.Errors[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestImportTasksRefusesFile(t *testing.T) {
+	service, _ := newService(t, task_service.Settings{})
+
+	tests := []struct {
+		name    string
+		options models.ImportOptions
+		data    string
+		wantErr error
+	}{
+		{"unknown format", models.ImportOptions{Format: "xlsx"}, "", my_err.ErrInvalidImport},
+		{"malformed file", models.ImportOptions{Format: models.ImportTrello}, "{", my_err.ErrInvalidImport},
+		{"unknown timezone", models.ImportOptions{Format: models.ImportCSV, Timezone: "Mars/Olympus"}, importFile, my_err.ErrInvalidTimezone},
+		{"unknown project", models.ImportOptions{Format: models.ImportCSV, ProjectID: uuid.NullUUID{UUID: uuid.New(), Valid: true}}, importFile, my_err.ErrProjectNotFound},
+	}
+
+	for _, tt := range tests {
+		_, err := service.ImportTasks(t.Context(), uuid.New(), tt.options, strings.NewReader(tt.data))
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: want %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/rank"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// maxRankLength is the key length past which a rank group gets its positions respread.
+const maxRankLength = 32
+
+// MoveTask places the task between two neighbours of the target column, either of which
+// may be uuid.Nil to move the task to the start or the end. An empty status keeps the task
+// in its current column, otherwise status and position change in one update.
+// Only the moved task is updated.
+func (ts *Service) MoveTask(ctx context.Context, taskID, authorID, beforeID, afterID uuid.UUID, status string) (string, error) {
+	const op = "task.MoveTask"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("task_id", taskID.String()),
+	)
+
+	log.Info("moving task")
+
+	task, err := ts.TaskProvider.GetTaskByID(ctx, taskID, authorID)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if status == "" {
+		status = task.Status
+	}
+
+	if _, ok := ts.boardColumn(status); !ok {
+		return "", fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidStatus, status)
+	}
+
+	group := models.RankGroup{AuthorID: authorID, ProjectID: task.ProjectID, Status: status}
+	position, err := ts.positionBetween(ctx, group, taskID, beforeID, afterID)
+	if err != nil {
+		if !errors.Is(err, my_err.ErrInvalidNeighbour) && !errors.Is(err, my_err.ErrStaleNeighbours) {
+			log.Error("failed to compute task position", slog.String("error", err.Error()))
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if status == task.Status {
+		err = ts.TaskProvider.SetTaskPosition(ctx, taskID, authorID, position)
+	} else {
+		err = ts.TaskProvider.SetTaskPlacement(ctx, taskID, authorID, status, position)
+	}
+	if err != nil {
+		log.Error("failed to set task position", slog.String("error", err.Error()))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, taskID)
+
+	return position, nil
+}
+
+// positionBetween computes a key between the neighbours in the given rank group, ignoring the moved task itself.
+// Duplicate keys left behind by concurrent writers are repaired by respreading the group once.
+func (ts *Service) positionBetween(ctx context.Context, group models.RankGroup, taskID, beforeID, afterID uuid.UUID) (string, error) {
+	for attempt := 0; ; attempt++ {
+		ranks, err := ts.TaskProvider.GetTaskRanks(ctx, group)
+		if err != nil {
+			return "", err
+		}
+
+		ranks = slices.DeleteFunc(ranks, func(r models.TaskRank) bool { return r.ID == taskID })
+
+		lower, upper, err := neighbourPositions(ranks, beforeID, afterID)
+		if err != nil {
+			return "", err
+		}
+
+		position, err := rank.Between(lower, upper)
+		if err == nil {
+			return position, nil
+		}
+
+		if attempt > 0 || !errors.Is(err, rank.ErrInvalidRange) {
+			return "", err
+		}
+
+		if err := ts.rebalanceGroup(ctx, group); err != nil {
+			return "", err
+		}
+	}
+}
+
+func neighbourPositions(ranks []models.TaskRank, beforeID, afterID uuid.UUID) (string, string, error) {
+	indexOf := func(id uuid.UUID) int {
+		return slices.IndexFunc(ranks, func(r models.TaskRank) bool { return r.ID == id })
+	}
+
+	switch {
+	case beforeID != uuid.Nil && afterID != uuid.Nil:
+		i, j := indexOf(beforeID), indexOf(afterID)
+		if i < 0 || j < 0 {
+			return "", "", my_err.ErrInvalidNeighbour
+		}
+		if j != i+1 {
+			return "", "", my_err.ErrStaleNeighbours
+		}
+		return ranks[i].Position, ranks[j].Position, nil
+	case beforeID != uuid.Nil:
+		i := indexOf(beforeID)
+		if i < 0 {
+			return "", "", my_err.ErrInvalidNeighbour
+		}
+		if i+1 < len(ranks) {
+			return ranks[i].Position, ranks[i+1].Position, nil
+		}
+		return ranks[i].Position, "", nil
+	case afterID != uuid.Nil:
+		j := indexOf(afterID)
+		if j < 0 {
+			return "", "", my_err.ErrInvalidNeighbour
+		}
+		if j > 0 {
+			return ranks[j-1].Position, ranks[j].Position, nil
+		}
+		return "", ranks[j].Position, nil
+	default:
+		if len(ranks) > 0 {
+			return "", "", my_err.ErrInvalidNeighbour
+		}
+		return "", "", nil
+	}
+}
+
+// nextPosition returns a key that sorts after every task of the rank group.
+func (ts *Service) nextPosition(ctx context.Context, group models.RankGroup) (string, error) {
+	last, err := ts.TaskProvider.LastTaskPosition(ctx, group)
+	if err != nil {
+		return "", err
+	}
+
+	return rank.Between(last, "")
+}
+
+// RebalancePositions respreads every rank group whose keys grew longer than maxRankLength.
+func (ts *Service) RebalancePositions(ctx context.Context) error {
+	const op = "task.RebalancePositions"
+
+	log := ts.logger.With(slog.String("op", op))
+
+	groups, err := ts.TaskProvider.GetOverlongRankGroups(ctx, maxRankLength)
+	if err != nil {
+		log.Error("failed to find groups to rebalance", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, group := range groups {
+		if err := ts.rebalanceGroup(ctx, group); err != nil {
+			log.Error("failed to rebalance group",
+				slog.String("author_id", group.AuthorID.String()),
+				slog.String("status", group.Status),
+				slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if len(groups) > 0 {
+		log.Info("rebalanced task positions", slog.Int("groups", len(groups)))
+	}
+
+	return nil
+}
+
+// RunRebalancer calls RebalancePositions every interval until ctx is cancelled.
+func (ts *Service) RunRebalancer(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = ts.RebalancePositions(ctx)
+		}
+	}
+}
+
+func (ts *Service) rebalanceGroup(ctx context.Context, group models.RankGroup) error {
+	ranks, err := ts.TaskProvider.GetTaskRanks(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	for i, key := range rank.Spread(len(ranks)) {
+		ranks[i].Position = key
+	}
+
+	if err := ts.TaskProvider.SetTaskPositions(ctx, group.AuthorID, ranks); err != nil {
+		return err
+	}
+
+	// The order stays, but watchers placing tasks by key need the new keys.
+	taskIDs := make([]uuid.UUID, len(ranks))
+	for i, r := range ranks {
+		taskIDs[i] = r.ID
+	}
+	ts.publishTasks(ctx, models.TaskEventUpdated, group.AuthorID, taskIDs...)
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const maxProjectNameLength = 100
+
+type ProjectProvider interface {
+	CreateProject(ctx context.Context, project *models.Project) error
+	GetProjects(ctx context.Context, author uuid.UUID) ([]*models.Project, error)
+	ProjectExists(ctx context.Context, projectID, author uuid.UUID) error
+
+	CountBoardTasks(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID) (map[string]int, error)
+	GetColumnTasks(ctx context.Context, group models.RankGroup, after models.BoardCursor, limit int) ([]*models.Task, error)
+}
+
+func (ts *Service) CreateProject(ctx context.Context, authorID uuid.UUID, name string) (*models.Project, error) {
+	const op = "task.CreateProject"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.String("name", name),
+	)
+
+	log.Info("creating project")
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxProjectNameLength {
+		return nil, fmt.Errorf("%s: %w: name must be 1 to %d characters", op, my_err.ErrInvalidProjectName, maxProjectNameLength)
+	}
+
+	project := &models.Project{
+		ID:        uuid.New(),
+		AuthorID:  authorID,
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := ts.ProjectProvider.CreateProject(ctx, project); err != nil {
+		if !errors.Is(err, my_err.ErrProjectExists) {
+			log.Error("failed to create project", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return project, nil
+}
+
+func (ts *Service) ListProjects(ctx context.Context, authorID uuid.UUID) ([]*models.Project, error) {
+	const op = "task.ListProjects"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("listing projects")
+
+	projects, err := ts.ProjectProvider.GetProjects(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get projects", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	efforts, err := ts.effortByProject(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get project effort", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, project := range projects {
+		project.Effort = models.RollUpEffort(efforts[uuid.NullUUID{UUID: project.ID, Valid: true}])
+	}
+
+	return projects, nil
+}
@@ -0,0 +1,66 @@
+package task_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/quickadd"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const maxQuickAddLength = 500
+
+// QuickAdd creates a task from a line of text such as "Pay rent every month on the 1st #home !high".
+// Dates and times are read in the given IANA timezone, an empty timezone means UTC. It returns the
+// created task along with what the parser understood.
+func (ts *Service) QuickAdd(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, text, timezone string) (*models.Task, *models.QuickAddParse, error) {
+	const op = "task.QuickAdd"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("quick adding task")
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidTimezone, timezone)
+	}
+
+	text = strings.TrimSpace(text)
+	if len(text) > maxQuickAddLength {
+		return nil, nil, fmt.Errorf("%s: %w: text is longer than %d bytes", op, my_err.ErrInvalidQuickAdd, maxQuickAddLength)
+	}
+
+	parse := quickadd.Parse(text, time.Now().In(loc))
+	if parse.Title == "" {
+		return nil, nil, fmt.Errorf("%s: %w", op, my_err.ErrEmptyTitle)
+	}
+
+	task := &models.Task{
+		ID:         uuid.New(),
+		AuthorID:   authorID,
+		ProjectID:  projectID,
+		Title:      parse.Title,
+		Status:     models.StatusToDo,
+		Deadline:   parse.Deadline,
+		DueDate:    parse.DueDate,
+		Priority:   parse.Priority,
+		Tags:       parse.Tags,
+		Recurrence: parse.Recurrence,
+		Assignee:   parse.Assignee,
+	}
+
+	if err := ts.insertTask(ctx, log, task); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return task, &parse, nil
+}
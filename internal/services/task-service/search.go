@@ -0,0 +1,47 @@
+package task_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+func (ts *Service) SearchTasks(ctx context.Context, authorID uuid.UUID, query string, limit, offset int) ([]*models.SearchHit, error) {
+	const op = "task.SearchTasks"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("searching tasks")
+
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("%s: %w", op, my_err.ErrEmptyQuery)
+	}
+
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+	offset = max(offset, 0)
+
+	hits, err := ts.TaskProvider.SearchTasks(ctx, authorID, query, limit, offset)
+	if err != nil {
+		log.Error("failed to search tasks", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,427 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// defaultChangeRetention is how long deletions are remembered for clients to sync them.
+const defaultChangeRetention = 30 * 24 * time.Hour
+
+// syncFields are the fields offline changes may set, in the order conflicts are reported.
+var syncFields = []string{
+	models.SyncFieldTitle,
+	models.SyncFieldDescription,
+	models.SyncFieldStatus,
+	models.SyncFieldDue,
+	models.SyncFieldPriority,
+	models.SyncFieldTags,
+	models.SyncFieldRecurrence,
+	models.SyncFieldProject,
+}
+
+type SyncProvider interface {
+	GetTaskClocks(ctx context.Context, taskID, author uuid.UUID) (map[string]time.Time, error)
+	CreateSyncedTask(ctx context.Context, task *models.Task, fields []string, changedAt time.Time) error
+	UpdateSyncedTask(ctx context.Context, task *models.Task, fields []string, changedAt time.Time) error
+	TaskDeleted(ctx context.Context, taskID, author uuid.UUID) (bool, error)
+	GetChangedTasks(ctx context.Context, author uuid.UUID, after, upTo int64) ([]uuid.UUID, error)
+	GetChangeHorizon(ctx context.Context, author uuid.UUID) (int64, error)
+	PruneTaskChanges(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Sync applies the changes a client made offline and returns what changed on the server since
+// syncToken, 0 on the first sync. clientTime is the time on the clock of the client when it sent
+// the changes, it moves the times of the changes onto the server clock.
+//
+// Changes merge field by field: a field written on the server after the client changed it keeps
+// the server value and the change is reported as a conflict. Deleting a task wins over changes to
+// it the server has not seen, a client deleting a task that was changed since loses against them.
+// Up to maxBatchSize changes go in one sync.
+func (ts *Service) Sync(ctx context.Context, authorID uuid.UUID, syncToken int64, clientTime time.Time, changes []models.TaskChange) (*models.SyncResult, error) {
+	const op = "task.Sync"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.Int64("sync_token", syncToken),
+		slog.Int("changes", len(changes)),
+	)
+
+	log.Info("syncing tasks")
+
+	if len(changes) > maxBatchSize {
+		return nil, fmt.Errorf("%s: %w: a sync holds up to %d changes", op, my_err.ErrInvalidSync, maxBatchSize)
+	}
+
+	// Sync tokens number the changes of the log CalDAV syncs with.
+	current, err := ts.CalDAVProvider.GetCalendarSyncToken(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get sync token", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if syncToken < 0 || syncToken > current {
+		return nil, fmt.Errorf("%s: %w", op, my_err.ErrInvalidSyncToken)
+	}
+
+	horizon, err := ts.SyncProvider.GetChangeHorizon(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get change horizon", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	var skew time.Duration
+	if !clientTime.IsZero() {
+		skew = now.Sub(clientTime)
+	}
+
+	result := &models.SyncResult{
+		Full:       syncToken == 0 || syncToken < horizon,
+		Results:    make([]models.TaskChangeResult, len(changes)),
+		ServerTime: now,
+	}
+
+	projects := make(map[uuid.UUID]error)
+	var conflicted []uuid.UUID
+	for idx, change := range changes {
+		changeResult, err := ts.applyTaskChange(ctx, authorID, change, changeTime(change.ChangedAt, skew, now), projects)
+		if err != nil {
+			log.Error("failed to apply change", slog.String("task_id", change.TaskID.String()), slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: change %d: %w", op, idx, err)
+		}
+		result.Results[idx] = changeResult
+
+		if changeResult.Status == models.SyncConflict {
+			conflicted = append(conflicted, change.TaskID)
+		}
+	}
+
+	upTo, err := ts.CalDAVProvider.GetCalendarSyncToken(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get sync token", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	result.SyncToken = upTo
+
+	if result.Full {
+		if result.Changed, err = ts.TaskProvider.GetTask(ctx, authorID); err != nil {
+			log.Error("failed to get tasks", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, task := range result.Changed {
+			summarizeTask(task)
+		}
+
+		return result, nil
+	}
+
+	taskIDs, err := ts.SyncProvider.GetChangedTasks(ctx, authorID, syncToken, upTo)
+	if err != nil {
+		log.Error("failed to get changed tasks", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Clients learn how their conflicts ended, even against changes they already had: a task
+	// deleted before syncToken is reported deleted again.
+	for _, taskID := range conflicted {
+		if !slices.Contains(taskIDs, taskID) {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+
+	tasks, err := ts.loadTasks(ctx, authorID, taskIDs)
+	if err != nil {
+		log.Error("failed to load changed tasks", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	live := make(map[uuid.UUID]bool, len(tasks))
+	for _, task := range tasks {
+		summarizeTask(task)
+		live[task.ID] = true
+	}
+	result.Changed = tasks
+
+	for _, taskID := range taskIDs {
+		if !live[taskID] {
+			result.Deleted = append(result.Deleted, taskID)
+		}
+	}
+
+	return result, nil
+}
+
+// changeTime moves the time a client made a change onto the server clock, to the millisecond
+// the server keeps. Changes without a time, or from the future, are made now.
+func changeTime(changedAt time.Time, skew time.Duration, now time.Time) time.Time {
+	t := changedAt.Add(skew)
+	if changedAt.IsZero() || t.After(now) {
+		t = now
+	}
+
+	return time.UnixMilli(t.UnixMilli())
+}
+
+// applyTaskChange merges one change made at changedAt. Invalid changes are rejected in the result,
+// the error is for failures of the service.
+func (ts *Service) applyTaskChange(ctx context.Context, authorID uuid.UUID, change models.TaskChange, changedAt time.Time, projects map[uuid.UUID]error) (models.TaskChangeResult, error) {
+	result := models.TaskChangeResult{ChangeID: change.ChangeID, TaskID: change.TaskID, Status: models.SyncAccepted}
+
+	if err := validateTaskChange(change); err != nil {
+		result.Status, result.Err = models.SyncRejected, err
+		return result, nil
+	}
+
+	current, err := ts.TaskProvider.GetTaskByID(ctx, change.TaskID, authorID)
+	if errors.Is(err, my_err.ErrTaskNotFound) {
+		deleted, err := ts.SyncProvider.TaskDeleted(ctx, change.TaskID, authorID)
+		if err != nil {
+			return result, err
+		}
+
+		switch {
+		case change.Deleted:
+			// Deleting twice deletes once.
+		case deleted:
+			result.Status, result.Conflicts = models.SyncConflict, change.Fields
+		default:
+			return ts.createSyncedTask(ctx, authorID, change, changedAt, projects, result)
+		}
+
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	clocks, err := ts.SyncProvider.GetTaskClocks(ctx, change.TaskID, authorID)
+	if err != nil {
+		return result, err
+	}
+
+	if change.Deleted {
+		for _, field := range syncFields {
+			if clocks[field].After(changedAt) {
+				result.Conflicts = append(result.Conflicts, field)
+			}
+		}
+		if len(result.Conflicts) > 0 {
+			result.Status = models.SyncConflict
+			return result, nil
+		}
+
+		if err := ts.DeleteTask(ctx, change.TaskID, authorID); err != nil && !errors.Is(err, my_err.ErrTaskNotFound) {
+			return result, err
+		}
+
+		return result, nil
+	}
+
+	task := *current
+	var applied []string
+	for _, field := range change.Fields {
+		if clocks[field].After(changedAt) {
+			result.Conflicts = append(result.Conflicts, field)
+			continue
+		}
+		setSyncField(&task, change.Task, field)
+		applied = append(applied, field)
+	}
+
+	if len(result.Conflicts) > 0 {
+		result.Status = models.SyncConflict
+	}
+	if len(applied) == 0 {
+		return result, nil
+	}
+
+	if err := ts.validateSyncedTask(ctx, &task, projects); err != nil {
+		result.Status, result.Conflicts, result.Err = models.SyncRejected, nil, err
+		return result, nil
+	}
+
+	// Like any other move between columns, a new status or project puts the task at the bottom of its column.
+	if task.Status != current.Status || task.ProjectID != current.ProjectID {
+		position, err := ts.nextPosition(ctx, models.RankGroup{AuthorID: authorID, ProjectID: task.ProjectID, Status: task.Status})
+		if err != nil {
+			return result, err
+		}
+		task.Position = position
+	}
+
+	if err := ts.SyncProvider.UpdateSyncedTask(ctx, &task, applied, changedAt); err != nil {
+		return result, err
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, task.ID)
+
+	return result, nil
+}
+
+// createSyncedTask creates the task of a change to a task the server never had.
+func (ts *Service) createSyncedTask(ctx context.Context, authorID uuid.UUID, change models.TaskChange, changedAt time.Time, projects map[uuid.UUID]error, result models.TaskChangeResult) (models.TaskChangeResult, error) {
+	task := &models.Task{
+		ID:       change.TaskID,
+		AuthorID: authorID,
+		Status:   models.StatusToDo,
+		Priority: models.PriorityNone,
+	}
+	for _, field := range change.Fields {
+		setSyncField(task, change.Task, field)
+	}
+
+	if err := ts.validateSyncedTask(ctx, task, projects); err != nil {
+		result.Status, result.Err = models.SyncRejected, err
+		return result, nil
+	}
+
+	position, err := ts.nextPosition(ctx, models.RankGroup{AuthorID: authorID, ProjectID: task.ProjectID, Status: task.Status})
+	if err != nil {
+		return result, err
+	}
+	task.Position = position
+
+	if err := ts.SyncProvider.CreateSyncedTask(ctx, task, change.Fields, changedAt); err != nil {
+		if errors.Is(err, my_err.ErrTaskIDTaken) {
+			result.Status, result.Err = models.SyncRejected, my_err.ErrTaskIDTaken
+			return result, nil
+		}
+		return result, err
+	}
+
+	ts.publishTasks(ctx, models.TaskEventCreated, authorID, task.ID)
+
+	return result, nil
+}
+
+// validateTaskChange checks the shape of a change, its values are checked once merged.
+func validateTaskChange(change models.TaskChange) error {
+	if change.TaskID == uuid.Nil {
+		return fmt.Errorf("%w: the change has no task ID", my_err.ErrInvalidSync)
+	}
+
+	if change.Deleted {
+		return nil
+	}
+
+	if len(change.Fields) == 0 || change.Task == nil {
+		return fmt.Errorf("%w: the change sets no field", my_err.ErrInvalidSync)
+	}
+
+	for idx, field := range change.Fields {
+		if !slices.Contains(syncFields, field) {
+			return fmt.Errorf("%w: unknown field %q", my_err.ErrInvalidSync, field)
+		}
+		if slices.Contains(change.Fields[:idx], field) {
+			return fmt.Errorf("%w: field %q is set twice", my_err.ErrInvalidSync, field)
+		}
+	}
+
+	return nil
+}
+
+func setSyncField(task, from *models.Task, field string) {
+	switch field {
+	case models.SyncFieldTitle:
+		task.Title = strings.TrimSpace(from.Title)
+	case models.SyncFieldDescription:
+		task.Description = from.Description
+	case models.SyncFieldStatus:
+		task.Status = from.Status
+	case models.SyncFieldDue:
+		task.Deadline, task.DueDate = from.Deadline.UTC(), from.DueDate
+	case models.SyncFieldPriority:
+		task.Priority = from.Priority
+	case models.SyncFieldTags:
+		task.Tags = from.Tags
+	case models.SyncFieldRecurrence:
+		task.Recurrence = from.Recurrence
+	case models.SyncFieldProject:
+		task.ProjectID = from.ProjectID
+	}
+}
+
+// validateSyncedTask checks a task merged from offline changes, projects caches the lookups of its project.
+func (ts *Service) validateSyncedTask(ctx context.Context, task *models.Task, projects map[uuid.UUID]error) error {
+	if task.Title == "" {
+		return my_err.ErrEmptyTitle
+	}
+
+	if _, ok := ts.boardColumn(task.Status); !ok {
+		return fmt.Errorf("%w: %q", my_err.ErrInvalidStatus, task.Status)
+	}
+
+	if err := validateDue(task.Deadline, task.DueDate); err != nil {
+		return err
+	}
+
+	if !validPriority(task.Priority) {
+		return fmt.Errorf("%w: unknown priority %q", my_err.ErrInvalidSync, task.Priority)
+	}
+
+	tags, err := normalizeTags(task.Tags)
+	if err != nil {
+		return fmt.Errorf("%w: %s", my_err.ErrInvalidSync, err)
+	}
+	task.Tags = tags
+
+	if task.ProjectID.Valid {
+		err, ok := projects[task.ProjectID.UUID]
+		if !ok {
+			err = ts.ProjectProvider.ProjectExists(ctx, task.ProjectID.UUID, task.AuthorID)
+			projects[task.ProjectID.UUID] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PruneChanges forgets the changes older than the change retention, except the last change of
+// every task still there. Clients that synced before a pruned deletion sync from scratch.
+func (ts *Service) PruneChanges(ctx context.Context) error {
+	const op = "task.PruneChanges"
+
+	log := ts.logger.With(slog.String("op", op))
+
+	pruned, err := ts.SyncProvider.PruneTaskChanges(ctx, time.Now().Add(-ts.changeRetention))
+	if err != nil {
+		log.Error("failed to prune changes", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pruned > 0 {
+		log.Info("pruned task changes", slog.Int64("changes", pruned))
+	}
+
+	return nil
+}
+
+// RunChangePruner calls PruneChanges every interval until ctx is cancelled.
+func (ts *Service) RunChangePruner(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = ts.PruneChanges(ctx)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package task_service_test
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/internal/storage/blob/local"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+)
+
+// titleChange is a change of a client setting the title of the task at changedAt.
+func titleChange(id uuid.UUID, title string, changedAt time.Time) models.TaskChange {
+	return models.TaskChange{ChangeID: title, TaskID: id, Fields: []string{models.SyncFieldTitle},
+		Task: &models.Task{Title: title}, ChangedAt: changedAt}
+}
+
+func TestSyncMergesFieldByField(t *testing.T) {
+	ctx := t.Context()
+	service, _ := newService(t, task_service.Settings{})
+	author := uuid.New()
+
+	created, err := service.CreateTask(ctx, author, uuid.NullUUID{}, "Write report", "", "", time.Time{}, "")
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	id := uuid.MustParse(created)
+
+	// Two clients went offline an hour ago and changed the task in the order of these times.
+	start := time.Now().Add(-time.Hour)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		name          string
+		change        models.TaskChange
+		wantStatus    string
+		wantConflicts []string
+		// want checks the task after the change.
+		want func(task *models.Task) bool
+	}{
+		{
+			name:       "title by the first client",
+			change:     titleChange(id, "Write the report", at(20)),
+			wantStatus: models.SyncAccepted,
+			want:       func(task *models.Task) bool { return task.Title == "Write the report" },
+		},
+		{
+			name: "priority by the second client, made before the title",
+			change: models.TaskChange{ChangeID: "priority", TaskID: id, Fields: []string{models.SyncFieldPriority},
+				Task: &models.Task{Priority: models.PriorityHigh}, ChangedAt: at(10)},
+			wantStatus: models.SyncAccepted,
+			want: func(task *models.Task) bool {
+				return task.Title == "Write the report" && task.Priority == models.PriorityHigh
+			},
+		},
+		{
+			name:          "title by the second client, made before the first",
+			change:        titleChange(id, "Draft report", at(10)),
+			wantStatus:    models.SyncConflict,
+			wantConflicts: []string{models.SyncFieldTitle},
+			want:          func(task *models.Task) bool { return task.Title == "Write the report" },
+		},
+		{
+			name:       "title by the second client, made after the first",
+			change:     titleChange(id, "Final report", at(30)),
+			wantStatus: models.SyncAccepted,
+			want:       func(task *models.Task) bool { return task.Title == "Final report" },
+		},
+		{
+			name: "title and description, the title made before the last one",
+			change: models.TaskChange{ChangeID: "both", TaskID: id, Fields: []string{models.SyncFieldTitle, models.SyncFieldDescription},
+				Task: &models.Task{Title: "Draft report", Description: "for Monday"}, ChangedAt: at(25)},
+			wantStatus:    models.SyncConflict,
+			wantConflicts: []string{models.SyncFieldTitle},
+			want: func(task *models.Task) bool {
+				return task.Title == "Final report" && task.Description == "for Monday"
+			},
+		},
+		{
+			name:          "deletion made before the last changes",
+			change:        models.TaskChange{ChangeID: "delete", TaskID: id, Deleted: true, ChangedAt: at(28)},
+			wantStatus:    models.SyncConflict,
+			wantConflicts: []string{models.SyncFieldTitle},
+			want:          func(task *models.Task) bool { return task != nil },
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := service.Sync(ctx, author, 0, time.Now(), []models.TaskChange{tt.change})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		got := result.Results[0]
+		if got.Status != tt.wantStatus || !slices.Equal(got.Conflicts, tt.wantConflicts) || got.Err != nil {
+			t.Errorf("%s: want %s with conflicts %v, got %+v", tt.name, tt.wantStatus, tt.wantConflicts, got)
+		}
+
+		var task *models.Task
+		for _, changed := range result.Changed {
+			if changed.ID == id {
+				task = changed
+			}
+		}
+		if !tt.want(task) {
+			t.Errorf("%s: unexpected task %+v", tt.name, task)
+		}
+	}
+
+	// A write on the server is as recent as it gets, an offline change to its field loses to it.
+	tasks, err := service.GetTasks(ctx, author)
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	task := tasks[0]
+	task.Title = "Report"
+	if err := service.UpdateTask(ctx, task); err != nil {
+		t.Fatalf("update task: %v", err)
+	}
+
+	result, err := service.Sync(ctx, author, 0, time.Now(), []models.TaskChange{titleChange(id, "Old report", at(59))})
+	if err != nil {
+		t.Fatalf("sync after server write: %v", err)
+	}
+	if got := result.Results[0]; got.Status != models.SyncConflict || result.Changed[0].Title != "Report" {
+		t.Errorf("want the server write kept, got %+v and title %q", got, result.Changed[0].Title)
+	}
+
+	result, err = service.Sync(ctx, author, 0, time.Now(),
+		[]models.TaskChange{{ChangeID: "delete", TaskID: id, Deleted: true, ChangedAt: time.Now()}})
+	if err != nil {
+		t.Fatalf("sync deletion: %v", err)
+	}
+	if got := result.Results[0]; got.Status != models.SyncAccepted || len(result.Changed) != 0 {
+		t.Errorf("want the task deleted by a deletion made after every change, got %+v and %v", got, result.Changed)
+	}
+
+	// A client that did not see the deletion changes the task.
+	result, err = service.Sync(ctx, author, 0, time.Now(), []models.TaskChange{titleChange(id, "Lost report", at(40))})
+	if err != nil {
+		t.Fatalf("sync change of deleted task: %v", err)
+	}
+	if got := result.Results[0]; got.Status != models.SyncConflict || len(result.Changed) != 0 {
+		t.Errorf("want the deletion kept over the change, got %+v and %v", got, result.Changed)
+	}
+}
+
+func TestSyncBehindPrunedHorizon(t *testing.T) {
+	ctx := t.Context()
+
+	blobs, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := memory.New()
+	service := task_service.New(s, blobs, nil, task_service.Settings{}, slog.New(slog.DiscardHandler))
+	author := uuid.New()
+
+	var ids []uuid.UUID
+	for _, title := range []string{"Write report", "Send report"} {
+		id, err := service.CreateTask(ctx, author, uuid.NullUUID{}, title, "", "", time.Time{}, "")
+		if err != nil {
+			t.Fatalf("create task %q: %v", title, err)
+		}
+		ids = append(ids, uuid.MustParse(id))
+	}
+
+	first, err := service.Sync(ctx, author, 0, time.Now(), nil)
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if !first.Full || len(first.Changed) != 2 {
+		t.Fatalf("want the first sync full with both tasks, got %+v", first)
+	}
+
+	if err := service.DeleteTask(ctx, ids[1], author); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+
+	behind, err := service.Sync(ctx, author, first.SyncToken, time.Now(), nil)
+	if err != nil {
+		t.Fatalf("sync before prune: %v", err)
+	}
+	if behind.Full || len(behind.Changed) != 0 || !slices.Equal(behind.Deleted, ids[1:]) {
+		t.Fatalf("want the deletion alone, got %+v", behind)
+	}
+
+	// A month passes and the tombstone is pruned.
+	if _, err := s.PruneTaskChanges(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("prune changes: %v", err)
+	}
+
+	// The client that synced before the deletion cannot learn of it, it gets every task anew.
+	// Its changes are still applied.
+	result, err := service.Sync(ctx, author, first.SyncToken, time.Now(), []models.TaskChange{titleChange(ids[0], "Write the report", time.Now())})
+	if err != nil {
+		t.Fatalf("sync behind horizon: %v", err)
+	}
+	if !result.Full || len(result.Deleted) != 0 || result.Results[0].Status != models.SyncAccepted {
+		t.Fatalf("want a full sync with the change accepted, got %+v", result)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].ID != ids[0] || result.Changed[0].Title != "Write the report" {
+		t.Errorf("want the remaining task as changed, got %+v", result.Changed)
+	}
+
+	// The client that synced after the deletion goes on from its token.
+	result, err = service.Sync(ctx, author, behind.SyncToken, time.Now(), nil)
+	if err != nil {
+		t.Fatalf("sync after deletion: %v", err)
+	}
+	if result.Full || len(result.Deleted) != 0 || len(result.Changed) != 1 || result.Changed[0].ID != ids[0] {
+		t.Errorf("want only the task changed since the token, got %+v", result)
+	}
+}
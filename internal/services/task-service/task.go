@@ -2,35 +2,171 @@ package task_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 
 	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/pubsub"
+	"github.com/SlashLight/todo-list/pkg/my_err"
 )
 
 type TaskProvider interface {
 	CreateTask(ctx context.Context, task *models.Task) error
+	CreateTasks(ctx context.Context, tasks []*models.Task) error
 	GetTask(ctx context.Context, author uuid.UUID) ([]*models.Task, error)
+	GetTaskPage(ctx context.Context, author uuid.UUID, after models.BoardCursor, limit int) ([]*models.Task, error)
 	UpdateTask(ctx context.Context, newTask *models.Task) error
 	DeleteTask(ctx context.Context, taskID, author uuid.UUID) error
+	GetTaskByID(ctx context.Context, taskID, author uuid.UUID) (*models.Task, error)
+	TaskExists(ctx context.Context, taskID, author uuid.UUID) error
+	SearchTasks(ctx context.Context, author uuid.UUID, query string, limit, offset int) ([]*models.SearchHit, error)
+	SetTaskEstimate(ctx context.Context, taskID, author uuid.UUID, minutes *int) error
+	GetEffortByStatus(ctx context.Context, author uuid.UUID) ([]models.StatusEffort, error)
+
+	LastTaskPosition(ctx context.Context, group models.RankGroup) (string, error)
+	GetTaskRanks(ctx context.Context, group models.RankGroup) ([]models.TaskRank, error)
+	SetTaskPosition(ctx context.Context, taskID, author uuid.UUID, position string) error
+	SetTaskPlacement(ctx context.Context, taskID, author uuid.UUID, status, position string) error
+	SetTaskPositions(ctx context.Context, author uuid.UUID, ranks []models.TaskRank) error
+	GetOverlongRankGroups(ctx context.Context, maxLength int) ([]models.RankGroup, error)
+}
+
+// Settings holds the tunables of the service.
+type Settings struct {
+	// AttachmentQuota is the total attachment size allowed per user, in bytes.
+	AttachmentQuota int64
+	// BoardColumns are the kanban columns in display order, defaultBoardColumns when empty.
+	BoardColumns []models.BoardColumn
+	// EventHistory is how many task events are kept for watchers to resume after.
+	EventHistory int
+	// ChangeRetention is how long deletions are remembered for clients to sync, defaultChangeRetention when zero.
+	ChangeRetention time.Duration
+	// WebhookMaxAttempts is how many times a webhook delivery is attempted, defaultWebhookMaxAttempts when zero.
+	WebhookMaxAttempts int
+	// WebhookDisableAfter is how many deliveries in a row may fail before their webhook is disabled,
+	// defaultWebhookDisableAfter when zero.
+	WebhookDisableAfter int
+	// WebhookTimeout is how long the sender waits for an endpoint, defaultWebhookTimeout when zero.
+	WebhookTimeout time.Duration
 }
 
 type Service struct {
-	TaskProvider TaskProvider
-	logger       *slog.Logger
+	TaskProvider       TaskProvider
+	ChecklistProvider  ChecklistProvider
+	ViewProvider       ViewProvider
+	AttachmentProvider AttachmentProvider
+	ProjectProvider    ProjectProvider
+	TimeEntryProvider  TimeEntryProvider
+	TemplateProvider   TemplateProvider
+	BatchProvider      BatchProvider
+	ImportProvider     ImportProvider
+	CalendarProvider   CalendarProvider
+	CalDAVProvider     CalDAVProvider
+	SyncProvider       SyncProvider
+	WebhookProvider    WebhookProvider
+	AccountProvider    AccountProvider
+	blobStore          BlobStore
+	webhookSender      WebhookSender
+	attachmentQuota    int64
+	boardColumns       []models.BoardColumn
+	changeRetention    time.Duration
+	events             *pubsub.Broker
+	// webhookLease is how long a claimed delivery is held back from other attempts.
+	webhookLease        time.Duration
+	webhookMaxAttempts  int
+	webhookDisableAfter int
+	webhookWake         chan struct{}
+	logger              *slog.Logger
+}
+
+// Storage is everything the service keeps in the database, a single backend implements it all.
+type Storage interface {
+	TaskProvider
+	ChecklistProvider
+	ViewProvider
+	AttachmentProvider
+	ProjectProvider
+	TimeEntryProvider
+	TemplateProvider
+	BatchProvider
+	ImportProvider
+	CalendarProvider
+	CalDAVProvider
+	SyncProvider
+	WebhookProvider
+	AccountProvider
 }
 
-func New(taskProvider TaskProvider, log *slog.Logger) *Service {
+func New(storage Storage, blobStore BlobStore, webhookSender WebhookSender, settings Settings, log *slog.Logger) *Service {
+	boardColumns := settings.BoardColumns
+	if len(boardColumns) == 0 {
+		boardColumns = defaultBoardColumns
+	}
+
+	eventHistory := settings.EventHistory
+	if eventHistory <= 0 {
+		eventHistory = defaultEventHistory
+	}
+
+	changeRetention := settings.ChangeRetention
+	if changeRetention <= 0 {
+		changeRetention = defaultChangeRetention
+	}
+
+	webhookMaxAttempts := settings.WebhookMaxAttempts
+	if webhookMaxAttempts <= 0 {
+		webhookMaxAttempts = defaultWebhookMaxAttempts
+	}
+
+	webhookDisableAfter := settings.WebhookDisableAfter
+	if webhookDisableAfter <= 0 {
+		webhookDisableAfter = defaultWebhookDisableAfter
+	}
+
+	webhookTimeout := settings.WebhookTimeout
+	if webhookTimeout <= 0 {
+		webhookTimeout = defaultWebhookTimeout
+	}
+
 	return &Service{
-		TaskProvider: taskProvider,
-		logger:       log,
+		TaskProvider:        storage,
+		ChecklistProvider:   storage,
+		ViewProvider:        storage,
+		AttachmentProvider:  storage,
+		ProjectProvider:     storage,
+		TimeEntryProvider:   storage,
+		TemplateProvider:    storage,
+		BatchProvider:       storage,
+		ImportProvider:      storage,
+		CalendarProvider:    storage,
+		CalDAVProvider:      storage,
+		SyncProvider:        storage,
+		WebhookProvider:     storage,
+		AccountProvider:     storage,
+		blobStore:           blobStore,
+		webhookSender:       webhookSender,
+		attachmentQuota:     settings.AttachmentQuota,
+		boardColumns:        boardColumns,
+		changeRetention:     changeRetention,
+		events:              pubsub.New(eventHistory),
+		webhookLease:        webhookTimeout + time.Minute,
+		webhookMaxAttempts:  webhookMaxAttempts,
+		webhookDisableAfter: webhookDisableAfter,
+		webhookWake:         make(chan struct{}, 1),
+		logger:              log,
 	}
 }
 
-func (ts *Service) CreateTask(ctx context.Context, authorID uuid.UUID, title, description string, deadline time.Time) (string, error) {
+// CreateTask adds a task at the bottom of the column of status, to-do when status is empty. An invalid
+// projectID puts the task in the inbox. The task may have a deadline or an all-day due date, not both.
+func (ts *Service) CreateTask(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, title, description, status string, deadline time.Time, dueDate string) (string, error) {
 	const op = "task.CreateTask"
 
 	log := ts.logger.With(
@@ -40,24 +176,63 @@ func (ts *Service) CreateTask(ctx context.Context, authorID uuid.UUID, title, de
 
 	log.Info("creating task")
 
+	if status == "" {
+		status = models.StatusToDo
+	}
+
+	if _, ok := ts.boardColumn(status); !ok {
+		return "", fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidStatus, status)
+	}
+
 	task := &models.Task{
 		ID:          uuid.New(),
 		AuthorID:    authorID,
+		ProjectID:   projectID,
 		Title:       title,
 		Description: description,
-		Deadline:    deadline,
+		Status:      status,
+		Deadline:    deadline.UTC(),
+		DueDate:     dueDate,
+		Priority:    models.PriorityNone,
 	}
 
-	err := ts.TaskProvider.CreateTask(ctx, task)
-	if err != nil {
-		//TODO ...
-		log.Error("failed to create task", err)
+	if err := ts.insertTask(ctx, log, task); err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	return task.ID.String(), nil
 }
 
+// insertTask validates a new task and stores it at the bottom of its to-do column.
+func (ts *Service) insertTask(ctx context.Context, log *slog.Logger, task *models.Task) error {
+	if err := validateDue(task.Deadline, task.DueDate); err != nil {
+		return err
+	}
+
+	if task.ProjectID.Valid {
+		if err := ts.ProjectProvider.ProjectExists(ctx, task.ProjectID.UUID, task.AuthorID); err != nil {
+			return err
+		}
+	}
+
+	position, err := ts.nextPosition(ctx, models.RankGroup{AuthorID: task.AuthorID, ProjectID: task.ProjectID, Status: task.Status})
+	if err != nil {
+		log.Error("failed to compute task position", slog.String("error", err.Error()))
+		return err
+	}
+	task.Position = position
+
+	if err := ts.TaskProvider.CreateTask(ctx, task); err != nil {
+		//TODO ...
+		log.Error("failed to create task", slog.String("error", err.Error()))
+		return err
+	}
+
+	ts.publishTasks(ctx, models.TaskEventCreated, task.AuthorID, task.ID)
+
+	return nil
+}
+
 func (ts *Service) GetTasks(ctx context.Context, authorID uuid.UUID) ([]*models.Task, error) {
 	const op = "task.GetTask"
 
@@ -71,13 +246,19 @@ func (ts *Service) GetTasks(ctx context.Context, authorID uuid.UUID) ([]*models.
 	tasks, err := ts.TaskProvider.GetTask(ctx, authorID)
 	if err != nil {
 		//TODO ...
-		log.Error("failed to get task", err)
+		log.Error("failed to get task", slog.String("error", err.Error()))
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	for _, task := range tasks {
+		summarizeTask(task)
+	}
+
 	return tasks, nil
 }
 
+// UpdateTask replaces the title, description, status and due date of the task. An empty status keeps
+// the current one.
 func (ts *Service) UpdateTask(ctx context.Context, newTask *models.Task) error {
 	const op = "task.UpdateTask"
 
@@ -88,13 +269,45 @@ func (ts *Service) UpdateTask(ctx context.Context, newTask *models.Task) error {
 
 	log.Info("updating task")
 
-	err := ts.TaskProvider.UpdateTask(ctx, newTask)
+	if err := validateDue(newTask.Deadline, newTask.DueDate); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	current, err := ts.TaskProvider.GetTaskByID(ctx, newTask.ID, newTask.AuthorID)
 	if err != nil {
-		//TODO ...
-		log.Error("failed to update task", err)
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if newTask.Status == "" {
+		newTask.Status = current.Status
+	}
+
+	if _, ok := ts.boardColumn(newTask.Status); !ok {
+		return fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidStatus, newTask.Status)
+	}
+
+	// Like any other move between columns, a new status puts the task at the bottom of its column.
+	newTask.Position = current.Position
+	if newTask.Status != current.Status {
+		position, err := ts.nextPosition(ctx, models.RankGroup{AuthorID: newTask.AuthorID, ProjectID: current.ProjectID, Status: newTask.Status})
+		if err != nil {
+			log.Error("failed to compute task position", slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		newTask.Position = position
+	}
+
+	err = ts.TaskProvider.UpdateTask(ctx, newTask)
+	if err != nil {
+		if errors.Is(err, my_err.ErrTaskNotFound) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		log.Error("failed to update task", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, newTask.AuthorID, newTask.ID)
+
 	return nil
 }
 
@@ -108,11 +321,56 @@ func (ts *Service) DeleteTask(ctx context.Context, taskID, authorID uuid.UUID) e
 
 	log.Info("deleting task")
 
+	// Remember where the blobs live before the cascade removes their metadata.
+	attachments, err := ts.AttachmentProvider.GetAttachments(ctx, taskID, authorID)
+	if err != nil {
+		log.Error("failed to get task attachments", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err := ts.TaskProvider.DeleteTask(ctx, taskID, authorID); err != nil {
 		//TODO ...
-		log.Error("failed to delete task", err)
+		log.Error("failed to delete task", slog.String("error", err.Error()))
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	ts.removeBlobs(ctx, attachments)
+	ts.publishTasks(ctx, models.TaskEventDeleted, authorID, taskID)
+
+	return nil
+}
+
+// validateDue checks that a task has at most one of a deadline and an all-day due date,
+// and that the due date is a calendar date.
+func validateDue(deadline time.Time, dueDate string) error {
+	if dueDate == "" {
+		return nil
+	}
+
+	if !deadline.IsZero() {
+		return fmt.Errorf("%w: a task cannot have both a deadline and a due date", my_err.ErrInvalidDueDate)
+	}
+
+	if _, err := time.Parse(time.DateOnly, dueDate); err != nil {
+		return fmt.Errorf("%w: %q is not a YYYY-MM-DD date", my_err.ErrInvalidDueDate, dueDate)
+	}
+
 	return nil
 }
+
+// normalizeTags lowercases the tags, drops a leading # and removes duplicates.
+// Tags are stored space separated, so they cannot contain spaces.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || strings.ContainsFunc(tag, unicode.IsSpace) {
+			return nil, errors.New("tags cannot be empty or contain spaces")
+		}
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized, nil
+}
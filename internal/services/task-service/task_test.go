@@ -0,0 +1,230 @@
+package task_service_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/internal/storage/blob/local"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// newService returns a service keeping its data in memory and its attachments in a temporary
+// directory, which is returned to look into.
+func newService(t *testing.T, settings task_service.Settings) (*task_service.Service, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	blobs, err := local.New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := memory.New()
+	service := task_service.New(s, blobs, nil, settings, slog.New(slog.DiscardHandler))
+
+	return service, root
+}
+
+func TestCreateTask(t *testing.T) {
+	ctx := t.Context()
+	service, _ := newService(t, task_service.Settings{})
+	author := uuid.New()
+
+	for _, title := range []string{"Write report", "Send report"} {
+		if _, err := service.CreateTask(ctx, author, uuid.NullUUID{}, title, "", "", time.Time{}, ""); err != nil {
+			t.Fatalf("create task %q: %v", title, err)
+		}
+	}
+
+	tasks, err := service.GetTasks(ctx, author)
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Title != "Write report" || tasks[1].Title != "Send report" {
+		t.Fatalf("get tasks: want both tasks in order, got %v", tasks)
+	}
+	if tasks[0].Status != models.StatusToDo || tasks[0].Position >= tasks[1].Position {
+		t.Errorf("get tasks: want to-do tasks positioned in order, got %+v and %+v", tasks[0], tasks[1])
+	}
+
+	others, err := service.GetTasks(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("get tasks of other user: %v", err)
+	}
+	if len(others) != 0 {
+		t.Errorf("get tasks of other user: got %v", others)
+	}
+}
+
+func TestCreateTaskInUnknownProject(t *testing.T) {
+	service, _ := newService(t, task_service.Settings{})
+
+	project := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	if _, err := service.CreateTask(t.Context(), uuid.New(), project, "Plan", "", "", time.Time{}, ""); !errors.Is(err, my_err.ErrProjectNotFound) {
+		t.Errorf("want %v, got %v", my_err.ErrProjectNotFound, err)
+	}
+}
+
+func TestCreateTaskInColumn(t *testing.T) {
+	ctx := t.Context()
+	service, _ := newService(t, task_service.Settings{})
+	author := uuid.New()
+
+	if _, err := service.CreateTask(ctx, author, uuid.NullUUID{}, "Write report", "", models.StatusInProgress, time.Time{}, ""); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := service.CreateTask(ctx, author, uuid.NullUUID{}, "Send report", "", "blocked", time.Time{}, ""); !errors.Is(err, my_err.ErrInvalidStatus) {
+		t.Errorf("create task with unknown status: want %v, got %v", my_err.ErrInvalidStatus, err)
+	}
+
+	tasks, err := service.GetTasks(ctx, author)
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Status != models.StatusInProgress {
+		t.Errorf("get tasks: want the task in progress alone, got %v", tasks)
+	}
+}
+
+func TestUpdateTaskStatus(t *testing.T) {
+	ctx := t.Context()
+	service, _ := newService(t, task_service.Settings{})
+	author := uuid.New()
+
+	// The task in progress sorts before the one moved there.
+	var ids []uuid.UUID
+	for _, status := range []string{models.StatusToDo, models.StatusInProgress} {
+		id, err := service.CreateTask(ctx, author, uuid.NullUUID{}, "Write report", "", status, time.Time{}, "")
+		if err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+		ids = append(ids, uuid.MustParse(id))
+	}
+
+	get := func(id uuid.UUID) *models.Task {
+		t.Helper()
+
+		tasks, err := service.GetTasks(ctx, author)
+		if err != nil {
+			t.Fatalf("get tasks: %v", err)
+		}
+		for _, task := range tasks {
+			if task.ID == id {
+				return task
+			}
+		}
+		t.Fatalf("get tasks: task %s is missing", id)
+		return nil
+	}
+
+	moved, inProgress := get(ids[0]), get(ids[1])
+
+	for _, status := range []string{"blocked", "To-Do"} {
+		update := *moved
+		update.Status = status
+		if err := service.UpdateTask(ctx, &update); !errors.Is(err, my_err.ErrInvalidStatus) {
+			t.Errorf("update with status %q: want %v, got %v", status, my_err.ErrInvalidStatus, err)
+		}
+	}
+
+	update := *moved
+	update.Title, update.Status = "Write the report", ""
+	if err := service.UpdateTask(ctx, &update); err != nil {
+		t.Fatalf("update without status: %v", err)
+	}
+	if got := get(moved.ID); got.Status != models.StatusToDo || got.Position != moved.Position || got.Title != "Write the report" {
+		t.Errorf("update without status: want the task left in its place, got %+v", got)
+	}
+
+	update.Status = models.StatusInProgress
+	if err := service.UpdateTask(ctx, &update); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	if got := get(moved.ID); got.Status != models.StatusInProgress || got.Position <= inProgress.Position {
+		t.Errorf("update status: want the task after %q in its new column, got %+v", inProgress.Position, got)
+	}
+
+	missing := update
+	missing.ID = uuid.New()
+	if err := service.UpdateTask(ctx, &missing); !errors.Is(err, my_err.ErrTaskNotFound) {
+		t.Errorf("update unknown task: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+}
+
+func TestDeleteTask(t *testing.T) {
+	ctx := t.Context()
+	service, _ := newService(t, task_service.Settings{})
+	author := uuid.New()
+
+	id, err := service.CreateTask(ctx, author, uuid.NullUUID{}, "Write report", "", "", time.Time{}, "")
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	taskID := uuid.MustParse(id)
+
+	if err := service.DeleteTask(ctx, taskID, uuid.New()); !errors.Is(err, my_err.ErrTaskNotFound) {
+		t.Errorf("delete task of other user: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+	if err := service.DeleteTask(ctx, taskID, author); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+
+	tasks, err := service.GetTasks(ctx, author)
+	if err != nil {
+		t.Fatalf("get tasks: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("get tasks: got %v", tasks)
+	}
+}
+
+func TestApplyUserEvent(t *testing.T) {
+	ctx := t.Context()
+	service, _ := newService(t, task_service.Settings{})
+	user, other := uuid.New(), uuid.New()
+
+	for _, userID := range []uuid.UUID{user, other} {
+		// Events are delivered at least once.
+		for range 2 {
+			if err := service.ApplyUserEvent(ctx, models.EventUserCreated, userID); err != nil {
+				t.Fatalf("apply user.created: %v", err)
+			}
+		}
+		if _, err := service.CreateTask(ctx, userID, uuid.NullUUID{}, "Write report", "", "", time.Time{}, ""); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+
+	for range 2 {
+		if err := service.ApplyUserEvent(ctx, models.EventUserDeleted, user); err != nil {
+			t.Fatalf("apply user.deleted: %v", err)
+		}
+	}
+
+	tasks, err := service.GetTasks(ctx, user)
+	if err != nil {
+		t.Fatalf("get tasks of deleted user: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("get tasks of deleted user: got %v", tasks)
+	}
+
+	tasks, err = service.GetTasks(ctx, other)
+	if err != nil {
+		t.Fatalf("get tasks of other user: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("get tasks of other user: want 1, got %v", tasks)
+	}
+
+	if err := service.ApplyUserEvent(ctx, models.EventUserUpdated, other); err != nil {
+		t.Errorf("apply user.updated: %v", err)
+	}
+}
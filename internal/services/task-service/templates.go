@@ -0,0 +1,446 @@
+package task_service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/rank"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	maxTemplateNameLength = 100
+	maxTemplateTasks      = 200
+	maxTemplateDepth      = 5
+	maxTemplateOffsetDays = 3660
+)
+
+// startVariable is filled with the start date unless the caller sets it.
+const startVariable = "start"
+
+type TemplateProvider interface {
+	SaveTemplate(ctx context.Context, template *models.Template) error
+	GetTemplates(ctx context.Context, author uuid.UUID) ([]*models.Template, error)
+	GetTemplate(ctx context.Context, templateID, author uuid.UUID) (*models.Template, error)
+	DeleteTemplate(ctx context.Context, templateID, author uuid.UUID) error
+}
+
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// ParseTemplateTasks decodes and validates the JSON task tree of a template.
+// Unknown fields are rejected, the same as for view queries.
+func ParseTemplateTasks(raw string) ([]models.TemplateTask, error) {
+	var tasks []models.TemplateTask
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&tasks); err != nil {
+		return nil, fmt.Errorf("%w: %s", my_err.ErrInvalidTemplate, err)
+	}
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("%w: a template needs at least one task", my_err.ErrInvalidTemplate)
+	}
+
+	count := 0
+	if err := validateTemplateTasks(tasks, 1, &count); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// validateTemplateTasks checks the tree in place, trimming titles and normalising tags.
+func validateTemplateTasks(tasks []models.TemplateTask, depth int, count *int) error {
+	if depth > maxTemplateDepth {
+		return fmt.Errorf("%w: subtasks may only be nested %d levels deep", my_err.ErrInvalidTemplate, maxTemplateDepth)
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+
+		*count++
+		if *count > maxTemplateTasks {
+			return fmt.Errorf("%w: a template may have at most %d tasks", my_err.ErrInvalidTemplate, maxTemplateTasks)
+		}
+
+		task.Title = strings.TrimSpace(task.Title)
+		if task.Title == "" {
+			return fmt.Errorf("%w: task titles cannot be empty", my_err.ErrInvalidTemplate)
+		}
+
+		if _, err := parseTemplateOffset(task.Due); err != nil {
+			return err
+		}
+
+		if task.At != "" {
+			if task.Due == "" {
+				return fmt.Errorf("%w: %q: a time of day needs a due offset", my_err.ErrInvalidTemplate, task.Title)
+			}
+			if _, err := time.Parse("15:04", task.At); err != nil {
+				return fmt.Errorf("%w: %q: at must be an HH:MM time", my_err.ErrInvalidTemplate, task.Title)
+			}
+		}
+
+		if task.Priority != "" && !validPriority(task.Priority) {
+			return fmt.Errorf("%w: %q: unknown priority %q", my_err.ErrInvalidTemplate, task.Title, task.Priority)
+		}
+
+		tags, err := normalizeTags(task.Tags)
+		if err != nil {
+			return fmt.Errorf("%w: %q: %s", my_err.ErrInvalidTemplate, task.Title, err)
+		}
+		task.Tags = tags
+
+		for j, item := range task.Checklist {
+			task.Checklist[j] = strings.TrimSpace(item)
+			if task.Checklist[j] == "" {
+				return fmt.Errorf("%w: %q: checklist items cannot be empty", my_err.ErrInvalidTemplate, task.Title)
+			}
+		}
+
+		if err := validateTemplateTasks(task.Subtasks, depth+1, count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseTemplateOffset reads a due offset such as "+3d", "-1d", "+2w" or "+3d from start" as a number of days.
+func parseTemplateOffset(due string) (int, error) {
+	if due == "" {
+		return 0, nil
+	}
+
+	value := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(due), "from start"))
+
+	multiplier := 0
+	switch {
+	case strings.HasSuffix(value, "d"):
+		multiplier = 1
+	case strings.HasSuffix(value, "w"):
+		multiplier = 7
+	default:
+		return 0, fmt.Errorf("%w: due offset %q must end in d or w", my_err.ErrInvalidTemplate, due)
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || value[0] != '+' && value[0] != '-' {
+		return 0, fmt.Errorf("%w: due offset %q must look like +3d or -1w", my_err.ErrInvalidTemplate, due)
+	}
+
+	days := n * multiplier
+	if days < -maxTemplateOffsetDays || days > maxTemplateOffsetDays {
+		return 0, fmt.Errorf("%w: due offset %q is too far from the start", my_err.ErrInvalidTemplate, due)
+	}
+
+	return days, nil
+}
+
+// templateVariables lists the placeholders used by the tasks in order of first use, without the built-in start.
+func templateVariables(tasks []models.TemplateTask) []string {
+	variables := []string{}
+
+	var walk func(tasks []models.TemplateTask)
+	collect := func(text string) {
+		for _, match := range templateVariablePattern.FindAllStringSubmatch(text, -1) {
+			if match[1] != startVariable && !slices.Contains(variables, match[1]) {
+				variables = append(variables, match[1])
+			}
+		}
+	}
+	walk = func(tasks []models.TemplateTask) {
+		for _, task := range tasks {
+			collect(task.Title)
+			collect(task.Description)
+			for _, item := range task.Checklist {
+				collect(item)
+			}
+			walk(task.Subtasks)
+		}
+	}
+	walk(tasks)
+
+	return variables
+}
+
+// CreateTemplate saves a template. A valid projectID makes it a project template,
+// whose tasks are created in that project.
+func (ts *Service) CreateTemplate(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, name, rawTasks string) (*models.Template, error) {
+	const op = "task.CreateTemplate"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.String("name", name),
+	)
+
+	log.Info("creating template")
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxTemplateNameLength {
+		return nil, fmt.Errorf("%s: %w: name must be 1 to %d characters", op, my_err.ErrInvalidTemplate, maxTemplateNameLength)
+	}
+
+	tasks, err := ParseTemplateTasks(rawTasks)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if projectID.Valid {
+		if err := ts.ProjectProvider.ProjectExists(ctx, projectID.UUID, authorID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	template := &models.Template{
+		ID:        uuid.New(),
+		AuthorID:  authorID,
+		ProjectID: projectID,
+		Name:      name,
+		Tasks:     tasks,
+		Variables: templateVariables(tasks),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := ts.TemplateProvider.SaveTemplate(ctx, template); err != nil {
+		if !errors.Is(err, my_err.ErrTemplateExists) {
+			log.Error("failed to save template", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return template, nil
+}
+
+func (ts *Service) ListTemplates(ctx context.Context, authorID uuid.UUID) ([]*models.Template, error) {
+	const op = "task.ListTemplates"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("listing templates")
+
+	templates, err := ts.TemplateProvider.GetTemplates(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get templates", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, template := range templates {
+		template.Variables = templateVariables(template.Tasks)
+	}
+
+	return templates, nil
+}
+
+func (ts *Service) DeleteTemplate(ctx context.Context, templateID, authorID uuid.UUID) error {
+	const op = "task.DeleteTemplate"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("template_id", templateID.String()),
+	)
+
+	log.Info("deleting template")
+
+	if err := ts.TemplateProvider.DeleteTemplate(ctx, templateID, authorID); err != nil {
+		if !errors.Is(err, my_err.ErrTemplateNotFound) {
+			log.Error("failed to delete template", slog.String("error", err.Error()))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// InstantiateTemplate creates the template's task tree in one transaction and returns the tasks, parents first.
+// Due offsets count from start, a YYYY-MM-DD date that defaults to today in the given timezone.
+// Every {{name}} placeholder must have a value in variables, {{start}} defaults to the start date.
+// A valid projectID overrides the project of the template.
+func (ts *Service) InstantiateTemplate(ctx context.Context, templateID, authorID uuid.UUID, projectID uuid.NullUUID, start, timezone string, variables map[string]string) ([]*models.Task, error) {
+	const op = "task.InstantiateTemplate"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("template_id", templateID.String()),
+	)
+
+	log.Info("instantiating template")
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidTimezone, timezone)
+	}
+
+	now := time.Now().In(loc)
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if start != "" {
+		if startDate, err = time.ParseInLocation(time.DateOnly, start, loc); err != nil {
+			return nil, fmt.Errorf("%s: %w: start %q is not a YYYY-MM-DD date", op, my_err.ErrInvalidTemplate, start)
+		}
+	}
+
+	template, err := ts.TemplateProvider.GetTemplate(ctx, templateID, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !projectID.Valid {
+		projectID = template.ProjectID
+	}
+	if projectID.Valid {
+		if err := ts.ProjectProvider.ProjectExists(ctx, projectID.UUID, authorID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	values := map[string]string{startVariable: startDate.Format(time.DateOnly)}
+	for name, value := range variables {
+		values[name] = value
+	}
+
+	var missing []string
+	for _, name := range templateVariables(template.Tasks) {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s: %w: missing variables %s", op, my_err.ErrInvalidTemplate, strings.Join(missing, ", "))
+	}
+
+	builder := &templateBuilder{
+		authorID:  authorID,
+		projectID: projectID,
+		start:     startDate,
+		values:    values,
+	}
+	if err := builder.build(template.Tasks, uuid.NullUUID{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Every task lands at the bottom of the same to-do column, in template order.
+	group := models.RankGroup{AuthorID: authorID, ProjectID: projectID, Status: models.StatusToDo}
+	last, err := ts.TaskProvider.LastTaskPosition(ctx, group)
+	if err != nil {
+		log.Error("failed to get last task position", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	positions, err := rank.After(last, len(builder.tasks))
+	if err != nil {
+		log.Error("failed to compute task positions", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for idx, task := range builder.tasks {
+		task.Position = positions[idx]
+	}
+
+	if err := ts.TaskProvider.CreateTasks(ctx, builder.tasks); err != nil {
+		log.Error("failed to create tasks", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	taskIDs := make([]uuid.UUID, len(builder.tasks))
+	for i, task := range builder.tasks {
+		taskIDs[i] = task.ID
+	}
+	ts.publishTasks(ctx, models.TaskEventCreated, authorID, taskIDs...)
+
+	for _, task := range builder.tasks {
+		summarizeTask(task)
+	}
+
+	return builder.tasks, nil
+}
+
+// templateBuilder turns a template tree into tasks, parents before their subtasks.
+type templateBuilder struct {
+	authorID  uuid.UUID
+	projectID uuid.NullUUID
+	start     time.Time
+	values    map[string]string
+
+	tasks []*models.Task
+}
+
+func (b *templateBuilder) build(templateTasks []models.TemplateTask, parentID uuid.NullUUID) error {
+	for _, templateTask := range templateTasks {
+		task := &models.Task{
+			ID:          uuid.New(),
+			AuthorID:    b.authorID,
+			ProjectID:   b.projectID,
+			ParentID:    parentID,
+			Title:       strings.TrimSpace(b.substitute(templateTask.Title)),
+			Description: b.substitute(templateTask.Description),
+			Status:      models.StatusToDo,
+			Priority:    templateTask.Priority,
+			Tags:        templateTask.Tags,
+		}
+
+		if task.Title == "" {
+			return my_err.ErrEmptyTitle
+		}
+
+		if task.Priority == "" {
+			task.Priority = models.PriorityNone
+		}
+
+		if templateTask.Due != "" {
+			days, err := parseTemplateOffset(templateTask.Due)
+			if err != nil {
+				return err
+			}
+
+			due := b.start.AddDate(0, 0, days)
+			if templateTask.At != "" {
+				at, err := time.Parse("15:04", templateTask.At)
+				if err != nil {
+					return fmt.Errorf("%w: at must be an HH:MM time", my_err.ErrInvalidTemplate)
+				}
+				task.Deadline = time.Date(due.Year(), due.Month(), due.Day(), at.Hour(), at.Minute(), 0, 0, due.Location()).UTC()
+			} else {
+				task.DueDate = due.Format(time.DateOnly)
+			}
+		}
+
+		for position, text := range templateTask.Checklist {
+			task.Checklist = append(task.Checklist, &models.ChecklistItem{
+				ID:       uuid.New(),
+				TaskID:   task.ID,
+				Text:     b.substitute(text),
+				Position: position,
+			})
+		}
+
+		b.tasks = append(b.tasks, task)
+
+		if err := b.build(templateTask.Subtasks, uuid.NullUUID{UUID: task.ID, Valid: true}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *templateBuilder) substitute(text string) string {
+	return templateVariablePattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		return b.values[templateVariablePattern.FindStringSubmatch(placeholder)[1]]
+	})
+}
@@ -0,0 +1,202 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	maxTimeEntryNoteLength = 500
+	maxLoggedDuration      = 24 * time.Hour
+	maxReportRange         = 366 * 24 * time.Hour
+)
+
+type TimeEntryProvider interface {
+	StartTimer(ctx context.Context, entry *models.TimeEntry) error
+	StopTimer(ctx context.Context, author uuid.UUID, endedAt time.Time) (*models.TimeEntry, error)
+	LogTime(ctx context.Context, entry *models.TimeEntry) error
+	GetTimeEntries(ctx context.Context, taskID, author uuid.UUID) ([]*models.TimeEntry, error)
+	GetTimeReport(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID, from, to time.Time) ([]*models.ProjectTime, error)
+}
+
+// StartTimer starts tracking time on the task. A user has at most one running timer,
+// starting a second one fails with ErrTimerRunning instead of stopping the first.
+func (ts *Service) StartTimer(ctx context.Context, taskID, authorID uuid.UUID, note string) (*models.TimeEntry, error) {
+	const op = "task.StartTimer"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("task_id", taskID.String()),
+	)
+
+	log.Info("starting timer")
+
+	if len(note) > maxTimeEntryNoteLength {
+		return nil, fmt.Errorf("%s: %w: note must be at most %d characters", op, my_err.ErrInvalidTimeEntry, maxTimeEntryNoteLength)
+	}
+
+	entry := &models.TimeEntry{
+		ID:        uuid.New(),
+		TaskID:    taskID,
+		AuthorID:  authorID,
+		StartedAt: entryTime(),
+		Note:      note,
+	}
+
+	if err := ts.TimeEntryProvider.StartTimer(ctx, entry); err != nil {
+		if !errors.Is(err, my_err.ErrTimerRunning) && !errors.Is(err, my_err.ErrTaskNotFound) {
+			log.Error("failed to start timer", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entry, nil
+}
+
+// StopTimer stops the user's running timer, whichever task it is on.
+func (ts *Service) StopTimer(ctx context.Context, authorID uuid.UUID) (*models.TimeEntry, error) {
+	const op = "task.StopTimer"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("stopping timer")
+
+	entry, err := ts.TimeEntryProvider.StopTimer(ctx, authorID, entryTime())
+	if err != nil {
+		if !errors.Is(err, my_err.ErrNoRunningTimer) {
+			log.Error("failed to stop timer", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, entry.TaskID)
+
+	return entry, nil
+}
+
+// LogTime records work done without a timer. A zero startedAt means the work just ended.
+func (ts *Service) LogTime(ctx context.Context, taskID, authorID uuid.UUID, startedAt time.Time, duration time.Duration, note string) (*models.TimeEntry, error) {
+	const op = "task.LogTime"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("task_id", taskID.String()),
+	)
+
+	log.Info("logging time")
+
+	if duration < time.Second || duration > maxLoggedDuration {
+		return nil, fmt.Errorf("%s: %w: duration must be between 1s and %s", op, my_err.ErrInvalidTimeEntry, maxLoggedDuration)
+	}
+
+	if len(note) > maxTimeEntryNoteLength {
+		return nil, fmt.Errorf("%s: %w: note must be at most %d characters", op, my_err.ErrInvalidTimeEntry, maxTimeEntryNoteLength)
+	}
+
+	if startedAt.IsZero() {
+		startedAt = entryTime().Add(-duration)
+	}
+	startedAt = startedAt.UTC().Truncate(time.Second)
+
+	if err := ts.TaskProvider.TaskExists(ctx, taskID, authorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entry := &models.TimeEntry{
+		ID:              uuid.New(),
+		TaskID:          taskID,
+		AuthorID:        authorID,
+		StartedAt:       startedAt,
+		EndedAt:         startedAt.Add(duration.Truncate(time.Second)),
+		DurationSeconds: int64(duration / time.Second),
+		Note:            note,
+	}
+
+	if err := ts.TimeEntryProvider.LogTime(ctx, entry); err != nil {
+		log.Error("failed to log time", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.publishTasks(ctx, models.TaskEventUpdated, authorID, taskID)
+
+	return entry, nil
+}
+
+func (ts *Service) GetTimeEntries(ctx context.Context, taskID, authorID uuid.UUID) ([]*models.TimeEntry, error) {
+	const op = "task.GetTimeEntries"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("task_id", taskID.String()),
+	)
+
+	log.Info("getting time entries")
+
+	if err := ts.TaskProvider.TaskExists(ctx, taskID, authorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entries, err := ts.TimeEntryProvider.GetTimeEntries(ctx, taskID, authorID)
+	if err != nil {
+		log.Error("failed to get time entries", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// TimeReport sums the user's finished time entries that started within [from, to), grouped by project and task.
+// An invalid projectID reports on every project including the inbox.
+func (ts *Service) TimeReport(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, from, to time.Time) (*models.TimeReport, error) {
+	const op = "task.TimeReport"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("building time report")
+
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return nil, fmt.Errorf("%s: %w: from must be before to", op, my_err.ErrInvalidDateRange)
+	}
+
+	if to.Sub(from) > maxReportRange {
+		return nil, fmt.Errorf("%s: %w: range must not exceed %d days", op, my_err.ErrInvalidDateRange, int(maxReportRange.Hours()/24))
+	}
+
+	if projectID.Valid {
+		if err := ts.ProjectProvider.ProjectExists(ctx, projectID.UUID, authorID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	projects, err := ts.TimeEntryProvider.GetTimeReport(ctx, authorID, projectID, from.UTC(), to.UTC())
+	if err != nil {
+		log.Error("failed to get time report", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report := &models.TimeReport{From: from, To: to, Projects: projects}
+	for _, project := range projects {
+		report.TotalSeconds += project.Seconds
+	}
+
+	return report, nil
+}
+
+// entryTime is the current time as stored in time entries: UTC with whole seconds, so stored timestamps compare as text.
+func entryTime() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
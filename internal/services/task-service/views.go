@@ -0,0 +1,394 @@
+package task_service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	defaultUpcomingDays = 7
+	maxUpcomingDays     = 366
+	maxViewNameLength   = 100
+)
+
+type ViewProvider interface {
+	SaveView(ctx context.Context, view *models.View) error
+	GetViews(ctx context.Context, author uuid.UUID) ([]*models.View, error)
+	GetView(ctx context.Context, viewID string, author uuid.UUID) (*models.View, error)
+	DeleteView(ctx context.Context, viewID string, author uuid.UUID) error
+}
+
+// builtinViews are the smart lists every user has. Their IDs are not UUIDs, so they never clash with saved views.
+var builtinViews = []*models.View{
+	{ID: "today", Name: "Today", Builtin: true, Query: models.ViewQuery{
+		Status: []string{models.StatusToDo, models.StatusInProgress},
+		Due:    models.DueToday,
+	}},
+	{ID: "upcoming", Name: "Upcoming 7 days", Builtin: true, Query: models.ViewQuery{
+		Status:        []string{models.StatusToDo, models.StatusInProgress},
+		Due:           models.DueUpcoming,
+		DueWithinDays: defaultUpcomingDays,
+	}},
+	{ID: "overdue", Name: "Overdue", Builtin: true, Query: models.ViewQuery{
+		Status: []string{models.StatusToDo, models.StatusInProgress},
+		Due:    models.DueOverdue,
+	}},
+	{ID: "no-deadline", Name: "No deadline", Builtin: true, Query: models.ViewQuery{
+		Status: []string{models.StatusToDo, models.StatusInProgress},
+		Due:    models.DueNone,
+		Sort:   []models.ViewSort{{Field: models.SortByTitle}},
+	}},
+}
+
+// ParseViewQuery decodes and validates a JSON view spec. Unknown fields are rejected
+// so that typos don't silently turn into a filter that matches everything.
+func ParseViewQuery(raw string) (models.ViewQuery, error) {
+	var query models.ViewQuery
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&query); err != nil {
+		return models.ViewQuery{}, fmt.Errorf("%w: %s", my_err.ErrInvalidViewQuery, err)
+	}
+
+	if err := validateViewQuery(query); err != nil {
+		return models.ViewQuery{}, err
+	}
+
+	return query, nil
+}
+
+func validateViewQuery(query models.ViewQuery) error {
+	for _, status := range query.Status {
+		switch status {
+		case models.StatusToDo, models.StatusInProgress, models.StatusDone:
+		default:
+			return fmt.Errorf("%w: unknown status %q", my_err.ErrInvalidViewQuery, status)
+		}
+	}
+
+	switch query.Due {
+	case "", models.DueOverdue, models.DueToday, models.DueUpcoming, models.DueNone, models.DueAny:
+	default:
+		return fmt.Errorf("%w: unknown due filter %q", my_err.ErrInvalidViewQuery, query.Due)
+	}
+
+	if query.DueWithinDays < 0 || query.DueWithinDays > maxUpcomingDays {
+		return fmt.Errorf("%w: due_within_days must be between 0 and %d", my_err.ErrInvalidViewQuery, maxUpcomingDays)
+	}
+
+	if query.DueWithinDays != 0 && query.Due != models.DueUpcoming {
+		return fmt.Errorf("%w: due_within_days requires due \"upcoming\"", my_err.ErrInvalidViewQuery)
+	}
+
+	for _, sort := range query.Sort {
+		switch sort.Field {
+		case models.SortByDeadline, models.SortByTitle, models.SortByStatus:
+		default:
+			return fmt.Errorf("%w: unknown sort field %q", my_err.ErrInvalidViewQuery, sort.Field)
+		}
+	}
+
+	if query.Limit < 0 {
+		return fmt.Errorf("%w: limit must not be negative", my_err.ErrInvalidViewQuery)
+	}
+
+	return nil
+}
+
+func (ts *Service) CreateView(ctx context.Context, authorID uuid.UUID, name, rawQuery string) (*models.View, error) {
+	const op = "task.CreateView"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.String("name", name),
+	)
+
+	log.Info("creating view")
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxViewNameLength {
+		return nil, fmt.Errorf("%s: %w: name must be 1 to %d characters", op, my_err.ErrInvalidViewQuery, maxViewNameLength)
+	}
+
+	query, err := ParseViewQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	view := &models.View{
+		ID:       uuid.New().String(),
+		AuthorID: authorID,
+		Name:     name,
+		Query:    query,
+	}
+
+	if err := ts.ViewProvider.SaveView(ctx, view); err != nil {
+		if !errors.Is(err, my_err.ErrViewExists) {
+			log.Error("failed to save view", slog.String("error", err.Error()))
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return view, nil
+}
+
+// ListViews returns the built-in smart lists followed by the user's saved views.
+func (ts *Service) ListViews(ctx context.Context, authorID uuid.UUID) ([]*models.View, error) {
+	const op = "task.ListViews"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("listing views")
+
+	saved, err := ts.ViewProvider.GetViews(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get views", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return append(slices.Clone(builtinViews), saved...), nil
+}
+
+// RunView evaluates a view against the user's tasks. Relative date filters such as
+// "today" are computed in the given IANA timezone, an empty timezone means UTC.
+func (ts *Service) RunView(ctx context.Context, authorID uuid.UUID, viewID, timezone string) ([]*models.Task, error) {
+	const op = "task.RunView"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+		slog.String("view_id", viewID),
+	)
+
+	log.Info("running view")
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %q", op, my_err.ErrInvalidTimezone, timezone)
+	}
+
+	view, err := ts.getView(ctx, authorID, viewID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tasks, err := ts.TaskProvider.GetTask(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get tasks", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	matched := filterTasks(tasks, view.Query, time.Now().In(loc))
+	for _, task := range matched {
+		summarizeTask(task)
+	}
+
+	return matched, nil
+}
+
+func (ts *Service) DeleteView(ctx context.Context, authorID uuid.UUID, viewID string) error {
+	const op = "task.DeleteView"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("view_id", viewID),
+	)
+
+	log.Info("deleting view")
+
+	if err := ts.ViewProvider.DeleteView(ctx, viewID, authorID); err != nil {
+		if !errors.Is(err, my_err.ErrViewNotFound) {
+			log.Error("failed to delete view", slog.String("error", err.Error()))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (ts *Service) getView(ctx context.Context, authorID uuid.UUID, viewID string) (*models.View, error) {
+	for _, view := range builtinViews {
+		if view.ID == viewID {
+			return view, nil
+		}
+	}
+
+	return ts.ViewProvider.GetView(ctx, viewID, authorID)
+}
+
+// filterTasks applies the filters and sort of query, see matchTasks for the filters.
+func filterTasks(tasks []*models.Task, query models.ViewQuery, now time.Time) []*models.Task {
+	matches := matchTasks(query, now)
+
+	matched := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if matches(task) {
+			matched = append(matched, task)
+		}
+	}
+
+	sortTasks(matched, query.Sort, now.Location())
+
+	if query.Limit > 0 && len(matched) > query.Limit {
+		matched = matched[:query.Limit]
+	}
+
+	return matched
+}
+
+// matchTasks returns whether a task passes the filters of query. now carries the user's location,
+// day boundaries are taken in that location. All-day due dates are calendar dates in that
+// location too: a task due today is overdue once the user's day is over.
+func matchTasks(query models.ViewQuery, now time.Time) func(task *models.Task) bool {
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfToday := startOfToday.AddDate(0, 0, 1)
+
+	days := query.DueWithinDays
+	if days == 0 {
+		days = defaultUpcomingDays
+	}
+	endOfUpcoming := startOfToday.AddDate(0, 0, days)
+
+	// Due dates are YYYY-MM-DD, so they compare as plain strings.
+	today := startOfToday.Format(time.DateOnly)
+	lastUpcomingDay := endOfUpcoming.AddDate(0, 0, -1).Format(time.DateOnly)
+
+	titleContains := strings.ToLower(query.TitleContains)
+
+	return func(task *models.Task) bool {
+		if len(query.Status) > 0 && !slices.Contains(query.Status, task.Status) {
+			return false
+		}
+
+		if titleContains != "" && !strings.Contains(strings.ToLower(task.Title), titleContains) {
+			return false
+		}
+
+		hasDeadline := !task.Deadline.IsZero()
+		hasDueDate := task.DueDate != ""
+		switch query.Due {
+		case models.DueNone:
+			if hasDeadline || hasDueDate {
+				return false
+			}
+		case models.DueAny:
+			if !hasDeadline && !hasDueDate {
+				return false
+			}
+		case models.DueOverdue:
+			switch {
+			case hasDueDate:
+				if task.DueDate >= today {
+					return false
+				}
+			case !hasDeadline || !task.Deadline.Before(now):
+				return false
+			}
+		case models.DueToday:
+			switch {
+			case hasDueDate:
+				if task.DueDate != today {
+					return false
+				}
+			case !hasDeadline || task.Deadline.Before(startOfToday) || !task.Deadline.Before(endOfToday):
+				return false
+			}
+		case models.DueUpcoming:
+			switch {
+			case hasDueDate:
+				if task.DueDate < today || task.DueDate > lastUpcomingDay {
+					return false
+				}
+			case !hasDeadline || task.Deadline.Before(startOfToday) || !task.Deadline.Before(endOfUpcoming):
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// sortTasks orders by the given keys, deadline ascending by default. Tasks without
+// a deadline or due date always go last when sorting by deadline.
+func sortTasks(tasks []*models.Task, keys []models.ViewSort, loc *time.Location) {
+	if len(keys) == 0 {
+		keys = []models.ViewSort{{Field: models.SortByDeadline}}
+	}
+
+	slices.SortStableFunc(tasks, func(a, b *models.Task) int {
+		for _, key := range keys {
+			var c int
+			switch key.Field {
+			case models.SortByDeadline:
+				aDue, bDue := dueAt(a, loc), dueAt(b, loc)
+				if aDue.IsZero() != bDue.IsZero() {
+					if aDue.IsZero() {
+						return 1
+					}
+					return -1
+				}
+				c = aDue.Compare(bDue)
+			case models.SortByTitle:
+				c = cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+			case models.SortByStatus:
+				c = cmp.Compare(statusOrder(a.Status), statusOrder(b.Status))
+			}
+
+			if key.Desc {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+}
+
+// dueAt is the moment a task falls due. An all-day due date lasts until the end of that
+// day in loc, so it sorts after the deadlines of the same day.
+func dueAt(task *models.Task, loc *time.Location) time.Time {
+	if task.DueDate == "" {
+		return task.Deadline
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, task.DueDate, loc)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return date.AddDate(0, 0, 1)
+}
+
+func statusOrder(status string) int {
+	switch status {
+	case models.StatusToDo:
+		return 0
+	case models.StatusInProgress:
+		return 1
+	case models.StatusDone:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,142 @@
+package task_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/pubsub"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// defaultEventHistory is how many task events are kept when the settings do not say.
+const defaultEventHistory = 10000
+
+// WatchTasks passes send the events of the author's tasks published after the event numbered after,
+// 0 for the events to come only, until ctx is done or send fails. Resuming after an event that is
+// no longer kept fails with my_err.ErrEventsExpired, or with resetOnExpiry starts with a reset event.
+func (ts *Service) WatchTasks(ctx context.Context, authorID uuid.UUID, after uint64, resetOnExpiry bool, send func(models.TaskEvent) error) error {
+	const op = "task.WatchTasks"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("watching tasks")
+
+	sub, err := ts.events.Subscribe(authorID, after, resetOnExpiry)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, watchError(err))
+	}
+	defer sub.Close()
+
+	for {
+		event, err := sub.Next(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, watchError(err))
+		}
+
+		if err := send(event); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+}
+
+// CloseWatchers ends the running WatchTasks calls and refuses new ones.
+func (ts *Service) CloseWatchers() {
+	ts.events.Close()
+}
+
+func watchError(err error) error {
+	switch {
+	case errors.Is(err, pubsub.ErrExpired):
+		return my_err.ErrEventsExpired
+	case errors.Is(err, pubsub.ErrLagging):
+		return my_err.ErrWatcherLagging
+	case errors.Is(err, pubsub.ErrClosed):
+		return my_err.ErrWatchersClosed
+	default:
+		return err
+	}
+}
+
+// publishTasks publishes an event for each of the author's tasks once their change is committed.
+// Created and updated events carry the task as it is by then, a task that cannot be read is skipped.
+func (ts *Service) publishTasks(ctx context.Context, eventType string, authorID uuid.UUID, taskIDs ...uuid.UUID) {
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	events := make([]models.TaskEvent, 0, len(taskIDs))
+
+	if eventType == models.TaskEventDeleted {
+		for _, id := range taskIDs {
+			events = append(events, models.TaskEvent{Type: eventType, AuthorID: authorID, TaskID: id, OccurredAt: now})
+		}
+		ts.events.Publish(events...)
+		return
+	}
+
+	// The change is made, a request cancelled by now still publishes it.
+	ctx = context.WithoutCancel(ctx)
+	tasks, err := ts.loadTasks(ctx, authorID, taskIDs)
+	if err != nil {
+		ts.logger.Error("failed to load changed tasks",
+			slog.String("op", "task.publishTasks"),
+			slog.String("author_id", authorID.String()),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	for _, task := range tasks {
+		summarizeTask(task)
+		events = append(events, models.TaskEvent{Type: eventType, AuthorID: authorID, TaskID: task.ID, Task: task, OccurredAt: now})
+	}
+	ts.events.Publish(events...)
+}
+
+// loadTasks reads the author's tasks with the given IDs in that order with their checklists,
+// leaving out the ones not found. GetTaskByID leaves the checklist out, GetTask fills it in.
+func (ts *Service) loadTasks(ctx context.Context, authorID uuid.UUID, taskIDs []uuid.UUID) ([]*models.Task, error) {
+	if len(taskIDs) == 1 {
+		task, err := ts.TaskProvider.GetTaskByID(ctx, taskIDs[0], authorID)
+		if errors.Is(err, my_err.ErrTaskNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if task.Checklist, err = ts.ChecklistProvider.GetChecklist(ctx, task.ID, authorID); err != nil {
+			return nil, err
+		}
+
+		return []*models.Task{task}, nil
+	}
+
+	all, err := ts.TaskProvider.GetTask(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Task, len(all))
+	for _, task := range all {
+		byID[task.ID] = task
+	}
+
+	tasks := make([]*models.Task, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		if task, ok := byID[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
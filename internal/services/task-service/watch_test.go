@@ -0,0 +1,72 @@
+package task_service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+)
+
+func TestWatchTasksCarriesChecklists(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	service, _ := newService(t, task_service.Settings{})
+	author := uuid.New()
+
+	var ids []uuid.UUID
+	for _, title := range []string{"Write report", "Send report"} {
+		id, err := service.CreateTask(ctx, author, uuid.NullUUID{}, title, "", "", time.Time{}, "")
+		if err != nil {
+			t.Fatalf("create task %q: %v", title, err)
+		}
+		ids = append(ids, uuid.MustParse(id))
+
+		if _, err := service.AddChecklistItem(ctx, ids[len(ids)-1], author, "Proofread", nil); err != nil {
+			t.Fatalf("add checklist item: %v", err)
+		}
+	}
+
+	events := make(chan models.TaskEvent)
+	done := make(chan error, 1)
+	go func() {
+		// Resuming after an event that was never kept starts with a reset, which tells the
+		// watcher is subscribed.
+		done <- service.WatchTasks(ctx, author, 1, true, func(event models.TaskEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	if event := <-events; event.Type != models.TaskEventReset {
+		t.Fatalf("want a reset first, got %+v", event)
+	}
+
+	priority := models.PriorityHigh
+	if _, err := service.BatchUpdateTasks(ctx, author, models.TaskSelector{IDs: ids}, models.TaskPatch{Priority: &priority}, true); err != nil {
+		t.Fatalf("batch update: %v", err)
+	}
+
+	for _, id := range ids {
+		event := <-events
+		if event.Type != models.TaskEventUpdated || event.TaskID != id || event.Task == nil {
+			t.Fatalf("want an update of task %s, got %+v", id, event)
+		}
+		if len(event.Task.Checklist) != 1 || event.Task.ChecklistSummary == nil || event.Task.ChecklistSummary.Total != 1 {
+			t.Errorf("want the checklist of task %s in its event, got %+v", id, event.Task)
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want the watch ended by its context, got %v", err)
+	}
+}
@@ -0,0 +1,607 @@
+package task_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/webhook"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	maxWebhooks         = 20
+	maxWebhookURLLength = 2048
+	// maxWebhookError is how much of the error of an attempt the delivery log keeps.
+	maxWebhookError = 500
+
+	defaultWebhookDeliveries = 50
+	maxWebhookDeliveries     = 200
+
+	// defaultWebhookMaxAttempts attempts a delivery for about four hours.
+	defaultWebhookMaxAttempts  = 10
+	defaultWebhookDisableAfter = 5
+	defaultWebhookTimeout      = 10 * time.Second
+
+	// The first retry waits webhookBackoff, each one after twice as long up to maxWebhookBackoff.
+	webhookBackoff    = 30 * time.Second
+	maxWebhookBackoff = 6 * time.Hour
+
+	// webhookBatchSize deliveries are claimed at a time and attempted by up to webhookWorkers at once.
+	webhookBatchSize = 100
+	webhookWorkers   = 8
+
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+	webhookPruneInterval     = time.Hour
+)
+
+// webhookEventTypes are the task event types webhooks may ask for.
+var webhookEventTypes = []string{models.TaskEventCreated, models.TaskEventUpdated, models.TaskEventDeleted}
+
+// webhookEvents maps the outbox events posted to webhooks to their task event types. Tag events
+// are left out, the tags are in the task of the created and updated events.
+var webhookEvents = map[string]string{
+	models.EventTaskCreated: models.TaskEventCreated,
+	models.EventTaskUpdated: models.TaskEventUpdated,
+	models.EventTaskDeleted: models.TaskEventDeleted,
+}
+
+// webhookEventNamespace derives the payload IDs of the outbox events from their sequence numbers.
+var webhookEventNamespace = uuid.MustParse("ae48c605-fa3c-4fe5-841f-0686e08bfa6a")
+
+type WebhookProvider interface {
+	SaveWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhooks(ctx context.Context, author uuid.UUID) ([]*models.Webhook, error)
+	GetWebhook(ctx context.Context, webhookID, author uuid.UUID) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, webhookID, author uuid.UUID) error
+	DisableWebhook(ctx context.Context, webhookID uuid.UUID, reason string) error
+
+	EnqueueWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error
+	GetWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, deliveryID, webhookID uuid.UUID) (*models.WebhookDelivery, error)
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.QueuedDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery) (int, error)
+	PruneWebhookDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookSender posts a signed payload and returns the HTTP status of the response.
+type WebhookSender interface {
+	Send(ctx context.Context, msg webhook.Message) (int, error)
+}
+
+// webhookPayload is the body posted for a task event. Its ID is the same for every webhook and
+// redelivery of the event, receivers may use it to skip events they already handled.
+type webhookPayload struct {
+	ID         uuid.UUID    `json:"id"`
+	Type       string       `json:"type"`
+	TaskID     uuid.UUID    `json:"task_id"`
+	Task       *models.Task `json:"task,omitempty"`
+	OccurredAt time.Time    `json:"occurred_at"`
+}
+
+// CreateWebhook registers an endpoint for the events of the given types, every type when none
+// is given, of the author's tasks in the project, or of every task when projectID is invalid.
+// The secret signing the payloads is only returned here.
+func (ts *Service) CreateWebhook(ctx context.Context, authorID uuid.UUID, projectID uuid.NullUUID, rawURL string, eventTypes []string) (*models.Webhook, error) {
+	const op = "task.CreateWebhook"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", authorID.String()),
+	)
+
+	log.Info("creating webhook")
+
+	endpoint, eventTypes, err := validateWebhook(rawURL, eventTypes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if projectID.Valid {
+		if err := ts.ProjectProvider.ProjectExists(ctx, projectID.UUID, authorID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	webhooks, err := ts.WebhookProvider.GetWebhooks(ctx, authorID)
+	if err != nil {
+		log.Error("failed to get webhooks", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(webhooks) >= maxWebhooks {
+		return nil, fmt.Errorf("%s: %w: a user may have at most %d webhooks", op, my_err.ErrInvalidWebhook, maxWebhooks)
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		log.Error("failed to generate webhook secret", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hook := &models.Webhook{
+		ID:         uuid.New(),
+		AuthorID:   authorID,
+		ProjectID:  projectID,
+		URL:        endpoint,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Enabled:    true,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err := ts.WebhookProvider.SaveWebhook(ctx, hook); err != nil {
+		log.Error("failed to save webhook", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hook, nil
+}
+
+func (ts *Service) ListWebhooks(ctx context.Context, authorID uuid.UUID) ([]*models.Webhook, error) {
+	const op = "task.ListWebhooks"
+
+	webhooks, err := ts.WebhookProvider.GetWebhooks(ctx, authorID)
+	if err != nil {
+		ts.logger.Error("failed to get webhooks", slog.String("op", op), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook replaces the endpoint and filters of the webhook and enables or disables it.
+// Enabling a webhook clears its failures, its pending deliveries are attempted again.
+func (ts *Service) UpdateWebhook(ctx context.Context, webhookID, authorID uuid.UUID, projectID uuid.NullUUID, rawURL string, eventTypes []string, enabled bool) (*models.Webhook, error) {
+	const op = "task.UpdateWebhook"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("webhook_id", webhookID.String()),
+	)
+
+	log.Info("updating webhook")
+
+	endpoint, eventTypes, err := validateWebhook(rawURL, eventTypes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hook, err := ts.WebhookProvider.GetWebhook(ctx, webhookID, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if projectID.Valid {
+		if err := ts.ProjectProvider.ProjectExists(ctx, projectID.UUID, authorID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if enabled && !hook.Enabled {
+		hook.Failures = 0
+		hook.DisabledReason = ""
+	}
+
+	hook.ProjectID = projectID
+	hook.URL = endpoint
+	hook.EventTypes = eventTypes
+	hook.Enabled = enabled
+
+	if err := ts.WebhookProvider.UpdateWebhook(ctx, hook); err != nil {
+		log.Error("failed to update webhook", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if enabled {
+		ts.wakeWebhookDispatcher()
+	}
+
+	return hook, nil
+}
+
+func (ts *Service) DeleteWebhook(ctx context.Context, webhookID, authorID uuid.UUID) error {
+	const op = "task.DeleteWebhook"
+
+	ts.logger.Info("deleting webhook", slog.String("op", op), slog.String("webhook_id", webhookID.String()))
+
+	if err := ts.WebhookProvider.DeleteWebhook(ctx, webhookID, authorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries returns the last limit deliveries of the webhook, the latest first.
+// A limit of 0 returns the last defaultWebhookDeliveries.
+func (ts *Service) ListWebhookDeliveries(ctx context.Context, webhookID, authorID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	const op = "task.ListWebhookDeliveries"
+
+	switch {
+	case limit < 0:
+		return nil, fmt.Errorf("%s: %w: limit cannot be negative", op, my_err.ErrInvalidWebhook)
+	case limit == 0:
+		limit = defaultWebhookDeliveries
+	case limit > maxWebhookDeliveries:
+		limit = maxWebhookDeliveries
+	}
+
+	if _, err := ts.WebhookProvider.GetWebhook(ctx, webhookID, authorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deliveries, err := ts.WebhookProvider.GetWebhookDeliveries(ctx, webhookID, limit)
+	if err != nil {
+		ts.logger.Error("failed to get webhook deliveries", slog.String("op", op), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// RedeliverWebhookDelivery queues the payload of a delivery to be posted again as a new delivery,
+// the webhook has to be enabled.
+func (ts *Service) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID, authorID uuid.UUID) (*models.WebhookDelivery, error) {
+	const op = "task.RedeliverWebhookDelivery"
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("webhook_id", webhookID.String()),
+		slog.String("delivery_id", deliveryID.String()),
+	)
+
+	log.Info("redelivering webhook delivery")
+
+	hook, err := ts.WebhookProvider.GetWebhook(ctx, webhookID, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !hook.Enabled {
+		return nil, fmt.Errorf("%s: %w", op, my_err.ErrWebhookDisabled)
+	}
+
+	original, err := ts.WebhookProvider.GetWebhookDelivery(ctx, deliveryID, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now().UTC()
+	delivery := &models.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		RedeliveryOf:  uuid.NullUUID{UUID: original.ID, Valid: true},
+		CreatedAt:     now,
+	}
+
+	if err := ts.WebhookProvider.EnqueueWebhookDeliveries(ctx, []*models.WebhookDelivery{delivery}); err != nil {
+		log.Error("failed to queue delivery", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.wakeWebhookDispatcher()
+
+	return delivery, nil
+}
+
+// validateWebhook checks the endpoint and event types of a webhook, returning the types
+// deduplicated in a stable order.
+func validateWebhook(rawURL string, eventTypes []string) (string, []string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if len(rawURL) > maxWebhookURLLength {
+		return "", nil, fmt.Errorf("%w: the URL may be at most %d characters", my_err.ErrInvalidWebhook, maxWebhookURLLength)
+	}
+
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return "", nil, fmt.Errorf("%w: the URL must be an absolute http or https URL", my_err.ErrInvalidWebhook)
+	}
+
+	types := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(webhookEventTypes, eventType) {
+			return "", nil, fmt.Errorf("%w: unknown event type %q", my_err.ErrInvalidWebhook, eventType)
+		}
+		if !slices.Contains(types, eventType) {
+			types = append(types, eventType)
+		}
+	}
+	slices.SortFunc(types, func(a, b string) int {
+		return slices.Index(webhookEventTypes, a) - slices.Index(webhookEventTypes, b)
+	})
+
+	return endpoint.String(), types, nil
+}
+
+// QueueWebhooks is the outbox event handler queuing the task events for the enabled webhooks of
+// their author that ask for them, so that an event reaches the webhooks if and only if the change
+// raising it is committed. Deletions are queued for the webhooks of every project.
+//
+// The deliveries of an event have IDs derived from its sequence number, an event published again
+// by the relay is not queued twice.
+func (ts *Service) QueueWebhooks(ctx context.Context, event models.OutboxEvent) error {
+	const op = "task.QueueWebhooks"
+
+	eventType, ok := webhookEvents[event.Type]
+	if !ok || event.AggregateType != models.AggregateTask {
+		return nil
+	}
+
+	// The payload is the task after the change, with its ID, author and project alone once deleted.
+	var aggregate models.Task
+	if err := json.Unmarshal(event.Payload, &aggregate); err != nil {
+		return fmt.Errorf("%s: decode event %d: %w", op, event.Seq, err)
+	}
+
+	log := ts.logger.With(
+		slog.String("op", op),
+		slog.String("author_id", aggregate.AuthorID.String()),
+		slog.Int64("seq", event.Seq),
+	)
+
+	webhooks, err := ts.WebhookProvider.GetWebhooks(ctx, aggregate.AuthorID)
+	if err != nil {
+		log.Error("failed to get webhooks", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !slices.ContainsFunc(webhooks, func(hook *models.Webhook) bool { return hook.Enabled }) {
+		return nil
+	}
+
+	taskEvent := models.TaskEvent{Type: eventType, AuthorID: aggregate.AuthorID, TaskID: event.AggregateID, OccurredAt: event.OccurredAt}
+	if eventType != models.TaskEventDeleted {
+		// Created and updated events carry the task as it is by now, with its tags and checklist,
+		// or as the event left it when it was deleted since.
+		tasks, err := ts.loadTasks(ctx, aggregate.AuthorID, []uuid.UUID{event.AggregateID})
+		if err != nil {
+			log.Error("failed to load task", slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		taskEvent.Task = &aggregate
+		if len(tasks) > 0 {
+			taskEvent.Task = tasks[0]
+		}
+		summarizeTask(taskEvent.Task)
+	}
+
+	payloadID := uuid.NewSHA1(webhookEventNamespace, strconv.AppendInt(nil, event.Seq, 10))
+	payload, err := json.Marshal(webhookPayload{
+		ID:         payloadID,
+		Type:       taskEvent.Type,
+		TaskID:     taskEvent.TaskID,
+		Task:       taskEvent.Task,
+		OccurredAt: taskEvent.OccurredAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: encode payload: %w", op, err)
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for _, hook := range webhooks {
+		if !webhookWants(hook, taskEvent) {
+			continue
+		}
+
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			ID:            uuid.NewSHA1(payloadID, hook.ID[:]),
+			WebhookID:     hook.ID,
+			EventType:     taskEvent.Type,
+			Payload:       payload,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: taskEvent.OccurredAt,
+			CreatedAt:     taskEvent.OccurredAt,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := ts.WebhookProvider.EnqueueWebhookDeliveries(ctx, deliveries); err != nil {
+		log.Error("failed to queue webhook deliveries", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ts.wakeWebhookDispatcher()
+
+	return nil
+}
+
+func webhookWants(hook *models.Webhook, event models.TaskEvent) bool {
+	if !hook.Enabled {
+		return false
+	}
+
+	if len(hook.EventTypes) > 0 && !slices.Contains(hook.EventTypes, event.Type) {
+		return false
+	}
+
+	return !hook.ProjectID.Valid || event.Task == nil || event.Task.ProjectID == hook.ProjectID
+}
+
+// wakeWebhookDispatcher has the dispatcher look for due deliveries without waiting for its interval.
+func (ts *Service) wakeWebhookDispatcher() {
+	select {
+	case ts.webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// DispatchWebhooks attempts the deliveries due by now and returns once they are all attempted.
+func (ts *Service) DispatchWebhooks(ctx context.Context) error {
+	const op = "task.DispatchWebhooks"
+
+	log := ts.logger.With(slog.String("op", op))
+
+	for {
+		now := time.Now()
+		queued, err := ts.WebhookProvider.ClaimWebhookDeliveries(ctx, now, now.Add(ts.webhookLease), webhookBatchSize)
+		if err != nil {
+			log.Error("failed to claim webhook deliveries", slog.String("error", err.Error()))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var (
+			wg      sync.WaitGroup
+			workers = make(chan struct{}, webhookWorkers)
+		)
+		for _, item := range queued {
+			wg.Add(1)
+			workers <- struct{}{}
+
+			go func() {
+				defer wg.Done()
+				defer func() { <-workers }()
+
+				ts.attemptDelivery(ctx, log, item)
+			}()
+		}
+		wg.Wait()
+
+		if len(queued) < webhookBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// attemptDelivery posts a delivery once and records the outcome, scheduling a retry or giving up
+// on it after the last attempt. A webhook is disabled once too many deliveries in a row failed.
+func (ts *Service) attemptDelivery(ctx context.Context, log *slog.Logger, item models.QueuedDelivery) {
+	delivery := item.Delivery
+
+	log = log.With(
+		slog.String("webhook_id", delivery.WebhookID.String()),
+		slog.String("delivery_id", delivery.ID.String()),
+	)
+
+	code, err := ts.webhookSender.Send(ctx, webhook.Message{
+		URL:        item.URL,
+		Secret:     item.Secret,
+		Event:      delivery.EventType,
+		DeliveryID: delivery.ID.String(),
+		Body:       delivery.Payload,
+	})
+	if ctx.Err() != nil {
+		// Stopped mid-attempt, the lease runs out and the delivery is attempted again.
+		return
+	}
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastAttemptAt = now
+	delivery.ResponseStatus = code
+	delivery.Error = ""
+	delivery.NextAttemptAt = time.Time{}
+
+	switch {
+	case err == nil && code >= 200 && code < 300:
+		delivery.Status = models.WebhookDeliveryDelivered
+	default:
+		if err != nil {
+			delivery.Error = err.Error()
+		} else {
+			delivery.Error = fmt.Sprintf("endpoint responded with status %d", code)
+		}
+		if len(delivery.Error) > maxWebhookError {
+			delivery.Error = delivery.Error[:maxWebhookError]
+		}
+
+		if delivery.Attempts >= ts.webhookMaxAttempts {
+			delivery.Status = models.WebhookDeliveryFailed
+		} else {
+			delivery.Status = models.WebhookDeliveryPending
+			delivery.NextAttemptAt = now.Add(webhookRetryDelay(delivery.Attempts))
+		}
+	}
+
+	failures, err := ts.WebhookProvider.RecordWebhookAttempt(ctx, delivery)
+	if err != nil {
+		log.Error("failed to record webhook attempt", slog.String("error", err.Error()))
+		return
+	}
+
+	if delivery.Status != models.WebhookDeliveryFailed {
+		return
+	}
+
+	log.Warn("webhook delivery failed", slog.Int("attempts", delivery.Attempts), slog.String("error", delivery.Error))
+
+	if failures >= ts.webhookDisableAfter {
+		reason := fmt.Sprintf("%d deliveries in a row failed, the last with: %s", failures, delivery.Error)
+		if err := ts.WebhookProvider.DisableWebhook(ctx, delivery.WebhookID, reason); err != nil {
+			log.Error("failed to disable webhook", slog.String("error", err.Error()))
+			return
+		}
+
+		log.Warn("webhook disabled", slog.Int("failures", failures))
+	}
+}
+
+// webhookRetryDelay is how long to wait after the given number of failed attempts, doubling
+// each time with up to a tenth added so that retries of many deliveries spread out.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := maxWebhookBackoff
+	if attempts < 20 {
+		delay = min(webhookBackoff<<(attempts-1), maxWebhookBackoff)
+	}
+
+	return delay + rand.N(delay/10+1)
+}
+
+// PruneWebhookDeliveries deletes the delivered and failed deliveries older than the delivery log keeps.
+func (ts *Service) PruneWebhookDeliveries(ctx context.Context) error {
+	const op = "task.PruneWebhookDeliveries"
+
+	log := ts.logger.With(slog.String("op", op))
+
+	pruned, err := ts.WebhookProvider.PruneWebhookDeliveries(ctx, time.Now().Add(-webhookDeliveryRetention))
+	if err != nil {
+		log.Error("failed to prune webhook deliveries", slog.String("error", err.Error()))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pruned > 0 {
+		log.Info("pruned webhook deliveries", slog.Int64("deliveries", pruned))
+	}
+
+	return nil
+}
+
+// RunWebhookDispatcher calls DispatchWebhooks every interval, and as soon as deliveries are queued,
+// until ctx is cancelled. It prunes the delivery log every webhookPruneInterval.
+func (ts *Service) RunWebhookDispatcher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pruneTicker := time.NewTicker(webhookPruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = ts.DispatchWebhooks(ctx)
+		case <-ts.webhookWake:
+			_ = ts.DispatchWebhooks(ctx)
+		case <-pruneTicker.C:
+			_ = ts.PruneWebhookDeliveries(ctx)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package task_service_test
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/events/inprocess"
+	"github.com/SlashLight/todo-list/internal/lib/webhook"
+	outbox_relay "github.com/SlashLight/todo-list/internal/services/outbox-relay"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/internal/storage/blob/local"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+)
+
+// sender records the messages it is given and answers them all the same way.
+type sender struct {
+	mu       sync.Mutex
+	messages []webhook.Message
+	status   int
+	err      error
+}
+
+func (s *sender) Send(ctx context.Context, msg webhook.Message) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, msg)
+	return s.status, s.err
+}
+
+func (s *sender) sent() []webhook.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.messages)
+}
+
+// webhookFixture is a service with its storage, whose outbox events are queued for the webhooks
+// by a relay the way the app does.
+type webhookFixture struct {
+	service *task_service.Service
+	storage *memory.Storage
+	relay   *outbox_relay.Relay
+	sender  *sender
+}
+
+func newWebhookFixture(t *testing.T, settings task_service.Settings) *webhookFixture {
+	t.Helper()
+
+	blobs, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log := slog.New(slog.DiscardHandler)
+	s := memory.New()
+	sender := &sender{status: 200}
+	service := task_service.New(s, blobs, sender, settings, log)
+
+	consumers := inprocess.New()
+	consumers.Subscribe(service.QueueWebhooks)
+
+	return &webhookFixture{
+		service: service,
+		storage: s,
+		relay:   outbox_relay.New(s, consumers, 100, time.Hour, log),
+		sender:  sender,
+	}
+}
+
+// deliver queues the pending outbox events and attempts the deliveries due.
+func (f *webhookFixture) deliver(t *testing.T) {
+	t.Helper()
+
+	if _, err := f.relay.Dispatch(t.Context()); err != nil {
+		t.Fatalf("dispatch outbox: %v", err)
+	}
+	if err := f.service.DispatchWebhooks(t.Context()); err != nil {
+		t.Fatalf("dispatch webhooks: %v", err)
+	}
+}
+
+func (f *webhookFixture) createTask(t *testing.T, author uuid.UUID, title string) uuid.UUID {
+	t.Helper()
+
+	id, err := f.service.CreateTask(t.Context(), author, uuid.NullUUID{}, title, "", "", time.Time{}, "")
+	if err != nil {
+		t.Fatalf("create task %q: %v", title, err)
+	}
+
+	return uuid.MustParse(id)
+}
+
+func TestQueueWebhooks(t *testing.T) {
+	ctx := t.Context()
+	f := newWebhookFixture(t, task_service.Settings{})
+	author := uuid.New()
+
+	every, err := f.service.CreateWebhook(ctx, author, uuid.NullUUID{}, "https://example.com/every", nil)
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	deletions, err := f.service.CreateWebhook(ctx, author, uuid.NullUUID{}, "https://example.com/deletions", []string{models.TaskEventDeleted})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	if _, err := f.service.CreateWebhook(ctx, uuid.New(), uuid.NullUUID{}, "https://example.com/other", nil); err != nil {
+		t.Fatalf("create webhook of other user: %v", err)
+	}
+
+	id := f.createTask(t, author, "Write report")
+	if err := f.service.DeleteTask(ctx, id, author); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+
+	events, err := f.storage.GetPendingOutboxEvents(ctx, 0, 100)
+	if err != nil {
+		t.Fatalf("get outbox events: %v", err)
+	}
+
+	f.deliver(t)
+
+	type posted struct{ url, event string }
+	var got []posted
+	for _, msg := range f.sender.sent() {
+		got = append(got, posted{msg.URL, msg.Event})
+
+		secret := every.Secret
+		if msg.URL == deletions.URL {
+			secret = deletions.Secret
+		}
+		if msg.Secret != secret {
+			t.Errorf("want %s signed with its secret", msg.URL)
+		}
+
+		var payload struct {
+			Type   string       `json:"type"`
+			TaskID uuid.UUID    `json:"task_id"`
+			Task   *models.Task `json:"task"`
+		}
+		if err := json.Unmarshal(msg.Body, &payload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		if payload.Type != msg.Event || payload.TaskID != id {
+			t.Errorf("want a %s payload of task %s, got %s", msg.Event, id, msg.Body)
+		}
+		if msg.Event == models.TaskEventCreated && (payload.Task == nil || payload.Task.Title != "Write report") {
+			t.Errorf("want the created task in the payload, got %s", msg.Body)
+		}
+	}
+	slices.SortFunc(got, func(a, b posted) int {
+		return cmp.Or(cmp.Compare(a.url, b.url), cmp.Compare(a.event, b.event))
+	})
+	want := []posted{{deletions.URL, models.TaskEventDeleted}, {every.URL, models.TaskEventCreated}, {every.URL, models.TaskEventDeleted}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("want %v posted, got %v", want, got)
+	}
+
+	// The relay publishes an event again when it fails to mark it dispatched.
+	for _, event := range events {
+		if err := f.service.QueueWebhooks(ctx, event); err != nil {
+			t.Fatalf("queue event %d again: %v", event.Seq, err)
+		}
+	}
+	f.deliver(t)
+
+	if sent := f.sender.sent(); len(sent) != len(want) {
+		t.Errorf("want events queued again not posted again, got %d messages", len(sent))
+	}
+	deliveries, err := f.service.ListWebhookDeliveries(ctx, every.ID, author, 0)
+	if err != nil {
+		t.Fatalf("list deliveries: %v", err)
+	}
+	if len(deliveries) != 2 || deliveries[0].Status != models.WebhookDeliveryDelivered || deliveries[1].Status != models.WebhookDeliveryDelivered {
+		t.Errorf("want both deliveries delivered once, got %+v", deliveries)
+	}
+}
+
+func TestDispatchWebhooksRetries(t *testing.T) {
+	ctx := t.Context()
+	f := newWebhookFixture(t, task_service.Settings{WebhookMaxAttempts: 2})
+	f.sender.status = 503
+	author := uuid.New()
+
+	hook, err := f.service.CreateWebhook(ctx, author, uuid.NullUUID{}, "https://example.com/hook", nil)
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+
+	f.createTask(t, author, "Write report")
+	f.deliver(t)
+
+	deliveries, err := f.service.ListWebhookDeliveries(ctx, hook.ID, author, 0)
+	if err != nil {
+		t.Fatalf("list deliveries: %v", err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("want 1 delivery, got %d", len(deliveries))
+	}
+
+	delivery := deliveries[0]
+	if delivery.Status != models.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseStatus != 503 ||
+		delivery.Error != "endpoint responded with status 503" {
+		t.Errorf("want the delivery pending a retry, got %+v", delivery)
+	}
+	if wait := delivery.NextAttemptAt.Sub(delivery.LastAttemptAt); wait < 30*time.Second || wait > 34*time.Second {
+		t.Errorf("want the first retry after 30s and up to a tenth more, got %v", wait)
+	}
+
+	// The retry is not due yet.
+	if err := f.service.DispatchWebhooks(ctx); err != nil {
+		t.Fatalf("dispatch webhooks: %v", err)
+	}
+	if sent := f.sender.sent(); len(sent) != 1 {
+		t.Errorf("want the retry held back, got %d attempts", len(sent))
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{9, 128 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{19, 6 * time.Hour},
+		{20, 6 * time.Hour},
+		{100, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		for range 20 {
+			got := task_service.WebhookRetryDelay(tt.attempts)
+			if got < tt.want || got > tt.want+tt.want/10 {
+				t.Errorf("after %d attempts: want %v and up to a tenth more, got %v", tt.attempts, tt.want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestWebhookDisabledAfterFailuresInRow(t *testing.T) {
+	ctx := t.Context()
+	f := newWebhookFixture(t, task_service.Settings{WebhookMaxAttempts: 1, WebhookDisableAfter: 2})
+	author := uuid.New()
+
+	hook, err := f.service.CreateWebhook(ctx, author, uuid.NullUUID{}, "https://example.com/hook", nil)
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+
+	webhookState := func() *models.Webhook {
+		t.Helper()
+
+		webhooks, err := f.service.ListWebhooks(ctx, author)
+		if err != nil {
+			t.Fatalf("list webhooks: %v", err)
+		}
+		return webhooks[0]
+	}
+
+	// A delivered event in between starts the count again.
+	f.sender.err = errors.New("connection refused")
+	f.createTask(t, author, "First")
+	f.deliver(t)
+	f.sender.err = nil
+	f.createTask(t, author, "Second")
+	f.deliver(t)
+	f.sender.err = errors.New("connection refused")
+	f.createTask(t, author, "Third")
+	f.deliver(t)
+
+	if state := webhookState(); !state.Enabled || state.Failures != 1 {
+		t.Fatalf("want the webhook enabled with 1 failure in a row, got %+v", state)
+	}
+
+	f.createTask(t, author, "Fourth")
+	f.deliver(t)
+
+	state := webhookState()
+	if state.Enabled || state.Failures != 2 || state.DisabledReason == "" {
+		t.Fatalf("want the webhook disabled after 2 failures in a row, got %+v", state)
+	}
+
+	f.createTask(t, author, "Fifth")
+	f.deliver(t)
+
+	if sent := f.sender.sent(); len(sent) != 4 {
+		t.Errorf("want no events posted to the disabled webhook, got %d attempts", len(sent))
+	}
+
+	updated, err := f.service.UpdateWebhook(ctx, hook.ID, author, uuid.NullUUID{}, hook.URL, nil, true)
+	if err != nil {
+		t.Fatalf("enable webhook: %v", err)
+	}
+	if !updated.Enabled || updated.Failures != 0 || updated.DisabledReason != "" {
+		t.Errorf("want the failures cleared by enabling the webhook, got %+v", updated)
+	}
+}
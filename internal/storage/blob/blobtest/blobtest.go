@@ -0,0 +1,105 @@
+// Package blobtest checks that a blob store behaves the way the task service relies on, whichever
+// store is behind it. The blob store packages call Run from their tests.
+package blobtest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// Run runs every check against the store as subtests. Every check uses keys of its own.
+func Run(t *testing.T, store task_service.BlobStore) {
+	t.Run("put and get", func(t *testing.T) {
+		key := newKey()
+		put(t, store, key, "first")
+		put(t, store, key, "second")
+
+		if got := get(t, store, key); got != "second" {
+			t.Errorf("get: want %q, got %q", "second", got)
+		}
+	})
+
+	t.Run("get unknown", func(t *testing.T) {
+		if _, err := store.Get(t.Context(), newKey()); !errors.Is(err, my_err.ErrBlobNotFound) {
+			t.Errorf("want %v, got %v", my_err.ErrBlobNotFound, err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		key := newKey()
+		put(t, store, key, "content")
+
+		if err := store.Delete(t.Context(), key); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if _, err := store.Get(t.Context(), key); !errors.Is(err, my_err.ErrBlobNotFound) {
+			t.Errorf("get deleted: want %v, got %v", my_err.ErrBlobNotFound, err)
+		}
+
+		// Removing blobs is best effort and may be repeated.
+		if err := store.Delete(t.Context(), key); err != nil {
+			t.Errorf("delete again: %v", err)
+		}
+	})
+
+	// The task service fails uploads going past the quota from the reader, and tells quota errors
+	// from others by the error the store returns.
+	t.Run("failed upload", func(t *testing.T) {
+		key := newKey()
+		errRead := errors.New("read failed")
+		r := io.MultiReader(strings.NewReader("partial"), errReader{errRead})
+
+		if err := store.Put(t.Context(), key, r, "text/plain"); !errors.Is(err, errRead) {
+			t.Fatalf("put: want %v, got %v", errRead, err)
+		}
+		if _, err := store.Get(t.Context(), key); !errors.Is(err, my_err.ErrBlobNotFound) {
+			t.Errorf("get failed upload: want %v, got %v", my_err.ErrBlobNotFound, err)
+		}
+	})
+}
+
+// newKey returns a key shaped like the ones of the task service, author/attachment.
+func newKey() string {
+	return uuid.NewString() + "/" + uuid.NewString()
+}
+
+func put(t *testing.T, store task_service.BlobStore, key, content string) {
+	t.Helper()
+
+	if err := store.Put(t.Context(), key, strings.NewReader(content), "text/plain"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+}
+
+func get(t *testing.T, store task_service.BlobStore, key string) string {
+	t.Helper()
+
+	body, err := store.Get(t.Context(), key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer body.Close()
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(body); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	return b.String()
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,78 @@
+package local_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SlashLight/todo-list/internal/storage/blob/blobtest"
+	"github.com/SlashLight/todo-list/internal/storage/blob/local"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func newStore(t *testing.T) (*local.Store, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	store, err := local.New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store, root
+}
+
+func TestStore(t *testing.T) {
+	store, _ := newStore(t)
+
+	blobtest.Run(t, store)
+}
+
+func TestInvalidKeys(t *testing.T) {
+	store, _ := newStore(t)
+
+	for _, key := range []string{"", "/etc/passwd", "..", "../outside", "a/../../outside"} {
+		if err := store.Put(t.Context(), key, strings.NewReader("x"), "text/plain"); !errors.Is(err, my_err.ErrInvalidBlobKey) {
+			t.Errorf("put %q: want %v, got %v", key, my_err.ErrInvalidBlobKey, err)
+		}
+		if _, err := store.Get(t.Context(), key); !errors.Is(err, my_err.ErrInvalidBlobKey) {
+			t.Errorf("get %q: want %v, got %v", key, my_err.ErrInvalidBlobKey, err)
+		}
+	}
+}
+
+func TestFailedUploadLeavesNoFiles(t *testing.T) {
+	store, root := newStore(t)
+
+	if err := store.Put(t.Context(), "author/blob", failingReader{}, "text/plain"); err == nil {
+		t.Fatal("put: want an error")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "author"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("want no files, got %v", entries)
+	}
+}
+
+func TestCancelledUpload(t *testing.T) {
+	store, _ := newStore(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	if err := store.Put(ctx, "author/blob", strings.NewReader("content"), "text/plain"); !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
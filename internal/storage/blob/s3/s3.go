@@ -0,0 +1,91 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// partSize is the smallest part size the client picks, which caps uploads at 160 GiB.
+const partSize = 16 << 20
+
+// Store keeps blobs in a bucket of any S3-compatible object storage (AWS S3, MinIO, ...).
+type Store struct {
+	client *minio.Client
+	bucket string
+}
+
+func New(ctx context.Context, endpoint, accessKey, secretKey, bucket, region string, useSSL bool) (*Store, error) {
+	const op = "storage.blob.s3.New"
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+		Region: region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("%s: check bucket: %w", op, err)
+	}
+
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: region}); err != nil {
+			return nil, fmt.Errorf("%s: create bucket: %w", op, err)
+		}
+	}
+
+	return &Store{client: client, bucket: bucket}, nil
+}
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	const op = "storage.blob.s3.Put"
+
+	// Size is unknown for streamed uploads, so let the client fall back to a multipart upload. It
+	// buffers a part at a time, sized for the largest object S3 takes unless given a size.
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, -1, minio.PutObjectOptions{ContentType: contentType, PartSize: partSize})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	const op = "storage.blob.s3.Get"
+
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// GetObject is lazy, stat the object to surface a missing key before the caller starts streaming.
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, fmt.Errorf("%s: %w", op, my_err.ErrBlobNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return obj, nil
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	const op = "storage.blob.s3.Delete"
+
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
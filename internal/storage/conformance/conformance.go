@@ -0,0 +1,1184 @@
+// Package conformance checks that the storage backends behave the way the services rely on:
+// the same my_err errors in the same situations and the same orderings, whichever database
+// is behind it. Every check works with users of its own, so the checks can run against a
+// database that is in use.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	taskgrpc "github.com/SlashLight/todo-list/internal/grpc/task-service"
+	auth_service "github.com/SlashLight/todo-list/internal/services/auth-service"
+	outbox_relay "github.com/SlashLight/todo-list/internal/services/outbox-relay"
+	task_service "github.com/SlashLight/todo-list/internal/services/task-service"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// AuthStorage is everything the auth service needs from a backend.
+type AuthStorage interface {
+	auth_service.UserSaver
+	auth_service.UserProvider
+	outbox_relay.OutboxProvider
+}
+
+// Storage is everything the task service needs from a backend.
+type Storage interface {
+	task_service.Storage
+	taskgrpc.IdempotencyStore
+	outbox_relay.OutboxProvider
+}
+
+type authCheck struct {
+	name string
+	run  func(ctx context.Context, s AuthStorage) error
+}
+
+var authChecks = []authCheck{
+	{"users", checkUsers},
+	{"account deletion", checkAccountDeletion},
+}
+
+type check struct {
+	name string
+	run  func(ctx context.Context, s Storage) error
+}
+
+var checks = []check{
+	{"user data", checkUserData},
+	{"write before user reference", checkWriteBeforeUserReference},
+	{"write after user deleted", checkWriteAfterUserDeleted},
+	{"tasks", checkTasks},
+	{"projects", checkProjects},
+	{"checklist", checkChecklist},
+	{"paging", checkPaging},
+	{"search", checkSearch},
+	{"timer", checkTimer},
+	{"views", checkViews},
+	{"templates", checkTemplates},
+	{"attachment quota", checkAttachmentQuota},
+	{"batch", checkBatch},
+	{"sync", checkSync},
+	{"calendar object update", checkCalendarObjectUpdate},
+	{"outbox", checkOutbox},
+	{"webhook deliveries", checkWebhookDeliveries},
+	{"idempotency", checkIdempotency},
+}
+
+// Run runs every check against the auth and the task storage as subtests, the storage packages
+// call it from their tests.
+func Run(t *testing.T, auth AuthStorage, tasks Storage) {
+	for _, c := range authChecks {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.run(t.Context(), auth); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.run(t.Context(), tasks); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Migrate applies the migrations in the directory to the database, e.g. ../../../migrations/task
+// to sqlite3:// and the path of a database file.
+func Migrate(t *testing.T, dir, databaseURL string) {
+	t.Helper()
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := migrate.New("file://"+filepath.ToSlash(dir), databaseURL)
+	if err != nil {
+		t.Fatalf("migrate %s: %v", dir, err)
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("migrate %s: %v", dir, err)
+	}
+}
+
+func checkUsers(ctx context.Context, s AuthStorage) error {
+	user, err := registerUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if err := s.Register(ctx, &models.User{ID: uuid.New(), Email: user.Email, PasswordHash: "hash", Timezone: "UTC"}); !errors.Is(err, my_err.ErrUserExists) {
+		return fmt.Errorf("register taken email: want %v, got %v", my_err.ErrUserExists, err)
+	}
+
+	got, err := s.GetByEmail(ctx, user.Email)
+	if err != nil {
+		return fmt.Errorf("get by email: %w", err)
+	}
+	if *got != *user {
+		return fmt.Errorf("get by email: want %+v, got %+v", user, got)
+	}
+
+	if _, err := s.GetByEmail(ctx, uuid.NewString()+"@example.com"); !errors.Is(err, my_err.ErrUserNotFound) {
+		return fmt.Errorf("get unknown email: want %v, got %v", my_err.ErrUserNotFound, err)
+	}
+
+	updated, err := s.SetTimezone(ctx, user.ID, "Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("set timezone: %w", err)
+	}
+	if updated.Timezone != "Europe/Berlin" {
+		return fmt.Errorf("set timezone: got %q", updated.Timezone)
+	}
+
+	if _, err := s.SetTimezone(ctx, uuid.New(), "UTC"); !errors.Is(err, my_err.ErrUserNotFound) {
+		return fmt.Errorf("set timezone of unknown user: want %v, got %v", my_err.ErrUserNotFound, err)
+	}
+
+	return nil
+}
+
+func checkAccountDeletion(ctx context.Context, s AuthStorage) error {
+	user, err := registerUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DeleteUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("delete user: %w", err)
+	}
+	if _, err := s.GetByEmail(ctx, user.Email); !errors.Is(err, my_err.ErrUserNotFound) {
+		return fmt.Errorf("get deleted user: want %v, got %v", my_err.ErrUserNotFound, err)
+	}
+	if err := s.DeleteUser(ctx, user.ID); !errors.Is(err, my_err.ErrUserNotFound) {
+		return fmt.Errorf("delete deleted user: want %v, got %v", my_err.ErrUserNotFound, err)
+	}
+
+	// The task service learns about users from these events.
+	types, err := pendingEventTypes(ctx, s, user.ID)
+	if err != nil {
+		return err
+	}
+
+	want := []string{models.EventUserCreated, models.EventUserDeleted}
+	if !slices.Equal(types, want) {
+		return fmt.Errorf("get pending events: want %q, got %q", want, types)
+	}
+
+	return nil
+}
+
+func checkUserData(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	// Events may be delivered more than once.
+	if err := s.SaveUserReference(ctx, user.ID); err != nil {
+		return fmt.Errorf("save user reference twice: %w", err)
+	}
+
+	task := newTask(user.ID, "Scan", "a")
+	if err := s.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+	attachment := newAttachment(task, 10)
+	if err := s.SaveAttachment(ctx, attachment, 100); err != nil {
+		return fmt.Errorf("save attachment: %w", err)
+	}
+
+	attachments, err := s.DeleteUserData(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("delete user data: %w", err)
+	}
+	if len(attachments) != 1 || attachments[0].ID != attachment.ID {
+		return fmt.Errorf("delete user data: want attachment %s, got %v", attachment.ID, attachments)
+	}
+
+	if _, err := s.GetTaskByID(ctx, task.ID, user.ID); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("get task of deleted user: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+
+	if _, err := s.DeleteUserData(ctx, user.ID); err != nil {
+		return fmt.Errorf("delete user data twice: %w", err)
+	}
+
+	return nil
+}
+
+// checkWriteBeforeUserReference writes for users whose user.created has not arrived yet, the way
+// users do who write right after registering.
+func checkWriteBeforeUserReference(ctx context.Context, s Storage) error {
+	author := uuid.New()
+	task := newTask(author, "First task", "a")
+	if err := s.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+
+	project := &models.Project{ID: uuid.New(), AuthorID: uuid.New(), Name: "Work", CreatedAt: time.Now().UTC()}
+	if err := s.CreateProject(ctx, project); err != nil {
+		return fmt.Errorf("create project: %w", err)
+	}
+
+	view := &models.View{ID: uuid.NewString(), AuthorID: uuid.New(), Name: "Urgent", Query: models.ViewQuery{Due: models.DueOverdue}}
+	if err := s.SaveView(ctx, view); err != nil {
+		return fmt.Errorf("save view: %w", err)
+	}
+
+	// The event arriving late changes nothing.
+	if err := s.SaveUserReference(ctx, author); err != nil {
+		return fmt.Errorf("save user reference: %w", err)
+	}
+	if _, err := s.GetTaskByID(ctx, task.ID, author); err != nil {
+		return fmt.Errorf("get task: %w", err)
+	}
+
+	for _, userID := range []uuid.UUID{author, project.AuthorID, view.AuthorID} {
+		if _, err := s.DeleteUserData(ctx, userID); err != nil {
+			return fmt.Errorf("delete user data: %w", err)
+		}
+	}
+	if _, err := s.GetTaskByID(ctx, task.ID, author); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("get task of deleted user: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+	if err := s.ProjectExists(ctx, project.ID, project.AuthorID); !errors.Is(err, my_err.ErrProjectNotFound) {
+		return fmt.Errorf("project of deleted user exists: want %v, got %v", my_err.ErrProjectNotFound, err)
+	}
+	if _, err := s.GetView(ctx, view.ID, view.AuthorID); !errors.Is(err, my_err.ErrViewNotFound) {
+		return fmt.Errorf("get view of deleted user: want %v, got %v", my_err.ErrViewNotFound, err)
+	}
+
+	return nil
+}
+
+// checkWriteAfterUserDeleted writes for a user whose user.deleted has arrived, the way a deleted
+// user does whose token has not expired yet. The writes are refused rather than bring them back.
+func checkWriteAfterUserDeleted(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+	if _, err := s.DeleteUserData(ctx, user.ID); err != nil {
+		return fmt.Errorf("delete user data: %w", err)
+	}
+
+	now := time.Now().UTC()
+	tokenHash := uuid.NewString()
+	writes := []struct {
+		name  string
+		write func() error
+	}{
+		{"create task", func() error { return s.CreateTask(ctx, newTask(user.ID, "Write report", "a")) }},
+		{"create project", func() error {
+			return s.CreateProject(ctx, &models.Project{ID: uuid.New(), AuthorID: user.ID, Name: "Work", CreatedAt: now})
+		}},
+		{"save view", func() error {
+			return s.SaveView(ctx, &models.View{ID: uuid.NewString(), AuthorID: user.ID, Name: "Urgent", Query: models.ViewQuery{Due: models.DueOverdue}})
+		}},
+		{"save template", func() error {
+			return s.SaveTemplate(ctx, &models.Template{ID: uuid.New(), AuthorID: user.ID, Name: "Release", CreatedAt: now})
+		}},
+		{"save calendar feed", func() error {
+			return s.SaveCalendarFeed(ctx, &models.CalendarFeed{AuthorID: user.ID, TokenHash: tokenHash, CreatedAt: now})
+		}},
+		{"save webhook", func() error {
+			return s.SaveWebhook(ctx, &models.Webhook{ID: uuid.New(), AuthorID: user.ID, URL: "https://example.com/hook", Enabled: true, CreatedAt: now})
+		}},
+		// A user.created delivered again after the user.deleted.
+		{"save user reference", func() error { return s.SaveUserReference(ctx, user.ID) }},
+	}
+	for _, w := range writes {
+		if err := w.write(); !errors.Is(err, my_err.ErrUserDeleted) {
+			return fmt.Errorf("%s of deleted user: want %v, got %v", w.name, my_err.ErrUserDeleted, err)
+		}
+	}
+
+	tasks, err := s.GetTask(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get tasks: %w", err)
+	}
+	projects, err := s.GetProjects(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get projects: %w", err)
+	}
+	views, err := s.GetViews(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get views: %w", err)
+	}
+	templates, err := s.GetTemplates(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get templates: %w", err)
+	}
+	webhooks, err := s.GetWebhooks(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get webhooks: %w", err)
+	}
+	if len(tasks)+len(projects)+len(views)+len(templates)+len(webhooks) != 0 {
+		return fmt.Errorf("refused writes left %d tasks, %d projects, %d views, %d templates and %d webhooks",
+			len(tasks), len(projects), len(views), len(templates), len(webhooks))
+	}
+	if _, err := s.GetCalendarFeed(ctx, tokenHash); !errors.Is(err, my_err.ErrCalendarFeedNotFound) {
+		return fmt.Errorf("get calendar feed of deleted user: want %v, got %v", my_err.ErrCalendarFeedNotFound, err)
+	}
+
+	// Deleting the user again, as a user.deleted delivered twice does, still works.
+	if _, err := s.DeleteUserData(ctx, user.ID); err != nil {
+		return fmt.Errorf("delete user data twice: %w", err)
+	}
+
+	return nil
+}
+
+func checkTasks(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+	other, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	task := newTask(user.ID, "Write report", "a")
+	task.Description = "first draft"
+	task.Deadline = time.Date(2030, 1, 2, 15, 4, 5, 0, time.FixedZone("CET", 3600))
+	task.Tags = []string{"work", "docs"}
+	task.Priority = models.PriorityHigh
+	if err := s.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+
+	got, err := s.GetTaskByID(ctx, task.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("get task: %w", err)
+	}
+	if got.Title != task.Title || got.Description != task.Description || got.Status != models.StatusToDo ||
+		got.Priority != task.Priority || !got.Deadline.Equal(task.Deadline) || got.Deadline.Location() != time.UTC {
+		return fmt.Errorf("get task: want %+v, got %+v", task, got)
+	}
+	if !slices.Equal(got.Tags, []string{"docs", "work"}) {
+		return fmt.Errorf("get task: want sorted tags, got %q", got.Tags)
+	}
+
+	if _, err := s.GetTaskByID(ctx, task.ID, other.ID); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("get task of another author: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+	if err := s.TaskExists(ctx, task.ID, other.ID); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("task of another author exists: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+
+	task.Title = "Write the report"
+	task.Status = models.StatusInProgress
+	task.Deadline = time.Time{}
+	task.DueDate = "2030-01-02"
+	if err := s.UpdateTask(ctx, task); err != nil {
+		return fmt.Errorf("update task: %w", err)
+	}
+
+	got, err = s.GetTaskByID(ctx, task.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("get updated task: %w", err)
+	}
+	if got.Title != task.Title || got.Status != task.Status || !got.Deadline.IsZero() || got.DueDate != task.DueDate {
+		return fmt.Errorf("get updated task: want %+v, got %+v", task, got)
+	}
+
+	missing := newTask(user.ID, "Missing", "b")
+	if err := s.UpdateTask(ctx, missing); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("update unknown task: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+
+	if err := s.DeleteTask(ctx, task.ID, other.ID); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("delete task of another author: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+	if err := s.DeleteTask(ctx, task.ID, user.ID); err != nil {
+		return fmt.Errorf("delete task: %w", err)
+	}
+	if err := s.DeleteTask(ctx, task.ID, user.ID); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("delete task twice: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+
+	return nil
+}
+
+func checkProjects(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{"Work", "Home", "garden"} {
+		project := &models.Project{ID: uuid.New(), AuthorID: user.ID, Name: name, CreatedAt: time.Now().UTC()}
+		if err := s.CreateProject(ctx, project); err != nil {
+			return fmt.Errorf("create project %q: %w", name, err)
+		}
+	}
+
+	taken := &models.Project{ID: uuid.New(), AuthorID: user.ID, Name: "Work", CreatedAt: time.Now().UTC()}
+	if err := s.CreateProject(ctx, taken); !errors.Is(err, my_err.ErrProjectExists) {
+		return fmt.Errorf("create project with taken name: want %v, got %v", my_err.ErrProjectExists, err)
+	}
+
+	projects, err := s.GetProjects(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get projects: %w", err)
+	}
+
+	// Names sort bytewise, capitals first.
+	var names []string
+	for _, project := range projects {
+		names = append(names, project.Name)
+	}
+	if want := []string{"Home", "Work", "garden"}; !slices.Equal(names, want) {
+		return fmt.Errorf("get projects: want %q, got %q", want, names)
+	}
+
+	if err := s.ProjectExists(ctx, uuid.New(), user.ID); !errors.Is(err, my_err.ErrProjectNotFound) {
+		return fmt.Errorf("unknown project exists: want %v, got %v", my_err.ErrProjectNotFound, err)
+	}
+
+	return nil
+}
+
+func checkChecklist(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	task := newTask(user.ID, "Pack", "a")
+	if err := s.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+
+	first := 0
+	var items []*models.ChecklistItem
+	for i, text := range []string{"socks", "shirt", "passport"} {
+		item := &models.ChecklistItem{ID: uuid.New(), TaskID: task.ID, Text: text}
+		var position *int
+		if i == 2 {
+			position = &first
+		}
+		if err := s.AddChecklistItem(ctx, user.ID, item, position); err != nil {
+			return fmt.Errorf("add item %q: %w", text, err)
+		}
+		items = append(items, item)
+	}
+
+	if err := checklistTexts(ctx, s, task.ID, user.ID, "passport", "socks", "shirt"); err != nil {
+		return err
+	}
+
+	if _, err := s.MoveChecklistItem(ctx, items[2].ID, user.ID, 10); err != nil {
+		return fmt.Errorf("move item: %w", err)
+	}
+	if err := checklistTexts(ctx, s, task.ID, user.ID, "socks", "shirt", "passport"); err != nil {
+		return err
+	}
+
+	if _, err := s.RemoveChecklistItem(ctx, items[0].ID, user.ID); err != nil {
+		return fmt.Errorf("remove item: %w", err)
+	}
+	if err := checklistTexts(ctx, s, task.ID, user.ID, "shirt", "passport"); err != nil {
+		return err
+	}
+
+	// Watchers and syncs of several tasks rely on GetTask filling in the checklists.
+	tasks, err := s.GetTask(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get tasks: %w", err)
+	}
+	if len(tasks) != 1 || len(tasks[0].Checklist) != 2 {
+		return fmt.Errorf("get tasks: want the task with its 2 checklist items, got %+v", tasks)
+	}
+
+	if _, err := s.ToggleChecklistItem(ctx, items[0].ID, user.ID); !errors.Is(err, my_err.ErrChecklistItemNotFound) {
+		return fmt.Errorf("toggle removed item: want %v, got %v", my_err.ErrChecklistItemNotFound, err)
+	}
+
+	missing := &models.ChecklistItem{ID: uuid.New(), TaskID: uuid.New(), Text: "lost"}
+	if err := s.AddChecklistItem(ctx, user.ID, missing, nil); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("add item to unknown task: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+
+	return nil
+}
+
+// checklistTexts checks that the checklist holds the texts in order, at contiguous positions from zero.
+func checklistTexts(ctx context.Context, s Storage, taskID, author uuid.UUID, texts ...string) error {
+	items, err := s.GetChecklist(ctx, taskID, author)
+	if err != nil {
+		return fmt.Errorf("get checklist: %w", err)
+	}
+
+	var got []string
+	for i, item := range items {
+		if item.Position != i {
+			return fmt.Errorf("get checklist: item %q at position %d, want %d", item.Text, item.Position, i)
+		}
+		got = append(got, item.Text)
+	}
+
+	if !slices.Equal(got, texts) {
+		return fmt.Errorf("get checklist: want %q, got %q", texts, got)
+	}
+
+	return nil
+}
+
+func checkPaging(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	for _, position := range []string{"c", "a", "b", "B"} {
+		if err := s.CreateTask(ctx, newTask(user.ID, "Task "+position, position)); err != nil {
+			return fmt.Errorf("create task: %w", err)
+		}
+	}
+	// Positions sort bytewise.
+	want := []string{"Task B", "Task a", "Task b", "Task c"}
+
+	group := models.RankGroup{AuthorID: user.ID, Status: models.StatusToDo}
+	var (
+		got   []string
+		after models.BoardCursor
+	)
+	for {
+		tasks, err := s.GetColumnTasks(ctx, group, after, 3)
+		if err != nil {
+			return fmt.Errorf("get column tasks: %w", err)
+		}
+		for _, task := range tasks {
+			got = append(got, task.Title)
+		}
+		if len(tasks) < 3 {
+			break
+		}
+		last := tasks[len(tasks)-1]
+		after = models.BoardCursor{Position: last.Position, TaskID: last.ID}
+	}
+	if !slices.Equal(got, want) {
+		return fmt.Errorf("page through column: want %q, got %q", want, got)
+	}
+
+	page, err := s.GetTaskPage(ctx, user.ID, models.BoardCursor{}, 2)
+	if err != nil {
+		return fmt.Errorf("get task page: %w", err)
+	}
+	if len(page) != 2 || page[0].Title != want[0] || page[1].Title != want[1] {
+		return fmt.Errorf("get first task page: want %q, got %d tasks", want[:2], len(page))
+	}
+
+	counts, err := s.CountBoardTasks(ctx, user.ID, uuid.NullUUID{})
+	if err != nil {
+		return fmt.Errorf("count board tasks: %w", err)
+	}
+	if counts[models.StatusToDo] != len(want) {
+		return fmt.Errorf("count board tasks: want %d to do, got %v", len(want), counts)
+	}
+
+	return nil
+}
+
+func checkSearch(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	report := newTask(user.ID, "Quarterly report", "a")
+	report.Description = "collect the numbers"
+	mention := newTask(user.ID, "Call accountant", "b")
+	mention.Description = "about the quarterly numbers"
+	other := newTask(user.ID, "Groceries", "c")
+	for _, task := range []*models.Task{report, mention, other} {
+		if err := s.CreateTask(ctx, task); err != nil {
+			return fmt.Errorf("create task: %w", err)
+		}
+	}
+
+	hits, err := s.SearchTasks(ctx, user.ID, "quarter", 10, 0)
+	if err != nil {
+		return fmt.Errorf("search: %w", err)
+	}
+	if len(hits) != 2 || hits[0].Task.ID != report.ID || hits[1].Task.ID != mention.ID {
+		return fmt.Errorf("search: want the title match before the description match, got %d hits", len(hits))
+	}
+	if hits[0].TitleHighlight != "<mark>Quarterly</mark> report" {
+		return fmt.Errorf("search: got title highlight %q", hits[0].TitleHighlight)
+	}
+
+	// The highlights are HTML, anything typed into a task comes out escaped.
+	markup := newTask(user.ID, `<img src=x onerror="alert(1)"> invoice`, "d")
+	markup.Description = "pay the <b>invoice</b> & file it"
+	if err := s.CreateTask(ctx, markup); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+	hits, err = s.SearchTasks(ctx, user.ID, "invoice", 10, 0)
+	if err != nil {
+		return fmt.Errorf("search markup: %w", err)
+	}
+	if len(hits) != 1 {
+		return fmt.Errorf("search markup: want 1 hit, got %d", len(hits))
+	}
+	if want := "&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>invoice</mark>"; hits[0].TitleHighlight != want {
+		return fmt.Errorf("search markup: want title highlight %q, got %q", want, hits[0].TitleHighlight)
+	}
+	if want := "pay the &lt;b&gt;<mark>invoice</mark>&lt;/b&gt; &amp; file it"; hits[0].Snippet != want {
+		return fmt.Errorf("search markup: want snippet %q, got %q", want, hits[0].Snippet)
+	}
+
+	if _, err := s.SearchTasks(ctx, user.ID, " -*", 10, 0); !errors.Is(err, my_err.ErrEmptyQuery) {
+		return fmt.Errorf("search without words: want %v, got %v", my_err.ErrEmptyQuery, err)
+	}
+
+	stranger, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+	hits, err = s.SearchTasks(ctx, stranger.ID, "quarter", 10, 0)
+	if err != nil {
+		return fmt.Errorf("search as another author: %w", err)
+	}
+	if len(hits) != 0 {
+		return fmt.Errorf("search as another author: got %d hits", len(hits))
+	}
+
+	return nil
+}
+
+func checkTimer(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	task := newTask(user.ID, "Focus", "a")
+	if err := s.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+
+	start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+	entry := &models.TimeEntry{ID: uuid.New(), TaskID: task.ID, AuthorID: user.ID, StartedAt: start}
+	if err := s.StartTimer(ctx, entry); err != nil {
+		return fmt.Errorf("start timer: %w", err)
+	}
+
+	second := &models.TimeEntry{ID: uuid.New(), TaskID: task.ID, AuthorID: user.ID, StartedAt: start}
+	if err := s.StartTimer(ctx, second); !errors.Is(err, my_err.ErrTimerRunning) {
+		return fmt.Errorf("start second timer: want %v, got %v", my_err.ErrTimerRunning, err)
+	}
+
+	stopped, err := s.StopTimer(ctx, user.ID, start.Add(90*time.Second))
+	if err != nil {
+		return fmt.Errorf("stop timer: %w", err)
+	}
+	if stopped.ID != entry.ID || stopped.DurationSeconds != 90 {
+		return fmt.Errorf("stop timer: want 90s of %s, got %ds of %s", entry.ID, stopped.DurationSeconds, stopped.ID)
+	}
+
+	if _, err := s.StopTimer(ctx, user.ID, time.Now()); !errors.Is(err, my_err.ErrNoRunningTimer) {
+		return fmt.Errorf("stop stopped timer: want %v, got %v", my_err.ErrNoRunningTimer, err)
+	}
+
+	lost := &models.TimeEntry{ID: uuid.New(), TaskID: uuid.New(), AuthorID: user.ID, StartedAt: start}
+	if err := s.StartTimer(ctx, lost); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("start timer on unknown task: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+
+	got, err := s.GetTaskByID(ctx, task.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("get task: %w", err)
+	}
+	if got.TrackedSeconds != 90 {
+		return fmt.Errorf("get task: want 90 tracked seconds, got %d", got.TrackedSeconds)
+	}
+
+	return nil
+}
+
+func checkViews(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	view := &models.View{ID: uuid.NewString(), AuthorID: user.ID, Name: "Urgent", Query: models.ViewQuery{Due: models.DueOverdue}}
+	if err := s.SaveView(ctx, view); err != nil {
+		return fmt.Errorf("save view: %w", err)
+	}
+
+	taken := &models.View{ID: uuid.NewString(), AuthorID: user.ID, Name: "Urgent"}
+	if err := s.SaveView(ctx, taken); !errors.Is(err, my_err.ErrViewExists) {
+		return fmt.Errorf("save view with taken name: want %v, got %v", my_err.ErrViewExists, err)
+	}
+
+	got, err := s.GetView(ctx, view.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("get view: %w", err)
+	}
+	if got.Name != view.Name || got.Query.Due != view.Query.Due {
+		return fmt.Errorf("get view: want %+v, got %+v", view, got)
+	}
+
+	// Built-in view IDs are not UUIDs and never reach the storage, but a client may send one anyway.
+	if _, err := s.GetView(ctx, "today", user.ID); !errors.Is(err, my_err.ErrViewNotFound) {
+		return fmt.Errorf("get view by malformed ID: want %v, got %v", my_err.ErrViewNotFound, err)
+	}
+
+	if err := s.DeleteView(ctx, view.ID, user.ID); err != nil {
+		return fmt.Errorf("delete view: %w", err)
+	}
+	if err := s.DeleteView(ctx, view.ID, user.ID); !errors.Is(err, my_err.ErrViewNotFound) {
+		return fmt.Errorf("delete view twice: want %v, got %v", my_err.ErrViewNotFound, err)
+	}
+
+	return nil
+}
+
+func checkTemplates(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	template := &models.Template{ID: uuid.New(), AuthorID: user.ID, Name: "Release",
+		Tasks: []models.TemplateTask{{Title: "Tag {{version}}"}}, CreatedAt: time.Now().UTC()}
+	if err := s.SaveTemplate(ctx, template); err != nil {
+		return fmt.Errorf("save template: %w", err)
+	}
+
+	taken := &models.Template{ID: uuid.New(), AuthorID: user.ID, Name: "Release", CreatedAt: time.Now().UTC()}
+	if err := s.SaveTemplate(ctx, taken); !errors.Is(err, my_err.ErrTemplateExists) {
+		return fmt.Errorf("save template with taken name: want %v, got %v", my_err.ErrTemplateExists, err)
+	}
+
+	if _, err := s.GetTemplate(ctx, uuid.New(), user.ID); !errors.Is(err, my_err.ErrTemplateNotFound) {
+		return fmt.Errorf("get unknown template: want %v, got %v", my_err.ErrTemplateNotFound, err)
+	}
+
+	return nil
+}
+
+func checkAttachmentQuota(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	task := newTask(user.ID, "Scan", "a")
+	if err := s.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+
+	const quota = 100
+	attachment := newAttachment(task, 60)
+	if err := s.SaveAttachment(ctx, attachment, quota); err != nil {
+		return fmt.Errorf("save attachment: %w", err)
+	}
+	if err := s.SaveAttachment(ctx, newAttachment(task, 60), quota); !errors.Is(err, my_err.ErrQuotaExceeded) {
+		return fmt.Errorf("save attachment past quota: want %v, got %v", my_err.ErrQuotaExceeded, err)
+	}
+	if err := s.SaveAttachment(ctx, newAttachment(task, 40), quota); err != nil {
+		return fmt.Errorf("save attachment up to quota: %w", err)
+	}
+
+	used, err := s.AttachmentUsage(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("attachment usage: %w", err)
+	}
+	if used != quota {
+		return fmt.Errorf("attachment usage: want %d, got %d", quota, used)
+	}
+
+	if err := s.DeleteAttachment(ctx, attachment.ID, user.ID); err != nil {
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+	if _, err := s.GetAttachment(ctx, attachment.ID, user.ID); !errors.Is(err, my_err.ErrAttachmentNotFound) {
+		return fmt.Errorf("get deleted attachment: want %v, got %v", my_err.ErrAttachmentNotFound, err)
+	}
+
+	return nil
+}
+
+func checkBatch(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	task := newTask(user.ID, "Keep", "a")
+	if err := s.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+	missing := uuid.New()
+
+	if _, err := s.BatchDeleteTasks(ctx, user.ID, []uuid.UUID{task.ID, missing}, true); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("atomic batch with unknown task: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+	if err := s.TaskExists(ctx, task.ID, user.ID); err != nil {
+		return fmt.Errorf("task after failed atomic batch: %w", err)
+	}
+
+	errs, err := s.BatchDeleteTasks(ctx, user.ID, []uuid.UUID{task.ID, missing}, false)
+	if err != nil {
+		return fmt.Errorf("batch with unknown task: %w", err)
+	}
+	if len(errs) != 2 || errs[0] != nil || !errors.Is(errs[1], my_err.ErrTaskNotFound) {
+		return fmt.Errorf("batch with unknown task: want [nil %v], got %v", my_err.ErrTaskNotFound, errs)
+	}
+	if err := s.TaskExists(ctx, task.ID, user.ID); !errors.Is(err, my_err.ErrTaskNotFound) {
+		return fmt.Errorf("task after batch: want %v, got %v", my_err.ErrTaskNotFound, err)
+	}
+
+	return nil
+}
+
+func checkSync(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	task := newTask(user.ID, "Offline", "a")
+	if err := s.CreateSyncedTask(ctx, task, []string{"title"}, time.Now()); err != nil {
+		return fmt.Errorf("create synced task: %w", err)
+	}
+
+	again := newTask(user.ID, "Offline again", "b")
+	again.ID = task.ID
+	if err := s.CreateSyncedTask(ctx, again, []string{"title"}, time.Now()); !errors.Is(err, my_err.ErrTaskIDTaken) {
+		return fmt.Errorf("create synced task with taken ID: want %v, got %v", my_err.ErrTaskIDTaken, err)
+	}
+
+	token, err := s.GetCalendarSyncToken(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get sync token: %w", err)
+	}
+
+	if err := s.DeleteTask(ctx, task.ID, user.ID); err != nil {
+		return fmt.Errorf("delete task: %w", err)
+	}
+
+	deleted, err := s.TaskDeleted(ctx, task.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("task deleted: %w", err)
+	}
+	if !deleted {
+		return errors.New("task deleted: the deletion was not recorded")
+	}
+
+	upTo, err := s.GetCalendarSyncToken(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("get sync token: %w", err)
+	}
+	if upTo <= token {
+		return fmt.Errorf("get sync token: %d after the deletion, %d before", upTo, token)
+	}
+
+	changed, err := s.GetChangedTasks(ctx, user.ID, token, upTo)
+	if err != nil {
+		return fmt.Errorf("get changed tasks: %w", err)
+	}
+	if !slices.Equal(changed, []uuid.UUID{task.ID}) {
+		return fmt.Errorf("get changed tasks: want [%s], got %v", task.ID, changed)
+	}
+
+	return nil
+}
+
+// checkCalendarObjectUpdate checks that a calendar object is only updated from the state it was
+// read in, a PUT with If-Match relies on it.
+func checkCalendarObjectUpdate(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	task := newTask(user.ID, "Write report", "a")
+	if err := s.CreateCalendarObject(ctx, &models.CalendarObject{Task: task, Name: "report.ics", UID: "report"}); err != nil {
+		return fmt.Errorf("create calendar object: %w", err)
+	}
+
+	read, err := s.GetCalendarObject(ctx, user.ID, "report.ics")
+	if err != nil {
+		return fmt.Errorf("get calendar object: %w", err)
+	}
+
+	// Another client changes the task after it was read.
+	changed := *read.Task
+	changed.Title = "Write the report"
+	if err := s.UpdateTask(ctx, &changed); err != nil {
+		return fmt.Errorf("update task: %w", err)
+	}
+
+	stale := *read.Task
+	stale.Title = "Send report"
+	if err := s.UpdateCalendarObject(ctx, &stale, read.ChangeSeq); !errors.Is(err, my_err.ErrETagMismatch) {
+		return fmt.Errorf("update from a stale state: want %v, got %v", my_err.ErrETagMismatch, err)
+	}
+
+	current, err := s.GetCalendarObject(ctx, user.ID, "report.ics")
+	if err != nil {
+		return fmt.Errorf("get calendar object: %w", err)
+	}
+	if current.Task.Title != changed.Title || current.ChangeSeq <= read.ChangeSeq {
+		return fmt.Errorf("want the change of the other client kept, got %q at change %d", current.Task.Title, current.ChangeSeq)
+	}
+
+	update := *current.Task
+	update.Title = "Send report"
+	if err := s.UpdateCalendarObject(ctx, &update, current.ChangeSeq); err != nil {
+		return fmt.Errorf("update from the current state: %w", err)
+	}
+
+	if err := s.DeleteTask(ctx, task.ID, user.ID); err != nil {
+		return fmt.Errorf("delete task: %w", err)
+	}
+	if err := s.UpdateCalendarObject(ctx, &update, current.ChangeSeq); !errors.Is(err, my_err.ErrETagMismatch) {
+		return fmt.Errorf("update deleted task: want %v, got %v", my_err.ErrETagMismatch, err)
+	}
+
+	return nil
+}
+
+func checkOutbox(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	task := newTask(user.ID, "Announce", "a")
+	task.Tags = []string{"news"}
+	if err := s.CreateTask(ctx, task); err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+	if err := s.DeleteTask(ctx, task.ID, user.ID); err != nil {
+		return fmt.Errorf("delete task: %w", err)
+	}
+
+	types, err := pendingEventTypes(ctx, s, task.ID)
+	if err != nil {
+		return err
+	}
+
+	want := []string{models.EventTaskCreated, models.EventTaskTagged, models.EventTaskDeleted}
+	if !slices.Equal(types, want) {
+		return fmt.Errorf("get pending events: want %q, got %q", want, types)
+	}
+
+	// A task imported as done is inserted so, raising no update.
+	imported := newTask(user.ID, "Announced", "b")
+	imported.Status = models.StatusDone
+	if err := s.ImportTasks(ctx, models.ImportCSV, []*models.Task{imported}, []string{""}); err != nil {
+		return fmt.Errorf("import task: %w", err)
+	}
+
+	got, err := s.GetTaskByID(ctx, imported.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("get imported task: %w", err)
+	}
+	if got.Status != models.StatusDone {
+		return fmt.Errorf("get imported task: want status %q, got %q", models.StatusDone, got.Status)
+	}
+
+	types, err = pendingEventTypes(ctx, s, imported.ID)
+	if err != nil {
+		return err
+	}
+	if want := []string{models.EventTaskCreated}; !slices.Equal(types, want) {
+		return fmt.Errorf("get pending events of imported task: want %q, got %q", want, types)
+	}
+
+	return nil
+}
+
+// checkWebhookDeliveries checks that a delivery queued again, as the deliveries of an outbox event
+// published twice are, keeps its first state.
+func checkWebhookDeliveries(ctx context.Context, s Storage) error {
+	user, err := newUser(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	hook := &models.Webhook{ID: uuid.New(), AuthorID: user.ID, URL: "https://example.com/hook", Enabled: true, CreatedAt: now}
+	if err := s.SaveWebhook(ctx, hook); err != nil {
+		return fmt.Errorf("save webhook: %w", err)
+	}
+
+	delivery := &models.WebhookDelivery{ID: uuid.New(), WebhookID: hook.ID, EventType: models.TaskEventCreated,
+		Payload: []byte(`{"type":"created"}`), Status: models.WebhookDeliveryPending, NextAttemptAt: now, CreatedAt: now}
+	if err := s.EnqueueWebhookDeliveries(ctx, []*models.WebhookDelivery{delivery}); err != nil {
+		return fmt.Errorf("enqueue delivery: %w", err)
+	}
+
+	again := *delivery
+	again.Payload = []byte(`{"type":"updated"}`)
+	second := &models.WebhookDelivery{ID: uuid.New(), WebhookID: hook.ID, EventType: models.TaskEventDeleted,
+		Payload: []byte(`{"type":"deleted"}`), Status: models.WebhookDeliveryPending, NextAttemptAt: now, CreatedAt: now}
+	if err := s.EnqueueWebhookDeliveries(ctx, []*models.WebhookDelivery{&again, second}); err != nil {
+		return fmt.Errorf("enqueue delivery again: %w", err)
+	}
+
+	deliveries, err := s.GetWebhookDeliveries(ctx, hook.ID, 10)
+	if err != nil {
+		return fmt.Errorf("get deliveries: %w", err)
+	}
+	if len(deliveries) != 2 {
+		return fmt.Errorf("want the delivery queued once along with the new one, got %d deliveries", len(deliveries))
+	}
+
+	got, err := s.GetWebhookDelivery(ctx, delivery.ID, hook.ID)
+	if err != nil {
+		return fmt.Errorf("get delivery: %w", err)
+	}
+	if string(got.Payload) != string(delivery.Payload) {
+		return fmt.Errorf("want the payload queued first %s, got %s", delivery.Payload, got.Payload)
+	}
+
+	return nil
+}
+
+func checkIdempotency(ctx context.Context, s Storage) error {
+	now := time.Now().UTC()
+	record := &models.IdempotencyRecord{Scope: uuid.NewString(), Key: "create-1", Method: "/todo.Todo/CreateTask",
+		Fingerprint: "abc", ExpiresAt: now.Add(time.Minute)}
+
+	existing, err := s.ReserveIdempotencyKey(ctx, record, now)
+	if err != nil {
+		return fmt.Errorf("reserve key: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("reserve new key: got record %+v", existing)
+	}
+
+	existing, err = s.ReserveIdempotencyKey(ctx, record, now)
+	if err != nil {
+		return fmt.Errorf("reserve key twice: %w", err)
+	}
+	if existing == nil || existing.Fingerprint != record.Fingerprint || existing.Completed {
+		return fmt.Errorf("reserve key twice: want the pending record, got %+v", existing)
+	}
+
+	record.Completed = true
+	record.Response = []byte{0, 1, 2}
+	if err := s.CompleteIdempotencyKey(ctx, record); err != nil {
+		return fmt.Errorf("complete key: %w", err)
+	}
+
+	existing, err = s.ReserveIdempotencyKey(ctx, record, now)
+	if err != nil {
+		return fmt.Errorf("reserve completed key: %w", err)
+	}
+	if existing == nil || !existing.Completed || !slices.Equal(existing.Response, record.Response) {
+		return fmt.Errorf("reserve completed key: want the completed record, got %+v", existing)
+	}
+
+	// Expired records give their key up.
+	existing, err = s.ReserveIdempotencyKey(ctx, record, now.Add(time.Hour))
+	if err != nil {
+		return fmt.Errorf("reserve expired key: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("reserve expired key: got record %+v", existing)
+	}
+
+	if err := s.ReleaseIdempotencyKey(ctx, record.Scope, record.Key); err != nil {
+		return fmt.Errorf("release key: %w", err)
+	}
+
+	return nil
+}
+
+func registerUser(ctx context.Context, s AuthStorage) (*models.User, error) {
+	user := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com", PasswordHash: "hash", Timezone: "UTC"}
+	if err := s.Register(ctx, user); err != nil {
+		return nil, fmt.Errorf("register: %w", err)
+	}
+
+	return user, nil
+}
+
+// newUser saves a reference to a user, the way the task service does on a user.created event.
+func newUser(ctx context.Context, s Storage) (*models.User, error) {
+	user := &models.User{ID: uuid.New()}
+	if err := s.SaveUserReference(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("save user reference: %w", err)
+	}
+
+	return user, nil
+}
+
+// pendingEventTypes returns the types of the pending events of the aggregate in order. Other
+// events may be pending, only the order of the ones of a check matters.
+func pendingEventTypes(ctx context.Context, s outbox_relay.OutboxProvider, aggregateID uuid.UUID) ([]string, error) {
+	var types []string
+	var afterSeq int64
+	for {
+		events, err := s.GetPendingOutboxEvents(ctx, afterSeq, 100)
+		if err != nil {
+			return nil, fmt.Errorf("get pending events: %w", err)
+		}
+		for _, event := range events {
+			if event.Seq <= afterSeq {
+				return nil, fmt.Errorf("get pending events: event %d after %d", event.Seq, afterSeq)
+			}
+			afterSeq = event.Seq
+
+			if event.AggregateID == aggregateID {
+				types = append(types, event.Type)
+			}
+		}
+		if len(events) < 100 {
+			return types, nil
+		}
+	}
+}
+
+// newTask returns a to-do task of the author in the inbox, the way the task service fills one in.
+func newTask(author uuid.UUID, title, position string) *models.Task {
+	return &models.Task{
+		ID:       uuid.New(),
+		AuthorID: author,
+		Title:    title,
+		Status:   models.StatusToDo,
+		Priority: models.PriorityNone,
+		Position: position,
+	}
+}
+
+func newAttachment(task *models.Task, size int64) *models.Attachment {
+	id := uuid.New()
+
+	return &models.Attachment{
+		ID:          id,
+		TaskID:      task.ID,
+		AuthorID:    task.AuthorID,
+		Name:        "scan.pdf",
+		ContentType: "application/pdf",
+		Size:        size,
+		SHA256:      "0000",
+		StorageKey:  id.String(),
+		CreatedAt:   time.Now().UTC(),
+	}
+}
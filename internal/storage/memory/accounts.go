@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// SaveUserReference records that the user exists, saving a known user again changes nothing.
+// A deleted user is refused with my_err.ErrUserDeleted.
+func (s *Storage) SaveUserReference(ctx context.Context, userID uuid.UUID) error {
+	const op = "storage.memory.SaveUserReference"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin().saveUserReference(userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteUserData deletes the reference to the user with everything of theirs, keeping the user as
+// deleted, and returns the attachments it deleted, whose blobs are left to the caller. An unknown
+// user has nothing to delete.
+func (s *Storage) DeleteUserData(ctx context.Context, userID uuid.UUID) ([]*models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var attachments []*models.Attachment
+	for _, attachment := range s.attachments {
+		if attachment.AuthorID == userID {
+			deleted := *attachment
+			attachments = append(attachments, &deleted)
+		}
+	}
+
+	// Tasks go in the order they were inserted, as the database deletes them, raising their events.
+	var rows []*taskRow
+	for _, row := range s.tasks {
+		if row.AuthorID == userID {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *taskRow) int { return cmp.Compare(a.seq, b.seq) })
+
+	t := s.begin()
+	for _, row := range rows {
+		t.deleteTask(row)
+	}
+
+	deleteAuthorRows(s.projects, userID, func(project *models.Project) uuid.UUID { return project.AuthorID })
+	deleteAuthorRows(s.timeEntries, userID, func(entry *models.TimeEntry) uuid.UUID { return entry.AuthorID })
+	deleteAuthorRows(s.views, userID, func(view *viewRow) uuid.UUID { return view.author })
+	deleteAuthorRows(s.templates, userID, func(template *templateRow) uuid.UUID { return template.author })
+	deleteAuthorRows(s.imports, userID, func(imported taskImport) uuid.UUID { return imported.author })
+	deleteAuthorRows(s.webhooks, userID, func(webhook *webhookRow) uuid.UUID { return webhook.AuthorID })
+	for id, delivery := range s.deliveries {
+		if _, ok := s.webhooks[delivery.WebhookID]; !ok {
+			delete(s.deliveries, id)
+		}
+	}
+	delete(s.feeds, userID)
+
+	// The change log forgets the user, tombstones of the tasks just deleted included.
+	s.changes = slices.DeleteFunc(s.changes, func(change taskChange) bool { return change.author == userID })
+	delete(s.horizons, userID)
+
+	for key := range s.idempotency {
+		if key.scope == userID.String() {
+			delete(s.idempotency, key)
+		}
+	}
+
+	delete(s.references, userID)
+	s.deleted[userID] = struct{}{}
+
+	return attachments, nil
+}
+
+func deleteAuthorRows[K comparable, V any](rows map[K]V, author uuid.UUID, authorOf func(row V) uuid.UUID) {
+	for key, row := range rows {
+		if authorOf(row) == author {
+			delete(rows, key)
+		}
+	}
+}
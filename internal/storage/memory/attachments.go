@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// SaveAttachment only stores the attachment if the author's total attachment size stays within the quota.
+func (s *Storage) SaveAttachment(ctx context.Context, attachment *models.Attachment, quota int64) error {
+	const op = "storage.memory.SaveAttachment"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.attachmentUsage(attachment.AuthorID)+attachment.Size > quota {
+		return fmt.Errorf("%s: %w", op, my_err.ErrQuotaExceeded)
+	}
+
+	if _, ok := s.attachments[attachment.ID]; ok {
+		return fmt.Errorf("%s: %w", op, errIDTaken)
+	}
+
+	saved := *attachment
+	saved.CreatedAt = saved.CreatedAt.UTC()
+	s.attachments[saved.ID] = &saved
+
+	return nil
+}
+
+func (s *Storage) GetAttachments(ctx context.Context, taskID, author uuid.UUID) ([]*models.Attachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var attachments []*models.Attachment
+	for _, attachment := range s.attachments {
+		if attachment.TaskID == taskID && attachment.AuthorID == author {
+			found := *attachment
+			attachments = append(attachments, &found)
+		}
+	}
+	slices.SortFunc(attachments, func(a, b *models.Attachment) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), compareIDs(a.ID, b.ID))
+	})
+
+	return attachments, nil
+}
+
+func (s *Storage) GetAttachment(ctx context.Context, attachmentID, author uuid.UUID) (*models.Attachment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	attachment, ok := s.attachments[attachmentID]
+	if !ok || attachment.AuthorID != author {
+		return nil, my_err.ErrAttachmentNotFound
+	}
+
+	found := *attachment
+	return &found, nil
+}
+
+func (s *Storage) AttachmentUsage(ctx context.Context, author uuid.UUID) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.attachmentUsage(author), nil
+}
+
+func (s *Storage) attachmentUsage(author uuid.UUID) int64 {
+	var used int64
+	for _, attachment := range s.attachments {
+		if attachment.AuthorID == author {
+			used += attachment.Size
+		}
+	}
+
+	return used
+}
+
+func (s *Storage) DeleteAttachment(ctx context.Context, attachmentID, author uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.attachments[attachmentID]
+	if !ok || attachment.AuthorID != author {
+		return my_err.ErrAttachmentNotFound
+	}
+
+	delete(s.attachments, attachmentID)
+
+	return nil
+}
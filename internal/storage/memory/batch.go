@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// BatchCreateTasks stores the tasks with their tags at once, see runBatch for atomic.
+func (s *Storage) BatchCreateTasks(ctx context.Context, tasks []*models.Task, atomic bool) ([]error, error) {
+	const op = "storage.memory.BatchCreateTasks"
+
+	errs, err := s.runBatch(len(tasks), atomic, func(t *tx, i int) error {
+		return t.insertTask(tasks[i])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return errs, nil
+}
+
+// BatchUpdateTasks applies patch to the tasks at once, see runBatch for atomic.
+// A non-empty positions[i] moves task i there, which goes with a change of status.
+func (s *Storage) BatchUpdateTasks(ctx context.Context, author uuid.UUID, taskIDs []uuid.UUID, positions []string, patch models.TaskPatch, atomic bool) ([]error, error) {
+	const op = "storage.memory.BatchUpdateTasks"
+
+	errs, err := s.runBatch(len(taskIDs), atomic, func(t *tx, i int) error {
+		row := s.authorTask(taskIDs[i], author)
+		if row == nil {
+			return my_err.ErrTaskNotFound
+		}
+
+		t.updateTask(row, true, func(task *models.Task) {
+			if patch.Status != nil {
+				task.Status = *patch.Status
+			}
+			if patch.Priority != nil {
+				task.Priority = *patch.Priority
+			}
+			if patch.SetDue {
+				task.Deadline = patch.Deadline
+				task.DueDate = patch.DueDate
+			}
+			if positions[i] != "" {
+				task.Position = positions[i]
+			}
+		})
+
+		for _, tag := range patch.AddTags {
+			t.addTag(row, tag)
+		}
+
+		for _, tag := range patch.RemoveTags {
+			t.removeTag(row, tag)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return errs, nil
+}
+
+// BatchDeleteTasks deletes the tasks at once, see runBatch for atomic.
+func (s *Storage) BatchDeleteTasks(ctx context.Context, author uuid.UUID, taskIDs []uuid.UUID, atomic bool) ([]error, error) {
+	const op = "storage.memory.BatchDeleteTasks"
+
+	errs, err := s.runBatch(len(taskIDs), atomic, func(t *tx, i int) error {
+		row := s.authorTask(taskIDs[i], author)
+		if row == nil {
+			return my_err.ErrTaskNotFound
+		}
+
+		t.deleteTask(row)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return errs, nil
+}
+
+// runBatch calls item for each of the n items of a batch under one hold of the write lock. In
+// atomic mode the first failing item undoes the whole batch and its error is returned. Otherwise
+// a failing item is undone on its own, its error is reported in the returned slice and the other
+// items are kept.
+func (s *Storage) runBatch(n int, atomic bool, item func(t *tx, i int) error) ([]error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.begin()
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		if atomic {
+			if err := item(t, i); err != nil {
+				t.rollback()
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			continue
+		}
+
+		savepoint := t.savepoint()
+		if errs[i] = item(t, i); errs[i] != nil {
+			t.rollbackTo(savepoint)
+		}
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// CountBoardTasks returns the number of tasks per status on the author's board for the project,
+// an invalid project selects the inbox.
+func (s *Storage) CountBoardTasks(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID) (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, row := range s.tasks {
+		if row.AuthorID == author && sameID(row.ProjectID, projectID) {
+			counts[row.Status]++
+		}
+	}
+
+	return counts, nil
+}
+
+// GetColumnTasks returns up to limit tasks of the rank group that sort after the cursor, in position order.
+func (s *Storage) GetColumnTasks(ctx context.Context, group models.RankGroup, after models.BoardCursor, limit int) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := s.authorTasks(group.AuthorID, func(row *taskRow) bool {
+		return row.inGroup(group) && row.sortsAfter(after)
+	})
+
+	return s.readTasksWithChecklists(limited(rows, limit)), nil
+}
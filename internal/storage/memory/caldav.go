@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// errNameTaken stands in for the violation of the unique CalDAV names of the databases.
+var errNameTaken = errors.New("calendar object name already taken")
+
+// GetCalendarSyncToken returns the sequence number of the last change to any task of the author.
+// Pruned changes count too, tokens never go back.
+func (s *Storage) GetCalendarSyncToken(ctx context.Context, author uuid.UUID) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	token := s.horizons[author]
+	for _, change := range s.changes {
+		if change.author == author {
+			token = max(token, change.seq)
+		}
+	}
+
+	return token, nil
+}
+
+// GetCalendarCTags returns the sequence number of the last change to each project of the author,
+// the inbox under the invalid project ID.
+func (s *Storage) GetCalendarCTags(ctx context.Context, author uuid.UUID) (map[uuid.NullUUID]int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ctags := make(map[uuid.NullUUID]int64)
+	for _, change := range s.changes {
+		if change.author == author {
+			ctags[change.projectID] = max(ctags[change.projectID], change.seq)
+		}
+	}
+
+	return ctags, nil
+}
+
+// GetCalendarObjects returns the tasks of a project, or of the inbox when projectID is not valid.
+func (s *Storage) GetCalendarObjects(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID) ([]*models.CalendarObject, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var objects []*models.CalendarObject
+	for _, row := range s.authorTasks(author, func(row *taskRow) bool { return sameID(row.ProjectID, projectID) }) {
+		objects = append(objects, s.calendarObject(row))
+	}
+
+	return objects, nil
+}
+
+// GetCalendarObject returns the task with the given CalDAV name, whichever project it is in.
+func (s *Storage) GetCalendarObject(ctx context.Context, author uuid.UUID, name string) (*models.CalendarObject, error) {
+	const op = "storage.memory.GetCalendarObject"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, row := range s.tasks {
+		if row.AuthorID == author && row.name == name {
+			return s.calendarObject(row), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, my_err.ErrCalendarObjectNotFound)
+}
+
+func (s *Storage) calendarObject(row *taskRow) *models.CalendarObject {
+	object := &models.CalendarObject{Task: s.readTask(row), Name: row.name, UID: row.uid}
+	for _, change := range s.changes {
+		if change.taskID == row.ID {
+			object.ModifiedAt = max(object.ModifiedAt, change.changedAt)
+			object.ChangeSeq = max(object.ChangeSeq, change.seq)
+		}
+	}
+
+	return object
+}
+
+// GetCalendarChanges returns the last change to each task of a collection with a sequence number
+// in (after, upTo].
+func (s *Storage) GetCalendarChanges(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID, after, upTo int64) ([]models.CalendarChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	last := make(map[uuid.UUID]taskChange)
+	for _, change := range s.changes {
+		if change.author == author && sameID(change.projectID, projectID) && change.seq > after && change.seq <= upTo {
+			last[change.taskID] = change
+		}
+	}
+
+	changes := make([]models.CalendarChange, 0, len(last))
+	for taskID, change := range last {
+		changes = append(changes, models.CalendarChange{TaskID: taskID, Deleted: change.deleted, Name: change.name})
+	}
+	slices.SortFunc(changes, func(a, b models.CalendarChange) int { return compareIDs(a.TaskID, b.TaskID) })
+
+	return changes, nil
+}
+
+// CreateCalendarObject stores the task of the object with its tags, under the name and UID of the object.
+func (s *Storage) CreateCalendarObject(ctx context.Context, object *models.CalendarObject) error {
+	const op = "storage.memory.CreateCalendarObject"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.begin()
+	if err := t.insertTask(object.Task); err != nil {
+		t.rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := s.tasks[object.Task.ID]
+
+	for _, other := range s.tasks {
+		if other == row || other.AuthorID != row.AuthorID {
+			continue
+		}
+
+		switch {
+		case other.uid == object.UID:
+			t.rollback()
+			return fmt.Errorf("%s: %w", op, my_err.ErrCalendarUIDExists)
+		case other.name == object.Name:
+			t.rollback()
+			return fmt.Errorf("%s: set name: %w", op, errNameTaken)
+		}
+	}
+
+	row.name, row.uid = object.Name, object.UID
+
+	return nil
+}
+
+// UpdateCalendarObject saves the fields a VTODO carries and replaces the tags of the task, as long as
+// changeSeq is still the sequence number of its last change.
+func (s *Storage) UpdateCalendarObject(ctx context.Context, task *models.Task, changeSeq int64) error {
+	const op = "storage.memory.UpdateCalendarObject"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The task was changed or deleted since the caller read it.
+	row := s.authorTask(task.ID, task.AuthorID)
+	if row == nil || s.calendarObject(row).ChangeSeq != changeSeq {
+		return fmt.Errorf("%s: %w", op, my_err.ErrETagMismatch)
+	}
+
+	t := s.begin()
+	t.updateTask(row, true, func(updated *models.Task) {
+		updated.Title = task.Title
+		updated.Description = task.Description
+		updated.Status = task.Status
+		updated.Deadline = task.Deadline
+		updated.DueDate = task.DueDate
+		updated.Priority = task.Priority
+		updated.Recurrence = task.Recurrence
+		updated.Position = task.Position
+	})
+	t.replaceTags(row, task.Tags)
+
+	return nil
+}
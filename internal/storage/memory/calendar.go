@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// SaveCalendarFeed stores the feed of its author, replacing the one before.
+func (s *Storage) SaveCalendarFeed(ctx context.Context, feed *models.CalendarFeed) error {
+	const op = "storage.memory.SaveCalendarFeed"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *feed
+	saved.CreatedAt = saved.CreatedAt.UTC()
+	if err := s.begin().saveUserReference(saved.AuthorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.feeds[saved.AuthorID] = &saved
+
+	return nil
+}
+
+func (s *Storage) GetCalendarFeed(ctx context.Context, tokenHash string) (*models.CalendarFeed, error) {
+	const op = "storage.memory.GetCalendarFeed"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, feed := range s.feeds {
+		if feed.TokenHash == tokenHash {
+			found := *feed
+			return &found, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, my_err.ErrCalendarFeedNotFound)
+}
+
+func (s *Storage) DeleteCalendarFeed(ctx context.Context, author uuid.UUID) error {
+	const op = "storage.memory.DeleteCalendarFeed"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.feeds[author]; !ok {
+		return fmt.Errorf("%s: %w", op, my_err.ErrCalendarFeedNotFound)
+	}
+
+	delete(s.feeds, author)
+
+	return nil
+}
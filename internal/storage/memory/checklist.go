@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// AddChecklistItem inserts the item at the given position, or appends it when position is nil.
+// Positions always stay contiguous starting from zero.
+func (s *Storage) AddChecklistItem(ctx context.Context, author uuid.UUID, item *models.ChecklistItem, position *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.authorTask(item.TaskID, author)
+	if row == nil {
+		return my_err.ErrTaskNotFound
+	}
+
+	count := len(row.checklist)
+	item.Position = count
+	if position != nil && *position >= 0 && *position < count {
+		item.Position = *position
+	}
+
+	items := slices.Clone(row.checklist)
+	for i := range items {
+		if items[i].Position >= item.Position {
+			items[i].Position++
+		}
+	}
+	s.begin().setChecklist(row, append(items, *item))
+
+	return nil
+}
+
+func (s *Storage) ToggleChecklistItem(ctx context.Context, itemID, author uuid.UUID) (*models.ChecklistItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, i := s.checklistItem(itemID, author)
+	if row == nil {
+		return nil, my_err.ErrChecklistItemNotFound
+	}
+
+	items := slices.Clone(row.checklist)
+	items[i].Checked = !items[i].Checked
+	s.begin().setChecklist(row, items)
+
+	toggled := items[i]
+	return &toggled, nil
+}
+
+// MoveChecklistItem moves the item to position, shifting the items in between by one.
+// Out of range positions are clamped to the checklist bounds.
+func (s *Storage) MoveChecklistItem(ctx context.Context, itemID, author uuid.UUID, position int) ([]*models.ChecklistItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, i := s.checklistItem(itemID, author)
+	if row == nil {
+		return nil, my_err.ErrChecklistItemNotFound
+	}
+
+	items := slices.Clone(row.checklist)
+	from := items[i].Position
+	position = max(0, min(position, len(items)-1))
+
+	for j := range items {
+		switch {
+		case j == i:
+			items[j].Position = position
+		case position < from && items[j].Position >= position && items[j].Position < from:
+			items[j].Position++
+		case position > from && items[j].Position > from && items[j].Position <= position:
+			items[j].Position--
+		}
+	}
+	s.begin().setChecklist(row, items)
+
+	return checklist(row), nil
+}
+
+// RemoveChecklistItem deletes the item and compacts the positions of the items after it.
+func (s *Storage) RemoveChecklistItem(ctx context.Context, itemID, author uuid.UUID) ([]*models.ChecklistItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, i := s.checklistItem(itemID, author)
+	if row == nil {
+		return nil, my_err.ErrChecklistItemNotFound
+	}
+
+	removed := row.checklist[i].Position
+	items := slices.Delete(slices.Clone(row.checklist), i, i+1)
+	for j := range items {
+		if items[j].Position > removed {
+			items[j].Position--
+		}
+	}
+	s.begin().setChecklist(row, items)
+
+	return checklist(row), nil
+}
+
+func (s *Storage) GetChecklistItem(ctx context.Context, itemID, author uuid.UUID) (*models.ChecklistItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row, i := s.checklistItem(itemID, author)
+	if row == nil {
+		return nil, my_err.ErrChecklistItemNotFound
+	}
+
+	item := row.checklist[i]
+	return &item, nil
+}
+
+func (s *Storage) GetChecklist(ctx context.Context, taskID, author uuid.UUID) ([]*models.ChecklistItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row := s.authorTask(taskID, author)
+	if row == nil {
+		return nil, nil
+	}
+
+	return checklist(row), nil
+}
+
+// checklistItem finds the item on a task of the author. It returns the task and the index of the
+// item in its checklist, or a nil task.
+func (s *Storage) checklistItem(itemID, author uuid.UUID) (*taskRow, int) {
+	for _, row := range s.tasks {
+		if row.AuthorID != author {
+			continue
+		}
+
+		for i, item := range row.checklist {
+			if item.ID == itemID {
+				return row, i
+			}
+		}
+	}
+
+	return nil, 0
+}
+
+// checklist copies the checklist of the task.
+func checklist(row *taskRow) []*models.ChecklistItem {
+	var items []*models.ChecklistItem
+	for _, item := range row.checklist {
+		items = append(items, &item)
+	}
+
+	return items
+}
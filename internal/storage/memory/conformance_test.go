@@ -0,0 +1,12 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/SlashLight/todo-list/internal/storage/conformance"
+	"github.com/SlashLight/todo-list/internal/storage/memory"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, memory.New(), memory.New())
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// SetTaskEstimate sets or, with a nil estimate, clears the estimate of a task.
+func (s *Storage) SetTaskEstimate(ctx context.Context, taskID, author uuid.UUID, minutes *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.authorTask(taskID, author)
+	if row == nil {
+		return my_err.ErrTaskNotFound
+	}
+
+	var estimate *int
+	if minutes != nil {
+		estimate = new(int)
+		*estimate = *minutes
+	}
+	s.begin().updateTask(row, false, func(task *models.Task) { task.EstimateMinutes = estimate })
+
+	return nil
+}
+
+// GetEffortByStatus sums the effort of the author's tasks per project and status. Remaining minutes
+// only count estimated tasks that are not done.
+func (s *Storage) GetEffortByStatus(ctx context.Context, author uuid.UUID) ([]models.StatusEffort, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var efforts []models.StatusEffort
+	for _, row := range s.authorTasks(author, func(*taskRow) bool { return true }) {
+		task := s.readTask(row)
+
+		i := slices.IndexFunc(efforts, func(effort models.StatusEffort) bool {
+			return sameID(effort.ProjectID, task.ProjectID) && effort.Status == task.Status
+		})
+		if i < 0 {
+			efforts = append(efforts, models.StatusEffort{ProjectID: task.ProjectID, Status: task.Status})
+			i = len(efforts) - 1
+		}
+
+		// Tracked time is summed in seconds and only then turned into minutes.
+		effort := &efforts[i]
+		effort.Tasks++
+		effort.ActualMinutes += int(task.TrackedSeconds)
+		if task.EstimateMinutes != nil {
+			effort.EstimatedTasks++
+			effort.EstimateMinutes += *task.EstimateMinutes
+			if task.Status != models.StatusDone {
+				effort.RemainingMinutes += max(*task.EstimateMinutes-int(task.TrackedSeconds/60), 0)
+			}
+		}
+	}
+
+	for i := range efforts {
+		efforts[i].ActualMinutes /= 60
+	}
+	slices.SortFunc(efforts, func(a, b models.StatusEffort) int {
+		return cmp.Or(compareNullIDs(a.ProjectID, b.ProjectID), strings.Compare(a.Status, b.Status))
+	})
+
+	return efforts, nil
+}
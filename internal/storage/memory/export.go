@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// GetTaskPage returns up to limit tasks of the author that sort after the cursor, in position order,
+// with their checklists.
+func (s *Storage) GetTaskPage(ctx context.Context, author uuid.UUID, after models.BoardCursor, limit int) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := s.authorTasks(author, func(row *taskRow) bool { return row.sortsAfter(after) })
+
+	return s.readTasksWithChecklists(limited(rows, limit)), nil
+}
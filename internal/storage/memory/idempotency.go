@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+type idempotencyKey struct {
+	scope string
+	key   string
+}
+
+// ReserveIdempotencyKey claims the key of record for a new request and returns nil, or returns
+// the record already holding the key. Expired records are dropped first.
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, now time.Time) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, existing := range s.idempotency {
+		if !existing.ExpiresAt.After(now) {
+			delete(s.idempotency, key)
+		}
+	}
+
+	key := idempotencyKey{scope: record.Scope, key: record.Key}
+	if existing, ok := s.idempotency[key]; ok {
+		found := *existing
+		found.Response = bytes.Clone(found.Response)
+		return &found, nil
+	}
+
+	s.idempotency[key] = &models.IdempotencyRecord{
+		Scope:       record.Scope,
+		Key:         record.Key,
+		Method:      record.Method,
+		Fingerprint: record.Fingerprint,
+		ExpiresAt:   record.ExpiresAt.UTC(),
+	}
+
+	return nil, nil
+}
+
+// CompleteIdempotencyKey stores the outcome of the request holding the key of record.
+func (s *Storage) CompleteIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.idempotency[idempotencyKey{scope: record.Scope, key: record.Key}]
+	if !ok {
+		return nil
+	}
+
+	existing.Completed = true
+	existing.Response = bytes.Clone(record.Response)
+	existing.Code = record.Code
+	existing.Message = record.Message
+	existing.ExpiresAt = record.ExpiresAt.UTC()
+
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets the key, so that the request can be retried with it.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.idempotency, idempotencyKey{scope: scope, key: key})
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// taskImport records the ID a task had in the source it was imported from.
+type taskImport struct {
+	author     uuid.UUID
+	source     string
+	externalID string
+}
+
+// GetImportedTasks maps the external IDs the author imported from source to their tasks.
+func (s *Storage) GetImportedTasks(ctx context.Context, author uuid.UUID, source string) (map[string]uuid.UUID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	imported := make(map[string]uuid.UUID)
+	for taskID, record := range s.imports {
+		if record.author == author && record.source == source {
+			imported[record.externalID] = taskID
+		}
+	}
+
+	return imported, nil
+}
+
+// ImportTasks stores the tasks with their tags and checklists at once and records externalIDs[i]
+// as the ID task i had in source, unless it is empty. Parents must come before their subtasks.
+// A task imported from source before fails the whole import with my_err.ErrAlreadyImported.
+func (s *Storage) ImportTasks(ctx context.Context, source string, tasks []*models.Task, externalIDs []string) error {
+	const op = "storage.memory.ImportTasks"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.begin()
+	for i, task := range tasks {
+		if err := t.insertTask(task); err != nil {
+			t.rollback()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		t.insertChecklist(task)
+
+		if externalIDs[i] == "" {
+			continue
+		}
+
+		record := taskImport{author: task.AuthorID, source: source, externalID: externalIDs[i]}
+		for _, existing := range s.imports {
+			if existing == record {
+				t.rollback()
+				return fmt.Errorf("%s: %w: %q", op, my_err.ErrAlreadyImported, externalIDs[i])
+			}
+		}
+		setRow(t, s.imports, task.ID, record)
+	}
+
+	return nil
+}
@@ -0,0 +1,404 @@
+// Package memory keeps the data of the services in the memory of the process, for tests and for a
+// demo that needs no database. It behaves like the sqlite package, the triggers of its schema
+// included, and loses everything when the process exits. Lookups scan the rows, which only suits
+// small data sets.
+//
+// Users live with the auth service, and in demo mode the auth and task services each hold a storage
+// of their own, the task service's knowing the users by the references it is told about. Like the
+// databases it saves the reference to an author along with their first row, which may come before
+// the user.created event does, and refuses the rows of the users it was told were deleted. The other
+// references are checked by the services before they write them.
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// errIDTaken stands in for the primary key violations of the databases. The services pick new
+// random IDs, so they never run into it.
+var errIDTaken = errors.New("ID already taken")
+
+// Storage guards every row with one lock, a write holds it from start to end the way a
+// serializable transaction would.
+type Storage struct {
+	mu sync.RWMutex
+
+	users map[uuid.UUID]*models.User
+	// references are the users of the auth service the task service was told about.
+	references map[uuid.UUID]struct{}
+	// deleted are the users whose user.deleted the task service was told about, whose writes are
+	// refused from then on.
+	deleted     map[uuid.UUID]struct{}
+	tasks       map[uuid.UUID]*taskRow
+	projects    map[uuid.UUID]*models.Project
+	attachments map[uuid.UUID]*models.Attachment
+	timeEntries map[uuid.UUID]*models.TimeEntry
+	views       map[string]*viewRow
+	templates   map[uuid.UUID]*templateRow
+	idempotency map[idempotencyKey]*models.IdempotencyRecord
+	// imports and feeds are keyed by task and by author.
+	imports    map[uuid.UUID]taskImport
+	feeds      map[uuid.UUID]*models.CalendarFeed
+	webhooks   map[uuid.UUID]*webhookRow
+	deliveries map[uuid.UUID]*deliveryRow
+
+	// changes is the log of changes to tasks clients sync from, in sequence order,
+	// horizons the sequence number of the last tombstone pruned from it per author.
+	changes  []taskChange
+	horizons map[uuid.UUID]int64
+	outbox   []outboxRow
+
+	// Last sequence numbers handed out to changes, outbox events and rows, which stand in for the
+	// rowid that orders the rows of a table the way they were inserted.
+	lastChange int64
+	lastEvent  int64
+	lastRow    int64
+}
+
+func New() *Storage {
+	return &Storage{
+		users:       make(map[uuid.UUID]*models.User),
+		references:  make(map[uuid.UUID]struct{}),
+		deleted:     make(map[uuid.UUID]struct{}),
+		tasks:       make(map[uuid.UUID]*taskRow),
+		projects:    make(map[uuid.UUID]*models.Project),
+		attachments: make(map[uuid.UUID]*models.Attachment),
+		timeEntries: make(map[uuid.UUID]*models.TimeEntry),
+		views:       make(map[string]*viewRow),
+		templates:   make(map[uuid.UUID]*templateRow),
+		idempotency: make(map[idempotencyKey]*models.IdempotencyRecord),
+		imports:     make(map[uuid.UUID]taskImport),
+		feeds:       make(map[uuid.UUID]*models.CalendarFeed),
+		webhooks:    make(map[uuid.UUID]*webhookRow),
+		deliveries:  make(map[uuid.UUID]*deliveryRow),
+		horizons:    make(map[uuid.UUID]int64),
+	}
+}
+
+func (s *Storage) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user := s.userByEmail(email)
+	if user == nil {
+		return nil, my_err.ErrUserNotFound
+	}
+
+	found := *user
+	return &found, nil
+}
+
+func (s *Storage) Register(ctx context.Context, user *models.User) error {
+	const op = "storage.memory.Register"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.userByEmail(user.Email) != nil {
+		return fmt.Errorf("%s: %w", op, my_err.ErrUserExists)
+	}
+
+	if _, ok := s.users[user.ID]; ok {
+		return fmt.Errorf("%s: %w", op, errIDTaken)
+	}
+
+	t := s.begin()
+	registered := *user
+	setRow(t, s.users, user.ID, &registered)
+	t.recordUserEvent(models.EventUserCreated, &registered)
+
+	return nil
+}
+
+// SetTimezone changes the timezone of the user's profile and returns the updated user.
+func (s *Storage) SetTimezone(ctx context.Context, userID uuid.UUID, timezone string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, my_err.ErrUserNotFound
+	}
+
+	t := s.begin()
+	updated := *user
+	updated.Timezone = timezone
+	setRow(t, s.users, userID, &updated)
+	t.recordUserEvent(models.EventUserUpdated, &updated)
+
+	found := updated
+	return &found, nil
+}
+
+// DeleteUser deletes the user, their deletion is published through the outbox.
+func (s *Storage) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return my_err.ErrUserNotFound
+	}
+
+	t := s.begin()
+	deleteRow(t, s.users, userID)
+	t.recordEvent(models.AggregateUser, userID, models.EventUserDeleted, struct {
+		ID uuid.UUID `json:"id"`
+	}{userID})
+
+	return nil
+}
+
+func (s *Storage) userByEmail(email string) *models.User {
+	for _, user := range s.users {
+		if user.Email == email {
+			return user
+		}
+	}
+
+	return nil
+}
+
+// CreateTask inserts the task together with its tags.
+func (s *Storage) CreateTask(ctx context.Context, task *models.Task) error {
+	const op = "storage.memory.CreateTask"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.begin()
+	if err := t.insertTask(task); err != nil {
+		t.rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CreateTasks stores the tasks with their tags and checklists all at once.
+// Parents must come before their subtasks.
+func (s *Storage) CreateTasks(ctx context.Context, tasks []*models.Task) error {
+	const op = "storage.memory.CreateTasks"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.begin()
+	for _, task := range tasks {
+		if err := t.insertTask(task); err != nil {
+			t.rollback()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		t.insertChecklist(task)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetTask(ctx context.Context, author uuid.UUID) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.readTasksWithChecklists(s.authorTasks(author, func(*taskRow) bool { return true })), nil
+}
+
+func (s *Storage) GetTaskByID(ctx context.Context, taskID, author uuid.UUID) (*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row := s.authorTask(taskID, author)
+	if row == nil {
+		return nil, my_err.ErrTaskNotFound
+	}
+
+	return s.readTask(row), nil
+}
+
+func (s *Storage) TaskExists(ctx context.Context, taskID, author uuid.UUID) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.authorTask(taskID, author) == nil {
+		return my_err.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) UpdateTask(ctx context.Context, newTask *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.authorTask(newTask.ID, newTask.AuthorID)
+	if row == nil {
+		return my_err.ErrTaskNotFound
+	}
+
+	s.begin().updateTask(row, true, func(task *models.Task) {
+		task.Title = newTask.Title
+		task.Description = newTask.Description
+		task.Status = newTask.Status
+		task.Deadline = newTask.Deadline
+		task.DueDate = newTask.DueDate
+		task.Position = newTask.Position
+	})
+
+	return nil
+}
+
+func (s *Storage) DeleteTask(ctx context.Context, taskID, author uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.authorTask(taskID, author)
+	if row == nil {
+		return my_err.ErrTaskNotFound
+	}
+
+	s.begin().deleteTask(row)
+
+	return nil
+}
+
+// authorTask returns the task if it belongs to the author, nil otherwise.
+func (s *Storage) authorTask(taskID, author uuid.UUID) *taskRow {
+	row, ok := s.tasks[taskID]
+	if !ok || row.AuthorID != author {
+		return nil
+	}
+
+	return row
+}
+
+// authorTasks returns the tasks of the author that match, in position order.
+func (s *Storage) authorTasks(author uuid.UUID, match func(row *taskRow) bool) []*taskRow {
+	var rows []*taskRow
+	for _, row := range s.tasks {
+		if row.AuthorID == author && match(row) {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, byPosition)
+
+	return rows
+}
+
+// readTask returns a copy of the task the way the databases read it: with the time tracked on it,
+// but without its checklist.
+func (s *Storage) readTask(row *taskRow) *models.Task {
+	task := row.Task
+	task.Tags = slices.Clone(row.Tags)
+	if row.EstimateMinutes != nil {
+		minutes := *row.EstimateMinutes
+		task.EstimateMinutes = &minutes
+	}
+
+	for _, entry := range s.timeEntries {
+		if entry.TaskID == row.ID {
+			task.TrackedSeconds += entry.DurationSeconds
+		}
+	}
+
+	return &task
+}
+
+// readTasks copies the rows with readTask.
+func (s *Storage) readTasks(rows []*taskRow) []*models.Task {
+	var tasks []*models.Task
+	for _, row := range rows {
+		tasks = append(tasks, s.readTask(row))
+	}
+
+	return tasks
+}
+
+// readTasksWithChecklists copies the rows with readTask and fills in their checklists.
+func (s *Storage) readTasksWithChecklists(rows []*taskRow) []*models.Task {
+	tasks := s.readTasks(rows)
+	for i, task := range tasks {
+		task.Checklist = checklist(rows[i])
+	}
+
+	return tasks
+}
+
+// byPosition orders tasks by position and then by ID, both compare bytewise.
+func byPosition(a, b *taskRow) int {
+	return compareRank(a.Position, a.ID, b.Position, b.ID)
+}
+
+// sortsAfter reports whether the task sorts after the cursor. The zero cursor sorts before every task.
+func (row *taskRow) sortsAfter(cursor models.BoardCursor) bool {
+	return compareRank(row.Position, row.ID, cursor.Position, cursor.TaskID) > 0
+}
+
+func compareRank(position string, id uuid.UUID, otherPosition string, otherID uuid.UUID) int {
+	return cmp.Or(strings.Compare(position, otherPosition), compareIDs(id, otherID))
+}
+
+// compareIDs orders IDs bytewise, which is how both databases order them.
+func compareIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// compareNullIDs orders invalid IDs first, the way SQLite orders NULL.
+func compareNullIDs(a, b uuid.NullUUID) int {
+	if a.Valid != b.Valid {
+		if a.Valid {
+			return 1
+		}
+		return -1
+	}
+
+	return compareIDs(a.UUID, b.UUID)
+}
+
+// inGroup reports whether the task belongs to the rank group.
+func (row *taskRow) inGroup(group models.RankGroup) bool {
+	return row.AuthorID == group.AuthorID && sameID(row.ProjectID, group.ProjectID) && row.Status == group.Status
+}
+
+// sameID compares IDs the way IS does in SQL, invalid IDs are NULL.
+func sameID(a, b uuid.NullUUID) bool {
+	return a.Valid == b.Valid && (!a.Valid || a.UUID == b.UUID)
+}
+
+// nullID drops the UUID of an invalid ID, so that stored IDs compare with ==.
+func nullID(id uuid.NullUUID) uuid.NullUUID {
+	if !id.Valid {
+		return uuid.NullUUID{}
+	}
+
+	return id
+}
+
+// limited returns the first limit items, every item when limit is negative as with LIMIT in SQLite.
+func limited[T any](items []T, limit int) []T {
+	if limit >= 0 && len(items) > limit {
+		return items[:limit]
+	}
+
+	return items
+}
+
+// deadlineValue keeps deadlines in UTC, the way the databases return them.
+func deadlineValue(deadline time.Time) time.Time {
+	if deadline.IsZero() {
+		return time.Time{}
+	}
+
+	return deadline.UTC()
+}
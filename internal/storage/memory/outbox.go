@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// The outbox is filled by the writes to tasks, tags and users through recordEvent, so that an
+// event is recorded if and only if the change raising it is kept.
+
+// GetPendingOutboxEvents returns up to limit events recorded after afterSeq that were not dispatched yet.
+func (s *Storage) GetPendingOutboxEvents(ctx context.Context, afterSeq int64, limit int) ([]models.OutboxEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []models.OutboxEvent
+	for _, row := range s.outbox {
+		if row.dispatchedAt == 0 && row.event.Seq > afterSeq {
+			event := row.event
+			event.Payload = bytes.Clone(event.Payload)
+			events = append(events, event)
+		}
+	}
+
+	return limited(events, limit), nil
+}
+
+func (s *Storage) MarkOutboxEventDispatched(ctx context.Context, seq int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.outbox {
+		if s.outbox[i].event.Seq == seq {
+			s.outbox[i].dispatchedAt = at.UnixMilli()
+		}
+	}
+
+	return nil
+}
+
+// PruneOutbox deletes the events dispatched before the given time and returns how many it deleted.
+func (s *Storage) PruneOutbox(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.outbox[:0]
+	for _, row := range s.outbox {
+		if row.dispatchedAt == 0 || row.dispatchedAt >= before.UnixMilli() {
+			kept = append(kept, row)
+		}
+	}
+
+	pruned := int64(len(s.outbox) - len(kept))
+	clear(s.outbox[len(kept):])
+	s.outbox = kept
+
+	return pruned, nil
+}
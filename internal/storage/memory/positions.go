@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func (s *Storage) LastTaskPosition(ctx context.Context, group models.RankGroup) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var position string
+	for _, row := range s.tasks {
+		if row.inGroup(group) {
+			position = max(position, row.Position)
+		}
+	}
+
+	return position, nil
+}
+
+// GetTaskRanks returns the positions of the tasks in the rank group, in order.
+func (s *Storage) GetTaskRanks(ctx context.Context, group models.RankGroup) ([]models.TaskRank, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ranks []models.TaskRank
+	for _, row := range s.authorTasks(group.AuthorID, func(row *taskRow) bool { return row.inGroup(group) }) {
+		ranks = append(ranks, models.TaskRank{ID: row.ID, Position: row.Position})
+	}
+
+	return ranks, nil
+}
+
+func (s *Storage) SetTaskPosition(ctx context.Context, taskID, author uuid.UUID, position string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.authorTask(taskID, author)
+	if row == nil {
+		return my_err.ErrTaskNotFound
+	}
+
+	s.begin().updateTask(row, false, func(task *models.Task) { task.Position = position })
+
+	return nil
+}
+
+// SetTaskPlacement moves a task to another status column and position at once.
+func (s *Storage) SetTaskPlacement(ctx context.Context, taskID, author uuid.UUID, status, position string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.authorTask(taskID, author)
+	if row == nil {
+		return my_err.ErrTaskNotFound
+	}
+
+	s.begin().setPlacement(row, status, position)
+
+	return nil
+}
+
+func (t *tx) setPlacement(row *taskRow, status, position string) {
+	t.updateTask(row, true, func(task *models.Task) {
+		task.Status = status
+		task.Position = position
+	})
+}
+
+// SetTaskPositions rewrites the positions of several tasks of one author atomically.
+// Tasks the author does not have are skipped.
+func (s *Storage) SetTaskPositions(ctx context.Context, author uuid.UUID, ranks []models.TaskRank) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.begin()
+	for _, rank := range ranks {
+		if row := s.authorTask(rank.ID, author); row != nil {
+			t.updateTask(row, false, func(task *models.Task) { task.Position = rank.Position })
+		}
+	}
+
+	return nil
+}
+
+// GetOverlongRankGroups lists the rank groups holding at least one position longer than maxLength.
+func (s *Storage) GetOverlongRankGroups(ctx context.Context, maxLength int) ([]models.RankGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var groups []models.RankGroup
+	for _, row := range s.tasks {
+		group := models.RankGroup{AuthorID: row.AuthorID, ProjectID: row.ProjectID, Status: row.Status}
+		if utf8.RuneCountInString(row.Position) > maxLength && !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	slices.SortFunc(groups, func(a, b models.RankGroup) int {
+		return cmp.Or(compareIDs(a.AuthorID, b.AuthorID), compareNullIDs(a.ProjectID, b.ProjectID), strings.Compare(a.Status, b.Status))
+	})
+
+	return groups, nil
+}
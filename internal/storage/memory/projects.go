@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func (s *Storage) CreateProject(ctx context.Context, project *models.Project) error {
+	const op = "storage.memory.CreateProject"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.projects {
+		if existing.AuthorID == project.AuthorID && existing.Name == project.Name {
+			return fmt.Errorf("%s: %w", op, my_err.ErrProjectExists)
+		}
+	}
+
+	if _, ok := s.projects[project.ID]; ok {
+		return fmt.Errorf("%s: %w", op, errIDTaken)
+	}
+
+	created := *project
+	created.CreatedAt = created.CreatedAt.UTC()
+	if err := s.begin().saveUserReference(created.AuthorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.projects[created.ID] = &created
+
+	return nil
+}
+
+func (s *Storage) GetProjects(ctx context.Context, author uuid.UUID) ([]*models.Project, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var projects []*models.Project
+	for _, project := range s.projects {
+		if project.AuthorID == author {
+			found := *project
+			projects = append(projects, &found)
+		}
+	}
+	slices.SortFunc(projects, func(a, b *models.Project) int { return strings.Compare(a.Name, b.Name) })
+
+	return projects, nil
+}
+
+func (s *Storage) ProjectExists(ctx context.Context, projectID, author uuid.UUID) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	project, ok := s.projects[projectID]
+	if !ok || project.AuthorID != author {
+		return my_err.ErrProjectNotFound
+	}
+
+	return nil
+}
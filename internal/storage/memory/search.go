@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/highlight"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	snippetMarker = "…"
+	// snippetTokens is the number of words a snippet shows, as given to the snippet function of FTS5.
+	snippetTokens = 12
+
+	// Title matches weigh more than description matches, as in the bm25 weights of the sqlite package.
+	titleWeight       = 10
+	descriptionWeight = 5
+)
+
+// SearchTasks looks for tasks of the author where every word of the query starts a word of the
+// title or the description, best matches first. Words are compared the way the unicode61 tokenizer
+// of the sqlite package compares them: ignoring case and diacritics.
+func (s *Storage) SearchTasks(ctx context.Context, author uuid.UUID, query string, limit, offset int) ([]*models.SearchHit, error) {
+	terms := strings.FieldsFunc(query, func(r rune) bool { return !isWordRune(r) })
+	if len(terms) == 0 {
+		return nil, my_err.ErrEmptyQuery
+	}
+	for i, term := range terms {
+		terms[i] = fold(term)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var hits []*models.SearchHit
+	for _, row := range s.authorTasks(author, func(*taskRow) bool { return true }) {
+		title, description := tokenize(row.Title), tokenize(row.Description)
+		if !slices.ContainsFunc(terms, func(term string) bool {
+			return !matches(title, term) && !matches(description, term)
+		}) {
+			hits = append(hits, &models.SearchHit{
+				Task: &models.Task{
+					ID:          row.ID,
+					AuthorID:    row.AuthorID,
+					Title:       row.Title,
+					Description: row.Description,
+					Status:      row.Status,
+					Deadline:    row.Deadline,
+					DueDate:     row.DueDate,
+				},
+				Rank:           -float64(titleWeight*countMatches(title, terms) + descriptionWeight*countMatches(description, terms)),
+				TitleHighlight: highlight.HTML(mark(row.Title, title, terms, 0, len(title))),
+				Snippet:        highlight.HTML(snippet(row.Description, description, terms)),
+			})
+		}
+	}
+	slices.SortStableFunc(hits, func(a, b *models.SearchHit) int { return cmp.Compare(a.Rank, b.Rank) })
+
+	if offset >= len(hits) {
+		return nil, nil
+	}
+
+	return limited(hits[offset:], limit), nil
+}
+
+// token is a word of a text, by its byte offsets and in the form it is compared in.
+type token struct {
+	start, end int
+	folded     string
+}
+
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		switch {
+		case isWordRune(r) && start < 0:
+			start = i
+		case !isWordRune(r) && start >= 0:
+			tokens = append(tokens, token{start: start, end: i, folded: fold(text[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{start: start, end: len(text), folded: fold(text[start:])})
+	}
+
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// fold lowercases the word and strips its diacritics.
+func fold(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, norm.NFD.String(word))
+}
+
+func (tok token) matches(terms []string) bool {
+	return slices.ContainsFunc(terms, func(term string) bool { return strings.HasPrefix(tok.folded, term) })
+}
+
+func matches(tokens []token, term string) bool {
+	return slices.ContainsFunc(tokens, func(tok token) bool { return strings.HasPrefix(tok.folded, term) })
+}
+
+func countMatches(tokens []token, terms []string) int {
+	var count int
+	for _, tok := range tokens {
+		if tok.matches(terms) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// mark returns the text from the first to the last of the tokens in [from, to) with the
+// matching ones marked, the whole text when the range covers every token.
+func mark(text string, tokens []token, terms []string, from, to int) string {
+	if from >= to {
+		return text
+	}
+
+	start, end := tokens[from].start, tokens[to-1].end
+	if from == 0 {
+		start = 0
+	}
+	if to == len(tokens) {
+		end = len(text)
+	}
+
+	var b strings.Builder
+	last := start
+	for _, tok := range tokens[from:to] {
+		if tok.matches(terms) {
+			b.WriteString(text[last:tok.start])
+			b.WriteString(highlight.Start)
+			b.WriteString(text[tok.start:tok.end])
+			b.WriteString(highlight.End)
+			last = tok.end
+		}
+	}
+	b.WriteString(text[last:end])
+
+	return b.String()
+}
+
+// snippet cuts the text down to snippetTokens words starting at its first match, or at its
+// start when nothing in it matches, and marks the ends it cut.
+func snippet(text string, tokens []token, terms []string) string {
+	if len(tokens) <= snippetTokens {
+		return mark(text, tokens, terms, 0, len(tokens))
+	}
+
+	from := max(slices.IndexFunc(tokens, func(tok token) bool { return tok.matches(terms) }), 0)
+	from = min(from, len(tokens)-snippetTokens)
+	to := from + snippetTokens
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString(snippetMarker)
+	}
+	b.WriteString(mark(text, tokens, terms, from, to))
+	if to < len(tokens) {
+		b.WriteString(snippetMarker)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// GetTaskClocks returns when each field of the task was last written, fields written only when
+// the task was created are left out.
+func (s *Storage) GetTaskClocks(ctx context.Context, taskID, author uuid.UUID) (map[string]time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clocks := make(map[string]time.Time)
+	if row := s.authorTask(taskID, author); row != nil {
+		for field, changedAt := range row.clocks {
+			clocks[field] = time.UnixMilli(changedAt)
+		}
+	}
+
+	return clocks, nil
+}
+
+// CreateSyncedTask stores a task a client created offline with its tags, recording changedAt as
+// the time its fields were written.
+func (s *Storage) CreateSyncedTask(ctx context.Context, task *models.Task, fields []string, changedAt time.Time) error {
+	const op = "storage.memory.CreateSyncedTask"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.begin()
+	if err := t.insertTask(task); err != nil {
+		t.rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	row := s.tasks[task.ID]
+
+	t.setClocks(row, fields, changedAt)
+
+	return nil
+}
+
+// UpdateSyncedTask saves the fields offline changes set and replaces the tags of the task,
+// recording changedAt as the time the fields were written.
+func (s *Storage) UpdateSyncedTask(ctx context.Context, task *models.Task, fields []string, changedAt time.Time) error {
+	const op = "storage.memory.UpdateSyncedTask"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.authorTask(task.ID, task.AuthorID)
+	if row == nil {
+		return fmt.Errorf("%s: %w", op, my_err.ErrTaskNotFound)
+	}
+
+	t := s.begin()
+	t.updateTask(row, true, func(updated *models.Task) {
+		updated.Title = task.Title
+		updated.Description = task.Description
+		updated.Status = task.Status
+		updated.Deadline = task.Deadline
+		updated.DueDate = task.DueDate
+		updated.Priority = task.Priority
+		updated.Recurrence = task.Recurrence
+		updated.ProjectID = task.ProjectID
+		updated.Position = task.Position
+	})
+	t.replaceTags(row, task.Tags)
+
+	// The write stamped the fields with the time of this write, the change was made earlier.
+	t.setClocks(row, fields, changedAt)
+
+	return nil
+}
+
+// TaskDeleted reports whether the author had the task and deleted it, as far as the change log remembers.
+func (s *Storage) TaskDeleted(ctx context.Context, taskID, author uuid.UUID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.tasks[taskID]; ok {
+		return false, nil
+	}
+
+	return slices.ContainsFunc(s.changes, func(change taskChange) bool {
+		return change.taskID == taskID && change.author == author && change.deleted
+	}), nil
+}
+
+// GetChangedTasks returns the IDs of the author's tasks with a change numbered in (after, upTo],
+// live and deleted ones alike, the last changed last.
+func (s *Storage) GetChangedTasks(ctx context.Context, author uuid.UUID, after, upTo int64) ([]uuid.UUID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	last := make(map[uuid.UUID]int64)
+	for _, change := range s.changes {
+		if change.author == author && change.seq > after && change.seq <= upTo {
+			last[change.taskID] = change.seq
+		}
+	}
+
+	var taskIDs []uuid.UUID
+	for taskID := range last {
+		taskIDs = append(taskIDs, taskID)
+	}
+	slices.SortFunc(taskIDs, func(a, b uuid.UUID) int { return cmp.Compare(last[a], last[b]) })
+
+	return taskIDs, nil
+}
+
+// GetChangeHorizon returns the sequence number of the last tombstone of the author that was
+// pruned, 0 when none was.
+func (s *Storage) GetChangeHorizon(ctx context.Context, author uuid.UUID) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.horizons[author], nil
+}
+
+// PruneTaskChanges deletes the changes made before the given time that are not the last change
+// of a live task, moving the horizon of their authors past the pruned tombstones. It returns how
+// many changes it deleted.
+func (s *Storage) PruneTaskChanges(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// CalDAV ETags and sync still need the last change of a live task.
+	last := make(map[uuid.UUID]int64)
+	for _, change := range s.changes {
+		if _, ok := s.tasks[change.taskID]; ok {
+			last[change.taskID] = change.seq
+		}
+	}
+
+	kept := s.changes[:0]
+	for _, change := range s.changes {
+		if change.changedAt >= before.Unix() || last[change.taskID] == change.seq {
+			kept = append(kept, change)
+			continue
+		}
+
+		if change.deleted {
+			s.horizons[change.author] = max(s.horizons[change.author], change.seq)
+		}
+	}
+
+	pruned := int64(len(s.changes) - len(kept))
+	clear(s.changes[len(kept):])
+	s.changes = kept
+
+	return pruned, nil
+}
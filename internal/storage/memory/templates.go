@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// templateRow keeps the tasks as JSON, as the databases do. Variables are derived from the tasks
+// by the service and are not stored.
+type templateRow struct {
+	id        uuid.UUID
+	author    uuid.UUID
+	projectID uuid.NullUUID
+	name      string
+	tasks     []byte
+	createdAt time.Time
+}
+
+func (s *Storage) SaveTemplate(ctx context.Context, template *models.Template) error {
+	const op = "storage.memory.SaveTemplate"
+
+	tasks, err := json.Marshal(template.Tasks)
+	if err != nil {
+		return fmt.Errorf("%s: marshal tasks: %w", op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.templates {
+		if existing.author == template.AuthorID && existing.name == template.Name {
+			return fmt.Errorf("%s: %w", op, my_err.ErrTemplateExists)
+		}
+	}
+
+	if _, ok := s.templates[template.ID]; ok {
+		return fmt.Errorf("%s: %w", op, errIDTaken)
+	}
+
+	if err := s.begin().saveUserReference(template.AuthorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.templates[template.ID] = &templateRow{
+		id:        template.ID,
+		author:    template.AuthorID,
+		projectID: nullID(template.ProjectID),
+		name:      template.Name,
+		tasks:     tasks,
+		createdAt: template.CreatedAt.UTC(),
+	}
+
+	return nil
+}
+
+func (s *Storage) GetTemplates(ctx context.Context, author uuid.UUID) ([]*models.Template, error) {
+	const op = "storage.memory.GetTemplates"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []*templateRow
+	for _, row := range s.templates {
+		if row.author == author {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *templateRow) int { return strings.Compare(a.name, b.name) })
+
+	templates := make([]*models.Template, 0, len(rows))
+	for _, row := range rows {
+		template, err := row.template()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+func (s *Storage) GetTemplate(ctx context.Context, templateID, author uuid.UUID) (*models.Template, error) {
+	const op = "storage.memory.GetTemplate"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row, ok := s.templates[templateID]
+	if !ok || row.author != author {
+		return nil, my_err.ErrTemplateNotFound
+	}
+
+	template, err := row.template()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return template, nil
+}
+
+func (s *Storage) DeleteTemplate(ctx context.Context, templateID, author uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.templates[templateID]
+	if !ok || row.author != author {
+		return my_err.ErrTemplateNotFound
+	}
+
+	delete(s.templates, templateID)
+
+	return nil
+}
+
+func (row *templateRow) template() (*models.Template, error) {
+	template := &models.Template{
+		ID:        row.id,
+		AuthorID:  row.author,
+		ProjectID: row.projectID,
+		Name:      row.name,
+		CreatedAt: row.createdAt,
+	}
+	if err := json.Unmarshal(row.tasks, &template.Tasks); err != nil {
+		return nil, fmt.Errorf("unmarshal tasks: %w", err)
+	}
+
+	return template, nil
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// StartTimer inserts a running entry. It fails with ErrTimerRunning when the author already has one.
+func (s *Storage) StartTimer(ctx context.Context, entry *models.TimeEntry) error {
+	const op = "storage.memory.StartTimer"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.authorTask(entry.TaskID, entry.AuthorID) == nil {
+		return my_err.ErrTaskNotFound
+	}
+
+	if s.runningEntry(entry.AuthorID) != nil {
+		return fmt.Errorf("%s: %w", op, my_err.ErrTimerRunning)
+	}
+
+	if _, ok := s.timeEntries[entry.ID]; ok {
+		return fmt.Errorf("%s: %w", op, errIDTaken)
+	}
+
+	s.timeEntries[entry.ID] = &models.TimeEntry{
+		ID:        entry.ID,
+		TaskID:    entry.TaskID,
+		AuthorID:  entry.AuthorID,
+		StartedAt: entry.StartedAt.UTC(),
+		Note:      entry.Note,
+	}
+
+	return nil
+}
+
+// StopTimer finishes the author's running entry at endedAt and returns it.
+func (s *Storage) StopTimer(ctx context.Context, author uuid.UUID, endedAt time.Time) (*models.TimeEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.runningEntry(author)
+	if entry == nil {
+		return nil, my_err.ErrNoRunningTimer
+	}
+
+	entry.EndedAt = endedAt.UTC()
+	entry.DurationSeconds = max(int64(endedAt.Sub(entry.StartedAt)/time.Second), 0)
+
+	stopped := *entry
+	return &stopped, nil
+}
+
+func (s *Storage) runningEntry(author uuid.UUID) *models.TimeEntry {
+	for _, entry := range s.timeEntries {
+		if entry.AuthorID == author && entry.Running() {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// LogTime stores a finished entry.
+func (s *Storage) LogTime(ctx context.Context, entry *models.TimeEntry) error {
+	const op = "storage.memory.LogTime"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.timeEntries[entry.ID]; ok {
+		return fmt.Errorf("%s: %w", op, errIDTaken)
+	}
+
+	logged := *entry
+	logged.StartedAt = logged.StartedAt.UTC()
+	logged.EndedAt = logged.EndedAt.UTC()
+	s.timeEntries[logged.ID] = &logged
+
+	return nil
+}
+
+func (s *Storage) GetTimeEntries(ctx context.Context, taskID, author uuid.UUID) ([]*models.TimeEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.authorTask(taskID, author) == nil {
+		return nil, nil
+	}
+
+	var entries []*models.TimeEntry
+	for _, entry := range s.timeEntries {
+		if entry.TaskID == taskID {
+			found := *entry
+			entries = append(entries, &found)
+		}
+	}
+	slices.SortFunc(entries, func(a, b *models.TimeEntry) int { return a.StartedAt.Compare(b.StartedAt) })
+
+	return entries, nil
+}
+
+// GetTimeReport sums the author's finished entries that started within [from, to) per project and task.
+// An invalid projectID reports on every project.
+func (s *Storage) GetTimeReport(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID, from, to time.Time) ([]*models.ProjectTime, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type reportRow struct {
+		project  models.ProjectTime
+		named    bool
+		taskTime models.TaskTime
+	}
+
+	var rows []*reportRow
+	for _, entry := range s.timeEntries {
+		task, ok := s.tasks[entry.TaskID]
+		if !ok || entry.AuthorID != author || entry.Running() ||
+			entry.StartedAt.Before(from) || !entry.StartedAt.Before(to) ||
+			projectID.Valid && !sameID(task.ProjectID, projectID) {
+			continue
+		}
+
+		i := slices.IndexFunc(rows, func(row *reportRow) bool { return row.taskTime.TaskID == task.ID })
+		if i < 0 {
+			row := &reportRow{
+				project:  models.ProjectTime{ProjectID: task.ProjectID},
+				taskTime: models.TaskTime{TaskID: task.ID, Title: task.Title},
+			}
+			if project, ok := s.projects[task.ProjectID.UUID]; ok && task.ProjectID.Valid {
+				row.project.ProjectName = project.Name
+				row.named = true
+			}
+			rows = append(rows, row)
+			i = len(rows) - 1
+		}
+		rows[i].taskTime.Seconds += entry.DurationSeconds
+	}
+
+	slices.SortFunc(rows, func(a, b *reportRow) int {
+		if a.named != b.named {
+			if a.named {
+				return -1
+			}
+			return 1
+		}
+
+		return cmp.Or(
+			strings.Compare(a.project.ProjectName, b.project.ProjectName),
+			compareNullIDs(a.project.ProjectID, b.project.ProjectID),
+			strings.Compare(a.taskTime.Title, b.taskTime.Title),
+		)
+	})
+
+	var projects []*models.ProjectTime
+	for _, row := range rows {
+		// Rows are ordered by project, so a project's tasks are contiguous.
+		if n := len(projects); n == 0 || projects[n-1].ProjectID != row.project.ProjectID {
+			project := row.project
+			projects = append(projects, &project)
+		}
+
+		last := projects[len(projects)-1]
+		last.Seconds += row.taskTime.Seconds
+		last.Tasks = append(last.Tasks, &row.taskTime)
+	}
+
+	return projects, nil
+}
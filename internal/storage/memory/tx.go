@@ -0,0 +1,426 @@
+package memory
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// taskRow is a task with the rows of other tables the databases keep for it. Tags stay sorted
+// and the checklist in position order. Slices are replaced rather than changed in place, so that
+// a copy of the row taken before a write can restore it.
+type taskRow struct {
+	models.Task
+
+	// seq orders the tasks the way they were inserted.
+	seq int64
+	// name and uid are the CalDAV name and UID of the task.
+	name string
+	uid  string
+	// clocks holds the Unix time in milliseconds each field was last written, see models.SyncFieldTitle.
+	clocks    map[string]int64
+	checklist []models.ChecklistItem
+}
+
+// taskChange is an entry of the change log clients sync from. Deleted entries are tombstones
+// of a task in a project, named as the client knew it.
+type taskChange struct {
+	seq       int64
+	author    uuid.UUID
+	taskID    uuid.UUID
+	projectID uuid.NullUUID
+	deleted   bool
+	name      string
+	// changedAt is a Unix time.
+	changedAt int64
+}
+
+type outboxRow struct {
+	event models.OutboxEvent
+	// dispatchedAt is a Unix time in milliseconds, 0 until the event is dispatched.
+	dispatchedAt int64
+}
+
+// tx collects how to undo the writes made through it, so that a write failing halfway can leave
+// no trace, the way a rolled back transaction does. Its methods do what the triggers of the
+// database schema do on top of the write.
+type tx struct {
+	s    *Storage
+	undo []func()
+}
+
+// begin starts a transaction, the caller holds the write lock until it is done with it.
+func (s *Storage) begin() *tx {
+	return &tx{s: s}
+}
+
+func (t *tx) onRollback(undo func()) {
+	t.undo = append(t.undo, undo)
+}
+
+// savepoint marks the writes made so far, rollbackTo undoes the ones after the mark.
+func (t *tx) savepoint() int {
+	return len(t.undo)
+}
+
+func (t *tx) rollbackTo(savepoint int) {
+	for len(t.undo) > savepoint {
+		last := len(t.undo) - 1
+		t.undo[last]()
+		t.undo = t.undo[:last]
+	}
+}
+
+func (t *tx) rollback() {
+	t.rollbackTo(0)
+}
+
+// setRow stores the row under the key.
+func setRow[K comparable, V any](t *tx, rows map[K]V, key K, row V) {
+	old, existed := rows[key]
+	rows[key] = row
+	t.onRollback(func() {
+		if existed {
+			rows[key] = old
+		} else {
+			delete(rows, key)
+		}
+	})
+}
+
+func deleteRow[K comparable, V any](t *tx, rows map[K]V, key K) {
+	old, existed := rows[key]
+	if !existed {
+		return
+	}
+
+	delete(rows, key)
+	t.onRollback(func() { rows[key] = old })
+}
+
+// nextRow hands out the number that orders a new row among the rows inserted before it.
+// Numbers are not given back on rollback, as with sequences in Postgres.
+func (t *tx) nextRow() int64 {
+	t.s.lastRow++
+	return t.s.lastRow
+}
+
+// insertTask stores the task the way InsertNewTask does: the task starts without an estimate,
+// then gets its tags. The checklist is left to insertChecklist.
+func (t *tx) insertTask(task *models.Task) error {
+	if _, ok := t.s.tasks[task.ID]; ok {
+		return my_err.ErrTaskIDTaken
+	}
+
+	row := &taskRow{
+		Task: models.Task{
+			ID:          task.ID,
+			AuthorID:    task.AuthorID,
+			ProjectID:   nullID(task.ProjectID),
+			ParentID:    nullID(task.ParentID),
+			Title:       task.Title,
+			Description: task.Description,
+			Status:      task.Status,
+			Deadline:    deadlineValue(task.Deadline),
+			DueDate:     task.DueDate,
+			Priority:    task.Priority,
+			Recurrence:  task.Recurrence,
+			Assignee:    task.Assignee,
+			Position:    task.Position,
+		},
+		seq:    t.nextRow(),
+		name:   task.ID.String() + ".ics",
+		uid:    task.ID.String() + "@todo-list",
+		clocks: make(map[string]int64),
+	}
+	if err := t.saveUserReference(task.AuthorID); err != nil {
+		return err
+	}
+	setRow(t, t.s.tasks, task.ID, row)
+
+	t.recordChange(row)
+	t.recordTaskEvent(models.EventTaskCreated, row)
+
+	for _, tag := range task.Tags {
+		t.addTag(row, tag)
+	}
+
+	return nil
+}
+
+// saveUserReference saves the reference to the author of a new row, whose user.created may not
+// have arrived yet, and refuses a deleted author.
+func (t *tx) saveUserReference(author uuid.UUID) error {
+	if _, ok := t.s.deleted[author]; ok {
+		return my_err.ErrUserDeleted
+	}
+	if _, ok := t.s.references[author]; !ok {
+		setRow(t, t.s.references, author, struct{}{})
+	}
+
+	return nil
+}
+
+// insertChecklist stores the checklist of a new task with the positions it has.
+func (t *tx) insertChecklist(task *models.Task) {
+	if len(task.Checklist) == 0 {
+		return
+	}
+
+	row := t.s.tasks[task.ID]
+	items := slices.Clone(row.checklist)
+	for _, item := range task.Checklist {
+		added := *item
+		added.TaskID = task.ID
+		items = append(items, added)
+	}
+	t.setChecklist(row, items)
+}
+
+// setChecklist replaces the checklist of the task, sorted by position.
+func (t *tx) setChecklist(row *taskRow, items []models.ChecklistItem) {
+	slices.SortStableFunc(items, func(a, b models.ChecklistItem) int { return a.Position - b.Position })
+
+	old := row.checklist
+	row.checklist = items
+	t.onRollback(func() { row.checklist = old })
+}
+
+// updateTask applies change to the task like an UPDATE statement would. synced tells whether the
+// statement sets a column clients sync, which records a change even when no value changes.
+func (t *tx) updateTask(row *taskRow, synced bool, change func(task *models.Task)) {
+	old := row.Task
+	t.onRollback(func() { row.Task = old })
+
+	change(&row.Task)
+	row.ProjectID = nullID(row.ProjectID)
+	row.ParentID = nullID(row.ParentID)
+	row.Deadline = deadlineValue(row.Deadline)
+
+	if !sameID(old.ProjectID, row.ProjectID) {
+		t.recordTombstone(&old, row.name)
+	}
+	if synced {
+		t.recordChange(row)
+	}
+
+	now := time.Now().UnixMilli()
+	changed := map[string]bool{
+		models.SyncFieldTitle:       old.Title != row.Title,
+		models.SyncFieldDescription: old.Description != row.Description,
+		models.SyncFieldStatus:      old.Status != row.Status,
+		models.SyncFieldDue:         !old.Deadline.Equal(row.Deadline) || old.DueDate != row.DueDate,
+		models.SyncFieldPriority:    old.Priority != row.Priority,
+		models.SyncFieldRecurrence:  old.Recurrence != row.Recurrence,
+		models.SyncFieldProject:     !sameID(old.ProjectID, row.ProjectID),
+	}
+	for field, fieldChanged := range changed {
+		if fieldChanged {
+			setRow(t, row.clocks, field, now)
+		}
+	}
+
+	t.recordTaskEvent(models.EventTaskUpdated, row)
+}
+
+// deleteTask deletes the task with the rows that hang off it and leaves its subtasks at the top level.
+func (t *tx) deleteTask(row *taskRow) {
+	t.recordTombstone(&row.Task, row.name)
+	deleteRow(t, t.s.tasks, row.ID)
+
+	for id, attachment := range t.s.attachments {
+		if attachment.TaskID == row.ID {
+			deleteRow(t, t.s.attachments, id)
+		}
+	}
+	for id, entry := range t.s.timeEntries {
+		if entry.TaskID == row.ID {
+			deleteRow(t, t.s.timeEntries, id)
+		}
+	}
+	deleteRow(t, t.s.imports, row.ID)
+
+	var subtasks []*taskRow
+	for _, subtask := range t.s.tasks {
+		if subtask.ParentID.Valid && subtask.ParentID.UUID == row.ID {
+			subtasks = append(subtasks, subtask)
+		}
+	}
+	slices.SortFunc(subtasks, func(a, b *taskRow) int { return cmp.Compare(a.seq, b.seq) })
+	for _, subtask := range subtasks {
+		t.updateTask(subtask, false, func(task *models.Task) { task.ParentID = uuid.NullUUID{} })
+	}
+
+	t.recordEvent(models.AggregateTask, row.ID, models.EventTaskDeleted, struct {
+		ID        uuid.UUID     `json:"id"`
+		AuthorID  uuid.UUID     `json:"author-id"`
+		ProjectID uuid.NullUUID `json:"project-id"`
+	}{row.ID, row.AuthorID, row.ProjectID})
+}
+
+// addTag tags the task unless it already has the tag.
+func (t *tx) addTag(row *taskRow, tag string) {
+	i, found := slices.BinarySearch(row.Tags, tag)
+	if found {
+		return
+	}
+
+	t.setTags(row, slices.Insert(slices.Clone(row.Tags), i, tag))
+	t.recordTagEvent(models.EventTaskTagged, row, tag)
+}
+
+// removeTag takes the tag off the task if it has it.
+func (t *tx) removeTag(row *taskRow, tag string) {
+	i, found := slices.BinarySearch(row.Tags, tag)
+	if !found {
+		return
+	}
+
+	t.setTags(row, slices.Delete(slices.Clone(row.Tags), i, i+1))
+	t.recordTagEvent(models.EventTaskUntagged, row, tag)
+}
+
+// replaceTags gives the task the tags. Only the tags that changed are touched, every write is a
+// change clients sync.
+func (t *tx) replaceTags(row *taskRow, tags []string) {
+	for _, tag := range row.Tags {
+		if !slices.Contains(tags, tag) {
+			t.removeTag(row, tag)
+		}
+	}
+
+	for _, tag := range tags {
+		t.addTag(row, tag)
+	}
+}
+
+func (t *tx) setTags(row *taskRow, tags []string) {
+	old := row.Tags
+	row.Tags = tags
+	t.onRollback(func() { row.Tags = old })
+
+	t.recordChange(row)
+	setRow(t, row.clocks, models.SyncFieldTags, time.Now().UnixMilli())
+}
+
+// setClocks records changedAt as the time the fields were written.
+func (t *tx) setClocks(row *taskRow, fields []string, changedAt time.Time) {
+	for _, field := range fields {
+		setRow(t, row.clocks, field, changedAt.UnixMilli())
+	}
+}
+
+// recordChange appends a change of the task to the change log.
+func (t *tx) recordChange(row *taskRow) {
+	t.appendChange(taskChange{author: row.AuthorID, taskID: row.ID, projectID: row.ProjectID})
+}
+
+// recordTombstone records that the task left its project under the CalDAV name.
+func (t *tx) recordTombstone(task *models.Task, name string) {
+	t.appendChange(taskChange{author: task.AuthorID, taskID: task.ID, projectID: task.ProjectID, deleted: true, name: name})
+}
+
+func (t *tx) appendChange(change taskChange) {
+	t.s.lastChange++
+	change.seq = t.s.lastChange
+	change.changedAt = time.Now().Unix()
+
+	n := len(t.s.changes)
+	t.s.changes = append(t.s.changes, change)
+	t.onRollback(func() { t.s.changes = t.s.changes[:n] })
+}
+
+// taskPayload is the state of a task in its events. The keys match the JSON of a task in the API,
+// tags have events of their own.
+type taskPayload struct {
+	ID              uuid.UUID     `json:"id"`
+	AuthorID        uuid.UUID     `json:"author-id"`
+	ProjectID       uuid.NullUUID `json:"project-id"`
+	ParentID        uuid.NullUUID `json:"parent-id"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description"`
+	Status          string        `json:"status"`
+	Deadline        *string       `json:"deadline"`
+	DueDate         *string       `json:"due-date"`
+	Priority        string        `json:"priority"`
+	Recurrence      *string       `json:"recurrence"`
+	Assignee        *string       `json:"assignee"`
+	Position        string        `json:"position"`
+	EstimateMinutes *int          `json:"estimate-minutes"`
+}
+
+func (t *tx) recordTaskEvent(eventType string, row *taskRow) {
+	payload := taskPayload{
+		ID:              row.ID,
+		AuthorID:        row.AuthorID,
+		ProjectID:       row.ProjectID,
+		ParentID:        row.ParentID,
+		Title:           row.Title,
+		Description:     row.Description,
+		Status:          row.Status,
+		DueDate:         nullString(row.DueDate),
+		Priority:        row.Priority,
+		Recurrence:      nullString(row.Recurrence),
+		Assignee:        nullString(row.Assignee),
+		Position:        row.Position,
+		EstimateMinutes: row.EstimateMinutes,
+	}
+	if !row.Deadline.IsZero() {
+		payload.Deadline = nullString(row.Deadline.Format("2006-01-02T15:04:05Z"))
+	}
+
+	t.recordEvent(models.AggregateTask, row.ID, eventType, payload)
+}
+
+// Tags deleted with their task need no event of their own, removeTag is never called for them.
+func (t *tx) recordTagEvent(eventType string, row *taskRow, tag string) {
+	t.recordEvent(models.AggregateTask, row.ID, eventType, struct {
+		ID  uuid.UUID `json:"id"`
+		Tag string    `json:"tag"`
+	}{row.ID, tag})
+}
+
+// The password hash never leaves the storage.
+func (t *tx) recordUserEvent(eventType string, user *models.User) {
+	t.recordEvent(models.AggregateUser, user.ID, eventType, struct {
+		ID       uuid.UUID `json:"id"`
+		Email    string    `json:"email"`
+		Timezone string    `json:"timezone"`
+	}{user.ID, user.Email, user.Timezone})
+}
+
+// recordEvent appends a domain event to the outbox, so that it is recorded if and only if the
+// write raising it is kept.
+func (t *tx) recordEvent(aggregateType string, aggregateID uuid.UUID, eventType string, payload any) {
+	// Payloads hold plain values only, marshaling them cannot fail.
+	data, _ := json.Marshal(payload)
+
+	t.s.lastEvent++
+	event := models.OutboxEvent{
+		Seq:           t.s.lastEvent,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Type:          eventType,
+		Payload:       data,
+		OccurredAt:    time.UnixMilli(time.Now().UnixMilli()).UTC(),
+	}
+
+	n := len(t.s.outbox)
+	t.s.outbox = append(t.s.outbox, outboxRow{event: event})
+	t.onRollback(func() { t.s.outbox = t.s.outbox[:n] })
+}
+
+// nullString stands in for a NULL column when value is empty.
+func nullString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// viewRow keeps the query as JSON, as the databases do, so that a saved view shares nothing with
+// the one it was saved from.
+type viewRow struct {
+	id     string
+	author uuid.UUID
+	name   string
+	query  []byte
+}
+
+func (s *Storage) SaveView(ctx context.Context, view *models.View) error {
+	const op = "storage.memory.SaveView"
+
+	query, err := json.Marshal(view.Query)
+	if err != nil {
+		return fmt.Errorf("%s: marshal query: %w", op, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.views {
+		if existing.author == view.AuthorID && existing.name == view.Name {
+			return fmt.Errorf("%s: %w", op, my_err.ErrViewExists)
+		}
+	}
+
+	if _, ok := s.views[view.ID]; ok {
+		return fmt.Errorf("%s: %w", op, errIDTaken)
+	}
+
+	if err := s.begin().saveUserReference(view.AuthorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.views[view.ID] = &viewRow{id: view.ID, author: view.AuthorID, name: view.Name, query: query}
+
+	return nil
+}
+
+func (s *Storage) GetViews(ctx context.Context, author uuid.UUID) ([]*models.View, error) {
+	const op = "storage.memory.GetViews"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []*viewRow
+	for _, row := range s.views {
+		if row.author == author {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *viewRow) int { return strings.Compare(a.name, b.name) })
+
+	views := make([]*models.View, 0, len(rows))
+	for _, row := range rows {
+		view, err := row.view()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+func (s *Storage) GetView(ctx context.Context, viewID string, author uuid.UUID) (*models.View, error) {
+	const op = "storage.memory.GetView"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row, ok := s.views[viewID]
+	if !ok || row.author != author {
+		return nil, my_err.ErrViewNotFound
+	}
+
+	view, err := row.view()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return view, nil
+}
+
+func (s *Storage) DeleteView(ctx context.Context, viewID string, author uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.views[viewID]
+	if !ok || row.author != author {
+		return my_err.ErrViewNotFound
+	}
+
+	delete(s.views, viewID)
+
+	return nil
+}
+
+func (row *viewRow) view() (*models.View, error) {
+	view := &models.View{ID: row.id, AuthorID: row.author, Name: row.name}
+	if err := json.Unmarshal(row.query, &view.Query); err != nil {
+		return nil, fmt.Errorf("unmarshal query: %w", err)
+	}
+
+	return view, nil
+}
@@ -0,0 +1,325 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+type webhookRow struct {
+	models.Webhook
+
+	// seq orders the webhooks the way they were created.
+	seq int64
+}
+
+// deliveryRow keeps the attempt times as Unix times, the precision the databases keep them in.
+type deliveryRow struct {
+	models.WebhookDelivery
+
+	// seq orders the deliveries the way they were queued.
+	seq           int64
+	nextAttemptAt int64
+	// lastAttemptAt is 0 before the first attempt.
+	lastAttemptAt int64
+}
+
+func (s *Storage) SaveWebhook(ctx context.Context, webhook *models.Webhook) error {
+	const op = "storage.memory.SaveWebhook"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[webhook.ID]; ok {
+		return fmt.Errorf("%s: %w", op, errIDTaken)
+	}
+
+	row := &webhookRow{Webhook: *webhook, seq: s.begin().nextRow()}
+	row.ProjectID = nullID(row.ProjectID)
+	row.EventTypes = eventTypes(row.EventTypes)
+	row.CreatedAt = row.CreatedAt.UTC()
+	if err := s.begin().saveUserReference(row.AuthorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.webhooks[row.ID] = row
+
+	return nil
+}
+
+func (s *Storage) GetWebhooks(ctx context.Context, author uuid.UUID) ([]*models.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []*webhookRow
+	for _, row := range s.webhooks {
+		if row.AuthorID == author {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *webhookRow) int { return cmp.Compare(a.seq, b.seq) })
+
+	var webhooks []*models.Webhook
+	for _, row := range rows {
+		webhooks = append(webhooks, row.webhook())
+	}
+
+	return webhooks, nil
+}
+
+func (s *Storage) GetWebhook(ctx context.Context, webhookID, author uuid.UUID) (*models.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row, ok := s.webhooks[webhookID]
+	if !ok || row.AuthorID != author {
+		return nil, my_err.ErrWebhookNotFound
+	}
+
+	return row.webhook(), nil
+}
+
+// UpdateWebhook saves the endpoint, filters and state of the webhook, its secret stays.
+func (s *Storage) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.webhooks[webhook.ID]
+	if !ok || row.AuthorID != webhook.AuthorID {
+		return my_err.ErrWebhookNotFound
+	}
+
+	row.ProjectID = nullID(webhook.ProjectID)
+	row.URL = webhook.URL
+	row.EventTypes = eventTypes(webhook.EventTypes)
+	row.Enabled = webhook.Enabled
+	row.Failures = webhook.Failures
+	row.DisabledReason = webhook.DisabledReason
+
+	return nil
+}
+
+// DeleteWebhook deletes the webhook with its queued and logged deliveries.
+func (s *Storage) DeleteWebhook(ctx context.Context, webhookID, author uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, ok := s.webhooks[webhookID]
+	if !ok || row.AuthorID != author {
+		return my_err.ErrWebhookNotFound
+	}
+
+	delete(s.webhooks, webhookID)
+	for id, delivery := range s.deliveries {
+		if delivery.WebhookID == webhookID {
+			delete(s.deliveries, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) DisableWebhook(ctx context.Context, webhookID uuid.UUID, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if row, ok := s.webhooks[webhookID]; ok {
+		row.Enabled = false
+		row.DisabledReason = reason
+	}
+
+	return nil
+}
+
+// EnqueueWebhookDeliveries queues the deliveries, all of them or none. A delivery already queued
+// under its ID is left as it is.
+func (s *Storage) EnqueueWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	const op = "storage.memory.EnqueueWebhookDeliveries"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.begin()
+	for _, delivery := range deliveries {
+		if err := t.insertDelivery(delivery); err != nil {
+			t.rollback()
+			return fmt.Errorf("%s: insert delivery: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *tx) insertDelivery(delivery *models.WebhookDelivery) error {
+	if _, ok := t.s.webhooks[delivery.WebhookID]; !ok {
+		return my_err.ErrWebhookNotFound
+	}
+
+	if _, ok := t.s.deliveries[delivery.ID]; ok {
+		return nil
+	}
+
+	row := &deliveryRow{WebhookDelivery: *delivery, seq: t.nextRow()}
+	row.Payload = bytes.Clone(row.Payload)
+	row.RedeliveryOf = nullID(row.RedeliveryOf)
+	row.CreatedAt = row.CreatedAt.UTC()
+	row.setAttempts(delivery)
+	setRow(t, t.s.deliveries, row.ID, row)
+
+	return nil
+}
+
+// GetWebhookDeliveries returns the last deliveries of the webhook, the latest first.
+func (s *Storage) GetWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []*deliveryRow
+	for _, row := range s.deliveries {
+		if row.WebhookID == webhookID {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *deliveryRow) int { return cmp.Compare(b.seq, a.seq) })
+
+	var deliveries []*models.WebhookDelivery
+	for _, row := range limited(rows, limit) {
+		deliveries = append(deliveries, row.delivery())
+	}
+
+	return deliveries, nil
+}
+
+func (s *Storage) GetWebhookDelivery(ctx context.Context, deliveryID, webhookID uuid.UUID) (*models.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row, ok := s.deliveries[deliveryID]
+	if !ok || row.WebhookID != webhookID {
+		return nil, my_err.ErrWebhookDeliveryNotFound
+	}
+
+	return row.delivery(), nil
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries of enabled webhooks due by now and
+// holds them back until leaseUntil, so that they are not attempted twice while in flight.
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.QueuedDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []*deliveryRow
+	for _, row := range s.deliveries {
+		webhook, ok := s.webhooks[row.WebhookID]
+		if ok && webhook.Enabled && row.Status == models.WebhookDeliveryPending && row.nextAttemptAt <= now.Unix() {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *deliveryRow) int {
+		return cmp.Or(cmp.Compare(a.nextAttemptAt, b.nextAttemptAt), cmp.Compare(a.seq, b.seq))
+	})
+
+	var queued []models.QueuedDelivery
+	for _, row := range limited(rows, limit) {
+		webhook := s.webhooks[row.WebhookID]
+		queued = append(queued, models.QueuedDelivery{Delivery: row.delivery(), URL: webhook.URL, Secret: webhook.Secret})
+		row.nextAttemptAt = leaseUntil.Unix()
+	}
+
+	return queued, nil
+}
+
+// RecordWebhookAttempt saves the outcome of an attempt of the delivery. A delivered delivery clears
+// the failures of its webhook, a failed one counts as one more. It returns the failures of the webhook.
+func (s *Storage) RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	const op = "storage.memory.RecordWebhookAttempt"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook, ok := s.webhooks[delivery.WebhookID]
+	if !ok {
+		return 0, fmt.Errorf("%s: update failures: %w", op, my_err.ErrWebhookNotFound)
+	}
+
+	if row, ok := s.deliveries[delivery.ID]; ok {
+		row.Status = delivery.Status
+		row.Attempts = delivery.Attempts
+		row.ResponseStatus = delivery.ResponseStatus
+		row.Error = delivery.Error
+		row.setAttempts(delivery)
+	}
+
+	switch delivery.Status {
+	case models.WebhookDeliveryDelivered:
+		webhook.Failures = 0
+	case models.WebhookDeliveryFailed:
+		webhook.Failures++
+	}
+
+	return webhook.Failures, nil
+}
+
+// PruneWebhookDeliveries deletes the delivered and failed deliveries last attempted before the
+// given time and returns how many it deleted.
+func (s *Storage) PruneWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned int64
+	for id, row := range s.deliveries {
+		if row.Status != models.WebhookDeliveryPending && row.lastAttemptAt != 0 && row.lastAttemptAt < before.Unix() {
+			delete(s.deliveries, id)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
+
+func (row *webhookRow) webhook() *models.Webhook {
+	webhook := row.Webhook
+	webhook.EventTypes = slices.Clone(webhook.EventTypes)
+
+	return &webhook
+}
+
+// eventTypes drops empty event types, which the databases cannot tell apart from the separators
+// they store the event types with.
+func eventTypes(types []string) []string {
+	return strings.Fields(strings.Join(types, " "))
+}
+
+func (row *deliveryRow) setAttempts(delivery *models.WebhookDelivery) {
+	row.nextAttemptAt, row.lastAttemptAt = 0, 0
+	if !delivery.NextAttemptAt.IsZero() {
+		row.nextAttemptAt = delivery.NextAttemptAt.Unix()
+	}
+	if !delivery.LastAttemptAt.IsZero() {
+		row.lastAttemptAt = delivery.LastAttemptAt.Unix()
+	}
+}
+
+// delivery returns a copy of the delivery with the times it was attempted at, the next one only
+// while it is pending.
+func (row *deliveryRow) delivery() *models.WebhookDelivery {
+	delivery := row.WebhookDelivery
+	delivery.Payload = bytes.Clone(delivery.Payload)
+	delivery.NextAttemptAt, delivery.LastAttemptAt = time.Time{}, time.Time{}
+	if delivery.Status == models.WebhookDeliveryPending {
+		delivery.NextAttemptAt = time.Unix(row.nextAttemptAt, 0).UTC()
+	}
+	if row.lastAttemptAt != 0 {
+		delivery.LastAttemptAt = time.Unix(row.lastAttemptAt, 0).UTC()
+	}
+
+	return &delivery
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// SaveUserReference records that the user exists, saving a known user again changes nothing.
+// A deleted user is refused with my_err.ErrUserDeleted.
+func (s *Storage) SaveUserReference(ctx context.Context, userID uuid.UUID) error {
+	const op = "storage.postgres.SaveUserReference"
+
+	err := s.inAuthorTx(ctx, userID, func(tx *sql.Tx) error {
+		return saveAuthor(ctx, tx, userID)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// saveAuthor saves the reference to the author of a row about to be inserted, whose user.created
+// may not have arrived yet. A deleted user keeps a valid token until it expires, their writes are
+// refused with my_err.ErrUserDeleted instead of bringing them back. The transaction must come from
+// beginAuthorTx, which makes it take turns with the deletion of the user.
+func saveAuthor(ctx context.Context, tx *sql.Tx, author uuid.UUID) error {
+	var deleted bool
+	if err := tx.QueryRowContext(ctx, SelectUserDeleted, author).Scan(&deleted); err != nil {
+		return fmt.Errorf("select deleted user: %w", err)
+	}
+
+	if deleted {
+		return my_err.ErrUserDeleted
+	}
+
+	if _, err := tx.ExecContext(ctx, InsertUserReference, author); err != nil {
+		return fmt.Errorf("insert user reference: %w", err)
+	}
+
+	return nil
+}
+
+// insertAuthorRow runs the statement inserting a row of the author in a transaction, after saveAuthor.
+func (s *Storage) insertAuthorRow(ctx context.Context, author uuid.UUID, query string, args ...any) error {
+	return s.inAuthorTx(ctx, author, func(tx *sql.Tx) error {
+		if err := saveAuthor(ctx, tx, author); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+}
+
+// DeleteUserData deletes the reference to the user with everything of theirs, keeping the user as
+// deleted, and returns the attachments it deleted, whose blobs are left to the caller. An unknown
+// user has nothing to delete.
+func (s *Storage) DeleteUserData(ctx context.Context, userID uuid.UUID) ([]*models.Attachment, error) {
+	const op = "storage.postgres.DeleteUserData"
+
+	var attachments []*models.Attachment
+	// The cascade deletes the tasks of the user, whose events must be numbered in turn.
+	err := s.inAuthorTx(ctx, userID, func(tx *sql.Tx) error {
+		var err error
+		attachments, err = authorAttachments(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, DeleteUserByID, userID); err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, DeleteIdempotencyKeysByScope, userID.String()); err != nil {
+			return fmt.Errorf("delete idempotency keys: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, InsertDeletedUser, userID); err != nil {
+			return fmt.Errorf("insert deleted user: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attachments, nil
+}
+
+func authorAttachments(ctx context.Context, tx *sql.Tx, author uuid.UUID) ([]*models.Attachment, error) {
+	rows, err := tx.QueryContext(ctx, SelectAttachmentsByAuthor, author)
+	if err != nil {
+		return nil, fmt.Errorf("select attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
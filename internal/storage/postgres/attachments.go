@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// SaveAttachment stores the attachment unless it takes the author past quota bytes. Uploads of one
+// author take turns, so two of them cannot both fit in the room left for one.
+func (s *Storage) SaveAttachment(ctx context.Context, attachment *models.Attachment, quota int64) error {
+	const op = "storage.postgres.SaveAttachment"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, LockUserQuota, attachment.AuthorID); err != nil {
+		return fmt.Errorf("%s: lock quota: %w", op, err)
+	}
+
+	result, err := tx.ExecContext(ctx, InsertAttachmentWithinQuota,
+		attachment.ID, attachment.TaskID, attachment.AuthorID, attachment.Name, attachment.ContentType,
+		attachment.Size, attachment.SHA256, attachment.StorageKey, attachment.CreatedAt, quota)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, my_err.ErrQuotaExceeded)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetAttachments(ctx context.Context, taskID, author uuid.UUID) ([]*models.Attachment, error) {
+	const op = "storage.postgres.GetAttachments"
+
+	rows, err := s.db.QueryContext(ctx, SelectAttachmentsByTask, taskID, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return attachments, nil
+}
+
+func (s *Storage) GetAttachment(ctx context.Context, attachmentID, author uuid.UUID) (*models.Attachment, error) {
+	const op = "storage.postgres.GetAttachment"
+
+	attachment, err := scanAttachment(s.db.QueryRowContext(ctx, SelectAttachmentByID, attachmentID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrAttachmentNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return attachment, nil
+}
+
+func (s *Storage) AttachmentUsage(ctx context.Context, author uuid.UUID) (int64, error) {
+	const op = "storage.postgres.AttachmentUsage"
+
+	var used int64
+	if err := s.db.QueryRowContext(ctx, SelectAttachmentUsage, author).Scan(&used); err != nil {
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return used, nil
+}
+
+func (s *Storage) DeleteAttachment(ctx context.Context, attachmentID, author uuid.UUID) error {
+	const op = "storage.postgres.DeleteAttachment"
+
+	result, err := s.db.ExecContext(ctx, DeleteAttachmentByID, attachmentID, author)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrAttachmentNotFound
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	err := row.Scan(&attachment.ID, &attachment.TaskID, &attachment.AuthorID, &attachment.Name, &attachment.ContentType,
+		&attachment.Size, &attachment.SHA256, &attachment.StorageKey, &attachment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	attachment.CreatedAt = attachment.CreatedAt.UTC()
+
+	return attachment, nil
+}
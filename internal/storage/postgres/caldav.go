@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// GetCalendarSyncToken returns the sequence number of the last change to any task of the author.
+func (s *Storage) GetCalendarSyncToken(ctx context.Context, author uuid.UUID) (int64, error) {
+	const op = "storage.postgres.GetCalendarSyncToken"
+
+	var token int64
+	if err := s.db.QueryRowContext(ctx, SelectCalendarSyncToken, author).Scan(&token); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// GetCalendarCTags returns the sequence number of the last change to each project of the author,
+// the inbox under the invalid project ID.
+func (s *Storage) GetCalendarCTags(ctx context.Context, author uuid.UUID) (map[uuid.NullUUID]int64, error) {
+	const op = "storage.postgres.GetCalendarCTags"
+
+	rows, err := s.db.QueryContext(ctx, SelectCalendarCTags, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	ctags := make(map[uuid.NullUUID]int64)
+	for rows.Next() {
+		var (
+			projectID uuid.NullUUID
+			seq       int64
+		)
+		if err := rows.Scan(&projectID, &seq); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		ctags[projectID] = seq
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return ctags, nil
+}
+
+// GetCalendarObjects returns the tasks of a project, or of the inbox when projectID is not valid.
+func (s *Storage) GetCalendarObjects(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID) ([]*models.CalendarObject, error) {
+	const op = "storage.postgres.GetCalendarObjects"
+
+	rows, err := s.db.QueryContext(ctx, SelectCalendarObjects, author, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var objects []*models.CalendarObject
+	for rows.Next() {
+		object, err := scanCalendarObject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		objects = append(objects, object)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return objects, nil
+}
+
+// GetCalendarObject returns the task with the given CalDAV name, whichever project it is in.
+func (s *Storage) GetCalendarObject(ctx context.Context, author uuid.UUID, name string) (*models.CalendarObject, error) {
+	const op = "storage.postgres.GetCalendarObject"
+
+	object, err := scanCalendarObject(s.db.QueryRowContext(ctx, SelectCalendarObjectByName, author, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, my_err.ErrCalendarObjectNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return object, nil
+}
+
+// GetCalendarChanges returns the last change to each task of a collection with a sequence number
+// in (after, upTo].
+func (s *Storage) GetCalendarChanges(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID, after, upTo int64) ([]models.CalendarChange, error) {
+	const op = "storage.postgres.GetCalendarChanges"
+
+	rows, err := s.db.QueryContext(ctx, SelectCalendarChanges, author, projectID, after, upTo)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var changes []models.CalendarChange
+	for rows.Next() {
+		var (
+			change models.CalendarChange
+			seq    int64
+		)
+		if err := rows.Scan(&change.TaskID, &seq, &change.Deleted, &change.Name); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return changes, nil
+}
+
+// CreateCalendarObject stores the task of the object with its tags, under the name and UID of the object.
+func (s *Storage) CreateCalendarObject(ctx context.Context, object *models.CalendarObject) error {
+	const op = "storage.postgres.CreateCalendarObject"
+
+	tx, err := s.beginAuthorTx(ctx, object.Task.AuthorID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := insertTask(ctx, tx, object.Task); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, UpdateCalendarObjectName, object.Name, object.UID, object.Task.ID); err != nil {
+		if constraint, ok := violation(err, uniqueViolation); ok && constraint == "calendar_object_uid_key" {
+			return fmt.Errorf("%s: %w", op, my_err.ErrCalendarUIDExists)
+		}
+		return fmt.Errorf("%s: set name: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateCalendarObject saves the fields a VTODO carries and replaces the tags of the task, as long as
+// changeSeq is still the sequence number of its last change.
+func (s *Storage) UpdateCalendarObject(ctx context.Context, task *models.Task, changeSeq int64) error {
+	const op = "storage.postgres.UpdateCalendarObject"
+
+	tx, err := s.beginAuthorTx(ctx, task.AuthorID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, UpdateCalendarTask, task.Title, task.Description, task.Status, deadlineValue(task.Deadline),
+		nullString(task.DueDate), task.Priority, nullString(task.Recurrence), task.Position, task.ID, task.AuthorID, changeSeq)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	// The task was changed or deleted since the caller read it.
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, my_err.ErrETagMismatch)
+	}
+
+	if err := replaceTags(ctx, tx, task.ID, task.Tags); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+// replaceTags gives the task the tags. Only the tags that changed are touched, every write is a
+// change clients sync.
+func replaceTags(ctx context.Context, tx *sql.Tx, taskID uuid.UUID, tags []string) error {
+	current, err := taskTags(ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range current {
+		if !slices.Contains(tags, tag) {
+			if _, err := tx.ExecContext(ctx, DeleteTaskTag, taskID, tag); err != nil {
+				return fmt.Errorf("delete tag: %w", err)
+			}
+		}
+	}
+
+	for _, tag := range tags {
+		if !slices.Contains(current, tag) {
+			if _, err := tx.ExecContext(ctx, InsertTaskTag, taskID, tag); err != nil {
+				return fmt.Errorf("insert tag: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func taskTags(ctx context.Context, tx *sql.Tx, taskID uuid.UUID) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, SelectTaskTags, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("select tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// scanCalendarObject reads a row selected with calendarObjectColumns.
+func scanCalendarObject(row rowScanner) (*models.CalendarObject, error) {
+	object := &models.CalendarObject{}
+
+	task, err := scanTask(trailingColumns{row: row, dest: []any{&object.Name, &object.UID, &object.ModifiedAt, &object.ChangeSeq}})
+	if err != nil {
+		return nil, err
+	}
+	object.Task = task
+
+	return object, nil
+}
+
+// trailingColumns scans the columns that follow the ones its caller knows about into dest.
+type trailingColumns struct {
+	row  rowScanner
+	dest []any
+}
+
+func (c trailingColumns) Scan(dest ...any) error {
+	return c.row.Scan(append(dest, c.dest...)...)
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// SaveCalendarFeed stores the feed of its author, replacing the one before.
+func (s *Storage) SaveCalendarFeed(ctx context.Context, feed *models.CalendarFeed) error {
+	const op = "storage.postgres.SaveCalendarFeed"
+
+	err := s.insertAuthorRow(ctx, feed.AuthorID, UpsertCalendarFeed, feed.AuthorID, feed.TokenHash, feed.ReminderMinutes, feed.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetCalendarFeed(ctx context.Context, tokenHash string) (*models.CalendarFeed, error) {
+	const op = "storage.postgres.GetCalendarFeed"
+
+	feed := &models.CalendarFeed{}
+	err := s.db.QueryRowContext(ctx, SelectCalendarFeedByToken, tokenHash).Scan(&feed.AuthorID, &feed.TokenHash,
+		&feed.ReminderMinutes, &feed.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, my_err.ErrCalendarFeedNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	feed.CreatedAt = feed.CreatedAt.UTC()
+
+	return feed, nil
+}
+
+func (s *Storage) DeleteCalendarFeed(ctx context.Context, author uuid.UUID) error {
+	const op = "storage.postgres.DeleteCalendarFeed"
+
+	result, err := s.db.ExecContext(ctx, DeleteCalendarFeed, author)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, my_err.ErrCalendarFeedNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package postgres
+
+const (
+	SelectUserByEmail  = "SELECT id, email, password, timezone FROM users WHERE email = $1"
+	InsertNewUser      = "INSERT INTO users(id, email, password, timezone) VALUES($1, $2, $3, $4)"
+	UpdateUserTimezone = "UPDATE users SET timezone = $1 WHERE id = $2 RETURNING id, email, password, timezone"
+	DeleteUserByID     = "DELETE FROM users WHERE id = $1"
+
+	// The task database keeps only the IDs of the users of the auth database, and of the deleted ones.
+	InsertUserReference = "INSERT INTO users(id) VALUES($1) ON CONFLICT DO NOTHING"
+	SelectUserDeleted   = "SELECT EXISTS(SELECT 1 FROM deleted_user WHERE id = $1)"
+	InsertDeletedUser   = "INSERT INTO deleted_user(id) VALUES($1) ON CONFLICT DO NOTHING"
+
+	// LockAuthor makes the writers of the author's tasks take turns until their transaction ends.
+	LockAuthor = "SELECT pg_advisory_xact_lock(hashtextextended('author:' || $1::text, 0))"
+	// LockUserQuota makes the uploads of the author take turns until their transaction ends.
+	LockUserQuota = "SELECT pg_advisory_xact_lock(hashtextextended('quota:' || $1::text, 0))"
+
+	// taskColumns is the column list scanTask expects.
+	// Tags cannot contain spaces, so they travel as one space separated string.
+	taskColumns = `id, author, project_id, parent_id, title, description, status, deadline, due_date, priority, recurrence, assignee,
+		(SELECT COALESCE(string_agg(tag, ' ' ORDER BY tag), '') FROM task_tag WHERE task_id = task.id),
+		position, (SELECT COALESCE(SUM(e.duration_seconds), 0)::bigint FROM time_entry e WHERE e.task_id = task.id), estimate_minutes`
+
+	SelectTasksByAuthor = "SELECT " + taskColumns + " FROM task WHERE author = $1 ORDER BY position, id"
+	SelectTaskByID      = "SELECT " + taskColumns + " FROM task WHERE id = $1 AND author = $2"
+	SelectTaskExists    = "SELECT 1 FROM task WHERE id = $1 AND author = $2"
+	// LockTask keeps the checklist of the task from changing until the transaction ends.
+	LockTask           = "SELECT 1 FROM task WHERE id = $1 AND author = $2 FOR NO KEY UPDATE"
+	InsertNewTask      = "INSERT INTO task(id, author, project_id, parent_id, title, description, deadline, due_date, priority, recurrence, assignee, position, status) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)"
+	InsertTaskTag      = "INSERT INTO task_tag(task_id, tag) VALUES($1, $2) ON CONFLICT DO NOTHING"
+	DeleteTaskTag      = "DELETE FROM task_tag WHERE task_id = $1 AND tag = $2"
+	UpdateTaskEstimate = "UPDATE task SET estimate_minutes = $1 WHERE id = $2 AND author = $3"
+	UpdateTaskByID     = "UPDATE task SET title = $1, description = $2, status = $3, deadline = $4, due_date = $5, position = $6 WHERE id = $7 AND author = $8"
+	DeleteTaskByID     = "DELETE FROM task WHERE id = $1 AND author = $2"
+
+	// PatchTaskByID leaves a column alone when its parameter is NULL, $3 says whether the due columns change.
+	PatchTaskByID = `UPDATE task SET status = COALESCE($1, status), priority = COALESCE($2, priority),
+		deadline = CASE WHEN $3::boolean THEN $4 ELSE deadline END, due_date = CASE WHEN $3::boolean THEN $5 ELSE due_date END,
+		position = COALESCE($6, position) WHERE id = $7 AND author = $8`
+
+	// Every item of a batch runs in its own savepoint, so a failing item can be undone alone.
+	SavepointBatchItem = "SAVEPOINT batch_item"
+	RollbackBatchItem  = "ROLLBACK TO batch_item"
+	ReleaseBatchItem   = "RELEASE batch_item"
+
+	// Rank groups are keyed by author, project and status. IS NOT DISTINCT FROM matches the NULL project of inbox tasks.
+	SelectLastTaskPosition  = "SELECT COALESCE(MAX(position), '') FROM task WHERE author = $1 AND project_id IS NOT DISTINCT FROM $2 AND status = $3"
+	SelectTaskRanks         = "SELECT id, position FROM task WHERE author = $1 AND project_id IS NOT DISTINCT FROM $2 AND status = $3 ORDER BY position, id"
+	UpdateTaskPosition      = "UPDATE task SET position = $1 WHERE id = $2 AND author = $3"
+	UpdateTaskPlacement     = "UPDATE task SET status = $1, position = $2 WHERE id = $3 AND author = $4"
+	SelectOverlongRankGroup = "SELECT DISTINCT author, project_id, status FROM task WHERE length(position) > $1"
+
+	// SelectEffortByStatus sums estimates and tracked time per project and status. Remaining minutes
+	// only count estimated tasks that are not in the done status $1.
+	SelectEffortByStatus = `SELECT project_id, status, COUNT(*), COUNT(estimate_minutes), COALESCE(SUM(estimate_minutes), 0),
+			SUM(tracked)::bigint / 60,
+			SUM(CASE WHEN status = $1 OR estimate_minutes IS NULL THEN 0 ELSE GREATEST(estimate_minutes - tracked / 60, 0) END)::bigint
+		FROM (SELECT project_id, status, estimate_minutes,
+				(SELECT COALESCE(SUM(e.duration_seconds), 0)::bigint FROM time_entry e WHERE e.task_id = task.id) AS tracked
+			FROM task WHERE author = $2) AS effort
+		GROUP BY project_id, status`
+
+	InsertProject          = "INSERT INTO project(id, author, name, created_at) VALUES($1, $2, $3, $4)"
+	SelectProjectsByAuthor = "SELECT id, author, name, created_at FROM project WHERE author = $1 ORDER BY name"
+	SelectProjectExists    = "SELECT 1 FROM project WHERE id = $1 AND author = $2"
+
+	CountBoardTasks = "SELECT status, COUNT(*) FROM task WHERE author = $1 AND project_id IS NOT DISTINCT FROM $2 GROUP BY status"
+	// SelectColumnTasks pages through one column with a (position, id) keyset cursor.
+	SelectColumnTasks = "SELECT " + taskColumns + ` FROM task
+		WHERE author = $1 AND project_id IS NOT DISTINCT FROM $2 AND status = $3 AND (position, id) > ($4, $5)
+		ORDER BY position, id LIMIT $6`
+	// SelectTaskPage pages through all tasks of the author with a (position, id) keyset cursor.
+	SelectTaskPage = "SELECT " + taskColumns + ` FROM task
+		WHERE author = $1 AND (position, id) > ($2, $3) ORDER BY position, id LIMIT $4`
+
+	// InsertAttachmentWithinQuota only inserts the row if the author's total attachment size stays within the quota.
+	InsertAttachmentWithinQuota = `INSERT INTO attachment(id, task_id, author, name, content_type, size, sha256, storage_key, created_at)
+		SELECT $1::uuid, $2::uuid, $3::uuid, $4::text, $5::text, $6::bigint, $7::text, $8::text, $9::timestamptz
+		WHERE (SELECT COALESCE(SUM(size), 0) FROM attachment WHERE author = $3::uuid) + $6::bigint <= $10`
+	SelectAttachmentsByTask = `SELECT id, task_id, author, name, content_type, size, sha256, storage_key, created_at
+		FROM attachment WHERE task_id = $1 AND author = $2 ORDER BY created_at`
+	SelectAttachmentByID = `SELECT id, task_id, author, name, content_type, size, sha256, storage_key, created_at
+		FROM attachment WHERE id = $1 AND author = $2`
+	SelectAttachmentsByAuthor = `SELECT id, task_id, author, name, content_type, size, sha256, storage_key, created_at
+		FROM attachment WHERE author = $1`
+	SelectAttachmentUsage = "SELECT COALESCE(SUM(size), 0)::bigint FROM attachment WHERE author = $1"
+	DeleteAttachmentByID  = "DELETE FROM attachment WHERE id = $1 AND author = $2"
+
+	SelectChecklistByTask = `SELECT i.id, i.task_id, i.text, i.checked, i.position
+		FROM task_checklist_item i JOIN task t ON t.id = i.task_id
+		WHERE i.task_id = $1 AND t.author = $2 ORDER BY i.position`
+	SelectChecklistsByAuthor = `SELECT i.id, i.task_id, i.text, i.checked, i.position
+		FROM task_checklist_item i JOIN task t ON t.id = i.task_id
+		WHERE t.author = $1 ORDER BY i.task_id, i.position`
+	// SelectChecklistsByTaskRange loads the checklists of the tasks with (position, id) in ($2, $3] to ($4, $5].
+	SelectChecklistsByTaskRange = `SELECT i.id, i.task_id, i.text, i.checked, i.position
+		FROM task_checklist_item i JOIN task t ON t.id = i.task_id
+		WHERE t.author = $1 AND (t.position, t.id) > ($2, $3) AND (t.position, t.id) <= ($4, $5)
+		ORDER BY i.task_id, i.position`
+	SelectChecklistItemByID = `SELECT i.id, i.task_id, i.text, i.checked, i.position
+		FROM task_checklist_item i JOIN task t ON t.id = i.task_id
+		WHERE i.id = $1 AND t.author = $2`
+	CountChecklistItems     = "SELECT COUNT(*) FROM task_checklist_item WHERE task_id = $1"
+	InsertChecklistItem     = "INSERT INTO task_checklist_item(id, task_id, text, checked, position) VALUES($1, $2, $3, $4, $5)"
+	ToggleChecklistItemByID = `UPDATE task_checklist_item SET checked = NOT checked
+		WHERE id = $1 AND task_id IN (SELECT id FROM task WHERE author = $2)
+		RETURNING id, task_id, text, checked, position`
+	SetChecklistItemPosition = "UPDATE task_checklist_item SET position = $1 WHERE id = $2"
+	ShiftChecklistItemsDown  = "UPDATE task_checklist_item SET position = position + 1 WHERE task_id = $1 AND position >= $2 AND position < $3"
+	ShiftChecklistItemsUp    = "UPDATE task_checklist_item SET position = position - 1 WHERE task_id = $1 AND position > $2 AND position <= $3"
+	DeleteChecklistItemByID  = "DELETE FROM task_checklist_item WHERE id = $1"
+
+	// SearchTasksByAuthor ranks title matches above description matches, the rank is negated so
+	// that better matches sort first as they do in SQLite.
+	SearchTasksByAuthor = `SELECT t.id, t.title, COALESCE(t.description, ''), t.status, t.deadline, t.due_date,
+			-ts_rank(t.search, q) AS rank,
+			ts_headline('simple', t.title, q, $1),
+			ts_headline('simple', COALESCE(t.description, ''), q, $2)
+		FROM task t, to_tsquery('simple', $3) q
+		WHERE t.search @@ q AND t.author = $4
+		ORDER BY rank, t.id
+		LIMIT $5 OFFSET $6`
+
+	timeEntryColumns = "id, task_id, author, started_at, ended_at, duration_seconds, note"
+
+	InsertTimeEntry = "INSERT INTO time_entry(" + timeEntryColumns + ") VALUES($1, $2, $3, $4, $5, $6, $7)"
+	// StartTimeEntry only starts the timer on a task of the author, the running index rejects a second timer.
+	StartTimeEntry = `INSERT INTO time_entry(id, task_id, author, started_at, note)
+		SELECT $1::uuid, $2::uuid, $3::uuid, $4::timestamptz, $5::text WHERE EXISTS (SELECT 1 FROM task WHERE id = $2::uuid AND author = $3::uuid)`
+	SelectRunningTimeEntry  = "SELECT " + timeEntryColumns + " FROM time_entry WHERE author = $1 AND ended_at IS NULL"
+	StopTimeEntry           = "UPDATE time_entry SET ended_at = $1, duration_seconds = $2 WHERE id = $3 AND ended_at IS NULL"
+	SelectTimeEntriesByTask = `SELECT e.id, e.task_id, e.author, e.started_at, e.ended_at, e.duration_seconds, e.note
+		FROM time_entry e JOIN task t ON t.id = e.task_id
+		WHERE e.task_id = $1 AND t.author = $2 ORDER BY e.started_at`
+	// SelectTimeReport sums finished entries per task, a NULL project in $4 selects every project.
+	SelectTimeReport = `SELECT t.project_id, COALESCE(p.name, ''), t.id, t.title, SUM(e.duration_seconds)::bigint
+		FROM time_entry e
+		JOIN task t ON t.id = e.task_id
+		LEFT JOIN project p ON p.id = t.project_id
+		WHERE e.author = $1 AND e.ended_at IS NOT NULL AND e.started_at >= $2 AND e.started_at < $3
+			AND ($4::uuid IS NULL OR t.project_id = $4::uuid)
+		GROUP BY t.id, p.name
+		ORDER BY p.name IS NULL, p.name, t.project_id, t.title COLLATE "C"`
+
+	InsertView          = "INSERT INTO view(id, author, name, query) VALUES($1, $2, $3, $4)"
+	SelectViewsByAuthor = "SELECT id, author, name, query FROM view WHERE author = $1 ORDER BY name"
+	SelectViewByID      = "SELECT id, author, name, query FROM view WHERE id = $1 AND author = $2"
+	DeleteViewByID      = "DELETE FROM view WHERE id = $1 AND author = $2"
+
+	InsertTemplate          = "INSERT INTO task_template(id, author, project_id, name, tasks, created_at) VALUES($1, $2, $3, $4, $5, $6)"
+	SelectTemplatesByAuthor = "SELECT id, author, project_id, name, tasks, created_at FROM task_template WHERE author = $1 ORDER BY name"
+	SelectTemplateByID      = "SELECT id, author, project_id, name, tasks, created_at FROM task_template WHERE id = $1 AND author = $2"
+	DeleteTemplateByID      = "DELETE FROM task_template WHERE id = $1 AND author = $2"
+
+	DeleteExpiredIdempotencyKeys = "DELETE FROM idempotency_key WHERE expires_at <= $1"
+	InsertIdempotencyKey         = "INSERT INTO idempotency_key(scope, request_key, method, fingerprint, expires_at) VALUES($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING"
+	SelectIdempotencyKey         = "SELECT scope, request_key, method, fingerprint, completed, response, code, message, expires_at FROM idempotency_key WHERE scope = $1 AND request_key = $2"
+	CompleteIdempotencyKey       = "UPDATE idempotency_key SET completed = TRUE, response = $1, code = $2, message = $3, expires_at = $4 WHERE scope = $5 AND request_key = $6"
+	DeleteIdempotencyKey         = "DELETE FROM idempotency_key WHERE scope = $1 AND request_key = $2"
+	DeleteIdempotencyKeysByScope = "DELETE FROM idempotency_key WHERE scope = $1"
+
+	InsertTaskImport  = "INSERT INTO task_import(task_id, author, source, external_id) VALUES($1, $2, $3, $4)"
+	SelectTaskImports = "SELECT external_id, task_id FROM task_import WHERE author = $1 AND source = $2"
+
+	// UpsertCalendarFeed replaces the token of the author, which revokes the one before.
+	UpsertCalendarFeed = `INSERT INTO calendar_feed(author, token_hash, reminder_minutes, created_at) VALUES($1, $2, $3, $4)
+		ON CONFLICT(author) DO UPDATE SET token_hash = excluded.token_hash,
+			reminder_minutes = excluded.reminder_minutes, created_at = excluded.created_at`
+	SelectCalendarFeedByToken = "SELECT author, token_hash, reminder_minutes, created_at FROM calendar_feed WHERE token_hash = $1"
+	DeleteCalendarFeed        = "DELETE FROM calendar_feed WHERE author = $1"
+
+	// calendarObjectColumns adds the CalDAV name, UID, Unix time and sequence number of the last change to taskColumns.
+	calendarObjectColumns = taskColumns + `, (SELECT name FROM calendar_object WHERE task_id = task.id),
+		(SELECT uid FROM calendar_object WHERE task_id = task.id),
+		(SELECT COALESCE(MAX(changed_at), 0) FROM task_change WHERE task_id = task.id),
+		(SELECT COALESCE(MAX(seq), 0) FROM task_change WHERE task_id = task.id)`
+
+	SelectCalendarObjects      = "SELECT " + calendarObjectColumns + " FROM task WHERE author = $1 AND project_id IS NOT DISTINCT FROM $2 ORDER BY position, id"
+	SelectCalendarObjectByName = "SELECT " + calendarObjectColumns + ` FROM task
+		WHERE author = $1 AND id = (SELECT task_id FROM calendar_object WHERE author = $1 AND name = $2)`
+	UpdateCalendarObjectName = "UPDATE calendar_object SET name = $1, uid = $2 WHERE task_id = $3"
+	// UpdateCalendarTask writes the task only while $11 is still the sequence number of its last change.
+	UpdateCalendarTask = `UPDATE task SET title = $1, description = $2, status = $3, deadline = $4, due_date = $5,
+		priority = $6, recurrence = $7, position = $8 WHERE id = $9 AND author = $10
+		AND (SELECT COALESCE(MAX(seq), 0) FROM task_change WHERE task_id = $9) = $11`
+	SelectTaskTags = "SELECT tag FROM task_tag WHERE task_id = $1"
+
+	// SelectCalendarSyncToken counts pruned changes too, tokens never go back.
+	SelectCalendarSyncToken = `SELECT GREATEST(COALESCE((SELECT MAX(seq) FROM task_change WHERE author = $1), 0),
+		COALESCE((SELECT seq FROM task_change_horizon WHERE author = $1), 0))`
+	SelectCalendarCTags = "SELECT project_id, MAX(seq) FROM task_change WHERE author = $1 GROUP BY project_id"
+	// SelectCalendarChanges returns the last change to each task of a collection with a sequence number in ($3, $4].
+	SelectCalendarChanges = `SELECT DISTINCT ON (task_id) task_id, seq, deleted, COALESCE(name, '') FROM task_change
+		WHERE author = $1 AND project_id IS NOT DISTINCT FROM $2 AND seq > $3 AND seq <= $4 ORDER BY task_id, seq DESC`
+
+	SelectTaskFieldClocks = `SELECT field, changed_at FROM task_field_clock
+		WHERE task_id = $1 AND EXISTS (SELECT 1 FROM task WHERE id = $1 AND author = $2)`
+	UpsertTaskFieldClock = `INSERT INTO task_field_clock(task_id, field, changed_at) VALUES($1, $2, $3)
+		ON CONFLICT(task_id, field) DO UPDATE SET changed_at = excluded.changed_at`
+	UpdateSyncedTask = `UPDATE task SET title = $1, description = $2, status = $3, deadline = $4, due_date = $5,
+		priority = $6, recurrence = $7, project_id = $8, position = $9 WHERE id = $10 AND author = $11`
+	SelectTaskDeleted = `SELECT EXISTS (SELECT 1 FROM task_change WHERE task_id = $1 AND author = $2 AND deleted)
+		AND NOT EXISTS (SELECT 1 FROM task WHERE id = $1)`
+	// SelectChangedTasks returns the tasks with a change numbered in ($2, $3], the last changed last.
+	SelectChangedTasks = `SELECT task_id FROM task_change WHERE author = $1 AND seq > $2 AND seq <= $3
+		GROUP BY task_id ORDER BY MAX(seq)`
+	SelectChangeHorizon = "SELECT COALESCE((SELECT seq FROM task_change_horizon WHERE author = $1), 0)"
+	// prunableChange selects the changes made before $1 that are not the last change of a live task,
+	// which CalDAV ETags and sync still need.
+	prunableChange = `changed_at < $1 AND seq NOT IN
+		(SELECT MAX(c.seq) FROM task_change c JOIN task t ON t.id = c.task_id GROUP BY c.task_id)`
+	UpsertChangeHorizon = `INSERT INTO task_change_horizon(author, seq)
+		SELECT author, MAX(seq) FROM task_change WHERE deleted AND ` + prunableChange + ` GROUP BY author
+		ON CONFLICT(author) DO UPDATE SET seq = GREATEST(task_change_horizon.seq, excluded.seq)`
+	DeleteTaskChanges = "DELETE FROM task_change WHERE " + prunableChange
+
+	webhookColumns         = "id, author, project_id, url, secret, event_types, enabled, failures, disabled_reason, created_at"
+	InsertWebhook          = "INSERT INTO webhook(" + webhookColumns + ") VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)"
+	SelectWebhooksByAuthor = "SELECT " + webhookColumns + " FROM webhook WHERE author = $1 ORDER BY seq"
+	SelectWebhookByID      = "SELECT " + webhookColumns + " FROM webhook WHERE id = $1 AND author = $2"
+	UpdateWebhookByID      = `UPDATE webhook SET project_id = $1, url = $2, event_types = $3, enabled = $4, failures = $5,
+		disabled_reason = $6 WHERE id = $7 AND author = $8`
+	DeleteWebhookByID     = "DELETE FROM webhook WHERE id = $1 AND author = $2"
+	DisableWebhookByID    = "UPDATE webhook SET enabled = FALSE, disabled_reason = $1 WHERE id = $2"
+	ResetWebhookFailures  = "UPDATE webhook SET failures = 0 WHERE id = $1 RETURNING failures"
+	CountWebhookFailure   = "UPDATE webhook SET failures = failures + 1 WHERE id = $1 RETURNING failures"
+	SelectWebhookFailures = "SELECT failures FROM webhook WHERE id = $1"
+
+	webhookDeliveryColumns = `id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at,
+		response_status, error, redelivery_of, created_at`
+	InsertWebhookDelivery = "INSERT INTO webhook_delivery(" + webhookDeliveryColumns + `)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) ON CONFLICT (id) DO NOTHING`
+	SelectWebhookDeliveries = "SELECT " + webhookDeliveryColumns + " FROM webhook_delivery WHERE webhook_id = $1 ORDER BY seq DESC LIMIT $2"
+	SelectWebhookDelivery   = "SELECT " + webhookDeliveryColumns + " FROM webhook_delivery WHERE id = $1 AND webhook_id = $2"
+	// SelectDueWebhookDeliveries returns the pending deliveries of enabled webhooks due by $1, the longest waiting first.
+	// Deliveries another replica is claiming are skipped rather than waited for.
+	SelectDueWebhookDeliveries = `SELECT d.id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+		d.last_attempt_at, d.response_status, d.error, d.redelivery_of, d.created_at, w.url, w.secret
+		FROM webhook_delivery d JOIN webhook w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.enabled ORDER BY d.next_attempt_at, d.seq LIMIT $2
+		FOR UPDATE OF d SKIP LOCKED`
+	LeaseWebhookDelivery         = "UPDATE webhook_delivery SET next_attempt_at = $1 WHERE id = $2"
+	UpdateWebhookDeliveryAttempt = `UPDATE webhook_delivery SET status = $1, attempts = $2, next_attempt_at = $3, last_attempt_at = $4,
+		response_status = $5, error = $6 WHERE id = $7`
+	DeleteWebhookDeliveries = "DELETE FROM webhook_delivery WHERE status <> 'pending' AND last_attempt_at < $1"
+
+	outboxColumns = "seq, aggregate_type, aggregate_id, event_type, payload, created_at"
+	// SelectPendingOutboxEvents returns the events after $1 not dispatched yet in the order they were recorded.
+	SelectPendingOutboxEvents = "SELECT " + outboxColumns + " FROM outbox WHERE dispatched_at IS NULL AND seq > $1 ORDER BY seq LIMIT $2"
+	MarkOutboxEventDispatched = "UPDATE outbox SET dispatched_at = $1 WHERE seq = $2"
+	DeleteOutboxEvents        = "DELETE FROM outbox WHERE dispatched_at < $1"
+)
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// SetTaskEstimate sets or, with a nil estimate, clears the estimate of a task.
+func (s *Storage) SetTaskEstimate(ctx context.Context, taskID, author uuid.UUID, minutes *int) error {
+	const op = "storage.postgres.SetTaskEstimate"
+
+	result, err := s.db.ExecContext(ctx, UpdateTaskEstimate, minutes, taskID, author)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// GetEffortByStatus sums the effort of the author's tasks per project and status.
+func (s *Storage) GetEffortByStatus(ctx context.Context, author uuid.UUID) ([]models.StatusEffort, error) {
+	const op = "storage.postgres.GetEffortByStatus"
+
+	rows, err := s.db.QueryContext(ctx, SelectEffortByStatus, models.StatusDone, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var efforts []models.StatusEffort
+	for rows.Next() {
+		var effort models.StatusEffort
+		err := rows.Scan(&effort.ProjectID, &effort.Status, &effort.Tasks, &effort.EstimatedTasks,
+			&effort.EstimateMinutes, &effort.ActualMinutes, &effort.RemainingMinutes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		efforts = append(efforts, effort)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return efforts, nil
+}
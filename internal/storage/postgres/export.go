@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// GetTaskPage returns up to limit tasks of the author that sort after the cursor, in position order,
+// with their checklists. Paging keeps large accounts out of memory.
+func (s *Storage) GetTaskPage(ctx context.Context, author uuid.UUID, after models.BoardCursor, limit int) ([]*models.Task, error) {
+	const op = "storage.postgres.GetTaskPage"
+
+	// The zero cursor sorts before every task, see GetColumnTasks.
+	rows, err := s.db.QueryContext(ctx, SelectTaskPage, author, after.Position, after.TaskID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var tasks []*models.Task
+	byID := make(map[uuid.UUID]*models.Task)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		tasks = append(tasks, task)
+		byID[task.ID] = task
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	last := tasks[len(tasks)-1]
+	items, err := s.db.QueryContext(ctx, SelectChecklistsByTaskRange, author, after.Position, after.TaskID, last.Position, last.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: load checklists: %w", op, err)
+	}
+	defer items.Close()
+
+	for items.Next() {
+		item, err := scanChecklistItem(items)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan checklist item: %w", op, err)
+		}
+		if task, ok := byID[item.TaskID]; ok {
+			task.Checklist = append(task.Checklist, item)
+		}
+	}
+
+	if err := items.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate checklist items: %w", op, err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// ReserveIdempotencyKey claims the key of record for a new request and returns nil, or returns
+// the record already holding the key. Expired records are dropped first.
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, now time.Time) (*models.IdempotencyRecord, error) {
+	const op = "storage.postgres.ReserveIdempotencyKey"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, DeleteExpiredIdempotencyKeys, now.UTC()); err != nil {
+		return nil, fmt.Errorf("%s: delete expired keys: %w", op, err)
+	}
+
+	result, err := tx.ExecContext(ctx, InsertIdempotencyKey, record.Scope, record.Key, record.Method, record.Fingerprint,
+		record.ExpiresAt.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert key: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	var existing *models.IdempotencyRecord
+	if rowsAffected == 0 {
+		existing = &models.IdempotencyRecord{}
+		err := tx.QueryRowContext(ctx, SelectIdempotencyKey, record.Scope, record.Key).Scan(&existing.Scope, &existing.Key,
+			&existing.Method, &existing.Fingerprint, &existing.Completed, &existing.Response, &existing.Code,
+			&existing.Message, &existing.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: select key: %w", op, err)
+		}
+		existing.ExpiresAt = existing.ExpiresAt.UTC()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return existing, nil
+}
+
+// CompleteIdempotencyKey stores the outcome of the request holding the key of record.
+func (s *Storage) CompleteIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error {
+	const op = "storage.postgres.CompleteIdempotencyKey"
+
+	_, err := s.db.ExecContext(ctx, CompleteIdempotencyKey, record.Response, record.Code, record.Message,
+		record.ExpiresAt.UTC(), record.Scope, record.Key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets the key, so that the request can be retried with it.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	const op = "storage.postgres.ReleaseIdempotencyKey"
+
+	if _, err := s.db.ExecContext(ctx, DeleteIdempotencyKey, scope, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// The outbox is filled by triggers of the task and task_tag tables in the task database and of the
+// user table in the auth database, so that an event is recorded if and only if the change raising
+// it is committed.
+
+// GetPendingOutboxEvents returns up to limit events recorded after afterSeq that were not dispatched yet.
+func (s *Storage) GetPendingOutboxEvents(ctx context.Context, afterSeq int64, limit int) ([]models.OutboxEvent, error) {
+	const op = "storage.postgres.GetPendingOutboxEvents"
+
+	rows, err := s.db.QueryContext(ctx, SelectPendingOutboxEvents, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var (
+			event      models.OutboxEvent
+			payload    string
+			occurredAt int64
+		)
+		if err := rows.Scan(&event.Seq, &event.AggregateType, &event.AggregateID, &event.Type, &payload, &occurredAt); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		event.Payload = []byte(payload)
+		event.OccurredAt = time.UnixMilli(occurredAt).UTC()
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return events, nil
+}
+
+func (s *Storage) MarkOutboxEventDispatched(ctx context.Context, seq int64, at time.Time) error {
+	const op = "storage.postgres.MarkOutboxEventDispatched"
+
+	if _, err := s.db.ExecContext(ctx, MarkOutboxEventDispatched, at.UnixMilli(), seq); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PruneOutbox deletes the events dispatched before the given time and returns how many it deleted.
+func (s *Storage) PruneOutbox(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.postgres.PruneOutbox"
+
+	result, err := s.db.ExecContext(ctx, DeleteOutboxEvents, before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	return pruned, nil
+}
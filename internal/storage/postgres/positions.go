@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func (s *Storage) LastTaskPosition(ctx context.Context, group models.RankGroup) (string, error) {
+	const op = "storage.postgres.LastTaskPosition"
+
+	var position string
+	if err := s.db.QueryRowContext(ctx, SelectLastTaskPosition, group.AuthorID, group.ProjectID, group.Status).Scan(&position); err != nil {
+		return "", fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return position, nil
+}
+
+// GetTaskRanks returns the positions of the tasks in the rank group, in order.
+func (s *Storage) GetTaskRanks(ctx context.Context, group models.RankGroup) ([]models.TaskRank, error) {
+	const op = "storage.postgres.GetTaskRanks"
+
+	rows, err := s.db.QueryContext(ctx, SelectTaskRanks, group.AuthorID, group.ProjectID, group.Status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ranks []models.TaskRank
+	for rows.Next() {
+		var rank models.TaskRank
+		if err := rows.Scan(&rank.ID, &rank.Position); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		ranks = append(ranks, rank)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return ranks, nil
+}
+
+func (s *Storage) SetTaskPosition(ctx context.Context, taskID, author uuid.UUID, position string) error {
+	const op = "storage.postgres.SetTaskPosition"
+
+	result, err := s.db.ExecContext(ctx, UpdateTaskPosition, position, taskID, author)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// SetTaskPlacement moves a task to another status column and position in a single statement.
+func (s *Storage) SetTaskPlacement(ctx context.Context, taskID, author uuid.UUID, status, position string) error {
+	const op = "storage.postgres.SetTaskPlacement"
+
+	// A change of status is a change clients sync, see beginAuthorTx.
+	err := s.inAuthorTx(ctx, author, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, UpdateTaskPlacement, status, position, taskID, author)
+		if err != nil {
+			return fmt.Errorf("execute statement: %w", err)
+		}
+
+		return expectRow(result, my_err.ErrTaskNotFound)
+	})
+	if err != nil {
+		if errors.Is(err, my_err.ErrTaskNotFound) {
+			return my_err.ErrTaskNotFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetTaskPositions rewrites the positions of several tasks of one author atomically.
+func (s *Storage) SetTaskPositions(ctx context.Context, author uuid.UUID, ranks []models.TaskRank) error {
+	const op = "storage.postgres.SetTaskPositions"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, UpdateTaskPosition)
+	if err != nil {
+		return fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for _, rank := range ranks {
+		if _, err := stmt.ExecContext(ctx, rank.Position, rank.ID, author); err != nil {
+			return fmt.Errorf("%s: execute statement: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetOverlongRankGroups lists the rank groups holding at least one position longer than maxLength.
+func (s *Storage) GetOverlongRankGroups(ctx context.Context, maxLength int) ([]models.RankGroup, error) {
+	const op = "storage.postgres.GetOverlongRankGroups"
+
+	rows, err := s.db.QueryContext(ctx, SelectOverlongRankGroup, maxLength)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var groups []models.RankGroup
+	for rows.Next() {
+		var group models.RankGroup
+		if err := rows.Scan(&group.AuthorID, &group.ProjectID, &group.Status); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/highlight"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+const (
+	// titleHighlight marks every match in the title, like the highlight function of FTS5.
+	titleHighlight = "StartSel=" + highlight.Start + ", StopSel=" + highlight.End + ", HighlightAll=true"
+	// descriptionSnippet cuts the description down to about twelve words around a match,
+	// like the snippet function of FTS5.
+	descriptionSnippet = "StartSel=" + highlight.Start + ", StopSel=" + highlight.End + ", MaxFragments=1, MaxWords=12, MinWords=3, FragmentDelimiter=…"
+)
+
+// SearchTasks runs a full-text search over the author's tasks, best matches first.
+// The highlighted title and the snippet are HTML, see highlight.HTML.
+func (s *Storage) SearchTasks(ctx context.Context, author uuid.UUID, query string, limit, offset int) ([]*models.SearchHit, error) {
+	const op = "storage.postgres.SearchTasks"
+
+	match := tsQueryExpression(query)
+	if match == "" {
+		return nil, my_err.ErrEmptyQuery
+	}
+
+	rows, err := s.db.QueryContext(ctx, SearchTasksByAuthor, titleHighlight, descriptionSnippet, match, author, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var hits []*models.SearchHit
+	for rows.Next() {
+		hit := &models.SearchHit{Task: &models.Task{AuthorID: author}}
+		var (
+			deadline sql.NullTime
+			dueDate  sql.NullString
+		)
+		err := rows.Scan(&hit.Task.ID, &hit.Task.Title, &hit.Task.Description, &hit.Task.Status, &deadline, &dueDate,
+			&hit.Rank, &hit.TitleHighlight, &hit.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		if deadline.Valid {
+			hit.Task.Deadline = deadline.Time.UTC()
+		}
+		hit.Task.DueDate = dueDate.String
+		hit.TitleHighlight, hit.Snippet = highlight.HTML(hit.TitleHighlight), highlight.HTML(hit.Snippet)
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return hits, nil
+}
+
+// tsQueryExpression turns free text into a tsquery where every word is a prefix match and all
+// words must be present. Words are quoted so tsquery operators in them are inert.
+func tsQueryExpression(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, "'"+strings.ToLower(word)+"':*")
+	}
+
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// StartTimer inserts a running entry. It fails with ErrTimerRunning when the author already has one.
+func (s *Storage) StartTimer(ctx context.Context, entry *models.TimeEntry) error {
+	const op = "storage.postgres.StartTimer"
+
+	result, err := s.db.ExecContext(ctx, StartTimeEntry, entry.ID, entry.TaskID, entry.AuthorID, entry.StartedAt, entry.Note)
+	if err != nil {
+		if _, ok := violation(err, uniqueViolation); ok {
+			return fmt.Errorf("%s: %w", op, my_err.ErrTimerRunning)
+		}
+
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// StopTimer finishes the author's running entry at endedAt and returns it.
+func (s *Storage) StopTimer(ctx context.Context, author uuid.UUID, endedAt time.Time) (*models.TimeEntry, error) {
+	const op = "storage.postgres.StopTimer"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	entry, err := scanTimeEntry(tx.QueryRowContext(ctx, SelectRunningTimeEntry, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrNoRunningTimer
+		}
+
+		return nil, fmt.Errorf("%s: select running entry: %w", op, err)
+	}
+
+	entry.EndedAt = endedAt
+	entry.DurationSeconds = max(int64(endedAt.Sub(entry.StartedAt)/time.Second), 0)
+
+	result, err := tx.ExecContext(ctx, StopTimeEntry, entry.EndedAt, entry.DurationSeconds, entry.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, my_err.ErrNoRunningTimer
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return entry, nil
+}
+
+// LogTime stores a finished entry.
+func (s *Storage) LogTime(ctx context.Context, entry *models.TimeEntry) error {
+	const op = "storage.postgres.LogTime"
+
+	_, err := s.db.ExecContext(ctx, InsertTimeEntry, entry.ID, entry.TaskID, entry.AuthorID,
+		entry.StartedAt, entry.EndedAt, entry.DurationSeconds, entry.Note)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetTimeEntries(ctx context.Context, taskID, author uuid.UUID) ([]*models.TimeEntry, error) {
+	const op = "storage.postgres.GetTimeEntries"
+
+	rows, err := s.db.QueryContext(ctx, SelectTimeEntriesByTask, taskID, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var entries []*models.TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// GetTimeReport sums the author's finished entries that started within [from, to) per project and task.
+// An invalid projectID reports on every project.
+func (s *Storage) GetTimeReport(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID, from, to time.Time) ([]*models.ProjectTime, error) {
+	const op = "storage.postgres.GetTimeReport"
+
+	rows, err := s.db.QueryContext(ctx, SelectTimeReport, author, from, to, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var projects []*models.ProjectTime
+	for rows.Next() {
+		var (
+			project  models.ProjectTime
+			taskTime models.TaskTime
+		)
+		if err := rows.Scan(&project.ProjectID, &project.ProjectName, &taskTime.TaskID, &taskTime.Title, &taskTime.Seconds); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+
+		// Rows come ordered by project, so a project's tasks are contiguous.
+		if n := len(projects); n == 0 || projects[n-1].ProjectID != project.ProjectID {
+			projects = append(projects, &project)
+		}
+
+		last := projects[len(projects)-1]
+		last.Seconds += taskTime.Seconds
+		last.Tasks = append(last.Tasks, &taskTime)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return projects, nil
+}
+
+func scanTimeEntry(row rowScanner) (*models.TimeEntry, error) {
+	entry := &models.TimeEntry{}
+	var endedAt sql.NullTime
+	err := row.Scan(&entry.ID, &entry.TaskID, &entry.AuthorID, &entry.StartedAt, &endedAt, &entry.DurationSeconds, &entry.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.StartedAt = entry.StartedAt.UTC()
+	if endedAt.Valid {
+		entry.EndedAt = endedAt.Time.UTC()
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// BatchCreateTasks stores the tasks with their tags in one transaction, see runBatch for atomic.
+func (s *Storage) BatchCreateTasks(ctx context.Context, tasks []*models.Task, atomic bool) ([]error, error) {
+	const op = "storage.sqlite.BatchCreateTasks"
+
+	errs, err := s.runBatch(ctx, len(tasks), atomic, func(tx *sql.Tx, i int) error {
+		return insertTask(ctx, tx, tasks[i])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return errs, nil
+}
+
+// BatchUpdateTasks applies patch to the tasks in one transaction, see runBatch for atomic.
+// A non-empty positions[i] moves task i there, which goes with a change of status.
+func (s *Storage) BatchUpdateTasks(ctx context.Context, author uuid.UUID, taskIDs []uuid.UUID, positions []string, patch models.TaskPatch, atomic bool) ([]error, error) {
+	const op = "storage.sqlite.BatchUpdateTasks"
+
+	errs, err := s.runBatch(ctx, len(taskIDs), atomic, func(tx *sql.Tx, i int) error {
+		result, err := tx.ExecContext(ctx, PatchTaskByID, patch.Status, patch.Priority, patch.SetDue,
+			deadlineValue(patch.Deadline), nullString(patch.DueDate), nullString(positions[i]), taskIDs[i], author)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return my_err.ErrTaskNotFound
+		}
+
+		for _, tag := range patch.AddTags {
+			if _, err := tx.ExecContext(ctx, InsertTaskTag, taskIDs[i], tag); err != nil {
+				return fmt.Errorf("insert tag: %w", err)
+			}
+		}
+
+		for _, tag := range patch.RemoveTags {
+			if _, err := tx.ExecContext(ctx, DeleteTaskTag, taskIDs[i], tag); err != nil {
+				return fmt.Errorf("delete tag: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return errs, nil
+}
+
+// BatchDeleteTasks deletes the tasks in one transaction, see runBatch for atomic.
+func (s *Storage) BatchDeleteTasks(ctx context.Context, author uuid.UUID, taskIDs []uuid.UUID, atomic bool) ([]error, error) {
+	const op = "storage.sqlite.BatchDeleteTasks"
+
+	errs, err := s.runBatch(ctx, len(taskIDs), atomic, func(tx *sql.Tx, i int) error {
+		result, err := tx.ExecContext(ctx, DeleteTaskByID, taskIDs[i], author)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return my_err.ErrTaskNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return errs, nil
+}
+
+// runBatch calls item for each of the n items of a batch inside one transaction. In atomic mode
+// the first failing item rolls the whole transaction back and its error is returned. Otherwise
+// every item runs in a savepoint: a failing item is undone on its own, its error is reported in
+// the returned slice and the other items are committed.
+func (s *Storage) runBatch(ctx context.Context, n int, atomic bool, item func(tx *sql.Tx, i int) error) ([]error, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		if atomic {
+			if err := item(tx, i); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, SavepointBatchItem); err != nil {
+			return nil, fmt.Errorf("savepoint: %w", err)
+		}
+
+		if errs[i] = item(tx, i); errs[i] != nil {
+			if _, err := tx.ExecContext(ctx, RollbackBatchItem); err != nil {
+				return nil, fmt.Errorf("rollback item %d: %w", i, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, ReleaseBatchItem); err != nil {
+			return nil, fmt.Errorf("release savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	return errs, nil
+}
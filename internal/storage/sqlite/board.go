@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+)
+
+// CountBoardTasks returns the number of tasks per status on the author's board for the project,
+// an invalid project selects the inbox.
+func (s *Storage) CountBoardTasks(ctx context.Context, author uuid.UUID, projectID uuid.NullUUID) (map[string]int, error) {
+	const op = "storage.sqlite.CountBoardTasks"
+
+	rows, err := s.db.QueryContext(ctx, CountBoardTasks, author, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			status string
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// GetColumnTasks returns up to limit tasks of the rank group that sort after the cursor, in position order.
+func (s *Storage) GetColumnTasks(ctx context.Context, group models.RankGroup, after models.BoardCursor, limit int) ([]*models.Task, error) {
+	const op = "storage.sqlite.GetColumnTasks"
+
+	// The zero cursor sorts before every task, uuid.Nil would not since IDs compare as text.
+	afterID := ""
+	if after.TaskID != uuid.Nil {
+		afterID = after.TaskID.String()
+	}
+
+	rows, err := s.db.QueryContext(ctx, SelectColumnTasks, group.AuthorID, group.ProjectID, group.Status, after.Position, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var tasks []*models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	if err := s.loadChecklists(ctx, group.AuthorID, tasks); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,260 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// AddChecklistItem inserts the item at the given position, or appends it when position is nil.
+// Positions always stay contiguous starting from zero.
+func (s *Storage) AddChecklistItem(ctx context.Context, author uuid.UUID, item *models.ChecklistItem, position *int) error {
+	const op = "storage.sqlite.AddChecklistItem"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, SelectTaskExists, item.TaskID, author).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return my_err.ErrTaskNotFound
+		}
+
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, CountChecklistItems, item.TaskID).Scan(&count); err != nil {
+		return fmt.Errorf("%s: count items: %w", op, err)
+	}
+
+	item.Position = count
+	if position != nil && *position >= 0 && *position < count {
+		item.Position = *position
+	}
+
+	if _, err := tx.ExecContext(ctx, ShiftChecklistItemsDown, item.TaskID, item.Position, math.MaxInt32); err != nil {
+		return fmt.Errorf("%s: shift items: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, InsertChecklistItem, item.ID, item.TaskID, item.Text, item.Checked, item.Position); err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) ToggleChecklistItem(ctx context.Context, itemID, author uuid.UUID) (*models.ChecklistItem, error) {
+	const op = "storage.sqlite.ToggleChecklistItem"
+
+	item, err := scanChecklistItem(s.db.QueryRowContext(ctx, ToggleChecklistItemByID, itemID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrChecklistItemNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return item, nil
+}
+
+// MoveChecklistItem moves the item to position, shifting the items in between by one.
+// Out of range positions are clamped to the checklist bounds.
+func (s *Storage) MoveChecklistItem(ctx context.Context, itemID, author uuid.UUID, position int) ([]*models.ChecklistItem, error) {
+	const op = "storage.sqlite.MoveChecklistItem"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	item, err := scanChecklistItem(tx.QueryRowContext(ctx, SelectChecklistItemByID, itemID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrChecklistItemNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, CountChecklistItems, item.TaskID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("%s: count items: %w", op, err)
+	}
+
+	position = max(0, min(position, count-1))
+
+	switch {
+	case position < item.Position:
+		_, err = tx.ExecContext(ctx, ShiftChecklistItemsDown, item.TaskID, position, item.Position)
+	case position > item.Position:
+		_, err = tx.ExecContext(ctx, ShiftChecklistItemsUp, item.TaskID, item.Position, position)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: shift items: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, SetChecklistItemPosition, position, item.ID); err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	items, err := queryChecklist(ctx, tx, item.TaskID, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// RemoveChecklistItem deletes the item and compacts the positions of the items after it.
+func (s *Storage) RemoveChecklistItem(ctx context.Context, itemID, author uuid.UUID) ([]*models.ChecklistItem, error) {
+	const op = "storage.sqlite.RemoveChecklistItem"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	item, err := scanChecklistItem(tx.QueryRowContext(ctx, SelectChecklistItemByID, itemID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrChecklistItemNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, DeleteChecklistItemByID, item.ID); err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, ShiftChecklistItemsUp, item.TaskID, item.Position, math.MaxInt32); err != nil {
+		return nil, fmt.Errorf("%s: compact items: %w", op, err)
+	}
+
+	items, err := queryChecklist(ctx, tx, item.TaskID, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return items, nil
+}
+
+func (s *Storage) GetChecklistItem(ctx context.Context, itemID, author uuid.UUID) (*models.ChecklistItem, error) {
+	const op = "storage.sqlite.GetChecklistItem"
+
+	item, err := scanChecklistItem(s.db.QueryRowContext(ctx, SelectChecklistItemByID, itemID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrChecklistItemNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return item, nil
+}
+
+func (s *Storage) GetChecklist(ctx context.Context, taskID, author uuid.UUID) ([]*models.ChecklistItem, error) {
+	const op = "storage.sqlite.GetChecklist"
+
+	items, err := queryChecklist(ctx, s.db, taskID, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// loadChecklists fills the checklist of every task of the author in a single query.
+func (s *Storage) loadChecklists(ctx context.Context, author uuid.UUID, tasks []*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, SelectChecklistsByAuthor, author)
+	if err != nil {
+		return fmt.Errorf("load checklists: %w", err)
+	}
+	defer rows.Close()
+
+	byTask := make(map[uuid.UUID][]*models.ChecklistItem)
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return fmt.Errorf("scan checklist item: %w", err)
+		}
+		byTask[item.TaskID] = append(byTask[item.TaskID], item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate checklist items: %w", err)
+	}
+
+	for _, task := range tasks {
+		task.Checklist = byTask[task.ID]
+	}
+
+	return nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func queryChecklist(ctx context.Context, q queryer, taskID, author uuid.UUID) ([]*models.ChecklistItem, error) {
+	rows, err := q.QueryContext(ctx, SelectChecklistByTask, taskID, author)
+	if err != nil {
+		return nil, fmt.Errorf("execute statement: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.ChecklistItem
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return items, nil
+}
+
+func scanChecklistItem(row rowScanner) (*models.ChecklistItem, error) {
+	item := &models.ChecklistItem{}
+	if err := row.Scan(&item.ID, &item.TaskID, &item.Text, &item.Checked, &item.Position); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,46 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/SlashLight/todo-list/internal/storage/conformance"
+	"github.com/SlashLight/todo-list/internal/storage/sqlite"
+)
+
+func TestConformance(t *testing.T) {
+	requireFTS5(t)
+
+	dir := t.TempDir()
+	authPath, taskPath := filepath.Join(dir, "auth.db"), filepath.Join(dir, "todo.db")
+	conformance.Migrate(t, "../../../migrations/auth", "sqlite3://"+authPath)
+	conformance.Migrate(t, "../../../migrations/task", "sqlite3://"+taskPath)
+
+	auth, err := sqlite.New(authPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := sqlite.New(taskPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conformance.Run(t, auth, tasks)
+}
+
+// requireFTS5 skips the test unless the driver was built with -tags sqlite_fts5, which search needs.
+// make test runs the tests with the tag.
+func requireFTS5(t *testing.T) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE VIRTUAL TABLE probe USING fts5(text)"); err != nil {
+		t.Skip("SQLite lacks FTS5, run the tests with make test or -tags sqlite_fts5")
+	}
+}
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// GetImportedTasks maps the external IDs the author imported from source to their tasks.
+func (s *Storage) GetImportedTasks(ctx context.Context, author uuid.UUID, source string) (map[string]uuid.UUID, error) {
+	const op = "storage.sqlite.GetImportedTasks"
+
+	rows, err := s.db.QueryContext(ctx, SelectTaskImports, author, source)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	imported := make(map[string]uuid.UUID)
+	for rows.Next() {
+		var (
+			externalID string
+			taskID     uuid.UUID
+		)
+		if err := rows.Scan(&externalID, &taskID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		imported[externalID] = taskID
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return imported, nil
+}
+
+// ImportTasks stores the tasks with their tags and checklists in one transaction and records
+// externalIDs[i] as the ID task i had in source, unless it is empty. Parents must come before their subtasks.
+// A task imported from source concurrently fails the whole import with my_err.ErrAlreadyImported.
+func (s *Storage) ImportTasks(ctx context.Context, source string, tasks []*models.Task, externalIDs []string) error {
+	const op = "storage.sqlite.ImportTasks"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for i, task := range tasks {
+		if err := insertTask(ctx, tx, task); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, item := range task.Checklist {
+			if _, err := tx.ExecContext(ctx, InsertChecklistItem, item.ID, task.ID, item.Text, item.Checked, item.Position); err != nil {
+				return fmt.Errorf("%s: insert checklist item: %w", op, err)
+			}
+		}
+
+		if externalIDs[i] == "" {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, InsertTaskImport, task.ID, task.AuthorID, source, externalIDs[i]); err != nil {
+			var sqliteErr sqlite3.Error
+
+			if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+				return fmt.Errorf("%s: %w: %q", op, my_err.ErrAlreadyImported, externalIDs[i])
+			}
+
+			return fmt.Errorf("%s: insert import: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
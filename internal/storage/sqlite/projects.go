@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func (s *Storage) CreateProject(ctx context.Context, project *models.Project) error {
+	const op = "storage.sqlite.CreateProject"
+
+	err := s.insertAuthorRow(ctx, project.AuthorID, InsertProject, project.ID, project.AuthorID, project.Name, project.CreatedAt)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, my_err.ErrProjectExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetProjects(ctx context.Context, author uuid.UUID) ([]*models.Project, error) {
+	const op = "storage.sqlite.GetProjects"
+
+	rows, err := s.db.QueryContext(ctx, SelectProjectsByAuthor, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var projects []*models.Project
+	for rows.Next() {
+		project := &models.Project{}
+		if err := rows.Scan(&project.ID, &project.AuthorID, &project.Name, &project.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return projects, nil
+}
+
+func (s *Storage) ProjectExists(ctx context.Context, projectID, author uuid.UUID) error {
+	const op = "storage.sqlite.ProjectExists"
+
+	var exists int
+	err := s.db.QueryRowContext(ctx, SelectProjectExists, projectID, author).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return my_err.ErrProjectNotFound
+		}
+
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return nil
+}
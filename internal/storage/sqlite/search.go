@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/internal/lib/highlight"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// SearchTasks runs a full-text search over the author's tasks, best matches first.
+// The highlighted title and the snippet are HTML, see highlight.HTML.
+// It relies on the FTS5 extension, so the binary has to be built with -tags sqlite_fts5.
+func (s *Storage) SearchTasks(ctx context.Context, author uuid.UUID, query string, limit, offset int) ([]*models.SearchHit, error) {
+	const op = "storage.sqlite.SearchTasks"
+
+	match := ftsMatchExpression(query)
+	if match == "" {
+		return nil, my_err.ErrEmptyQuery
+	}
+
+	rows, err := s.db.QueryContext(ctx, SearchTasksByAuthor, highlight.Start, highlight.End, match, author, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var hits []*models.SearchHit
+	for rows.Next() {
+		hit := &models.SearchHit{Task: &models.Task{AuthorID: author}}
+		var (
+			deadline sql.NullTime
+			dueDate  sql.NullString
+		)
+		err := rows.Scan(&hit.Task.ID, &hit.Task.Title, &hit.Task.Description, &hit.Task.Status, &deadline, &dueDate,
+			&hit.Rank, &hit.TitleHighlight, &hit.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		hit.Task.Deadline = deadline.Time
+		hit.Task.DueDate = dueDate.String
+		hit.TitleHighlight, hit.Snippet = highlight.HTML(hit.TitleHighlight), highlight.HTML(hit.Snippet)
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return hits, nil
+}
+
+// ftsMatchExpression turns free text into an FTS5 query where every word is a prefix match
+// and all words must be present. User input is always quoted so FTS5 operators in it are inert.
+func ftsMatchExpression(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+word+`"*`)
+	}
+
+	return strings.Join(terms, " ")
+}
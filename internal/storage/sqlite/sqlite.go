@@ -1,3 +1,5 @@
+// Package sqlite stores the tasks and the users in SQLite. The task migrations and search rely on
+// FTS5, which go-sqlite3 only builds in with -tags sqlite_fts5; make build and make test pass it.
 package sqlite
 
 import (
@@ -5,6 +7,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mattn/go-sqlite3"
@@ -20,7 +24,8 @@ type Storage struct {
 func New(storagePath string) (*Storage, error) {
 	const op = "storage.Sqlite.New"
 
-	db, err := sql.Open("sqlite3", storagePath)
+	// Foreign keys are off by default in SQLite, but cascades rely on them.
+	db, err := sql.Open("sqlite3", storagePath+"?_foreign_keys=on")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -33,7 +38,7 @@ func (s *Storage) GetByEmail(ctx context.Context, email string) (*models.User, e
 
 	user := &models.User{}
 
-	err := s.db.QueryRowContext(ctx, SelectUserByEmail, email).Scan(&user.ID, &user.Email, &user.PasswordHash)
+	err := s.db.QueryRowContext(ctx, SelectUserByEmail, email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Timezone)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, my_err.ErrUserNotFound
@@ -48,7 +53,7 @@ func (s *Storage) GetByEmail(ctx context.Context, email string) (*models.User, e
 func (s Storage) Register(ctx context.Context, user *models.User) error {
 	const op = "storage.sqlite.Register"
 
-	_, err := s.db.ExecContext(ctx, InsertNewUser, user.ID, user.Email, user.PasswordHash)
+	_, err := s.db.ExecContext(ctx, InsertNewUser, user.ID, user.Email, user.PasswordHash, user.Timezone)
 	if err != nil {
 		var sqliteErr sqlite3.Error
 
@@ -62,18 +67,118 @@ func (s Storage) Register(ctx context.Context, user *models.User) error {
 	return nil
 }
 
+// SetTimezone changes the timezone of the user's profile and returns the updated user.
+func (s *Storage) SetTimezone(ctx context.Context, userID uuid.UUID, timezone string) (*models.User, error) {
+	const op = "storage.sqlite.SetTimezone"
+
+	user := &models.User{}
+
+	err := s.db.QueryRowContext(ctx, UpdateUserTimezone, timezone, userID).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Timezone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrUserNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// DeleteUser deletes the user, their deletion is published through the outbox.
+func (s *Storage) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	const op = "storage.sqlite.DeleteUser"
+
+	result, err := s.db.ExecContext(ctx, DeleteUserByID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// CreateTask inserts the task together with its tags.
 func (s *Storage) CreateTask(ctx context.Context, task *models.Task) error {
 	const op = "storage.sqlite.CreateTask"
 
-	_, err := s.db.ExecContext(ctx, InsertNewTask, task.ID, task.AuthorID, task.Title, task.Description, task.Deadline)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := insertTask(ctx, tx, task); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+// CreateTasks stores the tasks with their tags and checklists in one transaction.
+// Parents must come before their subtasks.
+func (s *Storage) CreateTasks(ctx context.Context, tasks []*models.Task) error {
+	const op = "storage.sqlite.CreateTasks"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, task := range tasks {
+		if err := insertTask(ctx, tx, task); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, item := range task.Checklist {
+			if _, err := tx.ExecContext(ctx, InsertChecklistItem, item.ID, task.ID, item.Text, item.Checked, item.Position); err != nil {
+				return fmt.Errorf("%s: insert checklist item: %w", op, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+func insertTask(ctx context.Context, tx *sql.Tx, task *models.Task) error {
+	if err := saveAuthor(ctx, tx, task.AuthorID); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, InsertNewTask, task.ID, task.AuthorID, task.ProjectID, task.ParentID, task.Title,
+		task.Description, deadlineValue(task.Deadline), nullString(task.DueDate), task.Priority,
+		nullString(task.Recurrence), nullString(task.Assignee), task.Position, task.Status)
 	if err != nil {
 		var sqliteErr sqlite3.Error
 
 		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintNotNull {
-			return fmt.Errorf("%s: %w", op, my_err.ErrEmptyTitle)
+			return my_err.ErrEmptyTitle
 		}
 
-		return fmt.Errorf("%s: %w", op, err)
+		return err
+	}
+
+	for _, tag := range task.Tags {
+		if _, err := tx.ExecContext(ctx, InsertTaskTag, task.ID, tag); err != nil {
+			return fmt.Errorf("insert tag: %w", err)
+		}
 	}
 
 	return nil
@@ -94,20 +199,44 @@ func (s *Storage) GetTask(ctx context.Context, author uuid.UUID) ([]*models.Task
 	defer rows.Close()
 
 	for rows.Next() {
-		task := &models.Task{AuthorID: author}
-		if err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.Deadline); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, fmt.Errorf("%s: scan row: %w", op, err)
 		}
 		tasks = append(tasks, task)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	if err := s.loadChecklists(ctx, author, tasks); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return tasks, nil
 }
 
-func (s *Storage) UpdateTask(ctx context.Context, newTask *models.Task) error {
-	const op = "storage.sqlite.UpdateTask"
+func (s *Storage) GetTaskByID(ctx context.Context, taskID, author uuid.UUID) (*models.Task, error) {
+	const op = "storage.sqlite.GetTaskByID"
 
-	_, err := s.db.ExecContext(ctx, UpdateTaskByID, newTask.Title, newTask.Description, newTask.Status, newTask.Deadline, newTask.ID)
+	task, err := scanTask(s.db.QueryRowContext(ctx, SelectTaskByID, taskID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrTaskNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return task, nil
+}
+
+func (s *Storage) TaskExists(ctx context.Context, taskID, author uuid.UUID) error {
+	const op = "storage.sqlite.TaskExists"
+
+	var exists int
+	err := s.db.QueryRowContext(ctx, SelectTaskExists, taskID, author).Scan(&exists)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return my_err.ErrTaskNotFound
@@ -119,6 +248,27 @@ func (s *Storage) UpdateTask(ctx context.Context, newTask *models.Task) error {
 	return nil
 }
 
+func (s *Storage) UpdateTask(ctx context.Context, newTask *models.Task) error {
+	const op = "storage.sqlite.UpdateTask"
+
+	result, err := s.db.ExecContext(ctx, UpdateTaskByID, newTask.Title, newTask.Description, newTask.Status,
+		deadlineValue(newTask.Deadline), nullString(newTask.DueDate), newTask.Position, newTask.ID, newTask.AuthorID)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrTaskNotFound
+	}
+
+	return nil
+}
+
 func (s *Storage) DeleteTask(ctx context.Context, taskID, author uuid.UUID) error {
 	const op = "storage.sqlite.DeleteTask"
 
@@ -138,3 +288,45 @@ func (s *Storage) DeleteTask(ctx context.Context, taskID, author uuid.UUID) erro
 
 	return nil
 }
+
+// scanTask reads a row selected with taskColumns.
+func scanTask(row rowScanner) (*models.Task, error) {
+	task := &models.Task{}
+	var (
+		description sql.NullString
+		deadline    sql.NullTime
+		dueDate     sql.NullString
+		recurrence  sql.NullString
+		assignee    sql.NullString
+		tags        string
+		estimate    sql.NullInt64
+	)
+	err := row.Scan(&task.ID, &task.AuthorID, &task.ProjectID, &task.ParentID, &task.Title, &description, &task.Status, &deadline, &dueDate,
+		&task.Priority, &recurrence, &assignee, &tags, &task.Position, &task.TrackedSeconds, &estimate)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Description = description.String
+	task.Deadline = deadline.Time
+	task.DueDate = dueDate.String
+	task.Recurrence = recurrence.String
+	task.Assignee = assignee.String
+	task.Tags = strings.Fields(tags)
+	if estimate.Valid {
+		minutes := int(estimate.Int64)
+		task.EstimateMinutes = &minutes
+	}
+
+	return task, nil
+}
+
+// deadlineValue stores a missing deadline as NULL and every other one in UTC,
+// so deadlines compare correctly as text.
+func deadlineValue(deadline time.Time) sql.NullTime {
+	return sql.NullTime{Time: deadline.UTC(), Valid: !deadline.IsZero()}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+// GetTaskClocks returns when each field of the task was last written, fields written only when
+// the task was created are left out.
+func (s *Storage) GetTaskClocks(ctx context.Context, taskID, author uuid.UUID) (map[string]time.Time, error) {
+	const op = "storage.sqlite.GetTaskClocks"
+
+	rows, err := s.db.QueryContext(ctx, SelectTaskFieldClocks, taskID, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	clocks := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			field     string
+			changedAt int64
+		)
+		if err := rows.Scan(&field, &changedAt); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		clocks[field] = time.UnixMilli(changedAt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return clocks, nil
+}
+
+// CreateSyncedTask stores a task a client created offline with its tags, recording changedAt as
+// the time its fields were written.
+func (s *Storage) CreateSyncedTask(ctx context.Context, task *models.Task, fields []string, changedAt time.Time) error {
+	const op = "storage.sqlite.CreateSyncedTask"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := insertTask(ctx, tx, task); err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			return fmt.Errorf("%s: %w", op, my_err.ErrTaskIDTaken)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := setTaskClocks(ctx, tx, task.ID, fields, changedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+// UpdateSyncedTask saves the fields offline changes set and replaces the tags of the task,
+// recording changedAt as the time the fields were written.
+func (s *Storage) UpdateSyncedTask(ctx context.Context, task *models.Task, fields []string, changedAt time.Time) error {
+	const op = "storage.sqlite.UpdateSyncedTask"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, UpdateSyncedTask, task.Title, task.Description, task.Status, deadlineValue(task.Deadline),
+		nullString(task.DueDate), task.Priority, nullString(task.Recurrence), task.ProjectID, task.Position, task.ID, task.AuthorID)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, my_err.ErrTaskNotFound)
+	}
+
+	if err := replaceTags(ctx, tx, task.ID, task.Tags); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The triggers stamped the fields with the time of this write, the change was made earlier.
+	if err := setTaskClocks(ctx, tx, task.ID, fields, changedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+func setTaskClocks(ctx context.Context, tx *sql.Tx, taskID uuid.UUID, fields []string, changedAt time.Time) error {
+	for _, field := range fields {
+		if _, err := tx.ExecContext(ctx, UpsertTaskFieldClock, taskID, field, changedAt.UnixMilli()); err != nil {
+			return fmt.Errorf("set clock of %s: %w", field, err)
+		}
+	}
+
+	return nil
+}
+
+// TaskDeleted reports whether the author had the task and deleted it, as far as the change log remembers.
+func (s *Storage) TaskDeleted(ctx context.Context, taskID, author uuid.UUID) (bool, error) {
+	const op = "storage.sqlite.TaskDeleted"
+
+	var deleted bool
+	if err := s.db.QueryRowContext(ctx, SelectTaskDeleted, taskID, author).Scan(&deleted); err != nil {
+		return false, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// GetChangedTasks returns the IDs of the author's tasks with a change numbered in (after, upTo],
+// live and deleted ones alike, the last changed last.
+func (s *Storage) GetChangedTasks(ctx context.Context, author uuid.UUID, after, upTo int64) ([]uuid.UUID, error) {
+	const op = "storage.sqlite.GetChangedTasks"
+
+	rows, err := s.db.QueryContext(ctx, SelectChangedTasks, author, after, upTo)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var taskIDs []uuid.UUID
+	for rows.Next() {
+		var taskID uuid.UUID
+		if err := rows.Scan(&taskID); err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return taskIDs, nil
+}
+
+// GetChangeHorizon returns the sequence number of the last tombstone of the author that was
+// pruned, 0 when none was.
+func (s *Storage) GetChangeHorizon(ctx context.Context, author uuid.UUID) (int64, error) {
+	const op = "storage.sqlite.GetChangeHorizon"
+
+	var horizon int64
+	if err := s.db.QueryRowContext(ctx, SelectChangeHorizon, author).Scan(&horizon); err != nil {
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return horizon, nil
+}
+
+// PruneTaskChanges deletes the changes made before the given time that are not the last change
+// of a live task, moving the horizon of their authors past the pruned tombstones. It returns how
+// many changes it deleted.
+func (s *Storage) PruneTaskChanges(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.PruneTaskChanges"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, UpsertChangeHorizon, before.Unix()); err != nil {
+		return 0, fmt.Errorf("%s: move horizon: %w", op, err)
+	}
+
+	result, err := tx.ExecContext(ctx, DeleteTaskChanges, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: delete changes: %w", op, err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func (s *Storage) SaveTemplate(ctx context.Context, template *models.Template) error {
+	const op = "storage.sqlite.SaveTemplate"
+
+	tasks, err := json.Marshal(template.Tasks)
+	if err != nil {
+		return fmt.Errorf("%s: marshal tasks: %w", op, err)
+	}
+
+	err = s.insertAuthorRow(ctx, template.AuthorID, InsertTemplate, template.ID, template.AuthorID, template.ProjectID, template.Name,
+		string(tasks), template.CreatedAt)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, my_err.ErrTemplateExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetTemplates(ctx context.Context, author uuid.UUID) ([]*models.Template, error) {
+	const op = "storage.sqlite.GetTemplates"
+
+	rows, err := s.db.QueryContext(ctx, SelectTemplatesByAuthor, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var templates []*models.Template
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return templates, nil
+}
+
+func (s *Storage) GetTemplate(ctx context.Context, templateID, author uuid.UUID) (*models.Template, error) {
+	const op = "storage.sqlite.GetTemplate"
+
+	template, err := scanTemplate(s.db.QueryRowContext(ctx, SelectTemplateByID, templateID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrTemplateNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return template, nil
+}
+
+func (s *Storage) DeleteTemplate(ctx context.Context, templateID, author uuid.UUID) error {
+	const op = "storage.sqlite.DeleteTemplate"
+
+	result, err := s.db.ExecContext(ctx, DeleteTemplateByID, templateID, author)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+func scanTemplate(row rowScanner) (*models.Template, error) {
+	template := &models.Template{}
+
+	var tasks string
+	if err := row.Scan(&template.ID, &template.AuthorID, &template.ProjectID, &template.Name, &tasks, &template.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(tasks), &template.Tasks); err != nil {
+		return nil, fmt.Errorf("unmarshal tasks: %w", err)
+	}
+
+	return template, nil
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func (s *Storage) SaveView(ctx context.Context, view *models.View) error {
+	const op = "storage.sqlite.SaveView"
+
+	query, err := json.Marshal(view.Query)
+	if err != nil {
+		return fmt.Errorf("%s: marshal query: %w", op, err)
+	}
+
+	err = s.insertAuthorRow(ctx, view.AuthorID, InsertView, view.ID, view.AuthorID, view.Name, string(query))
+	if err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, my_err.ErrViewExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetViews(ctx context.Context, author uuid.UUID) ([]*models.View, error) {
+	const op = "storage.sqlite.GetViews"
+
+	rows, err := s.db.QueryContext(ctx, SelectViewsByAuthor, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var views []*models.View
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		views = append(views, view)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return views, nil
+}
+
+func (s *Storage) GetView(ctx context.Context, viewID string, author uuid.UUID) (*models.View, error) {
+	const op = "storage.sqlite.GetView"
+
+	view, err := scanView(s.db.QueryRowContext(ctx, SelectViewByID, viewID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrViewNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return view, nil
+}
+
+func (s *Storage) DeleteView(ctx context.Context, viewID string, author uuid.UUID) error {
+	const op = "storage.sqlite.DeleteView"
+
+	result, err := s.db.ExecContext(ctx, DeleteViewByID, viewID, author)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrViewNotFound
+	}
+
+	return nil
+}
+
+func scanView(row rowScanner) (*models.View, error) {
+	view := &models.View{}
+
+	var query string
+	if err := row.Scan(&view.ID, &view.AuthorID, &view.Name, &query); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(query), &view.Query); err != nil {
+		return nil, fmt.Errorf("unmarshal query: %w", err)
+	}
+
+	return view, nil
+}
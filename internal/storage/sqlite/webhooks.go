@@ -0,0 +1,337 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SlashLight/todo-list/internal/domain/models"
+	"github.com/SlashLight/todo-list/pkg/my_err"
+)
+
+func (s *Storage) SaveWebhook(ctx context.Context, webhook *models.Webhook) error {
+	const op = "storage.sqlite.SaveWebhook"
+
+	err := s.insertAuthorRow(ctx, webhook.AuthorID, InsertWebhook, webhook.ID, webhook.AuthorID, webhook.ProjectID, webhook.URL, webhook.Secret,
+		strings.Join(webhook.EventTypes, " "), webhook.Enabled, webhook.Failures, webhook.DisabledReason, webhook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetWebhooks(ctx context.Context, author uuid.UUID) ([]*models.Webhook, error) {
+	const op = "storage.sqlite.GetWebhooks"
+
+	rows, err := s.db.QueryContext(ctx, SelectWebhooksByAuthor, author)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *Storage) GetWebhook(ctx context.Context, webhookID, author uuid.UUID) (*models.Webhook, error) {
+	const op = "storage.sqlite.GetWebhook"
+
+	webhook, err := scanWebhook(s.db.QueryRowContext(ctx, SelectWebhookByID, webhookID, author))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrWebhookNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return webhook, nil
+}
+
+// UpdateWebhook saves the endpoint, filters and state of the webhook, its secret stays.
+func (s *Storage) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	const op = "storage.sqlite.UpdateWebhook"
+
+	result, err := s.db.ExecContext(ctx, UpdateWebhookByID, webhook.ProjectID, webhook.URL, strings.Join(webhook.EventTypes, " "),
+		webhook.Enabled, webhook.Failures, webhook.DisabledReason, webhook.ID, webhook.AuthorID)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes the webhook with its queued and logged deliveries.
+func (s *Storage) DeleteWebhook(ctx context.Context, webhookID, author uuid.UUID) error {
+	const op = "storage.sqlite.DeleteWebhook"
+
+	result, err := s.db.ExecContext(ctx, DeleteWebhookByID, webhookID, author)
+	if err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return my_err.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) DisableWebhook(ctx context.Context, webhookID uuid.UUID, reason string) error {
+	const op = "storage.sqlite.DisableWebhook"
+
+	if _, err := s.db.ExecContext(ctx, DisableWebhookByID, reason, webhookID); err != nil {
+		return fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return nil
+}
+
+// EnqueueWebhookDeliveries queues the deliveries, all of them or none. A delivery already queued
+// under its ID is left as it is.
+func (s *Storage) EnqueueWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	const op = "storage.sqlite.EnqueueWebhookDeliveries"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, delivery := range deliveries {
+		_, err := tx.ExecContext(ctx, InsertWebhookDelivery, delivery.ID, delivery.WebhookID, delivery.EventType, []byte(delivery.Payload),
+			delivery.Status, delivery.Attempts, unixValue(delivery.NextAttemptAt).Int64, unixValue(delivery.LastAttemptAt),
+			delivery.ResponseStatus, delivery.Error, delivery.RedeliveryOf, delivery.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("%s: insert delivery: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries returns the last deliveries of the webhook, the latest first.
+func (s *Storage) GetWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	const op = "storage.sqlite.GetWebhookDeliveries"
+
+	rows, err := s.db.QueryContext(ctx, SelectWebhookDeliveries, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *Storage) GetWebhookDelivery(ctx context.Context, deliveryID, webhookID uuid.UUID) (*models.WebhookDelivery, error) {
+	const op = "storage.sqlite.GetWebhookDelivery"
+
+	delivery, err := scanWebhookDelivery(s.db.QueryRowContext(ctx, SelectWebhookDelivery, deliveryID, webhookID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, my_err.ErrWebhookDeliveryNotFound
+		}
+
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	return delivery, nil
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries of enabled webhooks due by now and
+// holds them back until leaseUntil, so that they are not attempted twice while in flight.
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.QueuedDelivery, error) {
+	const op = "storage.sqlite.ClaimWebhookDeliveries"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, SelectDueWebhookDeliveries, now.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	var queued []models.QueuedDelivery
+	for rows.Next() {
+		var item models.QueuedDelivery
+
+		item.Delivery, err = scanWebhookDelivery(rows, &item.URL, &item.Secret)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: scan row: %w", op, err)
+		}
+		queued = append(queued, item)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: iterate rows: %w", op, err)
+	}
+
+	for _, item := range queued {
+		if _, err := tx.ExecContext(ctx, LeaseWebhookDelivery, leaseUntil.Unix(), item.Delivery.ID); err != nil {
+			return nil, fmt.Errorf("%s: lease delivery: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return queued, nil
+}
+
+// RecordWebhookAttempt saves the outcome of an attempt of the delivery. A delivered delivery clears
+// the failures of its webhook, a failed one counts as one more. It returns the failures of the webhook.
+func (s *Storage) RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	const op = "storage.sqlite.RecordWebhookAttempt"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, UpdateWebhookDeliveryAttempt, delivery.Status, delivery.Attempts, unixValue(delivery.NextAttemptAt).Int64,
+		unixValue(delivery.LastAttemptAt), delivery.ResponseStatus, delivery.Error, delivery.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: update delivery: %w", op, err)
+	}
+
+	query := SelectWebhookFailures
+	switch delivery.Status {
+	case models.WebhookDeliveryDelivered:
+		query = ResetWebhookFailures
+	case models.WebhookDeliveryFailed:
+		query = CountWebhookFailure
+	}
+
+	var failures int
+	if err := tx.QueryRowContext(ctx, query, delivery.WebhookID).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("%s: update failures: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+// PruneWebhookDeliveries deletes the delivered and failed deliveries last attempted before the
+// given time and returns how many it deleted.
+func (s *Storage) PruneWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.PruneWebhookDeliveries"
+
+	result, err := s.db.ExecContext(ctx, DeleteWebhookDeliveries, before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: execute statement: %w", op, err)
+	}
+
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: get rows affected: %w", op, err)
+	}
+
+	return pruned, nil
+}
+
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+
+	var eventTypes string
+	if err := row.Scan(&webhook.ID, &webhook.AuthorID, &webhook.ProjectID, &webhook.URL, &webhook.Secret, &eventTypes,
+		&webhook.Enabled, &webhook.Failures, &webhook.DisabledReason, &webhook.CreatedAt); err != nil {
+		return nil, err
+	}
+	webhook.EventTypes = strings.Fields(eventTypes)
+
+	return webhook, nil
+}
+
+// scanWebhookDelivery scans the delivery columns of the row, followed by extra.
+func scanWebhookDelivery(row rowScanner, extra ...any) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+
+	var (
+		payload       []byte
+		nextAttemptAt int64
+		lastAttemptAt sql.NullInt64
+	)
+	dest := append([]any{&delivery.ID, &delivery.WebhookID, &delivery.EventType, &payload, &delivery.Status, &delivery.Attempts,
+		&nextAttemptAt, &lastAttemptAt, &delivery.ResponseStatus, &delivery.Error, &delivery.RedeliveryOf, &delivery.CreatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	if delivery.Status == models.WebhookDeliveryPending {
+		delivery.NextAttemptAt = time.Unix(nextAttemptAt, 0).UTC()
+	}
+	if lastAttemptAt.Valid {
+		delivery.LastAttemptAt = time.Unix(lastAttemptAt.Int64, 0).UTC()
+	}
+
+	return delivery, nil
+}
+
+// unixValue stores a zero time as NULL, or as 0 where NULL is not allowed.
+func unixValue(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.Unix(), Valid: true}
+}
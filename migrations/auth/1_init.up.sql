@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user
+(
+    id UUID PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    password TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC'
+);
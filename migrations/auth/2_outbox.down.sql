@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS outbox_user_delete;
+DROP TRIGGER IF EXISTS outbox_user_update;
+DROP TRIGGER IF EXISTS outbox_user_insert;
+DROP INDEX IF EXISTS idx_outbox_dispatched;
+DROP INDEX IF EXISTS idx_outbox_pending;
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,37 @@
+-- Events of users, written by triggers in the transaction of the change that raised them. The relay
+-- publishes them to the task service in seq order and marks them dispatched.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    -- Always user.
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    -- JSON state of the user after the change, their ID alone once they are deleted.
+    payload TEXT NOT NULL,
+    -- Unix times in milliseconds.
+    created_at INTEGER NOT NULL DEFAULT (CAST(unixepoch('subsec') * 1000 AS INTEGER)),
+    dispatched_at INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_dispatched ON outbox(dispatched_at) WHERE dispatched_at IS NOT NULL;
+
+-- The password hash never leaves the database.
+CREATE TRIGGER IF NOT EXISTS outbox_user_insert AFTER INSERT ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', new.id, 'user.created', json_object('id', new.id, 'email', new.email, 'timezone', new.timezone));
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_user_update AFTER UPDATE OF email, timezone ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', new.id, 'user.updated', json_object('id', new.id, 'email', new.email, 'timezone', new.timezone));
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_user_delete AFTER DELETE ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', old.id, 'user.deleted', json_object('id', old.id));
+END;
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS outbox_user_after_delete ON users;
+DROP TRIGGER IF EXISTS outbox_user_after_update ON users;
+DROP TRIGGER IF EXISTS outbox_user_after_insert ON users;
+
+DROP FUNCTION IF EXISTS outbox_user_after_change();
+
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,53 @@
+-- Schema of the SQLite auth migrations up to 2_outbox.
+
+-- "user" is reserved in Postgres.
+CREATE TABLE IF NOT EXISTS users
+(
+    id UUID PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    password TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC'
+);
+
+-- Events of users, written by triggers in the transaction of the change that raised them. The relay
+-- publishes them to the task service in seq order and marks them dispatched.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    seq BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    -- Always user.
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    -- State of the user after the change, their ID alone once they are deleted.
+    payload JSONB NOT NULL,
+    -- Unix times in milliseconds.
+    created_at BIGINT NOT NULL DEFAULT ((EXTRACT(EPOCH FROM statement_timestamp()) * 1000)::BIGINT),
+    dispatched_at BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_dispatched ON outbox(dispatched_at) WHERE dispatched_at IS NOT NULL;
+
+-- The password hash never leaves the database.
+CREATE OR REPLACE FUNCTION outbox_user_after_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+        VALUES ('user', OLD.id, 'user.deleted', jsonb_build_object('id', OLD.id));
+    ELSE
+        INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+        VALUES ('user', NEW.id, CASE WHEN TG_OP = 'INSERT' THEN 'user.created' ELSE 'user.updated' END,
+            jsonb_build_object('id', NEW.id, 'email', NEW.email, 'timezone', NEW.timezone));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_user_after_insert AFTER INSERT ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
+
+CREATE TRIGGER outbox_user_after_update AFTER UPDATE OF email, timezone ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
+
+CREATE TRIGGER outbox_user_after_delete AFTER DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
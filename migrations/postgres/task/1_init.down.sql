@@ -0,0 +1,41 @@
+DROP TRIGGER IF EXISTS outbox_user_after_delete ON users;
+DROP TRIGGER IF EXISTS outbox_user_after_update ON users;
+DROP TRIGGER IF EXISTS outbox_user_after_insert ON users;
+DROP TRIGGER IF EXISTS task_tag_after_change ON task_tag;
+DROP TRIGGER IF EXISTS outbox_task_after_delete ON task;
+DROP TRIGGER IF EXISTS outbox_task_after_update ON task;
+DROP TRIGGER IF EXISTS task_field_clock_after_update ON task;
+DROP TRIGGER IF EXISTS task_change_before_delete ON task;
+DROP TRIGGER IF EXISTS task_change_after_update ON task;
+DROP TRIGGER IF EXISTS task_after_insert ON task;
+
+DROP FUNCTION IF EXISTS outbox_user_after_change();
+DROP FUNCTION IF EXISTS task_tag_after_change();
+DROP FUNCTION IF EXISTS outbox_task_after_delete();
+DROP FUNCTION IF EXISTS outbox_task_after_update();
+DROP FUNCTION IF EXISTS task_field_clock_after_update();
+DROP FUNCTION IF EXISTS task_change_before_delete();
+DROP FUNCTION IF EXISTS task_change_after_update();
+DROP FUNCTION IF EXISTS task_after_insert();
+DROP FUNCTION IF EXISTS record_task_tombstone(task);
+
+DROP VIEW IF EXISTS outbox_task_payload;
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TABLE IF EXISTS webhook;
+DROP TABLE IF EXISTS task_change_horizon;
+DROP TABLE IF EXISTS task_field_clock;
+DROP TABLE IF EXISTS task_change;
+DROP TABLE IF EXISTS calendar_object;
+DROP TABLE IF EXISTS calendar_feed;
+DROP TABLE IF EXISTS task_import;
+DROP TABLE IF EXISTS idempotency_key;
+DROP TABLE IF EXISTS task_template;
+DROP TABLE IF EXISTS time_entry;
+DROP TABLE IF EXISTS view;
+DROP TABLE IF EXISTS task_checklist_item;
+DROP TABLE IF EXISTS attachment;
+DROP TABLE IF EXISTS task_tag;
+DROP TABLE IF EXISTS task;
+DROP TABLE IF EXISTS project;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,460 @@
+-- Schema of the SQLite task migrations up to 19_outbox, Postgres deployments start from it.
+-- Text that is ordered, such as positions, tags and names, sorts bytewise as it does in SQLite.
+
+-- "user" is reserved in Postgres.
+CREATE TABLE IF NOT EXISTS users
+(
+    id UUID PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    password TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC'
+);
+
+CREATE TABLE IF NOT EXISTS project
+(
+    id UUID PRIMARY KEY,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT COLLATE "C" NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (author, name)
+);
+
+CREATE TABLE IF NOT EXISTS task
+(
+    id UUID PRIMARY KEY,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Tasks without a project belong to the author's inbox.
+    project_id UUID REFERENCES project(id) ON DELETE SET NULL,
+    -- Subtasks point at their parent. Deleting a parent leaves its subtasks as top level tasks.
+    parent_id UUID REFERENCES task(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    status TEXT DEFAULT 'to-do' CHECK ( status IN ('to-do','in-progress','done') ),
+    deadline TIMESTAMPTZ,
+    due_date TEXT CHECK ( CASE WHEN due_date ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}$'
+        THEN to_char(due_date::date, 'YYYY-MM-DD') = due_date ELSE due_date IS NULL END ),
+    priority TEXT NOT NULL DEFAULT 'none' CHECK ( priority IN ('none','low','medium','high') ),
+    -- RFC 5545 recurrence rule without the RRULE: prefix, e.g. FREQ=MONTHLY;BYMONTHDAY=1.
+    recurrence TEXT,
+    -- Handle of the person the task is for, as typed: there is no user directory to resolve it against.
+    assignee TEXT,
+    -- Fractional rank key.
+    position TEXT COLLATE "C" NOT NULL DEFAULT '',
+    estimate_minutes INTEGER CHECK ( estimate_minutes IS NULL OR estimate_minutes >= 0 ),
+    -- The simple configuration neither stems nor drops stop words, like the SQLite index.
+    -- Unlike it, diacritics are kept: unaccent is not immutable, so it cannot be used here.
+    search TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+    ) STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_position ON task(author, project_id, status, position);
+CREATE INDEX IF NOT EXISTS idx_task_parent ON task(parent_id);
+CREATE INDEX IF NOT EXISTS idx_task_search ON task USING GIN (search);
+
+CREATE TABLE IF NOT EXISTS task_tag
+(
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    tag TEXT COLLATE "C" NOT NULL,
+    PRIMARY KEY (task_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tag_tag ON task_tag(tag);
+
+CREATE TABLE IF NOT EXISTS attachment
+(
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    sha256 TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachment_task ON attachment(task_id);
+CREATE INDEX IF NOT EXISTS idx_attachment_author ON attachment(author);
+
+CREATE TABLE IF NOT EXISTS task_checklist_item
+(
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_checklist_item_task ON task_checklist_item(task_id, position);
+
+CREATE TABLE IF NOT EXISTS view
+(
+    id UUID PRIMARY KEY,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT COLLATE "C" NOT NULL,
+    query JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (author, name)
+);
+
+CREATE TABLE IF NOT EXISTS time_entry
+(
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    -- NULL while the timer is running.
+    ended_at TIMESTAMPTZ,
+    duration_seconds BIGINT NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT ''
+);
+
+-- At most one running timer per user.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entry_running ON time_entry(author) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_time_entry_task ON time_entry(task_id);
+CREATE INDEX IF NOT EXISTS idx_time_entry_author_started ON time_entry(author, started_at);
+
+CREATE TABLE IF NOT EXISTS task_template
+(
+    id UUID PRIMARY KEY,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Templates of a project create their tasks there, personal templates have no project.
+    project_id UUID REFERENCES project(id) ON DELETE CASCADE,
+    name TEXT COLLATE "C" NOT NULL,
+    -- Task tree, see models.TemplateTask.
+    tasks JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (author, name)
+);
+
+-- Outcome of requests sent with an idempotency key, replays within the TTL get it back.
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+    -- Scope is the author of the request, keys of different users never clash.
+    scope TEXT NOT NULL,
+    request_key TEXT NOT NULL,
+    method TEXT NOT NULL,
+    -- Hash of the request, a replay with another payload is rejected.
+    fingerprint TEXT NOT NULL,
+    -- Pending rows hold the key while the first request runs and expire soon after.
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    response BYTEA,
+    code INTEGER NOT NULL DEFAULT 0,
+    message TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, request_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires ON idempotency_key(expires_at);
+
+-- Tasks created by an import, by the ID they had in the app they came from.
+CREATE TABLE IF NOT EXISTS task_import
+(
+    task_id UUID PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Source is the import format, external IDs of different apps never clash.
+    source TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    UNIQUE (author, source, external_id)
+);
+
+-- Secret calendar subscription URLs, one per user. Only a hash of the token is kept.
+CREATE TABLE IF NOT EXISTS calendar_feed
+(
+    author UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    -- Minutes before the due time events alert, 0 for no alerts.
+    reminder_minutes INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+-- CalDAV names of the tasks, which clients may choose when they create a task.
+-- Every other task is served as <id>.ics with the UID <id>@todo-list.
+CREATE TABLE IF NOT EXISTS calendar_object
+(
+    task_id UUID PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    uid TEXT NOT NULL,
+    CONSTRAINT calendar_object_name_key UNIQUE (author, name),
+    CONSTRAINT calendar_object_uid_key UNIQUE (author, uid)
+);
+
+-- Log of the changes to tasks as CalDAV and sync see them, the sync tokens of clients are sequence numbers.
+-- Deleted rows are tombstones of a task in a project, named as the client knew it.
+CREATE TABLE IF NOT EXISTS task_change
+(
+    seq BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL,
+    project_id UUID,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    name TEXT,
+    -- Unix time.
+    changed_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM statement_timestamp())::BIGINT)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_change_author ON task_change(author, seq);
+CREATE INDEX IF NOT EXISTS idx_task_change_task ON task_change(task_id);
+CREATE INDEX IF NOT EXISTS idx_task_change_changed_at ON task_change(changed_at);
+
+-- Server time in Unix milliseconds each field of a task was last written, for the field level
+-- last-writer-wins merge of offline changes. Fields without a row were not written since the task was created.
+CREATE TABLE IF NOT EXISTS task_field_clock
+(
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    changed_at BIGINT NOT NULL,
+    PRIMARY KEY (task_id, field)
+);
+
+-- Highest sequence number of the tombstones pruned from task_change per user. Clients that synced
+-- before it may have missed deletions and have to sync from scratch.
+CREATE TABLE IF NOT EXISTS task_change_horizon
+(
+    author UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    seq BIGINT NOT NULL
+);
+
+-- Endpoints the events of a user's tasks are posted to. The secret signs the payloads,
+-- so unlike calendar tokens it is kept as is.
+CREATE TABLE IF NOT EXISTS webhook
+(
+    id UUID PRIMARY KEY,
+    -- Creation order, which SQLite keeps in the rowid.
+    seq BIGINT GENERATED ALWAYS AS IDENTITY,
+    author UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Webhooks of a project only get the events of its tasks and every deletion.
+    project_id UUID REFERENCES project(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    -- Space separated task event types posted, every type when empty.
+    event_types TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Deliveries in a row that failed every attempt, the webhook is disabled after too many.
+    failures INTEGER NOT NULL DEFAULT 0,
+    disabled_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_author ON webhook(author, seq);
+
+-- Queue of the events to post and log of the posted ones.
+CREATE TABLE IF NOT EXISTS webhook_delivery
+(
+    id UUID PRIMARY KEY,
+    seq BIGINT GENERATED ALWAYS AS IDENTITY,
+    webhook_id UUID NOT NULL REFERENCES webhook(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    -- pending, delivered or failed.
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    -- Unix time a pending delivery is due, pushed back while an attempt is in flight.
+    next_attempt_at BIGINT NOT NULL,
+    -- Unix time of the last attempt, NULL before the first.
+    last_attempt_at BIGINT,
+    -- HTTP status of the last response, 0 when there was none.
+    response_status INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    -- The delivery this one posts again, NULL for the first delivery of an event.
+    redelivery_of UUID,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_due ON webhook_delivery(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_webhook ON webhook_delivery(webhook_id, seq);
+
+-- Domain events of tasks and users, written by triggers in the transaction of the change that
+-- raised them. The relay publishes them in seq order and marks them dispatched.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    seq BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    -- task or user.
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    -- State of the aggregate after the change, its ID alone once it is deleted.
+    payload JSONB NOT NULL,
+    -- Unix times in milliseconds.
+    created_at BIGINT NOT NULL DEFAULT ((EXTRACT(EPOCH FROM statement_timestamp()) * 1000)::BIGINT),
+    dispatched_at BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_dispatched ON outbox(dispatched_at) WHERE dispatched_at IS NOT NULL;
+
+-- The keys match the JSON of a task in the API. Tags have events of their own.
+CREATE OR REPLACE VIEW outbox_task_payload AS
+SELECT id, jsonb_build_object(
+    'id', id,
+    'author-id', author,
+    'project-id', project_id,
+    'parent-id', parent_id,
+    'title', title,
+    'description', description,
+    'status', status,
+    'deadline', to_char(deadline AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+    'due-date', due_date,
+    'priority', priority,
+    'recurrence', recurrence,
+    'assignee', assignee,
+    'position', position,
+    'estimate-minutes', estimate_minutes
+) AS payload FROM task;
+
+-- Sequence numbers are taken when a row is inserted, not when it is committed. The storage makes the
+-- writers of one author's tasks take turns, so that their changes and events become visible in sequence
+-- order: a client or the relay that read up to a number never misses a smaller one later.
+
+-- Tombstones of a task deleted with its author are left out, they would reference the deleted user.
+CREATE OR REPLACE FUNCTION record_task_tombstone(old_task task) RETURNS VOID AS $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM users WHERE id = old_task.author) THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO task_change(author, task_id, project_id, deleted, name)
+    VALUES (old_task.author, old_task.id, old_task.project_id, TRUE, (SELECT c.name FROM calendar_object c WHERE c.task_id = old_task.id));
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION task_after_insert() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO calendar_object(task_id, author, name, uid) VALUES (NEW.id, NEW.author, NEW.id || '.ics', NEW.id || '@todo-list');
+    INSERT INTO task_change(author, task_id, project_id) VALUES (NEW.author, NEW.id, NEW.project_id);
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    SELECT 'task', id, 'task.created', payload FROM outbox_task_payload WHERE id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_after_insert AFTER INSERT ON task
+    FOR EACH ROW EXECUTE FUNCTION task_after_insert();
+
+CREATE OR REPLACE FUNCTION task_change_after_update() RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.project_id IS DISTINCT FROM NEW.project_id THEN
+        PERFORM record_task_tombstone(OLD);
+    END IF;
+    INSERT INTO task_change(author, task_id, project_id) VALUES (NEW.author, NEW.id, NEW.project_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_change_after_update
+    AFTER UPDATE OF title, description, status, deadline, due_date, priority, recurrence, project_id ON task
+    FOR EACH ROW EXECUTE FUNCTION task_change_after_update();
+
+CREATE OR REPLACE FUNCTION task_change_before_delete() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM record_task_tombstone(OLD);
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_change_before_delete BEFORE DELETE ON task
+    FOR EACH ROW EXECUTE FUNCTION task_change_before_delete();
+
+-- A task has either a deadline or a due date, they merge as one field.
+CREATE OR REPLACE FUNCTION task_field_clock_after_update() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO task_field_clock(task_id, field, changed_at)
+    SELECT NEW.id, field, (EXTRACT(EPOCH FROM statement_timestamp()) * 1000)::BIGINT
+    FROM unnest(ARRAY[
+        CASE WHEN OLD.title IS DISTINCT FROM NEW.title THEN 'title' END,
+        CASE WHEN OLD.description IS DISTINCT FROM NEW.description THEN 'description' END,
+        CASE WHEN OLD.status IS DISTINCT FROM NEW.status THEN 'status' END,
+        CASE WHEN OLD.deadline IS DISTINCT FROM NEW.deadline OR OLD.due_date IS DISTINCT FROM NEW.due_date THEN 'due' END,
+        CASE WHEN OLD.priority IS DISTINCT FROM NEW.priority THEN 'priority' END,
+        CASE WHEN OLD.recurrence IS DISTINCT FROM NEW.recurrence THEN 'recurrence' END,
+        CASE WHEN OLD.project_id IS DISTINCT FROM NEW.project_id THEN 'project_id' END
+    ]) AS field
+    WHERE field IS NOT NULL
+    ON CONFLICT (task_id, field) DO UPDATE SET changed_at = excluded.changed_at;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_field_clock_after_update AFTER UPDATE ON task
+    FOR EACH ROW EXECUTE FUNCTION task_field_clock_after_update();
+
+CREATE OR REPLACE FUNCTION outbox_task_after_update() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    SELECT 'task', id, 'task.updated', payload FROM outbox_task_payload WHERE id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_task_after_update AFTER UPDATE ON task
+    FOR EACH ROW EXECUTE FUNCTION outbox_task_after_update();
+
+CREATE OR REPLACE FUNCTION outbox_task_after_delete() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('task', OLD.id, 'task.deleted', jsonb_build_object('id', OLD.id, 'author-id', OLD.author, 'project-id', OLD.project_id));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_task_after_delete AFTER DELETE ON task
+    FOR EACH ROW EXECUTE FUNCTION outbox_task_after_delete();
+
+-- Tags deleted with their task are no change of their own.
+CREATE OR REPLACE FUNCTION task_tag_after_change() RETURNS TRIGGER AS $$
+DECLARE
+    tag_row task_tag%ROWTYPE;
+    task_row task%ROWTYPE;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        tag_row := NEW;
+    ELSE
+        tag_row := OLD;
+    END IF;
+
+    SELECT * INTO task_row FROM task WHERE id = tag_row.task_id;
+    IF NOT FOUND THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO task_change(author, task_id, project_id) VALUES (task_row.author, task_row.id, task_row.project_id);
+
+    INSERT INTO task_field_clock(task_id, field, changed_at)
+    VALUES (task_row.id, 'tags', (EXTRACT(EPOCH FROM statement_timestamp()) * 1000)::BIGINT)
+    ON CONFLICT (task_id, field) DO UPDATE SET changed_at = excluded.changed_at;
+
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('task', task_row.id, CASE WHEN TG_OP = 'INSERT' THEN 'task.tagged' ELSE 'task.untagged' END,
+        jsonb_build_object('id', task_row.id, 'tag', tag_row.tag));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_tag_after_change AFTER INSERT OR DELETE ON task_tag
+    FOR EACH ROW EXECUTE FUNCTION task_tag_after_change();
+
+-- The password hash never leaves the database.
+CREATE OR REPLACE FUNCTION outbox_user_after_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+        VALUES ('user', OLD.id, 'user.deleted', jsonb_build_object('id', OLD.id));
+    ELSE
+        INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+        VALUES ('user', NEW.id, CASE WHEN TG_OP = 'INSERT' THEN 'user.created' ELSE 'user.updated' END,
+            jsonb_build_object('id', NEW.id, 'email', NEW.email, 'timezone', NEW.timezone));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_user_after_insert AFTER INSERT ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
+
+CREATE TRIGGER outbox_user_after_update AFTER UPDATE OF email, timezone ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
+
+CREATE TRIGGER outbox_user_after_delete AFTER DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
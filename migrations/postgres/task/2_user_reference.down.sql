@@ -0,0 +1,27 @@
+-- The credentials are gone, the users come back with placeholders they cannot log in with.
+ALTER TABLE users ADD COLUMN email TEXT, ADD COLUMN password TEXT NOT NULL DEFAULT '', ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+UPDATE users SET email = id::text;
+ALTER TABLE users ALTER COLUMN email SET NOT NULL, ADD UNIQUE (email), ALTER COLUMN password DROP DEFAULT;
+
+CREATE OR REPLACE FUNCTION outbox_user_after_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+        VALUES ('user', OLD.id, 'user.deleted', jsonb_build_object('id', OLD.id));
+    ELSE
+        INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+        VALUES ('user', NEW.id, CASE WHEN TG_OP = 'INSERT' THEN 'user.created' ELSE 'user.updated' END,
+            jsonb_build_object('id', NEW.id, 'email', NEW.email, 'timezone', NEW.timezone));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_user_after_insert AFTER INSERT ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
+
+CREATE TRIGGER outbox_user_after_update AFTER UPDATE OF email, timezone ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
+
+CREATE TRIGGER outbox_user_after_delete AFTER DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION outbox_user_after_change();
@@ -0,0 +1,10 @@
+-- Users live in the database of the auth service. The task service keeps the IDs of the users its
+-- data belongs to, added and removed as the auth service publishes user.created and user.deleted.
+-- The credentials are dropped here. The migrator copies the users to the migrated auth database
+-- given with -auth-dsn before, and refuses to apply it without one while there are users.
+DROP TRIGGER IF EXISTS outbox_user_after_delete ON users;
+DROP TRIGGER IF EXISTS outbox_user_after_update ON users;
+DROP TRIGGER IF EXISTS outbox_user_after_insert ON users;
+DROP FUNCTION IF EXISTS outbox_user_after_change();
+
+ALTER TABLE users DROP COLUMN email, DROP COLUMN password, DROP COLUMN timezone;
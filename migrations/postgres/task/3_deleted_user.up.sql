@@ -0,0 +1,7 @@
+-- Users the auth service deleted, kept for good. The reference to a user is saved along with their
+-- first row, which may come before user.created does, but the token of a deleted user stays valid
+-- until it expires and must not bring them back.
+CREATE TABLE IF NOT EXISTS deleted_user
+(
+    id UUID PRIMARY KEY
+);
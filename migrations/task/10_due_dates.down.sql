@@ -0,0 +1,2 @@
+ALTER TABLE user DROP COLUMN timezone;
+ALTER TABLE task DROP COLUMN due_date;
@@ -0,0 +1,7 @@
+ALTER TABLE task ADD COLUMN due_date TEXT CHECK ( due_date IS NULL OR date(due_date) IS due_date );
+
+-- Tasks without a deadline used to store the zero time, and deadlines kept the offset they were sent with.
+UPDATE task SET deadline = NULL WHERE deadline LIKE '0001-01-01%';
+UPDATE task SET deadline = strftime('%Y-%m-%d %H:%M:%f+00:00', deadline) WHERE deadline IS NOT NULL;
+
+ALTER TABLE user ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS task_tag;
+
+ALTER TABLE task DROP COLUMN assignee;
+ALTER TABLE task DROP COLUMN recurrence;
+ALTER TABLE task DROP COLUMN priority;
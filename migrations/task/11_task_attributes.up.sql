@@ -0,0 +1,14 @@
+ALTER TABLE task ADD COLUMN priority TEXT NOT NULL DEFAULT 'none' CHECK ( priority IN ('none','low','medium','high') );
+-- RFC 5545 recurrence rule without the RRULE: prefix, e.g. FREQ=MONTHLY;BYMONTHDAY=1.
+ALTER TABLE task ADD COLUMN recurrence TEXT;
+-- Handle of the person the task is for, as typed: there is no user directory to resolve it against.
+ALTER TABLE task ADD COLUMN assignee TEXT;
+
+CREATE TABLE IF NOT EXISTS task_tag
+(
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (task_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tag_tag ON task_tag(tag);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS task_template;
+
+DROP INDEX IF EXISTS idx_task_parent;
+ALTER TABLE task DROP COLUMN parent_id;
@@ -0,0 +1,17 @@
+-- Subtasks point at their parent. Deleting a parent leaves its subtasks as top level tasks.
+ALTER TABLE task ADD COLUMN parent_id UUID REFERENCES task(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_task_parent ON task(parent_id);
+
+CREATE TABLE IF NOT EXISTS task_template
+(
+    id UUID PRIMARY KEY,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    -- Templates of a project create their tasks there, personal templates have no project.
+    project_id UUID REFERENCES project(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    -- JSON task tree, see models.TemplateTask.
+    tasks TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (author, name)
+);
@@ -0,0 +1,19 @@
+-- Outcome of requests sent with an idempotency key, replays within the TTL get it back.
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+    -- Scope is the author of the request, keys of different users never clash.
+    scope TEXT NOT NULL,
+    request_key TEXT NOT NULL,
+    method TEXT NOT NULL,
+    -- Hash of the request, a replay with another payload is rejected.
+    fingerprint TEXT NOT NULL,
+    -- Pending rows hold the key while the first request runs and expire soon after.
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    response BLOB,
+    code INTEGER NOT NULL DEFAULT 0,
+    message TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, request_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires ON idempotency_key(expires_at);
@@ -0,0 +1,10 @@
+-- Tasks created by an import, by the ID they had in the app they came from.
+CREATE TABLE IF NOT EXISTS task_import
+(
+    task_id UUID PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    -- Source is the import format, external IDs of different apps never clash.
+    source TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    UNIQUE (author, source, external_id)
+);
@@ -0,0 +1,9 @@
+-- Secret calendar subscription URLs, one per user. Only a hash of the token is kept.
+CREATE TABLE IF NOT EXISTS calendar_feed
+(
+    author UUID PRIMARY KEY REFERENCES user(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    -- Minutes before the due time events alert, 0 for no alerts.
+    reminder_minutes INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS task_change_after_tag_delete;
+DROP TRIGGER IF EXISTS task_change_after_tag_insert;
+DROP TRIGGER IF EXISTS task_change_before_delete;
+DROP TRIGGER IF EXISTS task_change_after_move;
+DROP TRIGGER IF EXISTS task_change_after_update;
+DROP TRIGGER IF EXISTS calendar_object_after_task_insert;
+DROP TABLE IF EXISTS task_change;
+DROP TABLE IF EXISTS calendar_object;
@@ -0,0 +1,65 @@
+-- CalDAV names of the tasks, which clients may choose when they create a task.
+-- Every other task is served as <id>.ics with the UID <id>@todo-list.
+CREATE TABLE IF NOT EXISTS calendar_object
+(
+    task_id UUID PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    uid TEXT NOT NULL,
+    UNIQUE (author, name),
+    UNIQUE (author, uid)
+);
+
+-- Log of the changes to tasks as CalDAV sees them, the sync tokens of clients are sequence numbers.
+-- Deleted rows are tombstones of a task in a project, named as the client knew it.
+CREATE TABLE IF NOT EXISTS task_change
+(
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL,
+    project_id UUID,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    name TEXT,
+    changed_at INTEGER NOT NULL DEFAULT (CAST(strftime('%s', 'now') AS INTEGER))
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_change_author ON task_change(author, seq);
+CREATE INDEX IF NOT EXISTS idx_task_change_task ON task_change(task_id);
+
+CREATE TRIGGER IF NOT EXISTS calendar_object_after_task_insert AFTER INSERT ON task
+BEGIN
+    INSERT INTO calendar_object(task_id, author, name, uid) VALUES (new.id, new.author, new.id || '.ics', new.id || '@todo-list');
+    INSERT INTO task_change(author, task_id, project_id) VALUES (new.author, new.id, new.project_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_change_after_update
+    AFTER UPDATE OF title, description, status, deadline, due_date, priority, recurrence, project_id ON task
+BEGIN
+    INSERT INTO task_change(author, task_id, project_id) VALUES (new.author, new.id, new.project_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_change_after_move AFTER UPDATE OF project_id ON task
+    WHEN old.project_id IS NOT new.project_id
+BEGIN
+    INSERT INTO task_change(author, task_id, project_id, deleted, name)
+    VALUES (old.author, old.id, old.project_id, TRUE, (SELECT name FROM calendar_object WHERE task_id = old.id));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_change_before_delete BEFORE DELETE ON task
+BEGIN
+    INSERT INTO task_change(author, task_id, project_id, deleted, name)
+    VALUES (old.author, old.id, old.project_id, TRUE, (SELECT name FROM calendar_object WHERE task_id = old.id));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_change_after_tag_insert AFTER INSERT ON task_tag
+BEGIN
+    INSERT INTO task_change(author, task_id, project_id) SELECT author, id, project_id FROM task WHERE id = new.task_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_change_after_tag_delete AFTER DELETE ON task_tag
+BEGIN
+    INSERT INTO task_change(author, task_id, project_id) SELECT author, id, project_id FROM task WHERE id = old.task_id;
+END;
+
+INSERT INTO calendar_object(task_id, author, name, uid) SELECT id, author, id || '.ics', id || '@todo-list' FROM task;
+INSERT INTO task_change(author, task_id, project_id) SELECT author, id, project_id FROM task ORDER BY rowid;
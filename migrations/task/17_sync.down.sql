@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS task_field_clock_tag_delete;
+DROP TRIGGER IF EXISTS task_field_clock_tag_insert;
+DROP TRIGGER IF EXISTS task_field_clock_project;
+DROP TRIGGER IF EXISTS task_field_clock_recurrence;
+DROP TRIGGER IF EXISTS task_field_clock_priority;
+DROP TRIGGER IF EXISTS task_field_clock_due;
+DROP TRIGGER IF EXISTS task_field_clock_status;
+DROP TRIGGER IF EXISTS task_field_clock_description;
+DROP TRIGGER IF EXISTS task_field_clock_title;
+DROP INDEX IF EXISTS idx_task_change_changed_at;
+DROP TABLE IF EXISTS task_change_horizon;
+DROP TABLE IF EXISTS task_field_clock;
@@ -0,0 +1,67 @@
+-- Server time in Unix milliseconds each field of a task was last written, for the field level
+-- last-writer-wins merge of offline changes. Fields without a row were not written since the task was created.
+CREATE TABLE IF NOT EXISTS task_field_clock
+(
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    changed_at INTEGER NOT NULL,
+    PRIMARY KEY (task_id, field)
+);
+
+-- Highest sequence number of the tombstones pruned from task_change per user. Clients that synced
+-- before it may have missed deletions and have to sync from scratch.
+CREATE TABLE IF NOT EXISTS task_change_horizon
+(
+    author UUID PRIMARY KEY REFERENCES user(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_change_changed_at ON task_change(changed_at);
+
+CREATE TRIGGER IF NOT EXISTS task_field_clock_title AFTER UPDATE OF title ON task WHEN old.title IS NOT new.title
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at) VALUES (new.id, 'title', CAST(unixepoch('subsec') * 1000 AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_field_clock_description AFTER UPDATE OF description ON task WHEN old.description IS NOT new.description
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at) VALUES (new.id, 'description', CAST(unixepoch('subsec') * 1000 AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_field_clock_status AFTER UPDATE OF status ON task WHEN old.status IS NOT new.status
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at) VALUES (new.id, 'status', CAST(unixepoch('subsec') * 1000 AS INTEGER));
+END;
+
+-- A task has either a deadline or a due date, they merge as one field.
+CREATE TRIGGER IF NOT EXISTS task_field_clock_due AFTER UPDATE OF deadline, due_date ON task
+    WHEN old.deadline IS NOT new.deadline OR old.due_date IS NOT new.due_date
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at) VALUES (new.id, 'due', CAST(unixepoch('subsec') * 1000 AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_field_clock_priority AFTER UPDATE OF priority ON task WHEN old.priority IS NOT new.priority
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at) VALUES (new.id, 'priority', CAST(unixepoch('subsec') * 1000 AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_field_clock_recurrence AFTER UPDATE OF recurrence ON task WHEN old.recurrence IS NOT new.recurrence
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at) VALUES (new.id, 'recurrence', CAST(unixepoch('subsec') * 1000 AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_field_clock_project AFTER UPDATE OF project_id ON task WHEN old.project_id IS NOT new.project_id
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at) VALUES (new.id, 'project_id', CAST(unixepoch('subsec') * 1000 AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_field_clock_tag_insert AFTER INSERT ON task_tag
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at) VALUES (new.task_id, 'tags', CAST(unixepoch('subsec') * 1000 AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_field_clock_tag_delete AFTER DELETE ON task_tag
+BEGIN
+    INSERT OR REPLACE INTO task_field_clock(task_id, field, changed_at)
+    SELECT old.task_id, 'tags', CAST(unixepoch('subsec') * 1000 AS INTEGER) WHERE EXISTS (SELECT 1 FROM task WHERE id = old.task_id);
+END;
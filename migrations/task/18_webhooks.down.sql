@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_webhook_delivery_webhook;
+DROP INDEX IF EXISTS idx_webhook_delivery_due;
+DROP TABLE IF EXISTS webhook_delivery;
+DROP INDEX IF EXISTS idx_webhook_author;
+DROP TABLE IF EXISTS webhook;
@@ -0,0 +1,45 @@
+-- Endpoints the events of a user's tasks are posted to. The secret signs the payloads,
+-- so unlike calendar tokens it is kept as is.
+CREATE TABLE IF NOT EXISTS webhook
+(
+    id UUID PRIMARY KEY,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    -- Webhooks of a project only get the events of its tasks and every deletion.
+    project_id UUID REFERENCES project(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    -- Space separated task event types posted, every type when empty.
+    event_types TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Deliveries in a row that failed every attempt, the webhook is disabled after too many.
+    failures INTEGER NOT NULL DEFAULT 0,
+    disabled_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_author ON webhook(author);
+
+-- Queue of the events to post and log of the posted ones.
+CREATE TABLE IF NOT EXISTS webhook_delivery
+(
+    id UUID PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES webhook(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    payload BLOB NOT NULL,
+    -- pending, delivered or failed.
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    -- Unix time a pending delivery is due, pushed back while an attempt is in flight.
+    next_attempt_at INTEGER NOT NULL,
+    -- Unix time of the last attempt, NULL before the first.
+    last_attempt_at INTEGER,
+    -- HTTP status of the last response, 0 when there was none.
+    response_status INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    -- The delivery this one posts again, NULL for the first delivery of an event.
+    redelivery_of UUID,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_due ON webhook_delivery(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_webhook ON webhook_delivery(webhook_id, created_at);
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS outbox_user_delete;
+DROP TRIGGER IF EXISTS outbox_user_update;
+DROP TRIGGER IF EXISTS outbox_user_insert;
+DROP TRIGGER IF EXISTS outbox_task_tag_delete;
+DROP TRIGGER IF EXISTS outbox_task_tag_insert;
+DROP TRIGGER IF EXISTS outbox_task_delete;
+DROP TRIGGER IF EXISTS outbox_task_update;
+DROP TRIGGER IF EXISTS outbox_task_insert;
+DROP VIEW IF EXISTS outbox_task_payload;
+DROP INDEX IF EXISTS idx_outbox_dispatched;
+DROP INDEX IF EXISTS idx_outbox_pending;
+DROP TABLE IF EXISTS outbox;
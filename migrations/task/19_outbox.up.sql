@@ -0,0 +1,89 @@
+-- Domain events of tasks and users, written by triggers in the transaction of the change that
+-- raised them. The relay publishes them in seq order and marks them dispatched.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    -- task or user.
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    -- JSON state of the aggregate after the change, its ID alone once it is deleted.
+    payload TEXT NOT NULL,
+    -- Unix times in milliseconds.
+    created_at INTEGER NOT NULL DEFAULT (CAST(unixepoch('subsec') * 1000 AS INTEGER)),
+    dispatched_at INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_dispatched ON outbox(dispatched_at) WHERE dispatched_at IS NOT NULL;
+
+-- The keys match the JSON of a task in the API. Tags have events of their own.
+CREATE VIEW IF NOT EXISTS outbox_task_payload AS
+SELECT id, json_object(
+    'id', id,
+    'author-id', author,
+    'project-id', project_id,
+    'parent-id', parent_id,
+    'title', title,
+    'description', description,
+    'status', status,
+    'deadline', strftime('%Y-%m-%dT%H:%M:%SZ', deadline),
+    'due-date', due_date,
+    'priority', priority,
+    'recurrence', recurrence,
+    'assignee', assignee,
+    'position', position,
+    'estimate-minutes', estimate_minutes
+) AS payload FROM task;
+
+CREATE TRIGGER IF NOT EXISTS outbox_task_insert AFTER INSERT ON task
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    SELECT 'task', id, 'task.created', payload FROM outbox_task_payload WHERE id = new.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_task_update AFTER UPDATE ON task
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    SELECT 'task', id, 'task.updated', payload FROM outbox_task_payload WHERE id = new.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_task_delete AFTER DELETE ON task
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('task', old.id, 'task.deleted', json_object('id', old.id, 'author-id', old.author, 'project-id', old.project_id));
+END;
+
+-- Tags deleted with their task need no event of their own.
+CREATE TRIGGER IF NOT EXISTS outbox_task_tag_insert AFTER INSERT ON task_tag
+    WHEN EXISTS (SELECT 1 FROM task WHERE id = new.task_id)
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('task', new.task_id, 'task.tagged', json_object('id', new.task_id, 'tag', new.tag));
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_task_tag_delete AFTER DELETE ON task_tag
+    WHEN EXISTS (SELECT 1 FROM task WHERE id = old.task_id)
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('task', old.task_id, 'task.untagged', json_object('id', old.task_id, 'tag', old.tag));
+END;
+
+-- The password hash never leaves the database.
+CREATE TRIGGER IF NOT EXISTS outbox_user_insert AFTER INSERT ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', new.id, 'user.created', json_object('id', new.id, 'email', new.email, 'timezone', new.timezone));
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_user_update AFTER UPDATE OF email, timezone ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', new.id, 'user.updated', json_object('id', new.id, 'email', new.email, 'timezone', new.timezone));
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_user_delete AFTER DELETE ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', old.id, 'user.deleted', json_object('id', old.id));
+END;
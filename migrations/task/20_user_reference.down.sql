@@ -0,0 +1,34 @@
+-- The credentials are gone, the users come back with placeholders they cannot log in with.
+CREATE TABLE user_account
+(
+    id UUID PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    password TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC'
+);
+
+INSERT INTO user_account(id, email, password) SELECT id, id, '' FROM user;
+
+DROP TABLE user;
+
+ALTER TABLE user_account RENAME TO user;
+
+CREATE INDEX IF NOT EXISTS idx_user_email ON user(email);
+
+CREATE TRIGGER IF NOT EXISTS outbox_user_insert AFTER INSERT ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', new.id, 'user.created', json_object('id', new.id, 'email', new.email, 'timezone', new.timezone));
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_user_update AFTER UPDATE OF email, timezone ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', new.id, 'user.updated', json_object('id', new.id, 'email', new.email, 'timezone', new.timezone));
+END;
+
+CREATE TRIGGER IF NOT EXISTS outbox_user_delete AFTER DELETE ON user
+BEGIN
+    INSERT INTO outbox(aggregate_type, aggregate_id, event_type, payload)
+    VALUES ('user', old.id, 'user.deleted', json_object('id', old.id));
+END;
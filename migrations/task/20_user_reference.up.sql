@@ -0,0 +1,19 @@
+-- Users live in the database of the auth service. The task service keeps the IDs of the users its
+-- data belongs to, added and removed as the auth service publishes user.created and user.deleted.
+-- The credentials are dropped here. The migrator copies the users to the migrated auth database
+-- given with -auth-storage-path before, and refuses to apply it without one while there are users.
+-- Copying publishes them to the task service again, harmlessly.
+--
+-- SQLite cannot drop a UNIQUE column, so the table is rebuilt. The migrator connects with foreign
+-- keys off, as rebuilding requires: with them on, dropping the table would delete every task.
+CREATE TABLE user_reference
+(
+    id UUID PRIMARY KEY
+);
+
+INSERT INTO user_reference(id) SELECT id FROM user;
+
+-- Drops the index on the email and the user triggers of the outbox too.
+DROP TABLE user;
+
+ALTER TABLE user_reference RENAME TO user;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_attachment_author;
+DROP INDEX IF EXISTS idx_attachment_task;
+DROP TABLE IF EXISTS attachment;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS attachment
+(
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    sha256 TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachment_task ON attachment(task_id);
+CREATE INDEX IF NOT EXISTS idx_attachment_author ON attachment(author);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_checklist_item_task;
+DROP TABLE IF EXISTS task_checklist_item;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS task_checklist_item
+(
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_checklist_item_task ON task_checklist_item(task_id, position);
@@ -0,0 +1,32 @@
+-- Requires SQLite built with FTS5 (go build -tags sqlite_fts5).
+-- task has no INTEGER PRIMARY KEY, so its rowid may change on VACUUM:
+-- the index keeps its own copy of the text and is keyed by task_id instead.
+CREATE VIRTUAL TABLE IF NOT EXISTS task_fts USING fts5
+(
+    task_id UNINDEXED,
+    author UNINDEXED,
+    title,
+    description,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS task_fts_after_insert AFTER INSERT ON task
+BEGIN
+    INSERT INTO task_fts(task_id, author, title, description)
+    VALUES (new.id, new.author, new.title, COALESCE(new.description, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_fts_after_update AFTER UPDATE OF title, description, author ON task
+BEGIN
+    DELETE FROM task_fts WHERE task_id = old.id;
+    INSERT INTO task_fts(task_id, author, title, description)
+    VALUES (new.id, new.author, new.title, COALESCE(new.description, ''));
+END;
+
+CREATE TRIGGER IF NOT EXISTS task_fts_after_delete AFTER DELETE ON task
+BEGIN
+    DELETE FROM task_fts WHERE task_id = old.id;
+END;
+
+INSERT INTO task_fts(task_id, author, title, description)
+SELECT id, author, title, COALESCE(description, '') FROM task;
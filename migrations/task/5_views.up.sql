@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS view
+(
+    id UUID PRIMARY KEY,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    query TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (author, name)
+);
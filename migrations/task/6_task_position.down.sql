@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_position;
+ALTER TABLE task DROP COLUMN position;
@@ -0,0 +1,10 @@
+ALTER TABLE task ADD COLUMN position TEXT NOT NULL DEFAULT '';
+
+-- Seed fractional rank keys in creation order, leaving room before and after.
+UPDATE task SET position = (
+    SELECT printf('U%06dV', COUNT(*))
+    FROM task t
+    WHERE t.author = task.author AND t.status = task.status AND t.rowid <= task.rowid
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_position ON task(author, status, position);
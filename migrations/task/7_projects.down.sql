@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_task_position;
+ALTER TABLE task DROP COLUMN project_id;
+CREATE INDEX IF NOT EXISTS idx_task_position ON task(author, status, position);
+DROP TABLE IF EXISTS project;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS project
+(
+    id UUID PRIMARY KEY,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (author, name)
+);
+
+-- Tasks without a project belong to the author's inbox.
+ALTER TABLE task ADD COLUMN project_id UUID REFERENCES project(id) ON DELETE SET NULL;
+
+DROP INDEX IF EXISTS idx_task_position;
+CREATE INDEX IF NOT EXISTS idx_task_position ON task(author, project_id, status, position);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS time_entry
+(
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    author UUID NOT NULL REFERENCES user(id) ON DELETE CASCADE,
+    started_at TIMESTAMP NOT NULL,
+    -- NULL while the timer is running.
+    ended_at TIMESTAMP,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT ''
+);
+
+-- At most one running timer per user.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entry_running ON time_entry(author) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_time_entry_task ON time_entry(task_id);
+CREATE INDEX IF NOT EXISTS idx_time_entry_author_started ON time_entry(author, started_at);
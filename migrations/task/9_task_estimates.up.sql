@@ -0,0 +1 @@
+ALTER TABLE task ADD COLUMN estimate_minutes INTEGER CHECK ( estimate_minutes IS NULL OR estimate_minutes >= 0 );
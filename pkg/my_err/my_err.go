@@ -7,11 +7,76 @@ import (
 var (
 	ErrUserExists   = errors.New("user already exists")
 	ErrUserNotFound = errors.New("user not found")
+	ErrUserDeleted  = errors.New("user was deleted")
 	ErrNoAuth       = errors.New("no authentication session found")
 
 	ErrEmptyTitle   = errors.New("task title cannot be empty")
 	ErrTaskNotFound = errors.New("user does not have task with given ID")
 
+	ErrAttachmentNotFound = errors.New("user does not have attachment with given ID")
+	ErrQuotaExceeded      = errors.New("attachment storage quota exceeded")
+	ErrBlobNotFound       = errors.New("blob not found")
+	ErrInvalidBlobKey     = errors.New("invalid blob key")
+
+	ErrChecklistItemNotFound = errors.New("user does not have checklist item with given ID")
+
+	ErrEmptyQuery = errors.New("search query cannot be empty")
+
+	ErrViewNotFound     = errors.New("user does not have view with given ID")
+	ErrViewExists       = errors.New("view with given name already exists")
+	ErrInvalidViewQuery = errors.New("invalid view query")
+	ErrInvalidTimezone  = errors.New("invalid timezone")
+
+	ErrInvalidNeighbour = errors.New("neighbour task is not in the same list")
+	ErrStaleNeighbours  = errors.New("neighbour tasks are no longer adjacent")
+
+	ErrProjectNotFound    = errors.New("user does not have project with given ID")
+	ErrProjectExists      = errors.New("project with given name already exists")
+	ErrInvalidProjectName = errors.New("invalid project name")
+	ErrInvalidStatus      = errors.New("unknown task status")
+	ErrInvalidCursor      = errors.New("invalid page token")
+
+	ErrTimerRunning     = errors.New("a timer is already running")
+	ErrNoRunningTimer   = errors.New("no timer is running")
+	ErrInvalidTimeEntry = errors.New("invalid time entry")
+	ErrInvalidDateRange = errors.New("invalid date range")
+
+	ErrInvalidEstimate = errors.New("invalid estimate")
+	ErrInvalidDueDate  = errors.New("invalid due date")
+	ErrInvalidQuickAdd = errors.New("invalid quick add text")
+
+	ErrTemplateNotFound = errors.New("user does not have template with given ID")
+	ErrTemplateExists   = errors.New("template with given name already exists")
+	ErrInvalidTemplate  = errors.New("invalid template")
+
+	ErrInvalidBatch = errors.New("invalid batch")
+
+	ErrInvalidExportFormat = errors.New("unknown export format")
+
+	ErrInvalidImport   = errors.New("invalid import")
+	ErrAlreadyImported = errors.New("task was already imported")
+
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+	ErrInvalidCalendarFeed  = errors.New("invalid calendar feed")
+
+	ErrCalendarObjectNotFound = errors.New("calendar object not found")
+	ErrInvalidCalendarObject  = errors.New("invalid calendar object")
+	ErrCalendarUIDExists      = errors.New("calendar object with given UID already exists")
+	ErrETagMismatch           = errors.New("calendar object does not match the ETag")
+	ErrInvalidSyncToken       = errors.New("invalid sync token")
+
+	ErrEventsExpired  = errors.New("task events after the sequence number are no longer kept")
+	ErrWatcherLagging = errors.New("task watcher fell behind")
+	ErrWatchersClosed = errors.New("task watchers closed")
+
+	ErrInvalidSync = errors.New("invalid sync")
+	ErrTaskIDTaken = errors.New("task ID is already taken")
+
+	ErrWebhookNotFound         = errors.New("user does not have webhook with given ID")
+	ErrWebhookDeliveryNotFound = errors.New("webhook does not have delivery with given ID")
+	ErrInvalidWebhook          = errors.New("invalid webhook")
+	ErrWebhookDisabled         = errors.New("webhook is disabled")
+
 	ErrEmptyField = errors.New("field cannot be empty")
 	ErrParseUUID  = errors.New("failed to parse UUID")
 )